@@ -15,6 +15,17 @@ interface GoNativeBlobCompressor {
    */
   fun Init(dataLimit: Int, dictPath: String): Boolean
 
+  /**
+   * InitWithVersion behaves as Init, except that the compressor produces blobs of the given version:
+   * 0xffff (v1) or 0xfffe (v2, with the EIP-7702 set-code transactions)
+   *
+   * @param dataLimit Size limit for compressed data in bytes
+   * @param dictPath Path to the compression dictionary
+   * @param version Blob format version, as written in the blob header
+   * @return returns true if the compressor was successfully intialized else false
+   */
+  fun InitWithVersion(dataLimit: Int, dictPath: String, version: Short): Boolean
+
   /**
    * Reset clears the compressor
    */
@@ -229,21 +229,7 @@ func CraftResponse(cfg *config.Config, cf *CollectedFields) (resp *Response, err
 		return resp, nil
 	}
 
-	pubInputParts := public_input.Aggregation{
-		FinalShnarf:                             cf.FinalShnarf,
-		ParentAggregationFinalShnarf:            cf.ParentAggregationFinalShnarf,
-		ParentStateRootHash:                     cf.ParentStateRootHash,
-		ParentAggregationLastBlockTimestamp:     cf.ParentAggregationLastBlockTimestamp,
-		FinalTimestamp:                          cf.FinalTimestamp,
-		LastFinalizedBlockNumber:                cf.LastFinalizedBlockNumber,
-		FinalBlockNumber:                        cf.FinalBlockNumber,
-		LastFinalizedL1RollingHash:              cf.LastFinalizedL1RollingHash,
-		L1RollingHash:                           cf.L1RollingHash,
-		LastFinalizedL1RollingHashMessageNumber: cf.LastFinalizedL1RollingHashMessageNumber,
-		L1RollingHashMessageNumber:              resp.L1RollingHashMessageNumber,
-		L2MsgRootHashes:                         cf.L2MsgRootHashes,
-		L2MsgMerkleTreeDepth:                    l2MsgMerkleTreeDepth,
-	}
+	pubInputParts := resp.FuncInput(cf.LastFinalizedL1RollingHash, cf.LastFinalizedL1RollingHashMessageNumber)
 
 	resp.AggregatedProofPublicInput = pubInputParts.GetPublicInputHex()
 
@@ -267,6 +253,29 @@ func CraftResponse(cfg *config.Config, cf *CollectedFields) (resp *Response, err
 	return resp, nil
 }
 
+// FuncInput returns the fields of the response that are used to construct the
+// public input of the aggregation proof. The rolling hash of the last L1
+// message finalized by the parent aggregation and its number are not part of
+// the response and must be provided by the caller, they are found in the
+// request.
+func (resp *Response) FuncInput(lastFinalizedL1RollingHash string, lastFinalizedL1RollingHashMessageNumber uint) public_input.Aggregation {
+	return public_input.Aggregation{
+		FinalShnarf:                             resp.FinalShnarf,
+		ParentAggregationFinalShnarf:            resp.ParentAggregationFinalShnarf,
+		ParentStateRootHash:                     resp.ParentStateRootHash,
+		ParentAggregationLastBlockTimestamp:     resp.ParentAggregationLastBlockTimestamp,
+		FinalTimestamp:                          resp.FinalTimestamp,
+		LastFinalizedBlockNumber:                resp.LastFinalizedBlockNumber,
+		FinalBlockNumber:                        resp.FinalBlockNumber,
+		LastFinalizedL1RollingHash:              lastFinalizedL1RollingHash,
+		L1RollingHash:                           resp.L1RollingHash,
+		LastFinalizedL1RollingHashMessageNumber: lastFinalizedL1RollingHashMessageNumber,
+		L1RollingHashMessageNumber:              resp.L1RollingHashMessageNumber,
+		L2MsgRootHashes:                         resp.L2MerkleRoots,
+		L2MsgMerkleTreeDepth:                    utils.ToInt(resp.L2MsgTreesDepth),
+	}
+}
+
 // validate the content of the collected fields.
 func validate(cf *CollectedFields) (err error) {
 
@@ -158,24 +158,41 @@ func makeDummyProof(cfg *config.Config, input string, circID circuits.MockCircui
 	return dummy.MakeProof(&setup, x, circID)
 }
 
-func makeBw6Proof(
-	cfg *config.Config,
-	cf *CollectedFields,
-	piProof plonk.Proof,
-	piPublicWitness witness.Witness,
-	publicInput string,
-) (proof plonk.Proof, circuitID int, err error) {
+// RequestCircuitID returns the ID of the aggregation circuit used to prove the
+// request, e.g. `aggregation-10`. It reads the responses the request
+// aggregates and the manifests of the aggregation setups.
+func RequestCircuitID(cfg *config.Config, req *Request) (circuits.CircuitID, error) {
+
+	cf, err := collectFields(cfg, req)
+	if err != nil {
+		return "", fmt.Errorf("could not collect the fields: %w", err)
+	}
+
+	_, size, _, err := selectBw6Circuit(cfg, cf.ProofClaims)
+	if err != nil {
+		return "", err
+	}
 
-	// This determines which is the best circuit to use for aggregation, we
-	// take the smallest circuit that has enough capacity.
+	return circuits.CircuitID(fmt.Sprintf("%s-%d", string(circuits.AggregationCircuitID), size)), nil
+}
+
+// selectBw6Circuit determines which is the best circuit to use for
+// aggregation: the smallest circuit that has enough capacity and supports the
+// verifying keys of the proofs. It returns its position in the config, the
+// number of proofs it aggregates and the digests of the verifying keys it
+// supports.
+func selectBw6Circuit(
+	cfg *config.Config,
+	proofClaims []aggregation.ProofClaimAssignment,
+) (circuitID, bestSize int, bestAllowedVkForAggregation []string, err error) {
 
 	var (
-		numProofClaims              = len(cf.ProofClaims)
-		biggestAvailable            = 0
-		bestSize                    = math.MaxInt
-		bestAllowedVkForAggregation []string
+		numProofClaims   = len(proofClaims)
+		biggestAvailable = 0
 	)
 
+	bestSize = math.MaxInt
+
 	// first we discover available setups
 	for setupPos, maxNbProofs := range cfg.Aggregation.NumProofs {
 		biggestAvailable = max(biggestAvailable, maxNbProofs)
@@ -191,15 +208,15 @@ func makeBw6Proof(
 		setupPath := cfg.PathForSetup(string(circuitIDStr))
 		manifest, err := circuits.ReadSetupManifest(filepath.Join(setupPath, config.ManifestFileName))
 		if err != nil {
-			return nil, 0, fmt.Errorf("could not read the manifest for circuit %v: %w", circuitIDStr, err)
+			return 0, 0, nil, fmt.Errorf("could not read the manifest for circuit %v: %w", circuitIDStr, err)
 		}
 		allowedVkForAggregation, err := manifest.GetStringArray("allowedVkForAggregationDigests")
 		if err != nil {
-			return nil, 0, fmt.Errorf("could not read the allowedVkForAggregationDigests: %w", err)
+			return 0, 0, nil, fmt.Errorf("could not read the allowedVkForAggregationDigests: %w", err)
 		}
 
 		// This reject condition may take longer
-		if !doesBw6CircuitSupportVKeys(allowedVkForAggregation, cf.ProofClaims) {
+		if !doesBw6CircuitSupportVKeys(allowedVkForAggregation, proofClaims) {
 			logrus.Infof("skipping setup with %v proofs because it does not support the required verifying keys", maxNbProofs)
 			continue
 		}
@@ -212,12 +229,28 @@ func makeBw6Proof(
 	}
 
 	if bestSize == math.MaxInt {
-		return nil, 0, fmt.Errorf(
+		return 0, 0, nil, fmt.Errorf(
 			"could not find a setup large enough for %v proofs: the biggest available size is %v",
 			numProofClaims, biggestAvailable,
 		)
 	}
 
+	return circuitID, bestSize, bestAllowedVkForAggregation, nil
+}
+
+func makeBw6Proof(
+	cfg *config.Config,
+	cf *CollectedFields,
+	piProof plonk.Proof,
+	piPublicWitness witness.Witness,
+	publicInput string,
+) (proof plonk.Proof, circuitID int, err error) {
+
+	circuitID, bestSize, bestAllowedVkForAggregation, err := selectBw6Circuit(cfg, cf.ProofClaims)
+	if err != nil {
+		return nil, 0, err
+	}
+
 	logrus.Infof("reading the BW6 setup for %v proofs", bestSize)
 	c := circuits.CircuitID(fmt.Sprintf("%s-%d", string(circuits.AggregationCircuitID), bestSize))
 	setup, err := circuits.LoadSetup(cfg, c)
@@ -10,7 +10,9 @@ import (
 	blob_v1 "github.com/consensys/linea-monorepo/prover/lib/compressor/blob/v1"
 
 	"github.com/consensys/gnark-crypto/ecc"
+	fr377 "github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
 	fr381 "github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/consensys/gnark/frontend"
 	"github.com/consensys/linea-monorepo/prover/circuits"
 	"github.com/consensys/linea-monorepo/prover/circuits/blobdecompression"
 	"github.com/consensys/linea-monorepo/prover/circuits/dummy"
@@ -22,8 +24,20 @@ import (
 	emPlonk "github.com/consensys/gnark/std/recursion/plonk"
 )
 
-// Generates a concrete proof for the decompression of the blob
-func Prove(cfg *config.Config, req *Request) (*Response, error) {
+// assignment collects the values derived from a request that are needed to
+// prove the decompression of the blob and to verify the proof.
+type assignment struct {
+	circuit                      frontend.Circuit
+	publicInput                  fr377.Element
+	circuitID                    circuits.CircuitID
+	expectedMaxUsableBytes       int
+	expectedMaxUncompressedBytes int
+}
+
+// assign parses the request and computes the assignment of the decompression
+// circuit. It also checks that the snark hash of the request matches the one
+// computed by the assigner.
+func assign(cfg *config.Config, req *Request) (*assignment, error) {
 
 	// Parsing / validating the request
 	blobBytes, err := base64.StdEncoding.DecodeString(req.CompressedData)
@@ -50,25 +64,21 @@ func Prove(cfg *config.Config, req *Request) (*Response, error) {
 
 	// First of all, we need to identify which setup-info to use
 	version := blob.GetVersion(blobBytes)
-	var (
-		circuitID                    circuits.CircuitID
-		expectedMaxUsableBytes       int
-		expectedMaxUncompressedBytes int
-	)
+	a := &assignment{}
 	switch version {
 	case 0:
-		circuitID = circuits.BlobDecompressionV0CircuitID
-		expectedMaxUsableBytes = blob_v0.MaxUsableBytes
-		expectedMaxUncompressedBytes = blob_v0.MaxUncompressedBytes
+		a.circuitID = circuits.BlobDecompressionV0CircuitID
+		a.expectedMaxUsableBytes = blob_v0.MaxUsableBytes
+		a.expectedMaxUncompressedBytes = blob_v0.MaxUncompressedBytes
 	case 1:
-		circuitID = circuits.BlobDecompressionV1CircuitID
-		expectedMaxUsableBytes = blob_v1.MaxUsableBytes
-		expectedMaxUncompressedBytes = blob_v1.MaxUncompressedBytes
+		a.circuitID = circuits.BlobDecompressionV1CircuitID
+		a.expectedMaxUsableBytes = blob_v1.MaxUsableBytes
+		a.expectedMaxUncompressedBytes = blob_v1.MaxUncompressedBytes
 	default:
 		return nil, fmt.Errorf("unsupported blob version: %v", version)
 	}
 
-	dictPath := cfg.BlobDecompressionDictPath(string(circuitID))
+	dictPath := cfg.BlobDecompressionDictPath(string(a.circuitID))
 
 	logrus.Infof("reading the dictionary at %v", dictPath)
 
@@ -86,8 +96,8 @@ func Prove(cfg *config.Config, req *Request) (*Response, error) {
 		return nil, fmt.Errorf("could not parse the snark hash: %w", err)
 	}
 
-	assignment, pubInput, _snarkHash, err := blobdecompression.Assign(
-		utils.RightPad(blobBytes, expectedMaxUsableBytes),
+	circuit, pubInput, _snarkHash, err := blobdecompression.Assign(
+		utils.RightPad(blobBytes, a.expectedMaxUsableBytes),
 		dict,
 		req.Eip4844Enabled,
 		xBytes,
@@ -102,9 +112,35 @@ func Prove(cfg *config.Config, req *Request) (*Response, error) {
 		return nil, fmt.Errorf("blob checksum does not match the one computed by the assigner")
 	}
 
+	a.circuit, a.publicInput = circuit, pubInput
+	return a, nil
+}
+
+// PublicInput recomputes the public input of the decompression proof of the
+// request and returns it along with the ID of the circuit proving it, which
+// depends on the version of the blob.
+func PublicInput(cfg *config.Config, req *Request) (fr377.Element, circuits.CircuitID, error) {
+	a, err := assign(cfg, req)
+	if err != nil {
+		return fr377.Element{}, "", err
+	}
+	return a.publicInput, a.circuitID, nil
+}
+
+// Generates a concrete proof for the decompression of the blob
+func Prove(cfg *config.Config, req *Request) (*Response, error) {
+
+	a, err := assign(cfg, req)
+	if err != nil {
+		return nil, err
+	}
+
 	var (
-		setup           circuits.Setup
-		proofSerialized string
+		pubInput                     = a.publicInput
+		expectedMaxUsableBytes       = a.expectedMaxUsableBytes
+		expectedMaxUncompressedBytes = a.expectedMaxUncompressedBytes
+		setup                        circuits.Setup
+		proofSerialized              string
 	)
 
 	if cfg.BlobDecompression.ProverMode == config.ProverModeDev {
@@ -121,7 +157,7 @@ func Prove(cfg *config.Config, req *Request) (*Response, error) {
 
 		proofSerialized = dummy.MakeProof(&setup, pubInput, circuits.MockCircuitIDDecompression)
 	} else {
-		if setup, err = circuits.LoadSetup(cfg, circuitID); err != nil {
+		if setup, err = circuits.LoadSetup(cfg, a.circuitID); err != nil {
 			return nil, fmt.Errorf("could not load the setup: %w", err)
 		}
 
@@ -157,7 +193,7 @@ func Prove(cfg *config.Config, req *Request) (*Response, error) {
 
 		proof, err := circuits.ProveCheck(
 			&setup,
-			assignment,
+			a.circuit,
 			opts...,
 		)
 
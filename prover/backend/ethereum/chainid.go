@@ -14,9 +14,10 @@ func GetSigner(tx *types.Transaction) types.Signer {
 	return getUnprotectedSigner()
 }
 
-// Get the signer
+// Get the signer. The Prague signer accepts all the transaction types up to
+// the EIP-7702 set-code transactions.
 func getSigner(chainID *big.Int) types.Signer {
-	return types.NewLondonSigner(chainID)
+	return types.NewPragueSigner(chainID)
 }
 
 // Get the unprotected signer
@@ -12,6 +12,8 @@ import (
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/crypto/secp256k1"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/holiman/uint256"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -73,6 +75,25 @@ var testTxDatas = []types.TxData{
 			types.AccessTuple{Address: TEST_ADDRESS_A, StorageKeys: []common.Hash{TEST_HASH_A, TEST_HASH_F}},
 		},
 	},
+	// set-code transaction, the signatures of the authorizations are not
+	// checked by the encoding
+	&types.SetCodeTx{
+		ChainID:   uint256.MustFromBig(CHAIN_ID),
+		Nonce:     2,
+		GasTipCap: uint256.NewInt(123543135),
+		GasFeeCap: uint256.NewInt(112121212),
+		Gas:       4531112,
+		To:        TEST_ADDRESS,
+		Value:     uint256.NewInt(845315452),
+		Data:      common.Hex2Bytes("deed8745a20f"),
+		AccessList: types.AccessList{
+			types.AccessTuple{Address: TEST_ADDRESS_A, StorageKeys: []common.Hash{TEST_HASH_A, TEST_HASH_F}},
+		},
+		AuthList: []types.SetCodeAuthorization{
+			{ChainID: *uint256.MustFromBig(CHAIN_ID), Address: TEST_ADDRESS_A, Nonce: 3, V: 1, R: *uint256.NewInt(4), S: *uint256.NewInt(5)},
+			{Address: TEST_ADDRESS, Nonce: 6},
+		},
+	},
 }
 
 // Test signers
@@ -81,6 +102,7 @@ var testSigners = []types.Signer{
 	types.NewEIP155Signer(CHAIN_ID),
 	types.NewEIP2930Signer(CHAIN_ID),
 	types.NewLondonSigner(CHAIN_ID),
+	types.NewPragueSigner(CHAIN_ID),
 }
 
 // Test the consistency with ethereum signatures for unprotected legacy tx.
@@ -127,6 +149,8 @@ func TestTransactionSigning(t *testing.T) {
 			assert.Equal(t, tx.Data(), decodedTx.Data())
 			assert.Equal(t, tx.Value(), decodedTx.Value())
 			assert.Equal(t, tx.Cost(), decodedTx.Cost())
+			assert.Equal(t, tx.AccessList(), decodedTx.AccessList())
+			assert.Equal(t, tx.SetCodeAuthorizations(), decodedTx.SetCodeAuthorizations())
 		})
 	}
 
@@ -148,3 +172,68 @@ func ecRecover(msg []byte, sig Signature) common.Address {
 	res.SetBytes(crypto.Keccak256(pubKey[:])[12:])
 	return res
 }
+
+func TestSetCodeTxAuthorities(t *testing.T) {
+
+	var (
+		privKey, errKey = crypto.GenerateKey()
+		signer          = types.NewPragueSigner(CHAIN_ID)
+		authList        = make([]types.SetCodeAuthorization, 2)
+		err             error
+	)
+
+	require.NoError(t, errKey)
+
+	for i := range authList {
+		authList[i], err = types.SignSetCode(privKey, types.SetCodeAuthorization{
+			ChainID: *uint256.MustFromBig(CHAIN_ID),
+			Address: TEST_ADDRESS_A,
+			Nonce:   uint64(i),
+		})
+		require.NoError(t, err)
+	}
+
+	tx, err := types.SignNewTx(privKey, signer, &types.SetCodeTx{
+		ChainID:   uint256.MustFromBig(CHAIN_ID),
+		GasTipCap: uint256.NewInt(1),
+		GasFeeCap: uint256.NewInt(2),
+		Gas:       21000,
+		To:        TEST_ADDRESS,
+		Value:     uint256.NewInt(0),
+		AuthList:  authList,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, signer.Hash(tx), GetTxHash(tx))
+	assert.Equal(t, crypto.PubkeyToAddress(privKey.PublicKey), common.Address(GetFrom(tx)))
+
+	// the authorities can be recovered from the decoded authorizations
+	decoded, err := DecodeTxFromBytes(bytes.NewReader(EncodeTxForSigning(tx)))
+	require.NoError(t, err)
+
+	for _, auth := range decoded.(*types.SetCodeTx).AuthList {
+		authority, err := auth.Authority()
+		require.NoError(t, err)
+		assert.Equal(t, crypto.PubkeyToAddress(privKey.PublicKey), authority)
+	}
+}
+
+func TestSetCodeTxDecodingErrors(t *testing.T) {
+
+	// the authorization list is missing
+	encoded := EncodeTxForSigning(types.NewTx(testTxDatas[3]))
+	encoded[0] = types.SetCodeTxType
+	_, err := DecodeTxFromBytes(bytes.NewReader(encoded))
+	assert.Error(t, err)
+
+	// an authorization lacks its signature
+	var buffer bytes.Buffer
+	buffer.WriteByte(types.SetCodeTxType)
+	err = rlp.Encode(&buffer, []any{
+		CHAIN_ID, uint64(0), big.NewInt(1), big.NewInt(2), uint64(21000), TEST_ADDRESS, big.NewInt(0), []byte{}, types.AccessList{},
+		[]any{[]any{CHAIN_ID, TEST_ADDRESS_A, uint64(0)}},
+	})
+	require.NoError(t, err)
+	_, err = DecodeTxFromBytes(bytes.NewReader(buffer.Bytes()))
+	assert.Error(t, err)
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/holiman/uint256"
 )
 
 // Returns the transaction hash of the transaction
@@ -30,6 +31,21 @@ func EncodeTxForSigning(tx *types.Transaction) (encodedTx []byte) {
 	var buffer bytes.Buffer
 
 	switch {
+	// EIP-7702 set-code transaction
+	case tx.Type() == types.SetCodeTxType:
+		buffer.Write([]byte{tx.Type()})
+		rlp.Encode(&buffer, []interface{}{
+			tx.ChainId(),
+			tx.Nonce(),
+			tx.GasTipCap(),
+			tx.GasFeeCap(),
+			tx.Gas(),
+			tx.To(),
+			tx.Value(),
+			tx.Data(),
+			tx.AccessList(),
+			tx.SetCodeAuthorizations(),
+		})
 	// LONDON with dynamic fees
 	case tx.Type() == types.DynamicFeeTxType:
 		buffer.Write([]byte{tx.Type()})
@@ -87,10 +103,14 @@ func EncodeTxForSigning(tx *types.Transaction) (encodedTx []byte) {
 
 const (
 	// Number of rlp encoded field of the transaction
+	setCodeTxNumField     int = 10
 	dynFeeNumField        int = 9
 	accessListTxNumField  int = 8
 	legacyTxNumField      int = 9
 	unprotectedTxNumField int = 6
+	// Number of rlp encoded field of an authorization of a set-code
+	// transaction, including its signature
+	authorizationNumField int = 6
 )
 
 // DecodeTxFromBytes from a string of bytes. If the stream of bytes is larger
@@ -112,6 +132,8 @@ func DecodeTxFromBytes(b *bytes.Reader) (tx types.TxData, err error) {
 	}
 
 	switch {
+	case firstByte == types.SetCodeTxType:
+		return decodeSetCodeTx(b)
 	case firstByte == types.DynamicFeeTxType:
 		return decodeDynamicFeeTx(b)
 	case firstByte == types.AccessListTxType:
@@ -131,6 +153,37 @@ func DecodeTxFromBytes(b *bytes.Reader) (tx types.TxData, err error) {
 	}
 }
 
+// decodeSetCodeTx decodes a [types.SetCodeTx] from a [bytes.Reader] and
+// returns an error if it did not pass.
+func decodeSetCodeTx(b *bytes.Reader) (parsedTx *types.SetCodeTx, err error) {
+	decTx := []any{}
+
+	if err = rlp.Decode(b, &decTx); err != nil {
+		return nil, fmt.Errorf("could not rlp decode transaction: %w", err)
+	}
+
+	if len(decTx) != setCodeTxNumField {
+		return nil, fmt.Errorf("invalid number of field for a set-code transaction")
+	}
+
+	parsedTx = new(types.SetCodeTx)
+
+	err = errors.Join(
+		TryCast(&parsedTx.ChainID, decTx[0], "chainID"),
+		TryCast(&parsedTx.Nonce, decTx[1], "nonce"),
+		TryCast(&parsedTx.GasTipCap, decTx[2], "gas-tip-cap"),
+		TryCast(&parsedTx.GasFeeCap, decTx[3], "gas-fee-cap"),
+		TryCast(&parsedTx.Gas, decTx[4], "gas"),
+		TryCast(&parsedTx.To, decTx[5], "to"),
+		TryCast(&parsedTx.Value, decTx[6], "value"),
+		TryCast(&parsedTx.Data, decTx[7], "data"),
+		TryCast(&parsedTx.AccessList, decTx[8], "access-list"),
+		TryCast(&parsedTx.AuthList, decTx[9], "authorization-list"),
+	)
+
+	return
+}
+
 // decodeDynamicFeeTx encodes a [types.DynamicFeeTx] into a [bytes.Reader] and
 // returns an error if it did not pass.
 func decodeDynamicFeeTx(b *bytes.Reader) (parsedTx *types.DynamicFeeTx, err error) {
@@ -263,6 +316,33 @@ func TryCast[T any](into *T, from any, explainer string) error {
 			*into = (any(tuple)).(T)
 			return err
 
+		case []types.SetCodeAuthorization:
+			authList := make([]types.SetCodeAuthorization, length)
+			for i := range authList {
+				err = errors.Join(
+					err,
+					TryCast(&authList[i], list[i], fmt.Sprintf("%v[%v]", explainer, i)),
+				)
+			}
+			*into = (any(authList)).(T)
+			return err
+
+		case types.SetCodeAuthorization:
+			if length != authorizationNumField {
+				return fmt.Errorf("invalid number of field for %v", explainer)
+			}
+			auth := types.SetCodeAuthorization{}
+			err = errors.Join(
+				TryCast(&auth.ChainID, list[0], fmt.Sprintf("%v.%v", explainer, "chainID")),
+				TryCast(&auth.Address, list[1], fmt.Sprintf("%v.%v", explainer, "address")),
+				TryCast(&auth.Nonce, list[2], fmt.Sprintf("%v.%v", explainer, "nonce")),
+				TryCast(&auth.V, list[3], fmt.Sprintf("%v.%v", explainer, "v")),
+				TryCast(&auth.R, list[4], fmt.Sprintf("%v.%v", explainer, "r")),
+				TryCast(&auth.S, list[5], fmt.Sprintf("%v.%v", explainer, "s")),
+			)
+			*into = (any(auth)).(T)
+			return err
+
 		case []common.Hash:
 			hashes := make([]common.Hash, length)
 			for i := range hashes {
@@ -307,12 +387,33 @@ func TryCast[T any](into *T, from any, explainer string) error {
 		var parsedBigInt big.Int
 		parsedBigInt.SetBytes(fromBytes)
 		*into = any(&parsedBigInt).(T)
+	case *uint256.Int:
+		if len(fromBytes) > 32 {
+			return fmt.Errorf("could not decode %v: %v bytes for a uint256", explainer, len(fromBytes))
+		}
+		*into = any(new(uint256.Int).SetBytes(fromBytes)).(T)
+	case uint256.Int:
+		if len(fromBytes) > 32 {
+			return fmt.Errorf("could not decode %v: %v bytes for a uint256", explainer, len(fromBytes))
+		}
+		var parsed uint256.Int
+		parsed.SetBytes(fromBytes)
+		*into = any(parsed).(T)
 	case uint64:
 		// The encoding of uint64 can use less than 8 bytes. For this
 		// reason we go through a big integer.
 		var parsedBigInt big.Int
 		parsedBigInt.SetBytes(fromBytes)
 		*into = any(parsedBigInt.Uint64()).(T)
+	case uint8:
+		if len(fromBytes) > 1 {
+			return fmt.Errorf("could not decode %v: %v bytes for a uint8", explainer, len(fromBytes))
+		}
+		var v uint8
+		if len(fromBytes) == 1 {
+			v = fromBytes[0]
+		}
+		*into = any(v).(T)
 	case []byte:
 		*into = any(fromBytes).(T)
 	default:
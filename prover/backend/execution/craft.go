@@ -29,11 +29,12 @@ import (
 //go:embed constraints-versions.txt
 var constraintsVersionsStr string
 
-// Craft prover's functional inputs
+// Craft prover's functional inputs. The returned error wraps
+// [ErrInvalidRequest] or [ErrStateManager].
 func CraftProverOutput(
 	cfg *config.Config,
 	req *Request,
-) Response {
+) (Response, error) {
 
 	// Split the embedded file contents into a string slice
 	constraintsVersions := strings.Split(strings.TrimSpace(constraintsVersionsStr), "\n")
@@ -41,12 +42,20 @@ func CraftProverOutput(
 	// Check the arithmetization version used to generate the trace is contained in the prover request
 	// and fail fast if the constraint version is not supported
 	if err := checkArithmetizationVersion(req.ConflatedExecutionTracesFile, req.TracesEngineVersion, constraintsVersions); err != nil {
-		panic(err.Error())
+		return Response{}, fmt.Errorf("%w: %w", ErrInvalidRequest, err)
+	}
+
+	blocks, err := req.ParseBlocks()
+	if err != nil {
+		return Response{}, fmt.Errorf("%w: %w", ErrInvalidRequest, err)
+	}
+
+	if len(blocks) == 0 {
+		return Response{}, fmt.Errorf("%w: the request has no blocks", ErrInvalidRequest)
 	}
 
 	var (
 		l2BridgeAddress = cfg.Layer2.MsgSvcContract
-		blocks          = req.Blocks()
 		execDataBuf     = &bytes.Buffer{}
 		rsp             = Response{
 			BlocksData:           make([]BlockData, len(blocks)),
@@ -75,7 +84,9 @@ func CraftProverOutput(
 
 		// This encodes the block as it will be by the compressor before running
 		// the compression algorithm.
-		blob.EncodeBlockForCompression(block, execDataBuf)
+		if err := blob.EncodeBlockForCompression(block, execDataBuf); err != nil {
+			return Response{}, fmt.Errorf("%w: could not encode block %v for the compression: %w", ErrInvalidRequest, i, err)
+		}
 
 		// Encode the transactions
 		rsp.BlocksData[i].RlpEncodedTransactions = RlpTransactions(block)
@@ -104,7 +115,9 @@ func CraftProverOutput(
 	// Run the inspector and pass the parsed traces back to the caller.
 	// These traces may be used by the state-manager module depending on
 	// if the flag `PROVER_WITH_STATE_MANAGER`
-	inspectStateManagerTraces(req, &rsp)
+	if err := inspectStateManagerTraces(req, &rsp); err != nil {
+		return Response{}, fmt.Errorf("%w: %w", ErrStateManager, err)
+	}
 
 	// Value of the first blocks
 	rsp.FirstBlockNumber = utils.ToInt(blocks[0].NumberU64())
@@ -113,19 +126,19 @@ func CraftProverOutput(
 	// easily debug issues during the proving.
 	rsp.PublicInput = types.Bytes32(rsp.FuncInput().Sum(nil))
 
-	return rsp
+	return rsp, nil
 }
 
 // inspectStateManagerTraces parsed the state-manager traces from the given
 // input and inspect them to see if they are self-consistent and if they match
 // the parentStateRootHash. This behaviour can be altered by setting the field
 // `tolerate_state_root_hash_mismatch`, see its documentation. In case of
-// success, the function returns the decoded state-manager traces. Otherwise, it
-// panics.
+// success, the function populates the root hashes of the response. Otherwise,
+// it returns an error.
 func inspectStateManagerTraces(
 	req *Request,
 	resp *Response,
-) {
+) error {
 
 	// Extract the traces from the inputs
 	var (
@@ -134,6 +147,10 @@ func inspectStateManagerTraces(
 		parent      = req.ZkParentStateRootHash
 	)
 
+	if len(traces) > len(resp.BlocksData) {
+		return fmt.Errorf("got state-manager traces for %v blocks but the request has %v blocks", len(traces), len(resp.BlocksData))
+	}
+
 	for i := range traces {
 
 		if len(traces[i]) > 0 {
@@ -141,12 +158,12 @@ func inspectStateManagerTraces(
 			old, new, err := statemanager.CheckTraces(traces[i])
 			// The trace must have been validated
 			if err != nil {
-				utils.Panic("error parsing the state manager traces : %v", err)
+				return fmt.Errorf("error parsing the state manager traces of block #%v : %w", i, err)
 			}
 
 			// The "old of a block" must equal the parent
 			if old != parent {
-				utils.Panic("old does not match with parent root hash")
+				return fmt.Errorf("old root hash of block #%v does not match with parent root hash", i)
 			}
 
 			// Populate the prover's output with the recovered root hash
@@ -161,6 +178,7 @@ func inspectStateManagerTraces(
 	}
 
 	resp.ParentStateRootHash = firstParent.Hex()
+	return nil
 }
 
 func (req *Request) collectSignatures() ([]ethereum.Signature, [][32]byte) {
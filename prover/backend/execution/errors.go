@@ -0,0 +1,33 @@
+package execution
+
+import "errors"
+
+// The errors returned by [ProveContext] wrap one of the following errors,
+// depending on the reason of the failure, so that the caller can tell them
+// apart using [errors.Is]. The errors that are not listed here (e.g. a missing
+// setup or a cancelled context) are returned as is.
+var (
+	// ErrInvalidRequest is returned when the request cannot be parsed or
+	// is not supported by the prover (e.g. it has no blocks or its traces
+	// have been generated for another version of the constraints).
+	ErrInvalidRequest = errors.New("invalid execution request")
+
+	// ErrTraceOverflow is returned when the traces of the request overflow
+	// the limits of the prover. The error also wraps the
+	// [arithmetization.TraceOverflowError] of every overflowing module so
+	// [arithmetization.TraceOverflows] can be used to recover them.
+	ErrTraceOverflow = errors.New("the traces overflow the limits")
+
+	// ErrStateManager is returned when the state-manager traces of the
+	// request are inconsistent with themselves or with the blocks.
+	ErrStateManager = errors.New("inconsistent state-manager traces")
+
+	// ErrConstraints is returned when the inner-prover fails because the
+	// witness does not satisfy the constraints of the zkEVM.
+	ErrConstraints = errors.New("the constraints of the zkEVM are not satisfied")
+
+	// ErrInternal is returned when the prover panics for another reason
+	// than the ones above, i.e. because of a bug. The error holds the stack
+	// of the panic.
+	ErrInternal = errors.New("internal error of the prover")
+)
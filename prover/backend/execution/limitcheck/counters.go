@@ -0,0 +1,397 @@
+package limitcheck
+
+import (
+	"strings"
+
+	"github.com/consensys/go-corset/pkg/trace"
+	"github.com/consensys/linea-monorepo/prover/config"
+	"github.com/consensys/linea-monorepo/prover/protocol/ifaces"
+)
+
+const (
+	// modexpNumRowsPerInstance is the number of rows of a Modexp call in the
+	// blake2fmodexpdata module: 32 limbs for the base, the exponent, the
+	// modulus and the result.
+	modexpNumRowsPerInstance = 32 * 4
+	// modexpMaxNb4096BitsCalls is the number of 4096 bits Modexp calls that
+	// the zkEVM supports in both the normal and the large provers.
+	modexpMaxNb4096BitsCalls = 1
+
+	// nbEcG1Limbs and nbEcG2Limbs are the number of limbs of the points of
+	// BN254 in the ecdata module.
+	nbEcG1Limbs = 4
+	nbEcG2Limbs = 8
+	// nbBlsG1Limbs, nbBlsG2Limbs and nbBlsScalarLimbs are the number of limbs
+	// of the points and of the scalars of BLS12-381 in the blsdata module.
+	nbBlsG1Limbs     = 8
+	nbBlsG2Limbs     = 16
+	nbBlsScalarLimbs = 2
+
+	// shaBlockSize is the size in bytes of the blocks of SHA2 and RIPEMD-160.
+	// Both hash functions pad the input with at least 9 bytes.
+	shaBlockSize   = 64
+	shaMinPadBytes = 9
+)
+
+// precompileCounter is a counter of precompile calls derived from the columns
+// of the traces. The counts follow the way the corresponding zkEVM modules
+// count the calls when checking them against their limits.
+type precompileCounter struct {
+	// name is the name of the field of [config.TracesLimits] holding the limit
+	name string
+	// columns are the columns of the traces passed to count, in that order
+	columns []ifaces.ColID
+	count   func(columns []trace.Column) int
+	limit   func(*config.TracesLimits) int
+}
+
+// module returns the name of the module holding the columns of the counter.
+func (pc *precompileCounter) module() string {
+	module, _, _ := strings.Cut(string(pc.columns[0]), ".")
+	return module
+}
+
+// precompileCounters lists the counters checked against the traces.
+var precompileCounters = []precompileCounter{
+	effectiveCalls("PrecompileEcrecoverEffectiveCalls", "ecdata", "ECRECOVER", "ECRECOVER",
+		func(tl *config.TracesLimits) int { return tl.PrecompileEcrecoverEffectiveCalls }),
+	effectiveCalls("PrecompileEcaddEffectiveCalls", "ecdata", "ECADD", "ECADD",
+		func(tl *config.TracesLimits) int { return tl.PrecompileEcaddEffectiveCalls }),
+	effectiveCalls("PrecompileEcmulEffectiveCalls", "ecdata", "ECMUL", "ECMUL",
+		func(tl *config.TracesLimits) int { return tl.PrecompileEcmulEffectiveCalls }),
+	effectiveCalls("PrecompileEcpairingEffectiveCalls", "ecdata", "ECPAIRING", "ECPAIRING",
+		func(tl *config.TracesLimits) int { return tl.PrecompileEcpairingEffectiveCalls }),
+	millerLoops("PrecompileEcpairingMillerLoops", "ecdata", "ECPAIRING", nbEcG1Limbs+nbEcG2Limbs,
+		func(tl *config.TracesLimits) int { return tl.PrecompileEcpairingMillerLoops }),
+	membershipCalls("PrecompileEcpairingG2MembershipCalls", "ecdata", "G2_MEMBERSHIP", nbEcG2Limbs,
+		func(tl *config.TracesLimits) int { return tl.PrecompileEcpairingG2MembershipCalls }),
+	effectiveCalls("PrecompileP256VerifyEffectiveCalls", "ecdata", "P256_VERIFY", "P256_VERIFY",
+		func(tl *config.TracesLimits) int { return tl.PrecompileP256VerifyEffectiveCalls }),
+	effectiveCalls("PrecompilePointEvaluationEffectiveCalls", "ecdata", "POINT_EVALUATION", "POINT_EVALUATION",
+		func(tl *config.TracesLimits) int { return tl.PrecompilePointEvaluationEffectiveCalls }),
+	effectiveCalls("PrecompilePointEvalFailureEffectiveCalls", "ecdata", "POINT_EVALUATION_FAILURE", "POINT_EVALUATION",
+		func(tl *config.TracesLimits) int { return tl.PrecompilePointEvalFailureEffectiveCalls }),
+	{
+		name:    "PrecompileModexpEffectiveCalls",
+		columns: modexpColumns,
+		count: func(columns []trace.Column) int {
+			small, _ := countModexpCalls(columns)
+			return small
+		},
+		limit: func(tl *config.TracesLimits) int { return tl.PrecompileModexpEffectiveCalls },
+	},
+	{
+		// The limit on the 4096 bits calls is not configurable.
+		name:    "PrecompileModexp4096BitsEffectiveCalls",
+		columns: modexpColumns,
+		count: func(columns []trace.Column) int {
+			_, large := countModexpCalls(columns)
+			return large
+		},
+		limit: func(*config.TracesLimits) int { return modexpMaxNb4096BitsCalls },
+	},
+	{
+		name:    "PrecompileBlakeEffectiveCalls",
+		columns: blakeColumns,
+		count: func(columns []trace.Column) int {
+			calls, _ := countBlakeCalls(columns)
+			return calls
+		},
+		limit: func(tl *config.TracesLimits) int { return tl.PrecompileBlakeEffectiveCalls },
+	},
+	{
+		name:    "PrecompileBlakeRounds",
+		columns: blakeColumns,
+		count: func(columns []trace.Column) int {
+			_, rounds := countBlakeCalls(columns)
+			return rounds
+		},
+		limit: func(tl *config.TracesLimits) int { return tl.PrecompileBlakeRounds },
+	},
+	hashBlocks("PrecompileSha2Blocks", "SHA2",
+		func(tl *config.TracesLimits) int { return tl.PrecompileSha2Blocks }),
+	hashBlocks("PrecompileRipemdBlocks", "RIPEMD",
+		func(tl *config.TracesLimits) int { return tl.PrecompileRipemdBlocks }),
+	effectiveCalls("PrecompileBlsG1AddEffectiveCalls", "blsdata", "G1_ADD", "G1_ADD",
+		func(tl *config.TracesLimits) int { return tl.PrecompileBlsG1AddEffectiveCalls }),
+	effectiveCalls("PrecompileBlsG2AddEffectiveCalls", "blsdata", "G2_ADD", "G2_ADD",
+		func(tl *config.TracesLimits) int { return tl.PrecompileBlsG2AddEffectiveCalls }),
+	scalarMuls("PrecompileBlsG1MsmScalarMuls", "G1_MSM", nbBlsG1Limbs+nbBlsScalarLimbs,
+		func(tl *config.TracesLimits) int { return tl.PrecompileBlsG1MsmScalarMuls }),
+	scalarMuls("PrecompileBlsG2MsmScalarMuls", "G2_MSM", nbBlsG2Limbs+nbBlsScalarLimbs,
+		func(tl *config.TracesLimits) int { return tl.PrecompileBlsG2MsmScalarMuls }),
+	millerLoops("PrecompileBlsPairingMillerLoops", "blsdata", "PAIRING_CHECK", nbBlsG1Limbs+nbBlsG2Limbs,
+		func(tl *config.TracesLimits) int { return tl.PrecompileBlsPairingMillerLoops }),
+	effectiveCalls("PrecompileBlsPairingFinalExponentiations", "blsdata", "PAIRING_CHECK", "PAIRING_CHECK",
+		func(tl *config.TracesLimits) int { return tl.PrecompileBlsPairingFinalExponentiations }),
+	effectiveCalls("PrecompileBlsMapFpToG1EffectiveCalls", "blsdata", "MAP_FP_TO_G1", "MAP_FP_TO_G1",
+		func(tl *config.TracesLimits) int { return tl.PrecompileBlsMapFpToG1EffectiveCalls }),
+	effectiveCalls("PrecompileBlsMapFp2ToG2EffectiveCalls", "blsdata", "MAP_FP2_TO_G2", "MAP_FP2_TO_G2",
+		func(tl *config.TracesLimits) int { return tl.PrecompileBlsMapFp2ToG2EffectiveCalls }),
+	membershipCalls("PrecompileBlsC1MembershipCalls", "blsdata", "C1_MEMBERSHIP", nbBlsG1Limbs,
+		func(tl *config.TracesLimits) int { return tl.PrecompileBlsC1MembershipCalls }),
+	membershipCalls("PrecompileBlsG1MembershipCalls", "blsdata", "G1_MEMBERSHIP", nbBlsG1Limbs,
+		func(tl *config.TracesLimits) int { return tl.PrecompileBlsG1MembershipCalls }),
+	membershipCalls("PrecompileBlsC2MembershipCalls", "blsdata", "C2_MEMBERSHIP", nbBlsG2Limbs,
+		func(tl *config.TracesLimits) int { return tl.PrecompileBlsC2MembershipCalls }),
+	membershipCalls("PrecompileBlsG2MembershipCalls", "blsdata", "G2_MEMBERSHIP", nbBlsG2Limbs,
+		func(tl *config.TracesLimits) int { return tl.PrecompileBlsG2MembershipCalls }),
+}
+
+var (
+	modexpColumns = []ifaces.ColID{
+		"blake2fmodexpdata.IS_MODEXP_BASE",
+		"blake2fmodexpdata.IS_MODEXP_EXPONENT",
+		"blake2fmodexpdata.IS_MODEXP_MODULUS",
+		"blake2fmodexpdata.IS_MODEXP_RESULT",
+		"blake2fmodexpdata.LIMB",
+	}
+	blakeColumns = []ifaces.ColID{
+		"blake2fmodexpdata.IS_BLAKE_PARAMS",
+		"blake2fmodexpdata.LIMB",
+	}
+)
+
+// callColumns returns the circuit selector of csOp, the data selector of
+// dataOp and the index column of the module.
+func callColumns(module, csOp, dataOp string) []ifaces.ColID {
+	return []ifaces.ColID{
+		ifaces.ColID(module + ".CIRCUIT_SELECTOR_" + csOp),
+		ifaces.ColID(module + ".IS_" + dataOp + "_DATA"),
+		ifaces.ColID(module + ".INDEX"),
+	}
+}
+
+// effectiveCalls returns a counter of the calls to dataOp for which the
+// circuit selector of csOp is set. A call is counted on its first data row.
+func effectiveCalls(name, module, csOp, dataOp string, limit func(*config.TracesLimits) int) precompileCounter {
+	return precompileCounter{
+		name:    name,
+		columns: callColumns(module, csOp, dataOp),
+		count:   countCalls,
+		limit:   limit,
+	}
+}
+
+// millerLoops returns a counter of the Miller loops of the pairing checks op.
+// The last pair of every call is handled by the final exponentiation circuit,
+// so a call with n pairs takes n-1 Miller loops. nbPairLimbs is the number of
+// rows of a pair.
+func millerLoops(name, module, op string, nbPairLimbs int, limit func(*config.TracesLimits) int) precompileCounter {
+	return precompileCounter{
+		name:    name,
+		columns: callColumns(module, op, op),
+		count: func(columns []trace.Column) int {
+			return countDataRows(columns)/nbPairLimbs - countCalls(columns)
+		},
+		limit: limit,
+	}
+}
+
+// scalarMuls returns a counter of the scalar multiplications of the BLS MSM
+// op. nbInputLimbs is the number of rows of a point and its scalar.
+func scalarMuls(name, op string, nbInputLimbs int, limit func(*config.TracesLimits) int) precompileCounter {
+	return precompileCounter{
+		name:    name,
+		columns: callColumns("blsdata", op, op),
+		count: func(columns []trace.Column) int {
+			return countDataRows(columns) / nbInputLimbs
+		},
+		limit: limit,
+	}
+}
+
+// membershipCalls returns a counter of the points whose rows are selected by
+// the membership circuit selector of op. nbPointLimbs is the number of rows of
+// a point.
+func membershipCalls(name, module, op string, nbPointLimbs int, limit func(*config.TracesLimits) int) precompileCounter {
+	return precompileCounter{
+		name:    name,
+		columns: []ifaces.ColID{ifaces.ColID(module + ".CIRCUIT_SELECTOR_" + op)},
+		count: func(columns []trace.Column) int {
+			return countOnes(columns[0]) / nbPointLimbs
+		},
+		limit: limit,
+	}
+}
+
+// hashBlocks returns a counter of the blocks of the hashes op of the
+// shakiradata module, once padded.
+func hashBlocks(name, op string, limit func(*config.TracesLimits) int) precompileCounter {
+	return precompileCounter{
+		name: name,
+		columns: []ifaces.ColID{
+			ifaces.ColID("shakiradata.IS_" + op + "_DATA"),
+			"shakiradata.INDEX",
+			"shakiradata.nBYTES",
+		},
+		count: countHashBlocks,
+		limit: limit,
+	}
+}
+
+// countCalls counts the first data rows of the calls for which the circuit
+// selector is set. The columns are those of [callColumns].
+func countCalls(columns []trace.Column) int {
+
+	var (
+		cs, isData, index = columns[0], columns[1], columns[2]
+		count             = 0
+	)
+
+	for i := 0; i < int(cs.Data().Len()); i++ {
+		var (
+			csI     = cs.Get(i)
+			isDataI = isData.Get(i)
+			indexI  = index.Get(i)
+		)
+		if csI.IsOne() && isDataI.IsOne() && indexI.IsZero() {
+			count++
+		}
+	}
+
+	return count
+}
+
+// countDataRows counts the data rows for which the circuit selector is set.
+// The columns are those of [callColumns].
+func countDataRows(columns []trace.Column) int {
+
+	var (
+		cs, isData = columns[0], columns[1]
+		count      = 0
+	)
+
+	for i := 0; i < int(cs.Data().Len()); i++ {
+		var (
+			csI     = cs.Get(i)
+			isDataI = isData.Get(i)
+		)
+		if csI.IsOne() && isDataI.IsOne() {
+			count++
+		}
+	}
+
+	return count
+}
+
+// countOnes counts the rows of a binary column that are set.
+func countOnes(col trace.Column) int {
+	count := 0
+	for i := 0; i < int(col.Data().Len()); i++ {
+		if x := col.Get(i); x.IsOne() {
+			count++
+		}
+	}
+	return count
+}
+
+// countModexpCalls counts the Modexp calls on 256 bits and on 4096 bits. A
+// call is on 4096 bits if any of its operands has more than 2 non-zero limbs.
+// The columns are those of [modexpColumns].
+func countModexpCalls(columns []trace.Column) (small, large int) {
+
+	var (
+		limbs  = columns[4]
+		height = int(limbs.Data().Len())
+	)
+
+	isModexp := func(i int) bool {
+		for _, col := range columns[:4] {
+			if x := col.Get(i); x.IsOne() {
+				return true
+			}
+		}
+		return false
+	}
+
+	for pos := 0; pos < height; {
+
+		if !isModexp(pos) {
+			pos++
+			continue
+		}
+
+		isLarge := false
+		for k := 0; k < modexpNumRowsPerInstance && pos+k < height; k++ {
+			if x := limbs.Get(pos + k); k%32 < 30 && !x.IsZero() {
+				isLarge = true
+				break
+			}
+		}
+
+		if isLarge {
+			large++
+		} else {
+			small++
+		}
+
+		pos += modexpNumRowsPerInstance
+	}
+
+	return small, large
+}
+
+// countBlakeCalls counts the BLAKE2f calls and their rounds. The number of
+// rounds is the limb of the first parameter row of each call. The columns are
+// those of [blakeColumns].
+func countBlakeCalls(columns []trace.Column) (calls, rounds int) {
+
+	isParams, limbs := columns[0], columns[1]
+
+	for i := 0; i < int(isParams.Data().Len()); i++ {
+		if x := isParams.Get(i); !x.IsOne() {
+			continue
+		}
+		if i > 0 {
+			if prev := isParams.Get(i - 1); prev.IsOne() {
+				continue
+			}
+		}
+		calls++
+		limb := limbs.Get(i)
+		rounds += int(limb.Uint64())
+	}
+
+	return calls, rounds
+}
+
+// countHashBlocks counts the blocks of the padded hash inputs. A new input
+// starts on the data rows with index 0. The columns are those of [hashBlocks].
+func countHashBlocks(columns []trace.Column) int {
+
+	var (
+		isData, index, nBytes = columns[0], columns[1], columns[2]
+		blocks                = 0
+		inputLen              = -1
+	)
+
+	for i := 0; i < int(isData.Data().Len()); i++ {
+
+		if x := isData.Get(i); !x.IsOne() {
+			continue
+		}
+
+		if indexI := index.Get(i); indexI.IsZero() {
+			if inputLen >= 0 {
+				blocks += nbPaddedBlocks(inputLen)
+			}
+			inputLen = 0
+		}
+
+		n := nBytes.Get(i)
+		inputLen += int(n.Uint64())
+	}
+
+	if inputLen >= 0 {
+		blocks += nbPaddedBlocks(inputLen)
+	}
+
+	return blocks
+}
+
+// nbPaddedBlocks returns the number of blocks of an input of n bytes once
+// padded.
+func nbPaddedBlocks(n int) int {
+	return (n + shaMinPadBytes + shaBlockSize - 1) / shaBlockSize
+}
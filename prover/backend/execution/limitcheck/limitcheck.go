@@ -0,0 +1,224 @@
+// Package limitcheck implements a pre-flight check of an execution request
+// against the traces limits of the normal and of the large provers. The check
+// only reads the request and its traces and does not build the wizard, so it
+// is meant to be run before dispatching a request to a prover.
+package limitcheck
+
+import (
+	"fmt"
+	"os"
+	"path"
+
+	"github.com/consensys/go-corset/pkg/air"
+	"github.com/consensys/go-corset/pkg/trace"
+	"github.com/consensys/linea-monorepo/prover/backend/execution"
+	"github.com/consensys/linea-monorepo/prover/config"
+	"github.com/consensys/linea-monorepo/prover/protocol/ifaces"
+	"github.com/consensys/linea-monorepo/prover/utils"
+	"github.com/consensys/linea-monorepo/prover/zkevm/arithmetization"
+	"github.com/consensys/linea-monorepo/prover/zkevm/prover/statemanager/accumulator"
+	"github.com/sirupsen/logrus"
+)
+
+// Target is the prover that a request should be sent to.
+type Target string
+
+const (
+	// TargetNormal indicates that the request fits in the normal limits.
+	TargetNormal Target = "normal"
+	// TargetLarge indicates that the request only fits in the large limits.
+	TargetLarge Target = "large"
+	// TargetReject indicates that the request overflows the large limits.
+	TargetReject Target = "reject"
+)
+
+// Usage is the value of a counter for a request along with the corresponding
+// limits of the normal and of the large provers.
+type Usage struct {
+	Name       string `json:"name"`
+	Count      int    `json:"count"`
+	Limit      int    `json:"limit"`
+	LimitLarge int    `json:"limitLarge"`
+	// Unknown is set for the modules of the traces that the limits do not
+	// define. Their limits are then zero, as for the prover, so that such a
+	// module rejects the request unless its height is zero.
+	Unknown bool `json:"unknown,omitempty"`
+}
+
+// Report lists the counters of a request and the prover that it should be
+// sent to.
+type Report struct {
+	Usages []Usage `json:"usages"`
+	Target Target  `json:"target"`
+}
+
+// Overflows returns the counters exceeding the limits of the recommended
+// target: these are the counters exceeding the normal limits if the target is
+// [TargetLarge] and the ones exceeding the large limits if the target is
+// [TargetReject].
+func (r *Report) Overflows() []Usage {
+	var res []Usage
+	for _, u := range r.Usages {
+		switch {
+		case r.Target == TargetLarge && u.Count > u.Limit:
+			res = append(res, u)
+		case r.Target == TargetReject && u.Count > u.LimitLarge:
+			res = append(res, u)
+		}
+	}
+	return res
+}
+
+// Check reads the traces of the request and the state-manager traces and
+// compares them with the normal and the large traces limits of the config.
+func Check(cfg *config.Config, req *execution.Request) (*Report, error) {
+
+	schema, err := arithmetization.ReadZkevmBin()
+	if err != nil {
+		return nil, fmt.Errorf("could not read the zkevm.bin: %w", err)
+	}
+
+	return checkSchema(cfg, req, schema)
+}
+
+// checkSchema is as [Check] with the provided schema in place of the one of
+// the zkevm.bin.
+func checkSchema(cfg *config.Config, req *execution.Request, schema *air.Schema) (*Report, error) {
+
+	traceFile := path.Join(cfg.Execution.ConflatedTracesDir, req.ConflatedExecutionTracesFile)
+	traceF, err := os.Open(traceFile)
+	if err != nil {
+		return nil, fmt.Errorf("could not open the traces fpath=%q: %w", traceFile, err)
+	}
+
+	expTraces, err := arithmetization.ReadLtTraces(traceF, schema)
+	if err != nil {
+		return nil, fmt.Errorf("could not read the traces fpath=%q: %w", traceFile, err)
+	}
+
+	blocks, err := req.ParseBlocks()
+	if err != nil {
+		return nil, fmt.Errorf("could not parse the blocks of the request: %w", err)
+	}
+
+	nbTxs := 0
+
+	for i := range blocks {
+		nbTxs += len(blocks[i].Transactions())
+	}
+
+	return CheckTraces(
+		schema,
+		expTraces,
+		nbTxs,
+		accumulator.NumProofs(utils.Join(req.StateManagerTraces()...)),
+		&cfg.TracesLimits,
+		&cfg.TracesLimitsLarge,
+	)
+}
+
+// CheckTraces compares the counters of the expanded traces, the number of
+// transactions and the number of Merkle proofs of the state-manager with the
+// provided limits.
+func CheckTraces(
+	schema *air.Schema,
+	expTraces trace.Trace,
+	nbTransactions, nbMerkleProofs int,
+	limits, limitsLarge *config.TracesLimits,
+) (*Report, error) {
+
+	var (
+		report  = &Report{}
+		columns = arithmetization.ColumnsByID(schema, expTraces)
+	)
+
+	modules := map[string]bool{}
+	for it := expTraces.Modules(); it.HasNext(); {
+		module := it.Next()
+		modules[module.Name()] = true
+
+		var (
+			limit, known           = arithmetization.ModuleLimit(limits, module.Name())
+			limitLarge, knownLarge = arithmetization.ModuleLimit(limitsLarge, module.Name())
+		)
+
+		report.Usages = append(report.Usages, Usage{
+			Name:       module.Name(),
+			Count:      int(module.Height()),
+			Limit:      limit,
+			LimitLarge: limitLarge,
+			Unknown:    !known || !knownLarge,
+		})
+	}
+
+	for _, pc := range precompileCounters {
+
+		usage := Usage{
+			Name:       pc.name,
+			Limit:      pc.limit(limits),
+			LimitLarge: pc.limit(limitsLarge),
+		}
+
+		// The counters of the modules or of the columns that the schema does
+		// not define are zero, for instance the ones of the BLS precompiles
+		// or of P256VERIFY before they are supported. The zkEVM skips the
+		// corresponding modules in the same way.
+		if pcColumns, ok := counterColumns(columns, modules, pc); ok {
+			usage.Count = pc.count(pcColumns)
+		}
+
+		report.Usages = append(report.Usages, usage)
+	}
+
+	report.Usages = append(report.Usages,
+		Usage{
+			Name:       "BlockTransactions",
+			Count:      nbTransactions,
+			Limit:      limits.BlockTransactions,
+			LimitLarge: limitsLarge.BlockTransactions,
+		},
+		Usage{
+			Name:       "ShomeiMerkleProofs",
+			Count:      nbMerkleProofs,
+			Limit:      limits.ShomeiMerkleProofs,
+			LimitLarge: limitsLarge.ShomeiMerkleProofs,
+		},
+	)
+
+	report.Target = TargetNormal
+	for _, u := range report.Usages {
+		if u.Unknown && u.Count > 0 {
+			logrus.Warnf("the traces limits do not define the module %v, which the prover rejects", u.Name)
+		}
+		if u.Count > u.LimitLarge {
+			report.Target = TargetReject
+			break
+		}
+		if u.Count > u.Limit {
+			report.Target = TargetLarge
+		}
+	}
+
+	return report, nil
+}
+
+// counterColumns returns the columns of the traces read by the counter, or
+// false if the module or one of the columns is absent from the traces.
+func counterColumns(columns map[ifaces.ColID]trace.Column, modules map[string]bool, pc precompileCounter) ([]trace.Column, bool) {
+
+	if !modules[pc.module()] {
+		return nil, false
+	}
+
+	res := make([]trace.Column, len(pc.columns))
+	for i, name := range pc.columns {
+		col, found := columns[name]
+		if !found {
+			logrus.Debugf("the column %v is missing from the traces, %v is not counted", name, pc.name)
+			return nil, false
+		}
+		res[i] = col
+	}
+
+	return res, true
+}
@@ -0,0 +1,475 @@
+package limitcheck
+
+import (
+	"math/big"
+	"os"
+	"path"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/consensys/go-corset/pkg/air"
+	"github.com/consensys/go-corset/pkg/schema"
+	"github.com/consensys/go-corset/pkg/trace"
+	"github.com/consensys/go-corset/pkg/trace/lt"
+	"github.com/consensys/go-corset/pkg/util"
+	"github.com/consensys/linea-monorepo/prover/backend/execution"
+	"github.com/consensys/linea-monorepo/prover/config"
+	"github.com/consensys/linea-monorepo/prover/maths/field"
+	"github.com/consensys/linea-monorepo/prover/protocol/ifaces"
+	"github.com/consensys/linea-monorepo/prover/zkevm/arithmetization"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testTraces returns a schema and expanded traces holding the provided
+// columns, indexed by module and by name. The other columns of these modules
+// read by the precompile counters are filled with zeroes.
+func testTraces(t *testing.T, modules map[string]map[string][]uint64) (*air.Schema, trace.Trace) {
+	return testTracesWithout(t, modules)
+}
+
+// testTracesWithout is as [testTraces] but leaves out the absent columns of
+// the schema and of the traces, as the older schemas do for the columns of
+// the more recent precompiles.
+func testTracesWithout(t *testing.T, modules map[string]map[string][]uint64, absent ...ifaces.ColID) (*air.Schema, trace.Trace) {
+
+	var (
+		sch = air.EmptySchema[air.Expr]()
+		raw []trace.RawColumn
+	)
+
+	for _, pc := range precompileCounters {
+		for _, colID := range pc.columns {
+			module, name, _ := strings.Cut(string(colID), ".")
+			if modules[module] == nil || slices.Contains(absent, colID) {
+				continue
+			}
+			if _, ok := modules[module][name]; ok {
+				continue
+			}
+			height := 0
+			for _, values := range modules[module] {
+				height = len(values)
+				break
+			}
+			modules[module][name] = make([]uint64, height)
+		}
+	}
+
+	for module, columns := range modules {
+
+		ctx := trace.NewContext(sch.AddModule(module), 1)
+
+		for name, values := range columns {
+			sch.AddColumn(ctx, name, schema.NewUintType(64))
+			data := util.NewFrArray(uint(len(values)), 64)
+			for i, v := range values {
+				data.Set(uint(i), field.NewElement(v))
+			}
+			raw = append(raw, trace.RawColumn{Module: module, Name: name, Data: data})
+		}
+	}
+
+	expTraces, errs := schema.NewTraceBuilder(sch).Build(raw)
+	require.Empty(t, errs)
+
+	return sch, expTraces
+}
+
+func TestCheckTraces(t *testing.T) {
+
+	// The traces contain two ECRECOVER calls and an ECADD call. The ECADD
+	// call spans two data rows with index 0 and 1.
+	sch, expTraces := testTraces(t, map[string]map[string][]uint64{
+		"ecdata": {
+			"INDEX":                      {0, 1, 0, 0, 1, 0},
+			"CIRCUIT_SELECTOR_ECRECOVER": {1, 1, 1, 0, 0, 0},
+			"IS_ECRECOVER_DATA":          {1, 0, 1, 0, 0, 0},
+			"CIRCUIT_SELECTOR_ECADD":     {0, 0, 0, 1, 1, 0},
+			"IS_ECADD_DATA":              {0, 0, 0, 1, 1, 0},
+		},
+	})
+
+	var (
+		limits      = &config.TracesLimits{Ecdata: 16, PrecompileEcrecoverEffectiveCalls: 1, PrecompileEcaddEffectiveCalls: 1, BlockTransactions: 10, ShomeiMerkleProofs: 10}
+		limitsLarge = &config.TracesLimits{Ecdata: 32, PrecompileEcrecoverEffectiveCalls: 2, PrecompileEcaddEffectiveCalls: 2, BlockTransactions: 20, ShomeiMerkleProofs: 20}
+	)
+
+	testCases := []struct {
+		Name                   string
+		NbTxs, NbMerkleProofs  int
+		ExpectedTarget         Target
+		ExpectedOverflowsNames []string
+	}{
+		{
+			Name:                   "ecrecover-overflows-normal",
+			NbTxs:                  5,
+			NbMerkleProofs:         4,
+			ExpectedTarget:         TargetLarge,
+			ExpectedOverflowsNames: []string{"PrecompileEcrecoverEffectiveCalls"},
+		},
+		{
+			Name:                   "merkle-proofs-overflow-large",
+			NbTxs:                  5,
+			NbMerkleProofs:         24,
+			ExpectedTarget:         TargetReject,
+			ExpectedOverflowsNames: []string{"ShomeiMerkleProofs"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+
+			report, err := CheckTraces(sch, expTraces, tc.NbTxs, tc.NbMerkleProofs, limits, limitsLarge)
+			require.NoError(t, err)
+
+			counts := map[string]int{}
+			for _, u := range report.Usages {
+				counts[u.Name] = u.Count
+			}
+
+			assert.Equal(t, 2, counts["PrecompileEcrecoverEffectiveCalls"])
+			assert.Equal(t, 1, counts["PrecompileEcaddEffectiveCalls"])
+			assert.Equal(t, 0, counts["PrecompileEcmulEffectiveCalls"])
+			assert.Equal(t, tc.NbTxs, counts["BlockTransactions"])
+			assert.Equal(t, tc.NbMerkleProofs, counts["ShomeiMerkleProofs"])
+			assert.Contains(t, counts, "ecdata")
+
+			assert.Equal(t, tc.ExpectedTarget, report.Target)
+
+			overflows := []string{}
+			for _, u := range report.Overflows() {
+				overflows = append(overflows, u.Name)
+			}
+			assert.Equal(t, tc.ExpectedOverflowsNames, overflows)
+		})
+	}
+}
+
+// testColumn returns a column of the given height whose rows in [from, to)
+// are set to one.
+func testColumn(height, from, to int) []uint64 {
+	res := make([]uint64, height)
+	for i := from; i < to; i++ {
+		res[i] = 1
+	}
+	return res
+}
+
+// testIndex returns an index column restarting from zero at every start of
+// calls and spanning the given number of rows.
+func testIndex(height int, starts, lengths []int) []uint64 {
+	res := make([]uint64, height)
+	for k := range starts {
+		for i := 0; i < lengths[k]; i++ {
+			res[starts[k]+i] = uint64(i)
+		}
+	}
+	return res
+}
+
+func TestPrecompileCounters(t *testing.T) {
+
+	var (
+		ecdata = map[string][]uint64{
+			// a point evaluation failure spanning 2 rows, a pairing check of
+			// 2 pairs spanning 24 rows and 1 G2 membership check.
+			"INDEX": testIndex(32, []int{0, 2}, []int{2, 24}),
+			"CIRCUIT_SELECTOR_POINT_EVALUATION_FAILURE": testColumn(32, 0, 2),
+			"IS_POINT_EVALUATION_DATA":                  testColumn(32, 0, 2),
+			"CIRCUIT_SELECTOR_ECPAIRING":                testColumn(32, 2, 26),
+			"IS_ECPAIRING_DATA":                         testColumn(32, 2, 26),
+			"CIRCUIT_SELECTOR_G2_MEMBERSHIP":            testColumn(32, 10, 18),
+		}
+		blake2fmodexpdata = map[string][]uint64{
+			// a 256 bits modexp, a 4096 bits modexp and two blake2f calls
+			// of 12 and 1 rounds.
+			"IS_MODEXP_BASE":     testColumn(264, 0, 32),
+			"IS_MODEXP_EXPONENT": testColumn(264, 32, 64),
+			"IS_MODEXP_MODULUS":  testColumn(264, 64, 96),
+			"IS_MODEXP_RESULT":   testColumn(264, 96, 128),
+			"IS_BLAKE_PARAMS":    append(testColumn(260, 256, 258), 0, 1, 1, 0),
+			"LIMB":               make([]uint64, 264),
+		}
+		shakiradata = map[string][]uint64{
+			// two SHA2 inputs of 32 and 64 bytes and a RIPEMD input of 56
+			// bytes.
+			"INDEX":          {0, 1, 0, 1, 2, 3, 0, 1, 2, 3},
+			"nBYTES":         {16, 16, 16, 16, 16, 16, 16, 16, 16, 8},
+			"IS_SHA2_DATA":   testColumn(10, 0, 6),
+			"IS_RIPEMD_DATA": testColumn(10, 6, 10),
+		}
+		blsdata = map[string][]uint64{
+			// a G1 MSM of 2 scalar multiplications spanning 20 rows, a
+			// pairing check of 3 pairs spanning 72 rows and 2 C2 membership
+			// checks.
+			"INDEX":                          testIndex(96, []int{0, 20}, []int{20, 72}),
+			"CIRCUIT_SELECTOR_G1_MSM":        testColumn(96, 0, 20),
+			"IS_G1_MSM_DATA":                 testColumn(96, 0, 20),
+			"CIRCUIT_SELECTOR_PAIRING_CHECK": testColumn(96, 20, 92),
+			"IS_PAIRING_CHECK_DATA":          testColumn(96, 20, 92),
+			"CIRCUIT_SELECTOR_C2_MEMBERSHIP": testColumn(96, 30, 62),
+		}
+	)
+
+	// The 4096 bits modexp has a non-zero limb in the high part of its base
+	// and the 256 bits one only in the low part.
+	blake2fmodexpdata["IS_MODEXP_BASE"] = append(blake2fmodexpdata["IS_MODEXP_BASE"][:128], testColumn(136, 0, 32)...)
+	blake2fmodexpdata["IS_MODEXP_EXPONENT"] = append(blake2fmodexpdata["IS_MODEXP_EXPONENT"][:128], testColumn(136, 32, 64)...)
+	blake2fmodexpdata["IS_MODEXP_MODULUS"] = append(blake2fmodexpdata["IS_MODEXP_MODULUS"][:128], testColumn(136, 64, 96)...)
+	blake2fmodexpdata["IS_MODEXP_RESULT"] = append(blake2fmodexpdata["IS_MODEXP_RESULT"][:128], testColumn(136, 96, 128)...)
+	blake2fmodexpdata["LIMB"][30] = 7
+	blake2fmodexpdata["LIMB"][128] = 7
+	blake2fmodexpdata["LIMB"][256] = 12
+	blake2fmodexpdata["LIMB"][261] = 1
+
+	sch, expTraces := testTraces(t, map[string]map[string][]uint64{
+		"ecdata":            ecdata,
+		"blake2fmodexpdata": blake2fmodexpdata,
+		"shakiradata":       shakiradata,
+		"blsdata":           blsdata,
+	})
+
+	limits := &config.TracesLimits{}
+	report, err := CheckTraces(sch, expTraces, 0, 0, limits, limits)
+	require.NoError(t, err)
+
+	counts := map[string]int{}
+	for _, u := range report.Usages {
+		counts[u.Name] = u.Count
+	}
+
+	expected := map[string]int{
+		"PrecompilePointEvaluationEffectiveCalls":  0,
+		"PrecompilePointEvalFailureEffectiveCalls": 1,
+		"PrecompileEcpairingEffectiveCalls":        1,
+		"PrecompileEcpairingMillerLoops":           1,
+		"PrecompileEcpairingG2MembershipCalls":     1,
+		"PrecompileModexpEffectiveCalls":           1,
+		"PrecompileModexp4096BitsEffectiveCalls":   1,
+		"PrecompileBlakeEffectiveCalls":            2,
+		"PrecompileBlakeRounds":                    13,
+		"PrecompileSha2Blocks":                     3,
+		"PrecompileRipemdBlocks":                   2,
+		"PrecompileBlsG1MsmScalarMuls":             2,
+		"PrecompileBlsPairingMillerLoops":          2,
+		"PrecompileBlsPairingFinalExponentiations": 1,
+		"PrecompileBlsC2MembershipCalls":           2,
+		"PrecompileBlsG1MembershipCalls":           0,
+	}
+
+	for name, count := range expected {
+		assert.Equalf(t, count, counts[name], "counter %v", name)
+	}
+}
+
+// The limits that are not tied to a module must all be counted, apart from
+// the ones that cannot be estimated from the traces. Otherwise, the precheck
+// of the controller would predict that the normal prover can handle traces
+// that overflow them.
+func TestCountersCoverLimits(t *testing.T) {
+
+	var (
+		// Counted from the request and the state-manager traces
+		counted = map[string]bool{"BlockTransactions": true, "ShomeiMerkleProofs": true}
+		// The keccak permutations, the L1 size and the L2 to L1 logs are
+		// only known to the prover.
+		unchecked = map[string]bool{"BlockKeccak": true, "BlockL1Size": true, "BlockL2L1Logs": true}
+	)
+
+	for _, pc := range precompileCounters {
+		counted[pc.name] = true
+	}
+
+	limitsType := reflect.TypeOf(config.TracesLimits{})
+	for i := 0; i < limitsType.NumField(); i++ {
+		field := limitsType.Field(i)
+		if len(field.Tag.Get("corset")) > 0 || unchecked[field.Name] {
+			continue
+		}
+		assert.Truef(t, counted[field.Name], "the limit %v is not counted", field.Name)
+	}
+}
+
+// A module of the traces that the limits do not define has a limit of zero,
+// as in [arithmetization.CheckTraceLimits]: the request is rejected.
+func TestCheckTracesUnknownModule(t *testing.T) {
+
+	sch, expTraces := testTraces(t, map[string]map[string][]uint64{
+		"ecdata":    {"INDEX": {0, 0}},
+		"newmodule": {"A": {1, 2, 3, 4}},
+	})
+
+	var (
+		limits      = &config.TracesLimits{Ecdata: 16, BlockTransactions: 10, ShomeiMerkleProofs: 10}
+		limitsLarge = &config.TracesLimits{Ecdata: 32, BlockTransactions: 20, ShomeiMerkleProofs: 20}
+	)
+
+	report, err := CheckTraces(sch, expTraces, 1, 1, limits, limitsLarge)
+	require.NoError(t, err)
+
+	assert.Equal(t, TargetReject, report.Target)
+	require.Len(t, report.Overflows(), 1)
+	assert.Equal(t, "newmodule", report.Overflows()[0].Name)
+	assert.True(t, report.Overflows()[0].Unknown)
+
+	require.Error(t, arithmetization.CheckTraceLimits(expTraces, limitsLarge), "the prover must reject the traces as well")
+}
+
+// The schemas up to v0.8.0-rc8 define the ecdata module without the columns
+// of the P256VERIFY and POINT_EVALUATION precompiles. Their counters are then
+// zero, as for an absent module.
+func TestCheckTracesMissingColumns(t *testing.T) {
+
+	var absent []ifaces.ColID
+	for _, pc := range precompileCounters {
+		for _, colID := range pc.columns {
+			if strings.Contains(string(colID), "P256_VERIFY") || strings.Contains(string(colID), "POINT_EVALUATION") {
+				absent = append(absent, colID)
+			}
+		}
+	}
+	require.NotEmpty(t, absent)
+
+	sch, expTraces := testTracesWithout(t, map[string]map[string][]uint64{
+		"ecdata": {
+			"INDEX":                      {0, 1},
+			"CIRCUIT_SELECTOR_ECRECOVER": {1, 1},
+			"IS_ECRECOVER_DATA":          {1, 0},
+		},
+	}, absent...)
+
+	var (
+		limits      = &config.TracesLimits{Ecdata: 16, PrecompileEcrecoverEffectiveCalls: 4, BlockTransactions: 10, ShomeiMerkleProofs: 10}
+		limitsLarge = &config.TracesLimits{Ecdata: 32, PrecompileEcrecoverEffectiveCalls: 8, BlockTransactions: 20, ShomeiMerkleProofs: 20}
+	)
+
+	report, err := CheckTraces(sch, expTraces, 1, 1, limits, limitsLarge)
+	require.NoError(t, err)
+
+	counts := map[string]int{}
+	for _, u := range report.Usages {
+		counts[u.Name] = u.Count
+	}
+
+	assert.Equal(t, 1, counts["PrecompileEcrecoverEffectiveCalls"])
+	assert.Contains(t, counts, "PrecompileP256VerifyEffectiveCalls")
+	assert.Zero(t, counts["PrecompileP256VerifyEffectiveCalls"])
+	assert.Zero(t, counts["PrecompilePointEvaluationEffectiveCalls"])
+	assert.Equal(t, TargetNormal, report.Target)
+}
+
+// testRequest writes the `.lt` traces of the schema, where each input column
+// holds `height` zeroes, and returns a request of an empty block pointing to
+// them along with a config reading them.
+func testRequest(t *testing.T, sch *air.Schema, height uint) (*config.Config, *execution.Request) {
+
+	var (
+		cfg     = &config.Config{}
+		modules []string
+		raw     []trace.RawColumn
+	)
+
+	for it := sch.Modules(); it.HasNext(); {
+		module := it.Next()
+		modules = append(modules, module.Name())
+	}
+
+	for it := sch.InputColumns(); it.HasNext(); {
+		col := it.Next()
+		raw = append(raw, trace.RawColumn{
+			Module: modules[col.Context().Module()],
+			Name:   col.Name(),
+			Data:   util.NewFrArray(height, 64),
+		})
+	}
+
+	encoded, err := lt.ToBytes(raw)
+	require.NoError(t, err)
+
+	cfg.Execution.ConflatedTracesDir = t.TempDir()
+	require.NoError(t, os.WriteFile(path.Join(cfg.Execution.ConflatedTracesDir, "1-1.conflated.lt"), encoded, 0600))
+
+	b, err := rlp.EncodeToBytes(ethtypes.NewBlockWithHeader(&ethtypes.Header{Number: big.NewInt(1), Difficulty: big.NewInt(0)}))
+	require.NoError(t, err)
+
+	req := &execution.Request{ConflatedExecutionTracesFile: "1-1.conflated.lt"}
+	req.BlocksData = append(req.BlocksData, struct {
+		Rlp        string         `json:"rlp"`
+		BridgeLogs []ethtypes.Log `json:"bridgeLogs"`
+	}{Rlp: hexutil.Encode(b)})
+
+	return cfg, req
+}
+
+// testLimits returns limits set to v for all the counters.
+func testLimits(v int) config.TracesLimits {
+	var limits config.TracesLimits
+	rv := reflect.ValueOf(&limits).Elem()
+	for i := 0; i < rv.NumField(); i++ {
+		rv.Field(i).SetInt(int64(v))
+	}
+	return limits
+}
+
+// The check reads the traces file of the request as the prover does.
+func TestCheck(t *testing.T) {
+
+	sch, _ := testTraces(t, map[string]map[string][]uint64{
+		"ecdata": {"INDEX": {0}},
+	})
+
+	cfg, req := testRequest(t, sch, 4)
+	cfg.TracesLimits = testLimits(2)
+	cfg.TracesLimitsLarge = testLimits(8)
+
+	report, err := checkSchema(cfg, req, sch)
+	require.NoError(t, err)
+
+	require.Len(t, report.Overflows(), 1)
+	assert.Equal(t, "ecdata", report.Overflows()[0].Name)
+	assert.Equal(t, TargetLarge, report.Target)
+
+	req.ConflatedExecutionTracesFile = "missing.lt"
+	_, err = checkSchema(cfg, req, sch)
+	assert.Error(t, err)
+}
+
+// The check runs on the traces of the bundled zkevm.bin, so that all its
+// modules and the columns read by the counters are covered.
+func TestCheckZkevmBin(t *testing.T) {
+
+	if st, err := os.Stat("../../../zkevm/arithmetization/zkevm.bin"); err != nil || st.Size() == 0 {
+		t.Skip("the zkevm.bin is not available")
+	}
+
+	sch, err := arithmetization.ReadZkevmBin()
+	require.NoError(t, err)
+
+	cfg, req := testRequest(t, sch, 4)
+	cfg.TracesLimits = testLimits(1 << 20)
+	cfg.TracesLimitsLarge = testLimits(1 << 20)
+
+	report, err := Check(cfg, req)
+	require.NoError(t, err)
+
+	names := map[string]bool{}
+	for _, u := range report.Usages {
+		names[u.Name] = true
+		assert.Falsef(t, u.Unknown, "the module %v has no limit", u.Name)
+	}
+
+	for it := sch.Modules(); it.HasNext(); {
+		module := it.Next()
+		if len(module.Name()) > 0 {
+			assert.Truef(t, names[module.Name()], "the module %v is not checked", module.Name())
+		}
+	}
+
+	assert.Equal(t, TargetNormal, report.Target)
+}
@@ -1,15 +1,22 @@
 package execution
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+
 	"github.com/consensys/gnark-crypto/ecc"
 	"github.com/consensys/linea-monorepo/prover/circuits"
 	"github.com/consensys/linea-monorepo/prover/circuits/dummy"
 	"github.com/consensys/linea-monorepo/prover/circuits/execution"
 	"github.com/consensys/linea-monorepo/prover/config"
+	wizarddummy "github.com/consensys/linea-monorepo/prover/protocol/compiler/dummy"
+	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
 	public_input "github.com/consensys/linea-monorepo/prover/public-input"
-	"github.com/consensys/linea-monorepo/prover/utils"
 	"github.com/consensys/linea-monorepo/prover/utils/profiling"
 	"github.com/consensys/linea-monorepo/prover/zkevm"
+	"github.com/consensys/linea-monorepo/prover/zkevm/arithmetization"
 	"github.com/sirupsen/logrus"
 )
 
@@ -18,13 +25,42 @@ type Witness struct {
 	ZkEVM   *zkevm.Witness
 }
 
+// ProveOptions are the options of [ProveContext]
+type ProveOptions struct {
+	// Large indicates that the prover uses the large traces limits
+	Large bool
+	// Progress, if set, is called every time the inner-prover completes a
+	// step, see [wizard.WithProgress].
+	Progress func(wizard.ProverProgress)
+}
+
+// Prove runs the execution prover on the request, see [ProveContext]. The
+// large parameter indicates whether the large traces limits are used.
 func Prove(cfg *config.Config, req *Request, large bool) (*Response, error) {
+	return ProveContext(context.Background(), cfg, req, ProveOptions{Large: large})
+}
+
+// ProveContext runs the execution prover on the request and returns the
+// response. It never panics: the failures are returned as errors wrapping
+// [ErrInvalidRequest], [ErrTraceOverflow], [ErrStateManager],
+// [ErrConstraints] or [ErrInternal] depending on their cause. The context is checked between
+// the steps of the inner-prover and before the outer-proof is generated; if
+// it is done, the function returns ctx.Err().
+func ProveContext(ctx context.Context, cfg *config.Config, req *Request, opts ProveOptions) (resp *Response, err error) {
 	traces := &cfg.TracesLimits
-	if large {
+	if opts.Large {
 		traces = &cfg.TracesLimitsLarge
 	}
 
-	var resp Response
+	// The prover and the libraries it relies on signal most of their
+	// failures by panicking. They are turned into errors so that the
+	// process embedding the prover does not crash.
+	defer func() {
+		if r := recover(); r != nil {
+			logrus.Errorf("the execution prover panicked: %v\n%s", r, debug.Stack())
+			resp, err = nil, fmt.Errorf("%w: the execution prover panicked: %v", ErrInternal, r)
+		}
+	}()
 
 	// TODO @gbotrel wrap profiling in the caller; so that we can properly return errors
 	profiling.ProfileTrace("execution",
@@ -32,21 +68,24 @@ func Prove(cfg *config.Config, req *Request, large bool) (*Response, error) {
 		cfg.Debug.Tracing,
 		func() {
 			// Compute the prover's output.
-			// WARN: CraftProverOutput calls functions that can panic.
-			out := CraftProverOutput(cfg, req)
+			out, errCraft := CraftProverOutput(cfg, req)
+			if errCraft != nil {
+				err = errCraft
+				return
+			}
 
 			if cfg.Execution.ProverMode != config.ProverModeProofless {
 				// Development, Partial, Full or Full-large Mode
-
-				// WARN:
-				// - MustProveAndPass can panic
-				// - Execution prover calls function that can panic
-				// - NewFromString can panic
-				out.Proof, out.VerifyingKeyShaSum = mustProveAndPass(
+				out.Proof, out.VerifyingKeyShaSum, err = proveAndPass(
+					ctx,
+					opts,
 					cfg,
 					traces,
 					NewWitness(cfg, req, &out),
 				)
+				if err != nil {
+					return
+				}
 
 				out.Version = cfg.Version
 				out.ProverMode = cfg.Execution.ProverMode
@@ -59,21 +98,36 @@ func Prove(cfg *config.Config, req *Request, large bool) (*Response, error) {
 				out.ProverMode = config.ProverModeProofless
 			}
 
-			resp = out
+			resp = &out
 		})
 
-	return &resp, nil
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
 }
 
-// mustProveAndPass the prover (in the void). Does not takes a
+// proveAndPass the prover (in the void). Does not takes a
 // prover-step function performing the assignment but a function
 // returning such a function. This is important to avoid side-effects
 // when calling it twice.
-func mustProveAndPass(
+//
+// The function returns an error wrapping [ErrConstraints] if the prover does
+// not pass and an error wrapping [ErrTraceOverflow] if the traces overflow
+// the limits.
+func proveAndPass(
+	ctx context.Context,
+	opts ProveOptions,
 	cfg *config.Config,
 	traces *config.TracesLimits,
 	w *Witness,
-) (proofHexString string, vkeyShaSum string) {
+) (proofHexString string, vkeyShaSum string, err error) {
+
+	innerOpts := []wizard.ProveOption{wizard.WithContext(ctx)}
+	if opts.Progress != nil {
+		innerOpts = append(innerOpts, wizard.WithProgress(opts.Progress))
+	}
 
 	switch cfg.Execution.ProverMode {
 	case config.ProverModeDev, config.ProverModePartial:
@@ -85,23 +139,22 @@ func mustProveAndPass(
 			// proof is sanity-checked to ensure that the prover never outputs
 			// invalid proofs.
 			partial := zkevm.FullZkEVMCheckOnly(traces)
-			proof := partial.ProveInner(w.ZkEVM)
-			if err := partial.VerifyInner(proof); err != nil {
-				utils.Panic("The prover did not pass: %v", err)
+			if _, err := proveInnerAndPass(partial, w.ZkEVM, innerOpts...); err != nil {
+				return "", "", err
 			}
 		}
 
 		srsProvider, err := circuits.NewSRSStore(cfg.PathForSRS())
 		if err != nil {
-			utils.Panic(err.Error())
+			return "", "", fmt.Errorf("could not load the SRS: %w", err)
 		}
 
 		setup, err := dummy.MakeUnsafeSetup(srsProvider, circuits.MockCircuitIDExecution, ecc.BLS12_377.ScalarField())
 		if err != nil {
-			utils.Panic(err.Error())
+			return "", "", fmt.Errorf("could not make the dummy setup: %w", err)
 		}
 
-		return dummy.MakeProof(&setup, w.FuncInp.SumAsField(), circuits.MockCircuitIDExecution), setup.VerifyingKeyDigest()
+		return dummy.MakeProof(&setup, w.FuncInp.SumAsField(), circuits.MockCircuitIDExecution), setup.VerifyingKeyDigest(), nil
 
 	case config.ProverModeFull:
 		logrus.Info("Running the FULL prover")
@@ -110,35 +163,35 @@ func mustProveAndPass(
 		logrus.Info("Get Full IOP")
 		fullZkEvm := zkevm.FullZkEvm(traces)
 
-		var (
-			setup       circuits.Setup
-			errSetup    error
-			chSetupDone = make(chan struct{})
-		)
-		go func() {
-			setup, errSetup = circuits.LoadSetup(cfg, circuits.ExecutionCircuitID)
-			close(chSetupDone)
-		}()
+		chSetup := loadSetupAsync(func() (circuits.Setup, error) {
+			return circuits.LoadSetup(cfg, circuits.ExecutionCircuitID)
+		})
 
 		// Generates the inner-proof and sanity-check it so that we ensure that
 		// the prover nevers outputs invalid proofs.
-		proof := fullZkEvm.ProveInner(w.ZkEVM)
-
-		logrus.Info("Sanity-checking the inner-proof")
-		if err := fullZkEvm.VerifyInner(proof); err != nil {
-			utils.Panic("The prover did not pass: %v", err)
+		proof, err := proveInnerAndPass(fullZkEvm, w.ZkEVM, innerOpts...)
+		if err != nil {
+			// The setup is not needed anymore but it is waited for, so that
+			// it is not loaded past the call, unless the prover is asked to
+			// stop.
+			select {
+			case <-chSetup:
+			case <-ctx.Done():
+			}
+			return "", "", err
 		}
 
 		// wait for setup to be loaded
-		<-chSetupDone
-		if errSetup != nil {
-			utils.Panic("could not load setup: %v", errSetup)
+		res := <-chSetup
+		if res.err != nil {
+			return "", "", fmt.Errorf("could not load setup: %w", res.err)
 		}
+		setup := res.setup
 
 		// ensure the checksum for the traces in the setup matches the one in the config
 		setupCfgChecksum, err := setup.Manifest.GetString("cfg_checksum")
 		if err != nil {
-			utils.Panic("could not get the traces checksum from the setup manifest: %v", err)
+			return "", "", fmt.Errorf("could not get the traces checksum from the setup manifest: %w", err)
 		}
 
 		if setupCfgChecksum != traces.Checksum() {
@@ -147,11 +200,17 @@ func mustProveAndPass(
 			// more interesting to directly include that information in the setup
 			// instead of the config. That way we are guaranteed to not pass the
 			// wrong value at runtime.
-			utils.Panic("traces checksum in the setup manifest does not match the one in the config")
+			return "", "", errors.New("traces checksum in the setup manifest does not match the one in the config")
+		}
+
+		// The outer-proof cannot be interrupted so this is the last
+		// opportunity to stop.
+		if err := ctx.Err(); err != nil {
+			return "", "", err
 		}
 
 		// TODO: implements the collection of the functional inputs from the prover response
-		return execution.MakeProof(traces, setup, fullZkEvm.WizardIOP, proof, *w.FuncInp), setup.VerifyingKeyDigest()
+		return execution.MakeProof(traces, setup, fullZkEvm.WizardIOP, proof, *w.FuncInp), setup.VerifyingKeyDigest(), nil
 
 	case config.ProverModeBench:
 
@@ -161,25 +220,105 @@ func mustProveAndPass(
 
 		// Generates the inner-proof and sanity-check it so that we ensure that
 		// the prover nevers outputs invalid proofs.
-		proof := fullZkEvm.ProveInner(w.ZkEVM)
-
-		logrus.Info("Sanity-checking the inner-proof")
-		if err := fullZkEvm.VerifyInner(proof); err != nil {
-			utils.Panic("The prover did not pass: %v", err)
+		if _, err := proveInnerAndPass(fullZkEvm, w.ZkEVM, innerOpts...); err != nil {
+			return "", "", err
 		}
-		return "", ""
+		return "", "", nil
 
 	case config.ProverModeCheckOnly:
 
 		fullZkEvm := zkevm.FullZkEVMCheckOnly(traces)
-		// this will panic to alert errors, so there is no need to handle or
-		// sanity-check anything.
+		// the constraints are checked by the inner-prover itself, so there
+		// is no need to sanity-check anything.
 		logrus.Infof("Prover starting the prover")
-		_ = fullZkEvm.ProveInner(w.ZkEVM)
+		if _, err := proveInner(fullZkEvm, w.ZkEVM, innerOpts...); err != nil {
+			return "", "", err
+		}
 		logrus.Infof("Prover checks passed")
-		return "", ""
+		return "", "", nil
 
 	default:
-		panic("not implemented")
+		return "", "", fmt.Errorf("prover mode %v is not implemented", cfg.Execution.ProverMode)
+	}
+}
+
+// setupResult is the outcome of [loadSetupAsync].
+type setupResult struct {
+	setup circuits.Setup
+	err   error
+}
+
+// loadSetupAsync runs load in a goroutine and sends its result on the
+// returned channel. A panic of load is sent as an error wrapping
+// [ErrInternal]. The channel is buffered so that the goroutine terminates
+// even if the result is never received.
+func loadSetupAsync(load func() (circuits.Setup, error)) <-chan setupResult {
+
+	ch := make(chan setupResult, 1)
+
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				ch <- setupResult{err: fmt.Errorf("%w: loading the setup panicked: %v\n%s", ErrInternal, r, debug.Stack())}
+			}
+		}()
+
+		setup, err := load()
+		ch <- setupResult{setup: setup, err: err}
+	}()
+
+	return ch
+}
+
+// proveInnerAndPass runs the inner-prover of the zkEVM and sanity-checks the
+// resulting inner-proof.
+func proveInnerAndPass(z *zkevm.ZkEvm, w *zkevm.Witness, opts ...wizard.ProveOption) (wizard.Proof, error) {
+
+	proof, err := proveInner(z, w, opts...)
+	if err != nil {
+		return wizard.Proof{}, err
+	}
+
+	logrus.Info("Sanity-checking the inner-proof")
+	if err := z.VerifyInner(proof); err != nil {
+		return wizard.Proof{}, fmt.Errorf("%w: the prover did not pass: %w", ErrConstraints, err)
+	}
+
+	return proof, nil
+}
+
+// proveInner runs the inner-prover of the zkEVM. The errors caused by the
+// traces overflowing the limits are wrapped in [ErrTraceOverflow] and the ones
+// caused by an unreadable trace file in [ErrInvalidRequest]. The
+// inner-prover panics when the witness does not satisfy the constraints (see
+// the dummy compiler), the panic is returned as an error wrapping
+// [ErrConstraints]. Any other panic is returned as an error wrapping
+// [ErrInternal].
+func proveInner(z *zkevm.ZkEvm, w *zkevm.Witness, opts ...wizard.ProveOption) (proof wizard.Proof, err error) {
+
+	defer func() {
+		if r := recover(); r != nil {
+			proof, err = wizard.Proof{}, innerProverPanicError(r, debug.Stack())
+		}
+	}()
+
+	proof, err = z.ProveInnerWithOptions(w, opts...)
+	if arithmetization.IsTraceOverflow(err) {
+		return wizard.Proof{}, fmt.Errorf("%w: %w", ErrTraceOverflow, err)
+	}
+	if errors.Is(err, arithmetization.ErrInvalidTraces) {
+		return wizard.Proof{}, fmt.Errorf("%w: %w", ErrInvalidRequest, err)
+	}
+
+	return proof, err
+}
+
+// Converts a panic of the inner-prover into an error. Only the failed checks
+// of the dummy compiler mean that the constraints are not satisfied, the
+// other panics are bugs and the error holds their stack.
+func innerProverPanicError(r any, stack []byte) error {
+	if e, ok := r.(error); ok && errors.Is(e, wizarddummy.ErrFailedQueries) {
+		return fmt.Errorf("%w: %w", ErrConstraints, e)
 	}
+	return fmt.Errorf("%w: the inner-prover panicked: %v\n%s", ErrInternal, r, stack)
 }
@@ -0,0 +1,183 @@
+package execution
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/consensys/linea-monorepo/prover/backend/execution/statemanager"
+	"github.com/consensys/linea-monorepo/prover/circuits"
+	"github.com/consensys/linea-monorepo/prover/config"
+	wizarddummy "github.com/consensys/linea-monorepo/prover/protocol/compiler/dummy"
+	"github.com/consensys/linea-monorepo/prover/utils/types"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProveContextErrors(t *testing.T) {
+
+	cfg := &config.Config{}
+	cfg.Execution.ProverMode = config.ProverModeProofless
+
+	// Returns a request for two empty blocks whose traces are supported by the
+	// constraints. The state-manager traces of the first block are taken
+	// from the test vectors of the state-manager.
+	newRequest := func() *Request {
+		f, err := os.Open("statemanager/testdata/read-account.json")
+		require.NoError(t, err)
+		defer f.Close()
+
+		var shomei statemanager.ShomeiOutput
+		require.NoError(t, json.NewDecoder(f).Decode(&shomei))
+
+		version := strings.Split(strings.TrimSpace(constraintsVersionsStr), "\n")[0]
+		req := &Request{
+			ZkParentStateRootHash:        shomei.Result.ZkParentStateRootHash,
+			ConflatedExecutionTracesFile: "1-2.conflated." + version + ".lt",
+			TracesEngineVersion:          version,
+			ZkStateMerkleProof:           shomei.Result.ZkStateMerkleProof,
+		}
+
+		for i := 1; i <= 2; i++ {
+			block := ethtypes.NewBlockWithHeader(&ethtypes.Header{
+				Number:     big.NewInt(int64(i)),
+				Time:       uint64(100 + i),
+				Difficulty: big.NewInt(0),
+			})
+			b, err := rlp.EncodeToBytes(block)
+			require.NoError(t, err)
+			req.BlocksData = append(req.BlocksData, struct {
+				Rlp        string         `json:"rlp"`
+				BridgeLogs []ethtypes.Log `json:"bridgeLogs"`
+			}{Rlp: hexutil.Encode(b)})
+		}
+
+		return req
+	}
+
+	// The proofless mode only crafts the response
+	resp, err := ProveContext(context.Background(), cfg, newRequest(), ProveOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, config.ProverModeProofless, resp.ProverMode)
+	assert.Equal(t, 1, resp.FirstBlockNumber)
+	assert.Len(t, resp.BlocksData, 2)
+
+	cases := []struct {
+		name   string
+		alter  func(req *Request)
+		target error
+	}{
+		{
+			name:   "unsupported-version",
+			alter:  func(req *Request) { req.TracesEngineVersion = "v0.0.0-unknown" },
+			target: ErrInvalidRequest,
+		},
+		{
+			name:   "malformed-rlp",
+			alter:  func(req *Request) { req.BlocksData[1].Rlp = "0x01020304" },
+			target: ErrInvalidRequest,
+		},
+		{
+			// The v1 blob format, whose encoding is checksummed in the
+			// response, cannot hold the set-code transactions.
+			name: "set-code-tx",
+			alter: func(req *Request) {
+				privKey, err := crypto.GenerateKey()
+				require.NoError(t, err)
+
+				chainID := uint256.NewInt(59144)
+				auth, err := ethtypes.SignSetCode(privKey, ethtypes.SetCodeAuthorization{ChainID: *chainID, Nonce: 2})
+				require.NoError(t, err)
+
+				tx, err := ethtypes.SignNewTx(privKey, ethtypes.NewPragueSigner(chainID.ToBig()), &ethtypes.SetCodeTx{
+					ChainID:   chainID,
+					Nonce:     1,
+					GasTipCap: uint256.NewInt(1),
+					GasFeeCap: uint256.NewInt(2),
+					Gas:       21000,
+					Value:     uint256.NewInt(0),
+					AuthList:  []ethtypes.SetCodeAuthorization{auth},
+				})
+				require.NoError(t, err)
+
+				block := ethtypes.NewBlockWithHeader(&ethtypes.Header{
+					Number:     big.NewInt(2),
+					Time:       102,
+					Difficulty: big.NewInt(0),
+				}).WithBody(ethtypes.Body{Transactions: []*ethtypes.Transaction{tx}})
+				b, err := rlp.EncodeToBytes(block)
+				require.NoError(t, err)
+				req.BlocksData[1].Rlp = hexutil.Encode(b)
+			},
+			target: ErrInvalidRequest,
+		},
+		{
+			name:   "no-blocks",
+			alter:  func(req *Request) { req.BlocksData = nil },
+			target: ErrInvalidRequest,
+		},
+		{
+			name:   "wrong-parent",
+			alter:  func(req *Request) { req.ZkParentStateRootHash = types.Bytes32{1} },
+			target: ErrStateManager,
+		},
+		{
+			name: "too-many-traces",
+			alter: func(req *Request) {
+				req.ZkStateMerkleProof = append(req.ZkStateMerkleProof, req.ZkStateMerkleProof[0], req.ZkStateMerkleProof[0])
+			},
+			target: ErrStateManager,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			req := newRequest()
+			c.alter(req)
+
+			resp, err := ProveContext(context.Background(), cfg, req, ProveOptions{})
+			assert.Nil(t, resp)
+			assert.ErrorIs(t, err, c.target)
+		})
+	}
+}
+
+func TestInnerProverPanicError(t *testing.T) {
+
+	stack := []byte("goroutine 1 [running]")
+
+	// The failed checks of the dummy compiler
+	err := innerProverPanicError(fmt.Errorf("%w: failed query", wizarddummy.ErrFailedQueries), stack)
+	assert.ErrorIs(t, err, ErrConstraints)
+	assert.NotErrorIs(t, err, ErrInternal)
+
+	// Any other panic is a bug
+	for _, r := range []any{"index out of range", errors.New("nil pointer"), 42} {
+		err := innerProverPanicError(r, stack)
+		assert.ErrorIs(t, err, ErrInternal)
+		assert.NotErrorIs(t, err, ErrConstraints)
+		assert.Contains(t, err.Error(), string(stack))
+	}
+}
+
+func TestLoadSetupAsync(t *testing.T) {
+
+	errLoad := errors.New("missing setup")
+	res := <-loadSetupAsync(func() (circuits.Setup, error) { return circuits.Setup{}, errLoad })
+	assert.ErrorIs(t, res.err, errLoad)
+
+	// A panic while loading the setup does not crash the prover
+	res = <-loadSetupAsync(func() (circuits.Setup, error) { panic("corrupted setup") })
+	assert.ErrorIs(t, res.err, ErrInternal)
+	assert.ErrorContains(t, res.err, "corrupted setup")
+}
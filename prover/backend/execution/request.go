@@ -2,6 +2,7 @@ package execution
 
 import (
 	"bytes"
+	"fmt"
 	"path"
 
 	"github.com/consensys/linea-monorepo/prover/backend/ethereum"
@@ -31,8 +32,19 @@ func (req *Request) StateManagerTraces() [][]statemanager.DecodedTrace {
 	return req.ZkStateMerkleProof
 }
 
-// Returns the parsed block data
+// Returns the parsed block data. It panics if a block cannot be parsed, see
+// [Request.ParseBlocks] for a non-panicking alternative.
 func (req *Request) Blocks() []ethtypes.Block {
+	res, err := req.ParseBlocks()
+	if err != nil {
+		utils.Panic("%v", err)
+	}
+	return res
+}
+
+// ParseBlocks returns the parsed block data or an error if the RLP encoding of
+// a block is malformed.
+func (req *Request) ParseBlocks() ([]ethtypes.Block, error) {
 	// Allocate the result
 	res := make([]ethtypes.Block, len(req.BlocksData))
 
@@ -40,19 +52,19 @@ func (req *Request) Blocks() []ethtypes.Block {
 		// Attempt to parse the block as an hexstring
 		blockRLPBytes, err := utils.HexDecodeString(blockdata.Rlp)
 		if err != nil {
-			utils.Panic("error while parsing the block RLP #%v : %v", i, err)
+			return nil, fmt.Errorf("error while parsing the block RLP #%v : %w", i, err)
 		}
 		buffer := bytes.NewReader(blockRLPBytes)
 
 		// Attempt to parse the RLP
 		err = rlp.Decode(buffer, &res[i])
 		if err != nil {
-			utils.Panic("Could not RLP decode the blockRLP 0x%x (block #%v)", blockRLPBytes, i)
+			return nil, fmt.Errorf("could not RLP decode the blockRLP 0x%x (block #%v): %w", blockRLPBytes, i, err)
 		}
 
 	}
 
-	return res
+	return res, nil
 }
 
 // Returns the transactions RLP encoded
@@ -4,7 +4,11 @@ import (
 	"testing"
 
 	"github.com/consensys/gnark-crypto/ecc"
+	fr377 "github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
 	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark/backend/plonk"
+	"github.com/consensys/gnark/frontend"
+	emPlonk "github.com/consensys/gnark/std/recursion/plonk"
 	"github.com/consensys/linea-monorepo/prover/circuits"
 	"github.com/consensys/linea-monorepo/prover/circuits/dummy"
 	"github.com/stretchr/testify/require"
@@ -25,3 +29,68 @@ func TestDummyCircuitProofGen(t *testing.T) {
 		_ = dummy.MakeProof(&pp, x, id)
 	}
 }
+
+// Test that the serialized proofs can be deserialized and verified against
+// the public input they were generated for, and only against it.
+func TestDummyCircuitProofDeserialize(t *testing.T) {
+
+	srsProvider := circuits.NewUnsafeSRSProvider()
+
+	t.Run("solidity-bn254", func(t *testing.T) {
+		assert := require.New(t)
+		var (
+			x     = fr.NewElement(uint64(0xabcdef0123456789))
+			other = fr.NewElement(42)
+			id    = circuits.MockCircuitIDEmulation
+		)
+
+		pp, err := dummy.MakeUnsafeSetup(srsProvider, id, ecc.BN254.ScalarField())
+		assert.NoError(err)
+
+		proofHex := dummy.MakeProof(&pp, x, id)
+
+		for _, c := range []struct {
+			x     fr.Element
+			valid bool
+		}{{x, true}, {other, false}} {
+			pub, err := frontend.NewWitness(dummy.Assign(id, c.x), ecc.BN254.ScalarField(), frontend.PublicOnly())
+			assert.NoError(err)
+
+			proof, err := circuits.DeserializeProofSolidityBn254(proofHex, pp.VerifyingKey, pub)
+			assert.NoError(err)
+
+			err = plonk.Verify(proof, pp.VerifyingKey, pub)
+			if c.valid {
+				assert.NoError(err)
+			} else {
+				assert.Error(err)
+			}
+		}
+
+		// A truncated proof is rejected
+		pub, err := frontend.NewWitness(dummy.Assign(id, x), ecc.BN254.ScalarField(), frontend.PublicOnly())
+		assert.NoError(err)
+		_, err = circuits.DeserializeProofSolidityBn254(proofHex[:len(proofHex)-2], pp.VerifyingKey, pub)
+		assert.Error(err)
+	})
+
+	t.Run("raw-bls12-377", func(t *testing.T) {
+		assert := require.New(t)
+		var (
+			x  = fr377.NewElement(uint64(0xabcdef0123456789))
+			id = circuits.MockCircuitIDExecution
+		)
+
+		pp, err := dummy.MakeUnsafeSetup(srsProvider, id, ecc.BLS12_377.ScalarField())
+		assert.NoError(err)
+
+		proof, err := circuits.DeserializeProofRaw(dummy.MakeProof(&pp, x, id), ecc.BLS12_377)
+		assert.NoError(err)
+
+		pub, err := frontend.NewWitness(dummy.Assign(id, x), ecc.BLS12_377.ScalarField(), frontend.PublicOnly())
+		assert.NoError(err)
+
+		assert.NoError(plonk.Verify(proof, pp.VerifyingKey, pub,
+			emPlonk.GetNativeVerifierOptions(ecc.BW6_761.ScalarField(), ecc.BLS12_377.ScalarField())))
+	})
+}
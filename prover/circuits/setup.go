@@ -149,11 +149,9 @@ func (s *Setup) WriteTo(rootDir string) error {
 func LoadSetup(cfg *config.Config, circuitID CircuitID) (Setup, error) {
 	runtime.GC()
 
-	rootDir := cfg.PathForSetup(string(circuitID))
-	manifestPath := filepath.Join(rootDir, config.ManifestFileName)
-	manifest, err := ReadSetupManifest(manifestPath)
+	vk, manifest, err := LoadVerifyingKey(cfg, circuitID)
 	if err != nil {
-		return Setup{}, fmt.Errorf("reading manifest from file: %w", err)
+		return Setup{}, err
 	}
 
 	curveID, err := ecc.IDFromString(manifest.CurveID)
@@ -161,27 +159,12 @@ func LoadSetup(cfg *config.Config, circuitID CircuitID) (Setup, error) {
 		return Setup{}, fmt.Errorf("parsing curve ID: %w", err)
 	}
 
-	circuitPath := filepath.Join(rootDir, config.CircuitFileName)
+	circuitPath := filepath.Join(cfg.PathForSetup(string(circuitID)), config.CircuitFileName)
 	circuit := plonk.NewCS(curveID)
 	if err := readFromFile(circuitPath, circuit); err != nil {
 		return Setup{}, fmt.Errorf("reading circuit from file: %w", err)
 	}
 
-	verifyingKeyPath := filepath.Join(rootDir, config.VerifyingKeyFileName)
-	vk := plonk.NewVerifyingKey(curveID)
-	if err := readFromFile(verifyingKeyPath, vk); err != nil {
-		return Setup{}, fmt.Errorf("reading verifying key from file: %w", err)
-	}
-
-	vkChecksum, err := objectChecksum(vk)
-	if err != nil {
-		return Setup{}, fmt.Errorf("computing checksum for verifying key: %w", err)
-	}
-
-	if vkChecksum != manifest.Checksums.VerifyingKey {
-		return Setup{}, fmt.Errorf("verifying key checksum mismatch: expected %q, got %q", manifest.Checksums.VerifyingKey, vkChecksum)
-	}
-
 	// Load the proving key from the SRS provider
 	srsProvider, err := NewSRSStore(cfg.PathForSRS())
 	if err != nil {
@@ -231,6 +214,41 @@ func LoadSetup(cfg *config.Config, circuitID CircuitID) (Setup, error) {
 	}, nil
 }
 
+// LoadVerifyingKey reads the manifest and the verifying key of a circuit and
+// checks that the verifying key matches the checksum of the manifest. Unlike
+// [LoadSetup], it reads neither the circuit nor the SRS.
+func LoadVerifyingKey(cfg *config.Config, circuitID CircuitID) (plonk.VerifyingKey, *SetupManifest, error) {
+
+	rootDir := cfg.PathForSetup(string(circuitID))
+	manifestPath := filepath.Join(rootDir, config.ManifestFileName)
+	manifest, err := ReadSetupManifest(manifestPath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("reading manifest from file: %w", err)
+	}
+
+	curveID, err := ecc.IDFromString(manifest.CurveID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("parsing curve ID: %w", err)
+	}
+
+	verifyingKeyPath := filepath.Join(rootDir, config.VerifyingKeyFileName)
+	vk := plonk.NewVerifyingKey(curveID)
+	if err := readFromFile(verifyingKeyPath, vk); err != nil {
+		return nil, nil, fmt.Errorf("reading verifying key from file: %w", err)
+	}
+
+	vkChecksum, err := objectChecksum(vk)
+	if err != nil {
+		return nil, nil, fmt.Errorf("computing checksum for verifying key: %w", err)
+	}
+
+	if vkChecksum != manifest.Checksums.VerifyingKey {
+		return nil, nil, fmt.Errorf("verifying key checksum mismatch: expected %q, got %q", manifest.Checksums.VerifyingKey, vkChecksum)
+	}
+
+	return vk, manifest, nil
+}
+
 func writeToFile(path string, object any) error {
 	f, err := os.Create(path)
 	if err != nil {
@@ -5,9 +5,11 @@ import (
 	"fmt"
 	"os"
 	"path"
+	"slices"
 	"time"
 
 	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/constraint"
 )
 
 // SetupManifest is the human-readable manifest of the assets generated by the prover setup command
@@ -112,6 +114,71 @@ func (m *SetupManifest) get(key string) (any, error) {
 	return v, nil
 }
 
+// Drift compares the manifest with the one that the setup of the circuit ccs
+// with the given extra flags would produce. Only the fields that do not depend
+// on the SRS are compared, that is neither the timestamp nor the checksums of
+// the keys. It returns a description of every difference, or nil if the
+// manifest matches.
+func (m *SetupManifest) Drift(ccs constraint.ConstraintSystem, extraFlags map[string]any) ([]string, error) {
+
+	var drift []string
+
+	circuitDigest, err := CircuitDigest(ccs)
+	if err != nil {
+		return nil, fmt.Errorf("computing the circuit digest: %w", err)
+	}
+
+	if m.Checksums.Circuit != circuitDigest {
+		drift = append(drift, fmt.Sprintf("circuit digest: setup has %v, compiled circuit has %v", m.Checksums.Circuit, circuitDigest))
+	}
+
+	if m.NbConstraints != ccs.GetNbConstraints() {
+		drift = append(drift, fmt.Sprintf("number of constraints: setup has %v, compiled circuit has %v", m.NbConstraints, ccs.GetNbConstraints()))
+	}
+
+	if curveID := fieldToCurve(ccs.Field()).String(); m.CurveID != curveID {
+		drift = append(drift, fmt.Sprintf("curve: setup has %v, compiled circuit has %v", m.CurveID, curveID))
+	}
+
+	// The flags read from the manifest went through a JSON encoding so they
+	// are compared in their JSON form.
+	keys := make([]string, 0, len(m.ExtraFlags)+len(extraFlags))
+	for k := range m.ExtraFlags {
+		keys = append(keys, k)
+	}
+	for k := range extraFlags {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	keys = slices.Compact(keys)
+
+	for _, k := range keys {
+		got, inManifest := m.ExtraFlags[k]
+		expected, inFlags := extraFlags[k]
+
+		switch {
+		case !inManifest:
+			drift = append(drift, fmt.Sprintf("flag `%v`: missing from the setup, expected %v", k, expected))
+		case !inFlags:
+			drift = append(drift, fmt.Sprintf("flag `%v`: set to %v in the setup but not expected", k, got))
+		default:
+			gotJSON, err := json.Marshal(got)
+			if err != nil {
+				return nil, fmt.Errorf("encoding flag `%v` of the setup: %w", k, err)
+			}
+			expectedJSON, err := json.Marshal(expected)
+			if err != nil {
+				return nil, fmt.Errorf("encoding flag `%v`: %w", k, err)
+			}
+			if string(gotJSON) != string(expectedJSON) {
+				drift = append(drift, fmt.Sprintf("flag `%v`: setup has %s, expected %s", k, gotJSON, expectedJSON))
+			}
+		}
+	}
+
+	return drift, nil
+}
+
 // ReadSetupManifestFromFile reads a manifest from a json file
 func ReadSetupManifest(filePath string) (*SetupManifest, error) {
 	f, err := os.Open(filePath)
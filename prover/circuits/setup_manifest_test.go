@@ -2,9 +2,15 @@ package circuits
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"path/filepath"
 	"testing"
 
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/constraint"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/scs"
 	"github.com/stretchr/testify/require"
 )
 
@@ -40,3 +46,45 @@ func TestParseManifestAndFlags(t *testing.T) {
 	assert.Equal("0xd1624b8e9e5987f7bbf85cb32bb7b9787144aceb4527864f31ba1957e300f7eb", keys[0])
 	assert.Equal("0xc4a868954d361bf8c18d4b3699c4fa973a6b2e4543ddea0ce7970d6941f55758", keys[1])
 }
+
+func TestSetupManifestDrift(t *testing.T) {
+	assert := require.New(t)
+
+	compile := func(nbInputs int) constraint.ConstraintSystem {
+		cs, err := frontend.Compile(ecc.BN254.ScalarField(), scs.NewBuilder, &circuit{make([]frontend.Variable, nbInputs)})
+		assert.NoError(err)
+		return cs
+	}
+
+	var (
+		cs    = compile(1)
+		flags = map[string]any{
+			"maxUsableBytes": 1024,
+			"cfg_checksum":   "0xabcd",
+		}
+		path = filepath.Join(t.TempDir(), "manifest.json")
+	)
+
+	setup, err := MakeSetup(context.TODO(), "test", cs, NewUnsafeSRSProvider(), flags)
+	assert.NoError(err)
+	assert.NoError(setup.Manifest.WriteTo(path))
+
+	// The flags are compared after being read from the disk
+	m, err := ReadSetupManifest(path)
+	assert.NoError(err)
+
+	drift, err := m.Drift(cs, flags)
+	assert.NoError(err)
+	assert.Empty(drift)
+
+	drift, err = m.Drift(cs, map[string]any{
+		"maxUsableBytes": 2048,
+		"extra":          true,
+	})
+	assert.NoError(err)
+	assert.Len(drift, 3) // cfg_checksum, extra and maxUsableBytes
+
+	drift, err = m.Drift(compile(2), flags)
+	assert.NoError(err)
+	assert.Len(drift, 2) // circuit digest and number of constraints
+}
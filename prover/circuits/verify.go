@@ -0,0 +1,289 @@
+package circuits
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	curve "github.com/consensys/gnark-crypto/ecc/bn254"
+	fr254 "github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr/hash_to_field"
+	fiatshamir "github.com/consensys/gnark-crypto/fiat-shamir"
+	"github.com/consensys/gnark/backend/plonk"
+	plonk_bn254 "github.com/consensys/gnark/backend/plonk/bn254"
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// DeserializeProofRaw parses a proof serialized with [SerializeProofRaw]
+func DeserializeProofRaw(proofHex string, curveID ecc.ID) (plonk.Proof, error) {
+
+	b, err := hexutil.Decode(proofHex)
+	if err != nil {
+		return nil, fmt.Errorf("could not decode the proof as an hexstring: %w", err)
+	}
+
+	proof := plonk.NewProof(curveID)
+	if _, err := proof.ReadFrom(bytes.NewReader(b)); err != nil {
+		return nil, fmt.Errorf("could not parse the proof: %w", err)
+	}
+
+	return proof, nil
+}
+
+// DeserializeProofSolidityBn254 parses a proof serialized with
+// [SerializeProofSolidityBn254]. The Solidity serialization leaves out the
+// opening of the linearized polynomial because the verifier contract
+// recomputes it. It is recomputed here in the same way from the verifying key
+// and the public witness the proof is going to be verified against, assuming
+// the default verifier options. A proof that does not match the public witness
+// is still returned but fails the verification.
+func DeserializeProofSolidityBn254(proofHex string, vk plonk.VerifyingKey, publicWitness witness.Witness) (plonk.Proof, error) {
+
+	b, err := hexutil.Decode(proofHex)
+	if err != nil {
+		return nil, fmt.Errorf("could not decode the proof as an hexstring: %w", err)
+	}
+
+	vkBn254, ok := vk.(*plonk_bn254.VerifyingKey)
+	if !ok {
+		return nil, fmt.Errorf("expected a bn254 verifying key, got %T", vk)
+	}
+
+	pub, ok := publicWitness.Vector().(fr254.Vector)
+	if !ok {
+		return nil, fmt.Errorf("expected a bn254 public witness, got %T", publicWitness.Vector())
+	}
+
+	const (
+		sizeG1 = curve.SizeOfG1AffineUncompressed
+		sizeFr = fr254.Bytes
+	)
+
+	var (
+		nbCommitments = len(vkBn254.Qcp)
+		expectedLen   = 9*sizeG1 + 6*sizeFr + nbCommitments*(sizeG1+sizeFr)
+	)
+
+	if len(b) != expectedLen {
+		return nil, fmt.Errorf("the proof has %v bytes but %v bytes are expected for %v commitments", len(b), expectedLen, nbCommitments)
+	}
+
+	var (
+		proof = &plonk_bn254.Proof{}
+		errs  []error
+	)
+
+	readG1 := func(p *curve.G1Affine) {
+		if _, err := p.SetBytes(b[:sizeG1]); err != nil {
+			errs = append(errs, err)
+		}
+		b = b[sizeG1:]
+	}
+
+	readFr := func(x *fr254.Element) {
+		if err := x.SetBytesCanonical(b[:sizeFr]); err != nil {
+			errs = append(errs, err)
+		}
+		b = b[sizeFr:]
+	}
+
+	// The order follows [plonk_bn254.Proof.MarshalSolidity]
+	proof.BatchedProof.ClaimedValues = make([]fr254.Element, 6+nbCommitments)
+	proof.Bsb22Commitments = make([]curve.G1Affine, nbCommitments)
+
+	for i := range proof.LRO {
+		readG1(&proof.LRO[i])
+	}
+	for i := range proof.H {
+		readG1(&proof.H[i])
+	}
+	for i := 1; i < 6; i++ {
+		readFr(&proof.BatchedProof.ClaimedValues[i])
+	}
+	readG1(&proof.Z)
+	readFr(&proof.ZShiftedOpening.ClaimedValue)
+	readG1(&proof.BatchedProof.H)
+	readG1(&proof.ZShiftedOpening.H)
+	for i := 0; i < nbCommitments; i++ {
+		readFr(&proof.BatchedProof.ClaimedValues[6+i])
+	}
+	for i := range proof.Bsb22Commitments {
+		readG1(&proof.Bsb22Commitments[i])
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return nil, fmt.Errorf("could not parse the proof: %w", err)
+	}
+
+	if len(pub) != int(vkBn254.NbPublicVariables) {
+		return nil, fmt.Errorf("the public witness has %v values but the verifying key expects %v", len(pub), vkBn254.NbPublicVariables)
+	}
+
+	opening, err := linearizedPolynomialOpening(proof, vkBn254, pub)
+	if err != nil {
+		return nil, fmt.Errorf("could not recompute the opening of the linearized polynomial: %w", err)
+	}
+	proof.BatchedProof.ClaimedValues[0] = opening
+
+	return proof, nil
+}
+
+// linearizedPolynomialOpening recomputes the claimed value of the linearized
+// polynomial at zeta in the same way as the verifier of gnark, with its default
+// options, and the verifier contract do.
+func linearizedPolynomialOpening(proof *plonk_bn254.Proof, vk *plonk_bn254.VerifyingKey, pub fr254.Vector) (fr254.Element, error) {
+
+	var res fr254.Element
+
+	// Derive the challenges
+	fs := fiatshamir.NewTranscript(sha256.New(), "gamma", "beta", "alpha", "zeta")
+
+	bind := func(challenge string, data ...[]byte) error {
+		for _, d := range data {
+			if err := fs.Bind(challenge, d); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	derive := func(challenge string, points ...*curve.G1Affine) (fr254.Element, error) {
+		var r fr254.Element
+		for _, p := range points {
+			buf := p.RawBytes()
+			if err := fs.Bind(challenge, buf[:]); err != nil {
+				return r, err
+			}
+		}
+		b, err := fs.ComputeChallenge(challenge)
+		if err != nil {
+			return r, err
+		}
+		r.SetBytes(b)
+		return r, nil
+	}
+
+	publicData := [][]byte{
+		vk.S[0].Marshal(), vk.S[1].Marshal(), vk.S[2].Marshal(),
+		vk.Ql.Marshal(), vk.Qr.Marshal(), vk.Qm.Marshal(), vk.Qo.Marshal(), vk.Qk.Marshal(),
+	}
+	for i := range vk.Qcp {
+		publicData = append(publicData, vk.Qcp[i].Marshal())
+	}
+	for i := range pub {
+		publicData = append(publicData, pub[i].Marshal())
+	}
+
+	if err := bind("gamma", publicData...); err != nil {
+		return res, err
+	}
+
+	gamma, err := derive("gamma", &proof.LRO[0], &proof.LRO[1], &proof.LRO[2])
+	if err != nil {
+		return res, err
+	}
+
+	beta, err := derive("beta")
+	if err != nil {
+		return res, err
+	}
+
+	alphaDeps := make([]*curve.G1Affine, 0, len(proof.Bsb22Commitments)+1)
+	for i := range proof.Bsb22Commitments {
+		alphaDeps = append(alphaDeps, &proof.Bsb22Commitments[i])
+	}
+	alphaDeps = append(alphaDeps, &proof.Z)
+
+	alpha, err := derive("alpha", alphaDeps...)
+	if err != nil {
+		return res, err
+	}
+
+	zeta, err := derive("zeta", &proof.H[0], &proof.H[1], &proof.H[2])
+	if err != nil {
+		return res, err
+	}
+
+	// ζⁿ-1 and L₁(ζ)
+	var (
+		zetaPowerM, zhZeta, lagrangeZero fr254.Element
+		one                              = fr254.One()
+	)
+	zetaPowerM.Exp(zeta, new(big.Int).SetUint64(vk.Size))
+	zhZeta.Sub(&zetaPowerM, &one)
+	lagrangeZero.Sub(&zeta, &one).
+		Inverse(&lagrangeZero).
+		Mul(&lagrangeZero, &zhZeta).
+		Mul(&lagrangeZero, &vk.SizeInv)
+
+	// PI(ζ), including the hashes of the BSB22 commitments
+	var pi, accw, xiLi fr254.Element
+	dens := make([]fr254.Element, len(pub))
+	accw.SetOne()
+	for i := range pub {
+		dens[i].Sub(&zeta, &accw)
+		accw.Mul(&accw, &vk.Generator)
+	}
+
+	invDens := fr254.BatchInvert(dens)
+	accw.SetOne()
+	for i := range pub {
+		xiLi.Mul(&zhZeta, &invDens[i]).
+			Mul(&xiLi, &vk.SizeInv).
+			Mul(&xiLi, &accw).
+			Mul(&xiLi, &pub[i])
+		accw.Mul(&accw, &vk.Generator)
+		pi.Add(&pi, &xiLi)
+	}
+
+	var (
+		htf                      = hash_to_field.New([]byte("BSB22-Plonk"))
+		hashedCmt                fr254.Element
+		wPowI, den, lagrangeCmtI fr254.Element
+	)
+	for i, cci := range vk.CommitmentConstraintIndexes {
+		htf.Write(proof.Bsb22Commitments[i].Marshal())
+		hashBts := htf.Sum(nil)
+		hashedCmt.SetBytes(hashBts[:min(len(hashBts), fr254.Bytes)])
+		htf.Reset()
+
+		wPowI.Exp(vk.Generator, big.NewInt(int64(vk.NbPublicVariables)+int64(cci)))
+		den.Sub(&zeta, &wPowI)
+		lagrangeCmtI.SetOne().
+			Sub(&zetaPowerM, &lagrangeCmtI).
+			Mul(&lagrangeCmtI, &wPowI).
+			Div(&lagrangeCmtI, &den).
+			Mul(&lagrangeCmtI, &vk.SizeInv)
+
+		xiLi.Mul(&lagrangeCmtI, &hashedCmt)
+		pi.Add(&pi, &xiLi)
+	}
+
+	// -[PI(ζ) - α²*L₁(ζ) + α(l(ζ)+β*s1(ζ)+γ)(r(ζ)+β*s2(ζ)+γ)(o(ζ)+γ)*z(ωζ)]
+	var (
+		l   = proof.BatchedProof.ClaimedValues[1]
+		r   = proof.BatchedProof.ClaimedValues[2]
+		o   = proof.BatchedProof.ClaimedValues[3]
+		s1  = proof.BatchedProof.ClaimedValues[4]
+		s2  = proof.BatchedProof.ClaimedValues[5]
+		zu  = proof.ZShiftedOpening.ClaimedValue
+		tmp fr254.Element
+	)
+
+	var alphaSquareLagrangeZero fr254.Element
+	alphaSquareLagrangeZero.Mul(&lagrangeZero, &alpha).Mul(&alphaSquareLagrangeZero, &alpha)
+
+	res.Mul(&beta, &s1).Add(&res, &gamma).Add(&res, &l)
+	tmp.Mul(&s2, &beta).Add(&tmp, &gamma).Add(&tmp, &r)
+	res.Mul(&res, &tmp)
+	tmp.Add(&o, &gamma)
+	res.Mul(&tmp, &res).Mul(&res, &alpha).Mul(&res, &zu)
+	res.Sub(&res, &alphaSquareLagrangeZero).Add(&res, &pi)
+	res.Neg(&res)
+
+	return res, nil
+}
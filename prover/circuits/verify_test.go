@@ -0,0 +1,70 @@
+package circuits
+
+import (
+	"context"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/plonk"
+	plonk_bn254 "github.com/consensys/gnark/backend/plonk/bn254"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/scs"
+	"github.com/stretchr/testify/require"
+)
+
+// committedCircuit is a circuit with a BSB22 commitment, as the circuits whose
+// proofs are verified on-chain.
+type committedCircuit struct {
+	X frontend.Variable `gnark:",public"`
+	Y frontend.Variable
+}
+
+func (c *committedCircuit) Define(api frontend.API) error {
+	api.AssertIsEqual(api.Mul(c.Y, c.Y), c.X)
+
+	cmt, err := api.(frontend.Committer).Commit(c.X, c.Y)
+	if err != nil {
+		return err
+	}
+	api.AssertIsDifferent(cmt, 0)
+	return nil
+}
+
+// Test that the proofs of a circuit with a commitment survive the Solidity
+// serialization: the claimed values and the commitments are read back in the
+// right order and the opening of the linearized polynomial is recomputed.
+func TestDeserializeProofSolidityBn254Commitment(t *testing.T) {
+
+	assert := require.New(t)
+
+	cs, err := frontend.Compile(ecc.BN254.ScalarField(), scs.NewBuilder, &committedCircuit{})
+	assert.NoError(err)
+
+	setup, err := MakeSetup(context.TODO(), "committed", cs, NewUnsafeSRSProvider(), nil)
+	assert.NoError(err)
+	assert.Len(setup.VerifyingKey.(*plonk_bn254.VerifyingKey).Qcp, 1)
+
+	proof, err := ProveCheck(&setup, &committedCircuit{X: 9, Y: 3})
+	assert.NoError(err)
+
+	proofHex := SerializeProofSolidityBn254(proof)
+
+	for _, c := range []struct {
+		x     int
+		valid bool
+	}{{9, true}, {4, false}} {
+		pub, err := frontend.NewWitness(&committedCircuit{X: c.x}, ecc.BN254.ScalarField(), frontend.PublicOnly())
+		assert.NoError(err)
+
+		deserialized, err := DeserializeProofSolidityBn254(proofHex, setup.VerifyingKey, pub)
+		assert.NoError(err)
+
+		err = plonk.Verify(deserialized, setup.VerifyingKey, pub)
+		if c.valid {
+			assert.NoError(err)
+			assert.Equal(proof, deserialized)
+		} else {
+			assert.Error(err)
+		}
+	}
+}
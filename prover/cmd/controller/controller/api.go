@@ -0,0 +1,565 @@
+package controller
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/consensys/linea-monorepo/prover/config"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/exp/slices"
+)
+
+const (
+	DEFAULT_API_HOST = "localhost"
+	DEFAULT_API_PORT = 8080
+	// Should be less than the global shutdown timeout
+	API_SHUTDOWN_TIMEOUT_SEC = 5 * time.Second
+)
+
+// Status of a job as reported by the API
+const (
+	JobPending = "pending"
+	// A pending job waiting for the responses of the jobs it depends on
+	JobBlocked    = "blocked"
+	JobInProgress = "in-progress"
+	JobSuccess    = "success"
+	JobFailure    = "failure"
+	JobCancelled  = "cancelled"
+)
+
+// JobAPI is an HTTP server allowing to submit jobs to the controller, to
+// query their status and to cancel them. It does not maintain a queue of its
+// own: the submitted requests are written in the requests directory of their
+// job definition where they are picked up by the FsWatcher of any controller
+// sharing the directory, exactly as if they had been dropped there directly.
+// Likewise, the status of a job is derived from the directory and the name of
+// its request file.
+type JobAPI struct {
+	// Unique ID of the container. Used to name the temporary files
+	LocalID string
+	// Definitions of the jobs that can be submitted
+	JobDefs []JobDefinition
+	// Suffix used by the controllers to lock a file
+	InProgress string
+	// Maximal size of a submitted request file. Zero means no limit.
+	MaxRequestSize int64
+	// Logger specific to the API
+	Logger *logrus.Entry
+	// Configuration of the controller, used to locate the response files
+	Config *config.Config
+	// Token that the clients must send in the Authorization header
+	Token string
+
+	server *http.Server
+}
+
+// JobStatus is the representation of a job returned by the API
+type JobStatus struct {
+	// Name of the job definition, e.g. "execution"
+	Type string `json:"type"`
+	// Name of the request file as it was submitted, without the suffixes
+	// added by the controllers.
+	File  string `json:"file"`
+	Start int    `json:"start"`
+	End   int    `json:"end"`
+	// One of the JobXXX constants
+	Status string `json:"status"`
+	// Set if the job was deferred to the large prover
+	Large bool `json:"large,omitempty"`
+	// LocalID of the controller processing the job, if it is in progress
+	Owner string `json:"owner,omitempty"`
+	// Exit code of the last run of the job, if any
+	ExitCode int `json:"exitCode,omitempty"`
+	// Name of the response file, if the job succeeded
+	Response string `json:"response,omitempty"`
+	// Response files the job is waiting for, if it is blocked
+	MissingDependencies []string `json:"missingDependencies,omitempty"`
+
+	// Path of the file the status was derived from
+	path string
+}
+
+// NewJobAPI returns a JobAPI accepting the jobs enabled in the configuration.
+// It returns an error if the token of the API cannot be read.
+func NewJobAPI(conf *config.Config) (*JobAPI, error) {
+
+	if len(conf.Controller.API.TokenFile) == 0 {
+		return nil, errors.New("no token file is configured for the job API")
+	}
+
+	token, err := os.ReadFile(conf.Controller.API.TokenFile)
+	if err != nil {
+		return nil, fmt.Errorf("could not read the token of the job API: %w", err)
+	}
+
+	tokenStr := strings.TrimSpace(string(token))
+	if len(tokenStr) == 0 {
+		return nil, fmt.Errorf("the token file %v of the job API is empty", conf.Controller.API.TokenFile)
+	}
+
+	// The API submits requests to the whole pool of controllers sharing the
+	// requests directory. So the requests are never marked for the large
+	// prover, even if this controller runs large jobs: the regular prover
+	// will defer them to the large one if needed.
+	confNotLarge := *conf
+	confNotLarge.Execution.CanRunFullLarge = false
+
+	return &JobAPI{
+		LocalID:        conf.Controller.LocalID,
+		JobDefs:        enabledDefinitions(&confNotLarge),
+		InProgress:     config.InProgressSufix,
+		MaxRequestSize: conf.Controller.API.MaxRequestSize,
+		Logger:         conf.Logger().WithField("component", "job-api"),
+		Config:         conf,
+		Token:          tokenStr,
+	}, nil
+}
+
+// Handler returns the routes of the API. Every route requires the token of
+// the API in the Authorization header as `Bearer <token>`.
+//
+//	GET    /jobs                          list all the jobs
+//	GET    /jobs/{type}                   list the jobs of a type
+//	POST   /jobs/{type}/{file}            submit a request, the body is the content of the request file
+//	GET    /jobs/{type}/{file}            status of a job
+//	DELETE /jobs/{type}/{file}            cancel a pending job
+//	GET    /jobs/{type}/{file}/response   content of the response file of a successful job
+func (a *JobAPI) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /jobs", a.handleList)
+	mux.HandleFunc("GET /jobs/{type}", a.handleList)
+	mux.HandleFunc("POST /jobs/{type}/{file}", a.handleSubmit)
+	mux.HandleFunc("GET /jobs/{type}/{file}", a.handleStatus)
+	mux.HandleFunc("DELETE /jobs/{type}/{file}", a.handleCancel)
+	mux.HandleFunc("GET /jobs/{type}/{file}/response", a.handleResponse)
+	return a.authenticate(mux)
+}
+
+// authenticate rejects the requests that do not carry the token of the API.
+// An API without a token rejects every request.
+func (a *JobAPI) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || len(a.Token) == 0 || subtle.ConstantTimeCompare([]byte(token), []byte(a.Token)) != 1 {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			writeError(w, http.StatusUnauthorized, "missing or invalid token")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Start the API server in the background
+func (a *JobAPI) Start(host string, port int) {
+
+	// We consider "0" to be an unset value
+	if port <= 0 || port >= 1<<16 {
+		port = DEFAULT_API_PORT
+	}
+
+	if len(host) == 0 {
+		host = DEFAULT_API_HOST
+	}
+
+	addr := net.JoinHostPort(host, strconv.Itoa(port))
+
+	a.server = &http.Server{
+		Addr:              addr,
+		ReadHeaderTimeout: 5 * time.Second,
+		Handler:           a.Handler(),
+	}
+
+	go func() {
+		err := a.server.ListenAndServe()
+		if err != nil && err != http.ErrServerClosed {
+			a.Logger.Errorf("Job API server closed with error %v", err)
+		}
+	}()
+
+	a.Logger.Infof("Started the job API server on %v", addr)
+}
+
+// Shutdown the API server gracefully
+func (a *JobAPI) Shutdown(ctx context.Context) {
+	if a.server == nil {
+		return
+	}
+
+	// The context is likely already cancelled when we shutdown
+	shutdownCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), API_SHUTDOWN_TIMEOUT_SEC)
+	a.server.Shutdown(shutdownCtx)
+	cancel()
+	a.Logger.Infof("job API server successfully shutdown")
+}
+
+func (a *JobAPI) handleList(w http.ResponseWriter, r *http.Request) {
+
+	jdefs := a.JobDefs
+	if jobType := r.PathValue("type"); len(jobType) > 0 {
+		jdef := a.definition(jobType)
+		if jdef == nil {
+			writeError(w, http.StatusNotFound, "unknown job type %q", jobType)
+			return
+		}
+		jdefs = []JobDefinition{*jdef}
+	}
+
+	res := []JobStatus{}
+	for i := range jdefs {
+		statuses, err := a.list(&jdefs[i])
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, "could not list the %v jobs: %v", jdefs[i].Name, err)
+			return
+		}
+		res = append(res, statuses...)
+	}
+
+	writeJSON(w, http.StatusOK, res)
+}
+
+func (a *JobAPI) handleSubmit(w http.ResponseWriter, r *http.Request) {
+
+	jdef, file, ok := a.parsePath(w, r)
+	if !ok {
+		return
+	}
+
+	if _, err := NewJob(jdef, file); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request file name: %v", err)
+		return
+	}
+
+	if ok, _ := jdef.FailureSuffix.MatchString(file); ok {
+		writeError(w, http.StatusBadRequest, "the request file name %q must not have a failure suffix", file)
+		return
+	}
+
+	// A failed job can be submitted again, the other ones are still known to
+	// the controllers. This only gives a meaningful error: the pending jobs
+	// are not overwritten by the link below in any case.
+	if st, found, err := a.find(jdef, file); err != nil {
+		writeError(w, http.StatusInternalServerError, "could not look up the job: %v", err)
+		return
+	} else if found && st.Status != JobFailure {
+		writeError(w, http.StatusConflict, "the job %q is already %v", file, st.Status)
+		return
+	}
+
+	body := r.Body
+	if a.MaxRequestSize > 0 {
+		body = http.MaxBytesReader(w, r.Body, a.MaxRequestSize)
+	}
+
+	// The request is written in a temporary file first so that the
+	// controllers never pick up a partially written file. Its name starts
+	// with a "." so that it does not match any input regexp.
+	tmp, err := os.CreateTemp(jdef.dirFrom(), ".submit-"+a.LocalID+"-*")
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "could not create the request file: %v", err)
+		return
+	}
+
+	_, errCopy := io.Copy(tmp, body)
+	errClose := tmp.Close()
+	if err := errors.Join(errCopy, errClose); err != nil {
+		os.Remove(tmp.Name())
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			writeError(w, http.StatusRequestEntityTooLarge, "the request is larger than %v bytes", a.MaxRequestSize)
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "could not write the request file: %v", err)
+		return
+	}
+
+	// Unlike a rename, the link fails if the request file exists, e.g. if
+	// the same job was submitted concurrently.
+	dst := filepath.Join(jdef.dirFrom(), file)
+	errLink := os.Link(tmp.Name(), dst)
+	os.Remove(tmp.Name())
+	if errors.Is(errLink, os.ErrExist) {
+		writeError(w, http.StatusConflict, "the job %q is already pending", file)
+		return
+	} else if errLink != nil {
+		writeError(w, http.StatusInternalServerError, "could not write the request file: %v", errLink)
+		return
+	}
+
+	a.Logger.Infof("Received the %v job %v through the API", jdef.Name, file)
+
+	st, _ := a.status(jdef, file, false)
+	writeJSON(w, http.StatusCreated, st)
+}
+
+func (a *JobAPI) handleStatus(w http.ResponseWriter, r *http.Request) {
+
+	st, ok := a.lookup(w, r)
+	if !ok {
+		return
+	}
+
+	writeJSON(w, http.StatusOK, st)
+}
+
+func (a *JobAPI) handleCancel(w http.ResponseWriter, r *http.Request) {
+
+	st, ok := a.lookup(w, r)
+	if !ok {
+		return
+	}
+
+	if st.Status != JobPending && st.Status != JobBlocked {
+		writeError(w, http.StatusConflict, "only pending jobs can be cancelled, the job %q is %v", st.File, st.Status)
+		return
+	}
+
+	// If the file no longer exists, a controller locked it in the meantime
+	if err := os.Remove(st.path); errors.Is(err, os.ErrNotExist) {
+		writeError(w, http.StatusConflict, "the job %q was picked up by a controller", st.File)
+		return
+	} else if err != nil {
+		writeError(w, http.StatusInternalServerError, "could not cancel the job: %v", err)
+		return
+	}
+
+	a.Logger.Infof("Cancelled the %v job %v through the API", st.Type, st.File)
+
+	st.Status = JobCancelled
+	writeJSON(w, http.StatusOK, st)
+}
+
+func (a *JobAPI) handleResponse(w http.ResponseWriter, r *http.Request) {
+
+	st, ok := a.lookup(w, r)
+	if !ok {
+		return
+	}
+
+	if st.Status != JobSuccess {
+		writeError(w, http.StatusNotFound, "the job %q has no response, it is %v", st.File, st.Status)
+		return
+	}
+
+	jdef := a.definition(st.Type)
+	http.ServeFile(w, r, filepath.Join(jdef.dirTo(), st.Response))
+}
+
+// Returns the job definition and the file name given in the path of the
+// request. If they are invalid, an error is written and ok is false.
+func (a *JobAPI) parsePath(w http.ResponseWriter, r *http.Request) (jdef *JobDefinition, file string, ok bool) {
+
+	jobType := r.PathValue("type")
+	jdef = a.definition(jobType)
+	if jdef == nil {
+		writeError(w, http.StatusNotFound, "unknown job type %q", jobType)
+		return nil, "", false
+	}
+
+	return jdef, r.PathValue("file"), true
+}
+
+// Returns the status of the job given in the path of the request. If the job
+// cannot be found, an error is written and ok is false.
+func (a *JobAPI) lookup(w http.ResponseWriter, r *http.Request) (st JobStatus, ok bool) {
+
+	jdef, file, ok := a.parsePath(w, r)
+	if !ok {
+		return JobStatus{}, false
+	}
+
+	st, found, err := a.find(jdef, file)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "could not look up the job: %v", err)
+		return JobStatus{}, false
+	}
+
+	if !found {
+		writeError(w, http.StatusNotFound, "unknown %v job %q", jdef.Name, file)
+		return JobStatus{}, false
+	}
+
+	return st, true
+}
+
+// Returns the job definition with the given name or nil
+func (a *JobAPI) definition(name string) *JobDefinition {
+	for i := range a.JobDefs {
+		if a.JobDefs[i].Name == name {
+			return &a.JobDefs[i]
+		}
+	}
+	return nil
+}
+
+// Returns the status of a job given the name of the request file
+func (a *JobAPI) find(jdef *JobDefinition, file string) (st JobStatus, found bool, err error) {
+
+	statuses, err := a.list(jdef)
+	if err != nil {
+		return JobStatus{}, false, err
+	}
+
+	for i := range statuses {
+		if statuses[i].File == file {
+			return statuses[i], true, nil
+		}
+	}
+
+	return JobStatus{}, false, nil
+}
+
+// Returns the status of all the jobs found in the requests and the
+// requests-done directories of a job definition, sorted by range. If the same
+// request file appears several times, e.g. because a failed job was
+// submitted again, the status of the most advanced run is kept.
+func (a *JobAPI) list(jdef *JobDefinition) ([]JobStatus, error) {
+
+	byFile := map[string]JobStatus{}
+
+	for _, dir := range []struct {
+		path string
+		done bool
+	}{
+		{path: jdef.dirFrom(), done: false},
+		{path: jdef.dirDone(), done: true},
+	} {
+		dirents, err := lsname(dir.path)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, dirent := range dirents {
+			if !dirent.Type().IsRegular() {
+				continue
+			}
+
+			st, ok := a.status(jdef, dirent.Name(), dir.done)
+			if !ok {
+				continue
+			}
+
+			if prev, found := byFile[st.File]; !found || statusRank(st.Status) < statusRank(prev.Status) {
+				byFile[st.File] = st
+			}
+		}
+	}
+
+	res := make([]JobStatus, 0, len(byFile))
+	for _, st := range byFile {
+		res = append(res, st)
+	}
+
+	slices.SortFunc(res, func(a, b JobStatus) int {
+		if a.Start != b.Start {
+			return a.Start - b.Start
+		}
+		return strings.Compare(a.File, b.File)
+	})
+
+	return res, nil
+}
+
+// Derives the status of a job from the name of a file found in the requests
+// directory or in the requests-done directory if done is true. It returns
+// false if the file is not a request file of the job definition.
+func (a *JobAPI) status(jdef *JobDefinition, name string, done bool) (st JobStatus, ok bool) {
+
+	st = JobStatus{
+		Type:   jdef.Name,
+		Status: JobPending,
+		path:   filepath.Join(jdef.dirFrom(), name),
+	}
+
+	if done {
+		st.path = filepath.Join(jdef.dirDone(), name)
+		st.Status = JobFailure
+		if trimmed, isSuccess := strings.CutSuffix(name, "."+config.SuccessSuffix); isSuccess {
+			name, st.Status = trimmed, JobSuccess
+		}
+	} else if orig, owner, isLocked := parseLockedFile(name, a.InProgress); isLocked {
+		name, st.Status, st.Owner = orig, JobInProgress, owner
+	}
+
+	// The exit code of the last run is given by the last failure suffix
+	if match, err := jdef.FailureSuffix.FindStringMatch(name); err == nil && match != nil {
+		for ; match != nil; match, _ = jdef.FailureSuffix.FindNextMatch(match) {
+			code := match.String()
+			st.ExitCode, _ = strconv.Atoi(code[strings.LastIndex(code, "_")+1:])
+		}
+
+		if name, err = jdef.FailureSuffix.Replace(name, "", -1, -1); err != nil {
+			// The error can only depend on the regexp, see [Job.DoneFile]
+			panic(err)
+		}
+	}
+
+	// A failed job is only recognized by its failure suffix
+	if st.Status == JobFailure && st.ExitCode == 0 {
+		return JobStatus{}, false
+	}
+
+	name, st.Large = strings.CutSuffix(name, "."+config.LargeSuffix)
+
+	job, err := NewJob(jdef, name)
+	if err != nil {
+		return JobStatus{}, false
+	}
+
+	st.File, st.Start, st.End = name, job.Start, job.End
+
+	if st.Status == JobSuccess {
+		resp, err := job.ResponseFile()
+		if err != nil {
+			return JobStatus{}, false
+		}
+		st.Response = filepath.Base(resp)
+	}
+
+	if st.Status == JobPending && hasDependencies(jdef) {
+		// An unparseable request is not blocked, the prover will fail it
+		missing, err := missingDependencies(a.Config, st.path)
+		if err == nil && len(missing) > 0 {
+			st.Status, st.MissingDependencies = JobBlocked, missing
+		}
+	}
+
+	return st, true
+}
+
+// Returns the precedence of a status when a request file appears several
+// times. The lower, the more advanced the run of the job.
+func statusRank(status string) int {
+	switch status {
+	case JobInProgress:
+		return 0
+	case JobPending, JobBlocked:
+		return 1
+	case JobSuccess:
+		return 2
+	default:
+		return 3
+	}
+}
+
+func writeJSON(w http.ResponseWriter, code int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		logrus.Errorf("could not write the API response: %v", err)
+	}
+}
+
+func writeError(w http.ResponseWriter, code int, format string, args ...any) {
+	writeJSON(w, code, map[string]string{"error": fmt.Sprintf(format, args...)})
+}
@@ -0,0 +1,230 @@
+package controller
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/consensys/linea-monorepo/prover/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testAPIToken is the token of the job APIs returned by newTestJobAPI
+const testAPIToken = "test-token"
+
+// newTestJobAPI returns the job API of the configuration with testAPIToken
+// as its token.
+func newTestJobAPI(t *testing.T, conf *config.Config) *JobAPI {
+	tokenFile := path.Join(t.TempDir(), "api-token")
+	require.NoError(t, os.WriteFile(tokenFile, []byte(testAPIToken+"\n"), 0600))
+	conf.Controller.API.TokenFile = tokenFile
+	jobAPI, err := NewJobAPI(conf)
+	require.NoError(t, err)
+	return jobAPI
+}
+
+func TestJobAPI(t *testing.T) {
+
+	confM, _ := setupFsTest(t)
+
+	var (
+		eFrom     = confM.Execution.DirFrom()
+		eDone     = confM.Execution.DirDone()
+		jobAPI    = newTestJobAPI(t, confM)
+		fsWatcher = NewFsWatcher(confM)
+		srv       = httptest.NewServer(jobAPI.Handler())
+		file      = "0-1-etv0.1.2-stv1.2.3-getZkProof.json"
+	)
+	defer srv.Close()
+
+	do := func(method, route, body string) (code int, resp string) {
+		req, err := http.NewRequest(method, srv.URL+route, strings.NewReader(body))
+		require.NoError(t, err)
+		req.Header.Set("Authorization", "Bearer "+testAPIToken)
+		res, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer res.Body.Close()
+		b, err := io.ReadAll(res.Body)
+		require.NoError(t, err)
+		return res.StatusCode, string(b)
+	}
+
+	status := func(resp string) (st JobStatus) {
+		require.NoError(t, json.Unmarshal([]byte(resp), &st), resp)
+		return st
+	}
+
+	// Submission of a new job
+	code, resp := do("POST", "/jobs/execution/"+file, "#!/bin/sh\nexit 0")
+	require.Equal(t, http.StatusCreated, code, resp)
+	assert.Equal(t, JobPending, status(resp).Status)
+	assert.FileExists(t, path.Join(eFrom, file))
+
+	// Invalid submissions
+	code, resp = do("POST", "/jobs/execution/"+file, "")
+	assert.Equal(t, http.StatusConflict, code, resp)
+	code, resp = do("POST", "/jobs/execution/not-a-request.json", "")
+	assert.Equal(t, http.StatusBadRequest, code, resp)
+	code, resp = do("POST", "/jobs/unknown/"+file, "")
+	assert.Equal(t, http.StatusNotFound, code, resp)
+
+	// The job is picked up by the controller as any other file
+	job := fsWatcher.GetBest()
+	require.NotNil(t, job)
+	assert.Equal(t, file, job.OriginalFile)
+
+	code, resp = do("GET", "/jobs/execution/"+file, "")
+	require.Equal(t, http.StatusOK, code, resp)
+	assert.Equal(t, JobInProgress, status(resp).Status)
+	assert.Equal(t, confM.Controller.LocalID, status(resp).Owner)
+
+	code, resp = do("DELETE", "/jobs/execution/"+file, "")
+	assert.Equal(t, http.StatusConflict, code, resp)
+
+	// Completion of the job
+	respFile, err := job.ResponseFile()
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(respFile, []byte("proof"), 0600))
+	require.NoError(t, os.Rename(job.InProgressPath(), job.DoneFile(Status{ExitCode: CodeSuccess})))
+
+	code, resp = do("GET", "/jobs/execution/"+file, "")
+	require.Equal(t, http.StatusOK, code, resp)
+	assert.Equal(t, JobSuccess, status(resp).Status)
+	assert.Equal(t, path.Base(respFile), status(resp).Response)
+
+	code, resp = do("GET", "/jobs/execution/"+file+"/response", "")
+	assert.Equal(t, http.StatusOK, code, resp)
+	assert.Equal(t, "proof", resp)
+
+	// Cancellation of a pending job
+	other := "1-2-etv0.1.2-stv1.2.3-getZkProof.json"
+	code, resp = do("POST", "/jobs/execution/"+other, "")
+	require.Equal(t, http.StatusCreated, code, resp)
+	code, resp = do("DELETE", "/jobs/execution/"+other, "")
+	require.Equal(t, http.StatusOK, code, resp)
+	assert.Equal(t, JobCancelled, status(resp).Status)
+	assert.NoFileExists(t, path.Join(eFrom, other))
+	code, resp = do("GET", "/jobs/execution/"+other, "")
+	assert.Equal(t, http.StatusNotFound, code, resp)
+
+	// Failed jobs and jobs deferred to the large prover
+	failed := createTestInputFile(eDone, 2, 3, execJob, 0)
+	require.NoError(t, os.Rename(path.Join(eDone, failed), path.Join(eDone, failed+".failure.code_2")))
+	deferred := createTestInputFile(eFrom, 3, 4, execJob, 0)
+	require.NoError(t, os.Rename(path.Join(eFrom, deferred), path.Join(eFrom, deferred+".large.failure.code_137")))
+
+	code, resp = do("GET", "/jobs/execution", "")
+	require.Equal(t, http.StatusOK, code, resp)
+	var statuses []JobStatus
+	require.NoError(t, json.Unmarshal([]byte(resp), &statuses), resp)
+	require.Len(t, statuses, 3)
+	assert.Equal(t, JobStatus{Type: jobNameExecution, File: file, Start: 0, End: 1, Status: JobSuccess, Response: path.Base(respFile)}, statuses[0])
+	assert.Equal(t, JobStatus{Type: jobNameExecution, File: failed, Start: 2, End: 3, Status: JobFailure, ExitCode: 2}, statuses[1])
+	assert.Equal(t, JobStatus{Type: jobNameExecution, File: deferred, Start: 3, End: 4, Status: JobPending, Large: true, ExitCode: 137}, statuses[2])
+
+	// A failed job can be submitted again
+	code, resp = do("POST", "/jobs/execution/"+failed, "")
+	require.Equal(t, http.StatusCreated, code, resp)
+	code, resp = do("GET", "/jobs/execution/"+failed, "")
+	require.Equal(t, http.StatusOK, code, resp)
+	assert.Equal(t, JobPending, status(resp).Status)
+
+	// Requests larger than the limit are rejected
+	jobAPI.MaxRequestSize = 4
+	code, resp = do("POST", "/jobs/execution/"+"4-5-getZkProof.json", "too large")
+	assert.Equal(t, http.StatusRequestEntityTooLarge, code, resp)
+	assert.NoFileExists(t, path.Join(eFrom, "4-5-getZkProof.json"))
+}
+
+func TestJobAPIAuthentication(t *testing.T) {
+
+	confM, _ := setupFsTest(t)
+
+	// The API does not start without a token
+	_, err := NewJobAPI(confM)
+	assert.Error(t, err)
+
+	emptyToken := path.Join(t.TempDir(), "empty-token")
+	require.NoError(t, os.WriteFile(emptyToken, []byte("\n"), 0600))
+	confM.Controller.API.TokenFile = emptyToken
+	_, err = NewJobAPI(confM)
+	assert.Error(t, err)
+
+	var (
+		srv  = httptest.NewServer(newTestJobAPI(t, confM).Handler())
+		file = "0-1-etv0.1.2-stv1.2.3-getZkProof.json"
+	)
+	defer srv.Close()
+
+	for _, header := range []string{"", "Bearer", "Bearer wrong-token", testAPIToken} {
+		req, err := http.NewRequest("POST", srv.URL+"/jobs/execution/"+file, strings.NewReader("exit 0"))
+		require.NoError(t, err)
+		if len(header) > 0 {
+			req.Header.Set("Authorization", header)
+		}
+		res, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		res.Body.Close()
+		assert.Equalf(t, http.StatusUnauthorized, res.StatusCode, "header %q", header)
+	}
+
+	assert.NoFileExists(t, path.Join(confM.Execution.DirFrom(), file))
+}
+
+func TestJobAPIConcurrentSubmissions(t *testing.T) {
+
+	confM, _ := setupFsTest(t)
+
+	var (
+		srv     = httptest.NewServer(newTestJobAPI(t, confM).Handler())
+		file    = "0-1-etv0.1.2-stv1.2.3-getZkProof.json"
+		codes   = make(chan int, 8)
+		wg      sync.WaitGroup
+		created int
+	)
+	defer srv.Close()
+
+	for i := 0; i < cap(codes); i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			req, err := http.NewRequest("POST", srv.URL+"/jobs/execution/"+file, strings.NewReader(fmt.Sprintf("exit %v", i)))
+			if !assert.NoError(t, err) {
+				return
+			}
+			req.Header.Set("Authorization", "Bearer "+testAPIToken)
+			res, err := http.DefaultClient.Do(req)
+			if !assert.NoError(t, err) {
+				return
+			}
+			res.Body.Close()
+			codes <- res.StatusCode
+		}(i)
+	}
+
+	wg.Wait()
+	close(codes)
+
+	// Only one of the submissions writes the request file, the other ones
+	// are rejected instead of overwriting it.
+	for code := range codes {
+		if code == http.StatusCreated {
+			created++
+			continue
+		}
+		assert.Equal(t, http.StatusConflict, code)
+	}
+	assert.Equal(t, 1, created)
+
+	dirents, err := os.ReadDir(confM.Execution.DirFrom())
+	require.NoError(t, err)
+	require.Len(t, dirents, 1, "the temporary files are removed")
+}
@@ -2,7 +2,11 @@ package controller
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"os"
+	"text/tabwriter"
+	"time"
 
 	"github.com/consensys/linea-monorepo/prover/config"
 	"github.com/sirupsen/logrus"
@@ -16,12 +20,34 @@ var rootCmd = &cobra.Command{
 	Run:   cobraControllerRunCmd,
 }
 
+// history represents the command to query the job history
+var historyCmd = &cobra.Command{
+	Use:   "history",
+	Short: "Query the history of the jobs run by the controllers",
+	Long: "Query the history of the jobs run by the controllers sharing the history directory " +
+		"of the configuration. The runs are listed by start time.",
+	Args: cobra.NoArgs,
+	RunE: cobraHistoryCmd,
+}
+
 // the arguments of the command
 var (
 	fConfig  string
 	fLocalID string
 )
 
+// the arguments of the history command
+var (
+	fHistConfig string
+	fHistType   string
+	fHistStatus string
+	fHistFrom   int
+	fHistTo     int
+	fHistSince  string
+	fHistUntil  string
+	fHistJSON   bool
+)
+
 // registers the arguments for the command
 func init() {
 	// mark the flags as required
@@ -29,6 +55,17 @@ func init() {
 	rootCmd.Flags().StringVar(&fLocalID, "local-id", "no-local-id-provided", "local ID of the controller container")
 	rootCmd.MarkFlagRequired("config")
 	rootCmd.MarkFlagRequired("local-id")
+
+	historyCmd.Flags().StringVar(&fHistConfig, "config", "", "config file")
+	historyCmd.Flags().StringVar(&fHistType, "type", "", "only the jobs of this type: execution, compression or aggregation")
+	historyCmd.Flags().StringVar(&fHistStatus, "status", "", "only the runs with this outcome: success, failure or deferred-to-large")
+	historyCmd.Flags().IntVar(&fHistFrom, "from", 0, "only the jobs whose range ends at or after this block")
+	historyCmd.Flags().IntVar(&fHistTo, "to", 0, "only the jobs whose range starts at or before this block")
+	historyCmd.Flags().StringVar(&fHistSince, "since", "", "only the runs started after this time, RFC3339 or a duration ago, e.g. 24h")
+	historyCmd.Flags().StringVar(&fHistUntil, "until", "", "only the runs started before this time, RFC3339 or a duration ago")
+	historyCmd.Flags().BoolVar(&fHistJSON, "json", false, "print the records as JSON lines")
+	historyCmd.MarkFlagRequired("config")
+	rootCmd.AddCommand(historyCmd)
 }
 
 // cobra command
@@ -68,6 +105,79 @@ func cobraControllerRunCmd(c *cobra.Command, args []string) {
 	runController(context.Background(), cfg)
 }
 
+// history command
+func cobraHistoryCmd(c *cobra.Command, args []string) error {
+
+	cfg, err := config.NewConfigFromFile(fHistConfig)
+	if err != nil {
+		return fmt.Errorf("could not get the config: %w", err)
+	}
+
+	if len(cfg.Controller.HistoryDir) == 0 {
+		return fmt.Errorf("the history is disabled in %v, set controller.history_dir", fHistConfig)
+	}
+
+	filter := HistoryFilter{
+		Type:   fHistType,
+		Status: fHistStatus,
+		From:   fHistFrom,
+		To:     fHistTo,
+	}
+
+	if filter.Since, err = parseTimeFlag(fHistSince); err != nil {
+		return fmt.Errorf("invalid --since: %w", err)
+	}
+	if filter.Until, err = parseTimeFlag(fHistUntil); err != nil {
+		return fmt.Errorf("invalid --until: %w", err)
+	}
+
+	records, err := ReadHistory(cfg.Controller.HistoryDir, filter)
+	if err != nil {
+		return err
+	}
+
+	if fHistJSON {
+		enc := json.NewEncoder(c.OutOrStdout())
+		for i := range records {
+			if err := enc.Encode(records[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	w := tabwriter.NewWriter(c.OutOrStdout(), 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "STARTED\tHOST\tCONTROLLER\tTYPE\tRANGE\tATTEMPT\tLARGE\tSTATUS\tCODE\tDURATION\tMAX RSS\tFILE")
+	for _, r := range records {
+		large := ""
+		switch {
+		case r.RetriedLarge:
+			large = "retried"
+		case r.Large:
+			large = "yes"
+		}
+		fmt.Fprintf(w, "%v\t%v\t%v\t%v\t%v-%v\t%v\t%v\t%v\t%v\t%v\t%.1fGiB\t%v\n",
+			r.StartedAt.Format(time.RFC3339), r.Host, r.LocalID, r.Type, r.Start, r.End,
+			r.Attempt, large, r.Status, r.ExitCode,
+			time.Duration(r.Duration*float64(time.Second)).Round(time.Second),
+			float64(r.MaxRSS)/(1<<30), r.File,
+		)
+	}
+	return w.Flush()
+}
+
+// Parses a time given either in RFC3339 or as a duration before now. The
+// empty string gives the zero time.
+func parseTimeFlag(s string) (time.Time, error) {
+	if len(s) == 0 {
+		return time.Time{}, nil
+	}
+	if d, err := time.ParseDuration(s); err == nil {
+		return time.Now().Add(-d), nil
+	}
+	return time.Parse(time.RFC3339, s)
+}
+
 // Execute the cobra root command
 func Execute() {
 	err := rootCmd.Execute()
@@ -2,26 +2,53 @@ package controller
 
 import (
 	"context"
-	"os"
+	"fmt"
 	"os/signal"
+	"sync"
 	"syscall"
 	"time"
 
 	"github.com/consensys/linea-monorepo/prover/cmd/controller/controller/metrics"
 	"github.com/consensys/linea-monorepo/prover/config"
 	"github.com/consensys/linea-monorepo/prover/utils"
-	"github.com/sirupsen/logrus"
 )
 
 // function to run the controller
 func runController(ctx context.Context, cfg *config.Config) {
 	var (
 		cLog          = cfg.Logger().WithField("component", "main-loop")
-		fsWatcher     = NewFsWatcher(cfg)
 		executor      = NewExecutor(cfg)
 		numRetrySoFar int
+		// The jobs run concurrently as long as the sum of their weights does
+		// not exceed the number of slots. When a job finishes, its weight is
+		// sent back in jobDone. The channel is large enough to never block
+		// the running jobs.
+		slots     = max(cfg.Controller.Slots, 1)
+		freeSlots = slots
+		jobDone   = make(chan int, slots)
+		running   sync.WaitGroup
+		// waitingFor is the job of the head of the queue that did not fit in
+		// the free slots at the last attempt, if any.
+		waitingFor *Job
+		fits       = func(job *Job) bool {
+			if job.Weight(cfg) > freeSlots {
+				waitingFor = job
+				return false
+			}
+			return true
+		}
 	)
 
+	source, err := NewJobSource(cfg)
+	if err != nil {
+		utils.Panic("could not create the job source: %v", err)
+	}
+
+	history, err := NewHistory(cfg)
+	if err != nil {
+		utils.Panic("could not open the job history: %v", err)
+	}
+
 	// Start the metric server
 	if cfg.Controller.Prometheus.Enabled {
 		metrics.StartServer(
@@ -31,6 +58,32 @@ func runController(ctx context.Context, cfg *config.Config) {
 		)
 	}
 
+	// Start the job API server. It only serves the requests directories.
+	var jobAPI *JobAPI
+	if cfg.Controller.API.Enabled && cfg.Controller.JobSource == config.JobSourceObjectStore {
+		cLog.Errorf("The job API is not available with the object store job source, not starting it")
+	} else if cfg.Controller.API.Enabled {
+		var err error
+		if jobAPI, err = NewJobAPI(cfg); err != nil {
+			cLog.Errorf("Not starting the job API: %v", err)
+		} else {
+			jobAPI.Start(cfg.Controller.API.Host, cfg.Controller.API.Port)
+		}
+	}
+
+	// Subscribe to the events of the queue if the source supports it. The
+	// polling is kept as a fallback.
+	var wakeup <-chan struct{}
+	if eventSource, ok := source.(EventSource); ok && cfg.Controller.WatchEvents {
+		ch, stopWatch, err := eventSource.Watch()
+		if err != nil {
+			cLog.Errorf("Could not watch the queue, falling back to polling: %v", err)
+		} else {
+			wakeup = ch
+			defer stopWatch()
+		}
+	}
+
 	ctx, stop := signal.NotifyContext(ctx, syscall.SIGTERM)
 	defer stop()
 
@@ -56,138 +109,138 @@ func runController(ctx context.Context, cfg *config.Config) {
 			// allows the ctx.Done channel to be read multiple times, which, in
 			// our scenario, ensures cancellation requests are effectively
 			// detected and handled.
-			cLog.Infoln("Context canceled by caller or SIGTERM. Exiting")
+			cLog.Infoln("Context canceled by caller or SIGTERM. Waiting for the running jobs to complete")
+			running.Wait()
+			cLog.Infoln("Exiting")
 			metrics.ShutdownServer(ctx)
+			if jobAPI != nil {
+				jobAPI.Shutdown(ctx)
+			}
 			return
 
-		// Processing a new job
-		case <-retryDelay(cfg.Controller.RetryDelays, numRetrySoFar):
-			// Fetch the best block we can fetch
-			job := fsWatcher.GetBest()
-
-			// No jobs, waiting a little before we retry
-			if job == nil {
-				numRetrySoFar++
-				noJobFoundMsg := "found no jobs in the queue"
-				if numRetrySoFar > 5 {
-					cLog.Debugf(noJobFoundMsg)
-				} else {
-					cLog.Infof(noJobFoundMsg)
-				}
-				continue
-			}
+		// A job finished and freed its slots
+		case weight := <-jobDone:
+			freeSlots += weight
 
-			// Else, reset the retry counter
-			numRetrySoFar = 0
-
-			// Run the command (potentially retrying in large mode)
-			status := executor.Run(job)
-
-			// createColumns the job according to the status we got
-			switch {
-
-			// Success
-			case status.ExitCode == CodeSuccess:
-				// NB: we already check that the response filename can be
-				// generated prior to running the command. So this actually
-				// will not panic.
-				respFile, err := job.ResponseFile()
-				tmpRespFile := job.TmpResponseFile(cfg)
-				if err != nil {
-					formatStr := "Could not generate the response file: %v (original request file: %v)"
-					utils.Panic(formatStr, err, job.OriginalFile)
-				}
-
-				logrus.Infof(
-					"Moving the response file from the tmp response file `%v`, to the final response file: `%v`",
-					tmpRespFile, respFile,
-				)
+		// A new request was written in the queue
+		case <-wakeup:
+			cLog.Debugf("woken up by a new request in the queue")
 
-				if err := os.Rename(tmpRespFile, respFile); err != nil {
-					// @Alex: it is unclear how the rename operation could fail
-					// here. If this happens, we prefer removing the tmp file.
-					// Note that the operation is an `mv -f`
-					os.Remove(tmpRespFile)
-
-					cLog.Errorf(
-						"Error renaming %v to %v: %v, removed the tmp file",
-						tmpRespFile, respFile, err,
-					)
-				}
-
-				// Move the inprogress to the done directory
-				cLog.Infof(
-					"Moving %v to %v with the success prefix",
-					job.OriginalFile, job.Def.dirDone(),
-				)
+		// Polling the queue
+		case <-pollQueue(cfg.Controller.RetryDelays, numRetrySoFar, freeSlots):
+		}
 
-				jobDone := job.DoneFile(status)
-				if err := os.Rename(job.InProgressPath(), jobDone); err != nil {
-					// When that happens, the only thing left to do is to log
-					// the error and let the inprogress file where it is. It
-					// will likely require a human intervention.
-					cLog.Errorf(
-						"Error renaming %v to %v: %v",
-						job.InProgressPath(), jobDone, err,
-					)
-				}
-
-			// Defer to the large prover
-			case job.Def.Name == jobNameExecution && isIn(status.ExitCode, cfg.Controller.DeferToOtherLargeCodes):
-				cLog.Infof("Renaming %v for the large prover", job.OriginalFile)
-				// Move the inprogress file back in the from directory with
-				// the new suffix
-				toLargePath, err := job.DeferToLargeFile(status)
-				if err != nil {
-					// There are two possibilities of errors. (1), the status
-					// we success but the above cases prevents that. The other
-					// case is that the suffix was not provided. But, during
-					// the config validation, we check already that the suffix
-					// must be provided if the size of the list of
-					// deferToOtherLargeCodes is non-zero. If the size of the
-					// list was zero, then there would be no way to reach this
-					// portion of the code given that the current exit code
-					// cannot be part of the empty list. Thus, this section is
-					// unreachable.
-					cLog.Errorf(
-						"error deriving the to-large-name of %v: %v",
-						job.InProgressPath(), err,
-					)
-				}
-
-				if err := os.Rename(job.InProgressPath(), toLargePath); err != nil {
-					// When that happens, the only thing left to do is to log
-					// the error and let the inprogress file where it is. It
-					// will likely require a human intervention.
-					cLog.Errorf(
-						"error renaming %v to %v: %v",
-						job.InProgressPath(), toLargePath, err,
-					)
-				}
-
-			// Failure case
-			default:
-				// Move the inprogress to the done directory
-				cLog.Infof(
-					"Moving %v with in %v with a failure suffix for code %v",
-					job.OriginalFile, job.Def.dirDone(), status.ExitCode,
-				)
+		if freeSlots == 0 {
+			continue
+		}
+
+		// Fetch the best block we can fetch
+		waitingFor = nil
+		job := getBest(source, cLog, fits)
 
-				jobFailed := job.DoneFile(status)
-				if err := os.Rename(job.InProgressPath(), jobFailed); err != nil {
-					// When that happens, the only thing left to do is to log
-					// the error and let the inprogress file where it is. It
-					// will likely require a human intervention.
-					cLog.Errorf(
-						"Error renaming %v to %v: %v",
-						job.InProgressPath(), jobFailed, err,
-					)
-				}
+		// No jobs, or the next job does not fit in the free slots, waiting a
+		// little before we retry. The message is only logged once per idle
+		// period.
+		if job == nil {
+			numRetrySoFar++
+			noJobFoundMsg := "found no jobs in the queue"
+			if waitingFor != nil {
+				noJobFoundMsg = fmt.Sprintf(
+					"waiting for slots: %v needs %v slots, %v/%v slots left",
+					waitingFor.OriginalFile, waitingFor.Weight(cfg), freeSlots, slots,
+				)
 			}
+			if numRetrySoFar > 1 {
+				cLog.Debug(noJobFoundMsg)
+			} else {
+				cLog.Info(noJobFoundMsg)
+			}
+			continue
+		}
+
+		// Else, reset the retry counter
+		numRetrySoFar = 0
+
+		weight := job.Weight(cfg)
+		freeSlots -= weight
+		cLog.Infof("Running %v on %v slots, %v/%v slots left", job.OriginalFile, weight, freeSlots, slots)
+
+		running.Add(1)
+		go func() {
+			defer running.Done()
+			runJob(cfg, source, executor, history, job)
+			jobDone <- weight
+		}()
+	}
+}
+
+// Runs a locked job, reports its status to the source and records the run in
+// the history if it is enabled.
+func runJob(cfg *config.Config, source JobSource, executor *Executor, history *History, job *Job) {
+
+	cLog := cfg.Logger().WithField("component", "main-loop")
+
+	// Run the command (potentially retrying in large mode), unless the job is
+	// predicted to overflow the limits of this prover in which case it is
+	// deferred to the large prover right away. The lock is kept alive in the
+	// meantime.
+	var (
+		startedAt      = time.Now()
+		stopHeartbeat  = source.StartHeartbeat(job)
+		predictedLarge = executor.PredictLarge(job)
+		status         Status
+	)
+
+	if predictedLarge {
+		status = Status{ExitCode: CodeTraceLimit, What: "traces predicted to overflow"}
+	} else {
+		status = executor.Run(job)
+	}
+	stopHeartbeat()
+
+	// createColumns the job according to the status we got
+	var (
+		err     error
+		outcome string
+	)
+	switch {
+
+	// Success
+	case status.ExitCode == CodeSuccess:
+		outcome, err = JobSuccess, source.Complete(job, status)
+
+	// Defer to the large prover
+	case predictedLarge:
+		outcome, err = JobDeferredToLarge, source.DeferToLarge(job, status)
+	case job.Def.Name == jobNameExecution && isIn(status.ExitCode, cfg.Controller.DeferToOtherLargeCodes):
+		outcome, err = JobDeferredToLarge, source.DeferToLarge(job, status)
+
+	// Failure case
+	default:
+		outcome, err = JobFailure, source.Fail(job, status)
+	}
+
+	if err != nil {
+		cLog.Errorf("Error reporting the status of %v: %v", job.OriginalFile, err)
+	}
+
+	if history != nil {
+		if err := history.Record(job, status, outcome, startedAt); err != nil {
+			cLog.Errorf("Could not record the run of %v in the history: %v", job.OriginalFile, err)
 		}
 	}
 }
 
+// Returns the channel on which to wait before polling the queue again. There
+// is no need to poll when all the slots are busy: the loop waits for a job to
+// complete instead.
+func pollQueue(retryDelaysSec []int, numRetrySoFar, freeSlots int) <-chan time.Time {
+	if freeSlots == 0 {
+		return nil
+	}
+	return retryDelay(retryDelaysSec, numRetrySoFar)
+}
+
 // Returns the duration to wait before retrying to find a job in the queue. This
 // is to avoid spamming the FS with LS queries.
 func retryDelay(retryDelaysSec []int, numRetrySoFar int) <-chan time.Time {
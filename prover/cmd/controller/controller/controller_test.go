@@ -275,6 +275,52 @@ func TestFileWatcherL(t *testing.T) {
 	assert.Nil(t, fw.GetBest(), "the queue should be empty now")
 }
 
+func TestSlots(t *testing.T) {
+
+	confM, _ := setupFsTest(t)
+	confM.Controller.Slots = 4
+	confM.Controller.ExecutionWeight = 1
+	confM.Controller.BlobDecompressionWeight = 3
+
+	var (
+		eFrom  = confM.Execution.DirFrom()
+		cFrom  = confM.BlobDecompression.DirFrom()
+		exit0  = 0
+		logger = confM.Logger().WithField("component", "test")
+		fw     = NewFsWatcher(confM)
+	)
+
+	// The compression job does not fit in the two remaining slots, the
+	// execution job behind it must not overtake it.
+	createTestInputFile(cFrom, 0, 2, compressionJob, exit0)
+	createTestInputFile(eFrom, 2, 3, execJob, exit0)
+	assert.Nil(t, getBest(fw, logger, func(j *Job) bool { return j.Weight(confM) <= 2 }))
+
+	job := getBest(fw, logger, func(j *Job) bool { return j.Weight(confM) <= 4 })
+	if assert.NotNil(t, job) {
+		assert.Equal(t, jobNameBlobDecompression, job.Def.Name)
+	}
+	job = getBest(fw, logger, func(j *Job) bool { return j.Weight(confM) <= 1 })
+	if assert.NotNil(t, job) {
+		assert.Equal(t, jobNameExecution, job.Def.Name)
+	}
+
+	// Four execution jobs of one second each run concurrently
+	for i := 10; i < 14; i++ {
+		fname := createTestInputFile(eFrom, i, i+1, execJob, exit0)
+		require.NoError(t, os.WriteFile(path.Join(eFrom, fname), []byte("#!/bin/sh\nsleep 1\nexit 0"), 0600))
+	}
+
+	ctx, stop := context.WithCancel(context.Background())
+	go runController(ctx, confM)
+	<-time.After(2500 * time.Millisecond)
+	stop()
+
+	responses, err := os.ReadDir(confM.Execution.DirTo())
+	require.NoError(t, err)
+	assert.Len(t, responses, 4)
+}
+
 func setupFsTest(t *testing.T) (confM, confL *config.Config) {
 
 	// Testdir is going to contain the whole test directory
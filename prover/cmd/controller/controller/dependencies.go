@@ -0,0 +1,155 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"sync"
+	"time"
+
+	"github.com/consensys/linea-monorepo/prover/backend/aggregation"
+	"github.com/consensys/linea-monorepo/prover/config"
+	"github.com/sirupsen/logrus"
+)
+
+// Returns true if the jobs of the definition depend on the responses of other
+// jobs. This is the case of the aggregation jobs which aggregate the proofs
+// of the execution and of the decompression jobs.
+func hasDependencies(jdef *JobDefinition) bool {
+	return jdef.Name == jobNameAggregation
+}
+
+// dependency is a response file a request refers to
+type dependency struct {
+	// Name of the job definition producing the response
+	jobName string
+	// Local directory in which the prover looks for the response
+	dir string
+	// Name of the response file, as written in the request
+	file string
+}
+
+// Parses an aggregation request and returns the response files it refers to.
+func requestDependencies(conf *config.Config, r io.Reader) ([]dependency, error) {
+
+	req := aggregation.Request{}
+	if err := json.NewDecoder(r).Decode(&req); err != nil {
+		return nil, fmt.Errorf("could not parse the aggregation request: %w", err)
+	}
+
+	// The directories are the ones in which the aggregation prover looks for
+	// the responses, see collectFields in the aggregation package.
+	deps := []dependency{}
+	for _, file := range req.ExecutionProofs {
+		deps = append(deps, dependency{jobName: jobNameExecution, dir: conf.Execution.DirTo(), file: file})
+	}
+	for _, file := range req.DecompressionProofs {
+		deps = append(deps, dependency{jobName: jobNameBlobDecompression, dir: conf.BlobDecompression.DirTo(), file: file})
+	}
+
+	return deps, nil
+}
+
+// Parses the aggregation request file and returns the response files it
+// refers to that are not in the responses directories yet. They are returned
+// as written in the request, i.e. relative to the responses directory of
+// their job type. An error is returned if the request cannot be parsed; in
+// that case, the job should not be held back as it will fail anyway.
+func missingDependencies(conf *config.Config, requestFile string) (missing []string, err error) {
+
+	f, err := os.Open(requestFile)
+	if err != nil {
+		return nil, fmt.Errorf("could not open the request %v: %w", requestFile, err)
+	}
+	defer f.Close()
+
+	deps, err := requestDependencies(conf, f)
+	if err != nil {
+		return nil, fmt.Errorf("%v: %w", requestFile, err)
+	}
+
+	for _, dep := range deps {
+		_, err := os.Stat(path.Join(dep.dir, dep.file))
+		if errors.Is(err, fs.ErrNotExist) {
+			missing = append(missing, dep.file)
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("could not check the response %v: %w", dep.file, err)
+		}
+	}
+
+	return missing, nil
+}
+
+// blockedJobs tracks since when the jobs of the queue are held back by
+// missing responses, so that a response that never arrives is reported
+// instead of holding the job back silently. The zero value is ready to use.
+type blockedJobs struct {
+	mu sync.Mutex
+	// The blocked jobs, indexed by job type and request file
+	jobs map[string]map[string]*blockedJob
+}
+
+type blockedJob struct {
+	// Time at which the job was first found blocked
+	since time.Time
+	// Whether the job was already reported as blocked for too long
+	warned bool
+}
+
+// update records the jobs of a job type found blocked by the latest listing,
+// with the responses they are missing, and forgets the ones that are not
+// blocked anymore. The jobs blocked for longer than warnDelay are reported
+// once with a warning; zero disables the warnings. Returns the time for which
+// the oldest blocked job has been waiting.
+func (b *blockedJobs) update(
+	logger *logrus.Entry,
+	jobType string,
+	blocked map[string][]string,
+	warnDelay time.Duration,
+) (maxWait time.Duration) {
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.jobs == nil {
+		b.jobs = map[string]map[string]*blockedJob{}
+	}
+
+	var (
+		now  = time.Now()
+		prev = b.jobs[jobType]
+		curr = make(map[string]*blockedJob, len(blocked))
+	)
+
+	for file, missing := range blocked {
+
+		job, ok := prev[file]
+		if !ok {
+			job = &blockedJob{since: now}
+		}
+		curr[file] = job
+
+		wait := now.Sub(job.since)
+		maxWait = max(maxWait, wait)
+
+		if warnDelay > 0 && wait > warnDelay && !job.warned {
+			job.warned = true
+			logger.Warnf(
+				"The job %v has been blocked for %v on %v missing responses: %v",
+				file, wait.Round(time.Second), len(missing), missing,
+			)
+			continue
+		}
+
+		logger.Debugf("The job %v is blocked on %v missing responses: %v", file, len(missing), missing)
+	}
+
+	b.jobs[jobType] = curr
+	return maxWait
+}
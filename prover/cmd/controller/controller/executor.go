@@ -5,6 +5,7 @@ import (
 	"os"
 	"os/exec"
 	"strings"
+	"sync/atomic"
 	"syscall"
 	"time"
 
@@ -24,6 +25,7 @@ const (
 	CodeOom            int = 137 // When the process exits on OOM
 	CodeFatal          int = 14  // When the process could not start
 	CodeCantRunCommand int = 15  // When the controller could not run the command
+	CodeTimeout        int = 124 // When the controller killed the process on timeout
 )
 
 // Status of a finished job
@@ -34,6 +36,13 @@ type Status struct {
 	What string
 	// Additional errors for context
 	Err error
+	// Set if the status is the one of a run of the large command
+	Large bool
+	// Set if the large command was run as a local retry after a first run
+	// failed with one of the `retry_locally_with_large_codes`
+	RetriedLarge bool
+	// Resources used by the prover process, zero if it could not run
+	Usage Usage
 }
 
 // Resource collects all the informations about the job that can be used to
@@ -43,6 +52,8 @@ type Resource struct {
 	ConfFile string
 	// The input and output file paths
 	InFile, OutFile string
+	// The type of the job, as expected by the --job-type flag of the prover
+	JobType string
 }
 
 // The executor is responsible for running the commands specified by the jobs
@@ -86,7 +97,9 @@ func (e *Executor) Run(job *Job) (status Status) {
 		}
 	}
 
-	status = runCmd(cmd, job, false)
+	timeout, gracePeriod := e.timeout(job, largeRun)
+	status = runCmd(cmd, job, false, timeout, gracePeriod)
+	status.Large = largeRun
 
 	// if it's a blob decompression or aggregation, we never retry with a large
 	// command. We can return the status as is.
@@ -120,7 +133,34 @@ func (e *Executor) Run(job *Job) (status Status) {
 	}
 
 	// And escalates the return whatever the return value is.
-	return runCmd(cmd, job, true)
+	timeout, gracePeriod = e.timeout(job, true)
+	status = runCmd(cmd, job, true, timeout, gracePeriod)
+	status.Large, status.RetriedLarge = true, true
+	return status
+}
+
+// Returns the wall-clock timeout of a job and the grace period between the
+// SIGTERM and the SIGKILL. A zero timeout means that the job can run
+// indefinitely.
+func (e *Executor) timeout(job *Job, large bool) (timeout, gracePeriod time.Duration) {
+
+	var (
+		ctrl    = &e.Config.Controller
+		seconds int
+	)
+
+	switch {
+	case job.Def.Name == jobNameExecution && large:
+		seconds = ctrl.ExecutionLargeTimeout
+	case job.Def.Name == jobNameExecution:
+		seconds = ctrl.ExecutionTimeout
+	case job.Def.Name == jobNameBlobDecompression:
+		seconds = ctrl.BlobDecompressionTimeout
+	case job.Def.Name == jobNameAggregation:
+		seconds = ctrl.AggregationTimeout
+	}
+
+	return time.Duration(seconds) * time.Second, time.Duration(ctrl.KillGracePeriod) * time.Second
 }
 
 // Builds a command from a template to run, returns a status if it failed
@@ -148,6 +188,7 @@ func (e *Executor) buildCmd(job *Job, large bool) (cmd string, err error) {
 		ConfFile: fConfig,
 		InFile:   job.InProgressPath(),
 		OutFile:  outFile,
+		JobType:  job.Def.ProverJobType,
 	}
 
 	// Build the command and args from the job
@@ -167,8 +208,10 @@ func (e *Executor) buildCmd(job *Job, large bool) (cmd string, err error) {
 }
 
 // Run a command and returns the status. Retry gives an indication on whether
-// this is a local retry or not.
-func runCmd(cmd string, job *Job, retry bool) Status {
+// this is a local retry or not. If the timeout is non-zero and the command
+// runs for longer, the process group of the command receives a SIGTERM and
+// then a SIGKILL after the grace period. The status code is then CodeTimeout.
+func runCmd(cmd string, job *Job, retry bool, timeout, gracePeriod time.Duration) Status {
 
 	// Split the command into a list of argvs that can be passed to the os
 	// package.
@@ -205,6 +248,9 @@ func runCmd(cmd string, job *Job, retry bool) Status {
 				os.Stdout,
 				os.Stderr,
 			},
+			// The command runs in its own process group so that the signals
+			// sent on timeout also reach the processes spawned by the shell.
+			Sys: &syscall.SysProcAttr{Setpgid: true},
 		},
 	)
 
@@ -222,8 +268,37 @@ func runCmd(cmd string, job *Job, retry bool) Status {
 		}
 	}
 
+	var (
+		done     = make(chan struct{})
+		timedOut atomic.Bool
+	)
+
+	if timeout > 0 {
+		go func() {
+			select {
+			case <-done:
+				return
+			case <-time.After(timeout):
+			}
+
+			timedOut.Store(true)
+			logrus.Warnf("process %v timed out after %v, sending a SIGTERM", pname, timeout)
+			signalGroup(curProcess, syscall.SIGTERM)
+
+			select {
+			case <-done:
+				return
+			case <-time.After(gracePeriod):
+			}
+
+			logrus.Errorf("process %v did not exit %v after the SIGTERM, sending a SIGKILL", pname, gracePeriod)
+			signalGroup(curProcess, syscall.SIGKILL)
+		}()
+	}
+
 	// Lock on the process until it finishes
 	pstate, err := curProcess.Wait()
+	close(done)
 	if err != nil {
 		// Here it means, the "os" package could not start the process. It
 		// can happen for many different reasons essentially pertaining to
@@ -240,6 +315,7 @@ func runCmd(cmd string, job *Job, retry bool) Status {
 	}
 
 	processingTime := time.Since(startTime)
+	usage := usageOf(pstate)
 	exitCode, err := unixExitCode(pstate)
 	if err != nil {
 		// NB: this would be only possible if we did not wait for the process
@@ -248,12 +324,23 @@ func runCmd(cmd string, job *Job, retry bool) Status {
 	}
 
 	logrus.Infof(
-		"The processing of file `%s` (process=%v) took %v seconds to complete and returned exit code %v",
+		"The processing of file `%s` (process=%v) took %v seconds to complete and returned exit code %v. "+
+			"Peak RSS %v MiB, CPU time %v user %v sys, %v major page faults",
 		job.OriginalFile, pname, processingTime.Seconds(), exitCode,
+		usage.MaxRSS>>20, usage.UserTime.Round(time.Second), usage.SysTime.Round(time.Second), usage.MajorFaults,
 	)
 
+	// The exit code of a process killed on timeout is whatever the signal
+	// produces. It is replaced so that it is not mistaken for an OOM. The
+	// processes of the group that survived the SIGTERM are killed as the
+	// shell may have exited without waiting for them.
+	if timedOut.Load() {
+		exitCode = CodeTimeout
+		signalGroup(curProcess, syscall.SIGKILL)
+	}
+
 	// Build the  response status
-	status := Status{ExitCode: exitCode}
+	status := Status{ExitCode: exitCode, Usage: usage}
 	switch status.ExitCode {
 	case CodeSuccess:
 		status.What = "success"
@@ -261,9 +348,15 @@ func runCmd(cmd string, job *Job, retry bool) Status {
 		status.What = "out of memory error"
 	case CodeTraceLimit:
 		status.What = "trace limit overflow"
+	case CodeTimeout:
+		status.What = "timeout"
 	}
 
 	metrics.CollectPostProcess(job.Def.Name, status.ExitCode, processingTime, retry)
+	metrics.CollectUsage(
+		job.Def.Name, status.ExitCode, retry,
+		usage.MaxRSS, usage.UserTime, usage.SysTime, usage.MajorFaults,
+	)
 
 	return status
 }
@@ -279,6 +372,14 @@ func processName(job *Job, cmd string) string {
 	)
 }
 
+// Sends a signal to the process group of the process. The errors are only
+// logged: they mean that the process has already exited.
+func signalGroup(proc *os.Process, sig syscall.Signal) {
+	if err := syscall.Kill(-proc.Pid, sig); err != nil {
+		logrus.Infof("could not send %v to the process group %v: %v", sig, proc.Pid, err)
+	}
+}
+
 // Returns true if the x is included in the given list
 func isIn[T comparable](x T, list []T) bool {
 	for _, y := range list {
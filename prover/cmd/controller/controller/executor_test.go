@@ -1,11 +1,19 @@
 package controller
 
 import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path"
+	"strconv"
 	"testing"
 	"text/template"
+	"time"
 
 	"github.com/consensys/linea-monorepo/prover/config"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sys/unix"
 )
 
 func TestRetryWithLarge(t *testing.T) {
@@ -97,3 +105,115 @@ func TestRetryWithLarge(t *testing.T) {
 		assert.Equalf(t, jobs[i].ExpCode, status.ExitCode, "got status %++v", status)
 	}
 }
+
+func TestTimeout(t *testing.T) {
+
+	var testDefinition = JobDefinition{
+		Name: jobNameBlobDecompression,
+		OutputFileTmpl: template.Must(
+			template.New("output-file").
+				Parse("output-fill-constant"),
+		),
+		RequestsRootDir: "./testdata",
+	}
+
+	e := NewExecutor(&config.Config{
+		Controller: config.Controller{
+			WorkerCmdTmpl: template.Must(
+				template.New("test-cmd").
+					Parse("/bin/sh {{.InFile}}"),
+			),
+			BlobDecompressionTimeout: 1,
+			KillGracePeriod:          1,
+		},
+	})
+
+	// The first script exits on SIGTERM, the second one ignores it and has to
+	// be killed with a SIGKILL after the grace period.
+	for i, file := range []string{"sleep.sh", "sleep-ignore-sigterm.sh"} {
+		job := Job{
+			Def:        &testDefinition,
+			LockedFile: file,
+			Start:      i,
+			End:        i,
+		}
+
+		start := time.Now()
+		status := e.Run(&job)
+		assert.Equalf(t, CodeTimeout, status.ExitCode, "got status %++v", status)
+		assert.Less(t, time.Since(start), 10*time.Second)
+	}
+}
+
+func TestUsage(t *testing.T) {
+
+	confM, _ := setupFsTest(t)
+
+	var (
+		eFrom     = confM.Execution.DirFrom()
+		fsWatcher = NewFsWatcher(confM)
+		e         = NewExecutor(confM)
+	)
+
+	// The prover allocates a few MiB and burns some CPU
+	fname := createTestInputFile(eFrom, 0, 1, execJob, 0)
+	script := "#!/bin/sh\nx=$(head -c 8000000 /dev/zero | tr '\\0' 'a')\nexit 0"
+	require.NoError(t, os.WriteFile(path.Join(eFrom, fname), []byte(script), 0600))
+
+	job := fsWatcher.GetBest()
+	require.NotNil(t, job)
+
+	status := e.Run(job)
+	require.Equal(t, CodeSuccess, status.ExitCode)
+	assert.Greater(t, status.Usage.MaxRSS, int64(8<<20))
+	assert.Greater(t, status.Usage.UserTime+status.Usage.SysTime, time.Duration(0))
+
+	// The usage is attached to the done file
+	require.NoError(t, fsWatcher.Complete(job, status))
+
+	content, err := os.ReadFile(job.DoneMetadataFile(status))
+	require.NoError(t, err)
+	metadata := map[string]string{}
+	require.NoError(t, json.Unmarshal(content, &metadata))
+	assert.Equal(t, strconv.FormatInt(status.Usage.MaxRSS, 10), metadata["max-rss-bytes"])
+
+	buf := make([]byte, 64)
+	n, err := unix.Getxattr(job.DoneFile(status), xattrPrefix+"max-rss-bytes", buf)
+	if errors.Is(err, unix.ENOTSUP) {
+		t.Skip("the filesystem does not support extended attributes")
+	}
+	require.NoError(t, err)
+	assert.Equal(t, strconv.FormatInt(status.Usage.MaxRSS, 10), string(buf[:n]))
+}
+
+// The commands pass the type of the job to the prover instead of letting it
+// derive the type from the name of the request file.
+func TestBuildCmdJobType(t *testing.T) {
+
+	confM, _ := setupFsTest(t)
+	confM.Controller.WorkerCmdTmpl = template.Must(template.New("test-cmd").
+		Parse("prover prove --in {{.InFile}} --job-type {{.JobType}} --traces-profile normal"))
+
+	var (
+		e     = NewExecutor(confM)
+		cases = []struct {
+			Def      JobDefinition
+			File     string
+			Expected string
+		}{
+			{ExecutionDefinition(confM), "0-1-etv0.1.2-stv1.2.3-getZkProof.json", "execution"},
+			{CompressionDefinition(confM), "0-1-bcv0.1.2-ccv0.1.2-getZkBlobCompressionProof.json", "blob-decompression"},
+			{AggregatedDefinition(confM), "0-1-deadbeef57-getZkAggregatedProof.json", "aggregation"},
+		}
+	)
+
+	for i := range cases {
+
+		job, err := NewJob(&cases[i].Def, cases[i].File)
+		require.NoError(t, err)
+
+		cmd, err := e.buildCmd(job, false)
+		require.NoError(t, err)
+		assert.Contains(t, cmd, "--job-type "+cases[i].Expected+" --traces-profile normal")
+	}
+}
@@ -2,17 +2,21 @@
 package controller
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"io/fs"
 	"os"
 	"path"
+	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/consensys/linea-monorepo/prover/cmd/controller/controller/metrics"
 	"github.com/consensys/linea-monorepo/prover/config"
+	"github.com/consensys/linea-monorepo/prover/utils"
+	"github.com/fsnotify/fsnotify"
 	"github.com/sirupsen/logrus"
-	"golang.org/x/exp/slices"
 )
 
 // FsWatcher is a struct who will watch the filesystem and return files as if
@@ -24,34 +28,51 @@ type FsWatcher struct {
 	JobToWatch []JobDefinition
 	// Suffix to append to the end of a file
 	InProgress string
+	// Period at which the modification time of a locked file is refreshed.
+	// Zero disables the heartbeat.
+	HeartbeatPeriod time.Duration
+	// Age of the heartbeat after which a locked file is considered stale and
+	// put back in the queue. Zero disables the recovery of stale locks.
+	StaleLockTTL time.Duration
+	// Time during which a request file must have been left unmodified before
+	// it is locked. Zero disables the check.
+	SettleDelay time.Duration
+	// Time after which a job still waiting for the responses it depends on
+	// is reported with a warning. Zero disables the warning.
+	BlockedWarnDelay time.Duration
 	// Logger specific to the file watcher
 	Logger *logrus.Entry
+	// Configuration of the controller, used to locate the response files
+	Config *config.Config
+
+	blocked blockedJobs
 }
 
 func NewFsWatcher(conf *config.Config) *FsWatcher {
 	fs := &FsWatcher{
-		LocalID:    conf.Controller.LocalID,
-		InProgress: config.InProgressSufix,
-		Logger:     conf.Logger().WithField("component", "filesystem-watcher"),
-	}
-
-	if conf.Controller.EnableExecution {
-		fs.JobToWatch = append(fs.JobToWatch, ExecutionDefinition(conf))
-	}
-
-	if conf.Controller.EnableBlobDecompression {
-		fs.JobToWatch = append(fs.JobToWatch, CompressionDefinition(conf))
-	}
-
-	if conf.Controller.EnableAggregation {
-		fs.JobToWatch = append(fs.JobToWatch, AggregatedDefinition(conf))
+		LocalID:          conf.Controller.LocalID,
+		InProgress:       config.InProgressSufix,
+		HeartbeatPeriod:  time.Duration(conf.Controller.HeartbeatPeriod) * time.Second,
+		StaleLockTTL:     time.Duration(conf.Controller.StaleLockTTL) * time.Second,
+		SettleDelay:      time.Duration(conf.Controller.RequestSettleDelay) * time.Second,
+		BlockedWarnDelay: time.Duration(conf.Controller.BlockedWarnDelay) * time.Second,
+		Logger:           conf.Logger().WithField("component", "filesystem-watcher"),
+		Config:           conf,
 	}
 
+	fs.JobToWatch = enabledDefinitions(conf)
 	return fs
 }
 
-// Returns the list of jobs to perform by priorities. If no
+// Returns the list of jobs to perform by priorities. If no job could be
+// locked, returns nil.
 func (fs *FsWatcher) GetBest() (job *Job) {
+	return getBest(fs, fs.Logger, nil)
+}
+
+// Candidates returns the jobs found in the requests directories of the job
+// definitions to watch. Implements [JobSource].
+func (fs *FsWatcher) Candidates() []*Job {
 
 	// Fetches the full job list from all three directories. The fetching
 	// operation will not ignore files if they are not in the expected
@@ -80,39 +101,7 @@ func (fs *FsWatcher) GetBest() (job *Job) {
 		}
 	}
 
-	if len(jobs) == 0 {
-		fs.Logger.Debugf("The queue is empty")
-		return nil
-	}
-
-	// Sort the jobs by scores in ascending order. Lower scores mean more
-	// priority.
-	slices.SortStableFunc(jobs, func(a, b *Job) int {
-		return a.Score() - b.Score()
-	})
-
-	best, success := fs.lockBest(jobs)
-	if !success {
-		fs.Logger.Infof(
-			"Found %v jobs in the queue. They were all locked before we could pick one",
-			len(jobs),
-		)
-		return nil
-	}
-
-	return jobs[best]
-}
-
-// Returns the best file that we could lock and its position in the slice. If
-// everything failed returns 0, false.
-func (f *FsWatcher) lockBest(jobs []*Job) (pos int, success bool) {
-	for pos := range jobs {
-		if f.tryLockFile(jobs[pos]) {
-			return pos, true
-		}
-	}
-
-	return 0, false
+	return jobs
 }
 
 // Try appending a list of jobs that are parsed from a given directory. An error
@@ -127,7 +116,7 @@ func (fs *FsWatcher) appendJobFromDef(jdef *JobDefinition, jobs *[]*Job) (err er
 	if err != nil {
 		return fmt.Errorf("cannot ls `%s` : %v", dirFrom, err)
 	}
-	numMatched := 0
+	numMatched, blocked := 0, map[string][]string{}
 
 	// Search and append the valid files into the list.
 	for _, dirent := range dirents {
@@ -140,29 +129,66 @@ func (fs *FsWatcher) appendJobFromDef(jdef *JobDefinition, jobs *[]*Job) (err er
 			continue
 		}
 
+		name := dirent.Name()
+
+		// If the file is a stale lock, it is put back in the queue under its
+		// original name and can be picked up right away.
+		if orig, reclaimed := fs.reclaimIfStale(jdef, dirent); reclaimed {
+			name = orig
+		}
+
 		// Attempt to construct a job from the filename. If the filename is
 		// not parseable to the target JobType, it will return an error.
-		job, err := NewJob(jdef, dirent.Name())
+		job, err := NewJob(jdef, name)
 		if err != nil {
 			fs.Logger.Debugf("Found invalid file  `%v` : %v", dirent.Name(), err)
 			continue
 		}
 
+		numMatched++
+
+		// The job is held back until the responses it depends on are
+		// available. Otherwise, it would fail.
+		if missing := fs.missingResponses(job); len(missing) > 0 {
+			blocked[job.OriginalFile] = missing
+			continue
+		}
+
 		// If all the checks passes, we append the filename to the list of the
 		// clean ones.
 		*jobs = append(*jobs, job)
-		numMatched++
 	}
 
 	// Pass prometheus metrics
 	metrics.CollectFS(jdef.Name, len(dirents), numMatched)
+	if hasDependencies(jdef) {
+		maxWait := fs.blocked.update(fs.Logger, jdef.Name, blocked, fs.BlockedWarnDelay)
+		metrics.CollectBlocked(jdef.Name, len(blocked), maxWait)
+	}
 
 	return nil
 }
 
-// Trylock attempts to rename a file by adding an IN_PROGRESS suffix. The lock
-// operation is atomic only on Unix systems.
-func (fs *FsWatcher) tryLockFile(job *Job) (success bool) {
+// Returns the responses the job depends on that are not available yet. The
+// job is blocked if there are any.
+func (fs *FsWatcher) missingResponses(job *Job) []string {
+
+	if !hasDependencies(job.Def) {
+		return nil
+	}
+
+	missing, err := missingDependencies(fs.Config, path.Join(job.Def.dirFrom(), job.OriginalFile))
+	if err != nil {
+		fs.Logger.Warnf("Could not check the dependencies of %v, not waiting for them: %v", job.OriginalFile, err)
+		return nil
+	}
+
+	return missing
+}
+
+// Lock attempts to rename a file by adding an IN_PROGRESS suffix. The lock
+// operation is atomic only on Unix systems. Implements [JobSource].
+func (fs *FsWatcher) Lock(job *Job) (success bool) {
 
 	dirName := job.Def.dirFrom()
 	lockedFile := strings.Join(
@@ -173,6 +199,31 @@ func (fs *FsWatcher) tryLockFile(job *Job) (success bool) {
 		}, ".")
 	old := path.Join(dirName, job.OriginalFile)
 	new := path.Join(dirName, lockedFile)
+
+	// Refresh the modification time of the file before locking it as it
+	// serves as the initial heartbeat of the lock. Otherwise, the lock could
+	// be immediately considered stale by the other controllers. If this fails,
+	// the file is likely gone and the rename below will fail too.
+	//
+	// The modification time before the refresh is the time at which the
+	// request was written and gives the pickup latency.
+	var writtenAt time.Time
+	if finfo, err := os.Stat(old); err == nil {
+		writtenAt = finfo.ModTime()
+	}
+
+	now := time.Now()
+
+	// Every write updates the modification time. A file modified less than
+	// the settle delay ago may still be being written and is left for a
+	// later attempt.
+	if age := now.Sub(writtenAt); !writtenAt.IsZero() && age < fs.SettleDelay {
+		fs.Logger.Debugf("Not locking %v yet: it was modified %v ago", old, age)
+		return false
+	}
+
+	_ = os.Chtimes(old, now, now)
+
 	err := os.Rename(old, new)
 
 	if err != nil {
@@ -198,10 +249,340 @@ func (fs *FsWatcher) tryLockFile(job *Job) (success bool) {
 
 	// Success, write the name of the locked file
 	job.LockedFile = lockedFile
+	if !writtenAt.IsZero() {
+		metrics.CollectPickup(job.Def.Name, now.Sub(writtenAt))
+	}
 
 	return true
 }
 
+// reclaimIfStale puts a locked file back in the queue if its heartbeat, i.e.
+// its modification time, is older than the stale lock TTL. This happens when
+// the controller owning the lock was killed while processing the job. It
+// returns the original name of the file and true if the lock was reclaimed.
+func (f *FsWatcher) reclaimIfStale(jdef *JobDefinition, dirent fs.DirEntry) (orig string, reclaimed bool) {
+
+	if f.StaleLockTTL <= 0 {
+		return "", false
+	}
+
+	orig, owner, isLocked := parseLockedFile(dirent.Name(), f.InProgress)
+	if !isLocked {
+		return "", false
+	}
+
+	// Only reclaim the files that are jobs of this definition
+	if ok, err := jdef.InputFileRegexp.MatchString(orig); !ok || err != nil {
+		return "", false
+	}
+
+	finfo, err := dirent.Info()
+	if err != nil {
+		// The file was likely renamed by its owner in the meantime
+		return "", false
+	}
+
+	age := time.Since(finfo.ModTime())
+	if age <= f.StaleLockTTL {
+		return "", false
+	}
+
+	var (
+		dirName = jdef.dirFrom()
+		locked  = path.Join(dirName, dirent.Name())
+	)
+
+	// The rename is atomic: if several controllers attempt to reclaim the
+	// same lock, only one of them succeeds.
+	if err := os.Rename(locked, path.Join(dirName, orig)); err != nil {
+		f.Logger.Debugf("could not reclaim the stale lock %v: %v", locked, err)
+		return "", false
+	}
+
+	// The modification time is left as is: it is older than the stale lock
+	// TTL, so the settle delay does not hold back the reclaimed job in Lock.
+
+	f.Logger.Warnf(
+		"Reclaimed the stale lock %v owned by %v: no heartbeat for %v. The job is put back in the queue",
+		locked, owner, age.Round(time.Second),
+	)
+	metrics.CollectReclaimedLock(jdef.Name)
+
+	return orig, true
+}
+
+// StartHeartbeat refreshes the modification time of the locked file of the
+// job at every heartbeat period so that the other controllers do not
+// consider the lock stale. The heartbeat runs until the returned function is
+// called.
+func (f *FsWatcher) StartHeartbeat(job *Job) (stop func()) {
+
+	if f.HeartbeatPeriod <= 0 {
+		return func() {}
+	}
+
+	var (
+		done    = make(chan struct{})
+		stopped = make(chan struct{})
+		ticker  = time.NewTicker(f.HeartbeatPeriod)
+	)
+
+	go func() {
+		defer close(stopped)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				now := time.Now()
+				if err := os.Chtimes(job.InProgressPath(), now, now); err != nil {
+					// This happens if the lock was reclaimed by another
+					// controller. Nothing can be done at this point.
+					f.Logger.Errorf("could not refresh the heartbeat of %v: %v", job.InProgressPath(), err)
+				}
+			}
+		}
+	}()
+
+	return func() {
+		close(done)
+		<-stopped
+	}
+}
+
+// Watch subscribes to the events of the requests directories. The returned
+// channel receives a value once a file matching a job definition has been
+// created or moved into one of them and no write to such a file happened for
+// the settle delay, so that the file is complete. It does not replace the polling of the
+// directories because some filesystems, like NFS or EFS, do not deliver the
+// events for the files written by other hosts. The watch runs until stop is
+// called. Implements [EventSource].
+func (fs *FsWatcher) Watch() (wakeup <-chan struct{}, stop func(), err error) {
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not create the watcher: %w", err)
+	}
+
+	for i := range fs.JobToWatch {
+		dirFrom := fs.JobToWatch[i].dirFrom()
+		if err := watcher.Add(dirFrom); err != nil {
+			watcher.Close()
+			return nil, nil, fmt.Errorf("could not watch %v: %w", dirFrom, err)
+		}
+	}
+
+	var (
+		// A single pending wake up is enough as the controller looks at the
+		// whole queue when it wakes up.
+		ch   = make(chan struct{}, 1)
+		done = make(chan struct{})
+		// settled fires once no request file was written for the settle
+		// delay. It is nil when no event is pending.
+		settled <-chan time.Time
+	)
+
+	go func() {
+		defer close(done)
+		for {
+			select {
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+
+				// Moving a file into a directory triggers a creation event.
+				// The writes postpone the wake up until the file is complete,
+				// unless the settle delay is disabled.
+				isWrite := event.Has(fsnotify.Write) && fs.SettleDelay > 0
+				if !event.Has(fsnotify.Create) && !isWrite {
+					continue
+				}
+				if !fs.isRequestFile(filepath.Base(event.Name)) {
+					continue
+				}
+
+				fs.Logger.Tracef("Event %v on the request file %v", event.Op, event.Name)
+				settled = time.After(fs.SettleDelay)
+
+			case <-settled:
+				settled = nil
+				select {
+				case ch <- struct{}{}:
+				default:
+				}
+
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+				fs.Logger.Errorf("error watching the requests directories: %v", err)
+			}
+		}
+	}()
+
+	stop = func() {
+		watcher.Close()
+		<-done
+	}
+
+	return ch, stop, nil
+}
+
+// Returns true if the file name matches one of the job definitions to watch
+func (fs *FsWatcher) isRequestFile(name string) bool {
+	for i := range fs.JobToWatch {
+		if ok, err := fs.JobToWatch[i].InputFileRegexp.MatchString(name); ok && err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// Complete moves the response file written by the prover to its final
+// location and the request file to the done directory with the success
+// suffix. Implements [JobSource].
+func (fs *FsWatcher) Complete(job *Job, status Status) error {
+
+	// NB: we already check that the response filename can be generated
+	// prior to running the command. So this actually will not panic.
+	respFile, err := job.ResponseFile()
+	tmpRespFile := job.TmpResponseFile(fs.Config)
+	if err != nil {
+		formatStr := "Could not generate the response file: %v (original request file: %v)"
+		utils.Panic(formatStr, err, job.OriginalFile)
+	}
+
+	fs.Logger.Infof(
+		"Moving the response file from the tmp response file `%v`, to the final response file: `%v`",
+		tmpRespFile, respFile,
+	)
+
+	var errResp error
+	if err := os.Rename(tmpRespFile, respFile); err != nil {
+		// @Alex: it is unclear how the rename operation could fail here. If
+		// this happens, we prefer removing the tmp file. Note that the
+		// operation is an `mv -f`
+		os.Remove(tmpRespFile)
+		errResp = fmt.Errorf(
+			"error renaming %v to %v: %w, removed the tmp file",
+			tmpRespFile, respFile, err,
+		)
+	}
+
+	// Move the inprogress to the done directory
+	fs.Logger.Infof(
+		"Moving %v to %v with the success prefix",
+		job.OriginalFile, job.Def.dirDone(),
+	)
+
+	return errors.Join(errResp, fs.moveToDone(job, status))
+}
+
+// Fail moves the request file to the done directory with a failure suffix
+// for the exit code. Implements [JobSource].
+func (fs *FsWatcher) Fail(job *Job, status Status) error {
+
+	fs.Logger.Infof(
+		"Moving %v with in %v with a failure suffix for code %v",
+		job.OriginalFile, job.Def.dirDone(), status.ExitCode,
+	)
+
+	fs.keepOverflowReport(job, job.DoneFile(status))
+	return fs.moveToDone(job, status)
+}
+
+// DeferToLarge moves the request file back in the requests directory with
+// the suffix of the large prover. Implements [JobSource].
+func (fs *FsWatcher) DeferToLarge(job *Job, status Status) error {
+
+	fs.Logger.Infof("Renaming %v for the large prover", job.OriginalFile)
+
+	// Move the inprogress file back in the from directory with the new
+	// suffix
+	toLargePath, err := job.DeferToLargeFile(status)
+	if err != nil {
+		// There are two possibilities of errors. (1), the status we success
+		// but the caller prevents that. The other case is that the suffix
+		// was not provided. But, during the config validation, we check
+		// already that the suffix must be provided if the size of the list
+		// of deferToOtherLargeCodes is non-zero. If the size of the list was
+		// zero, then there would be no way to reach this portion of the code
+		// given that the current exit code cannot be part of the empty list.
+		// Thus, this section is unreachable.
+		return fmt.Errorf("error deriving the to-large-name of %v: %w", job.InProgressPath(), err)
+	}
+
+	fs.keepOverflowReport(job, toLargePath)
+	return fs.moveLocked(job, toLargePath)
+}
+
+// Moves the overflow report written by the prover, if any, next to the
+// metadata of the job, under the name of the file its request is moved to.
+// Failing to do so is not an error.
+func (fs *FsWatcher) keepOverflowReport(job *Job, to string) {
+
+	tmpReport := job.TmpOverflowReportFile(fs.Config)
+	if _, err := os.Stat(tmpReport); err != nil {
+		return
+	}
+
+	report := job.OverflowReportFile(to)
+	err := os.MkdirAll(filepath.Dir(report), os.ModePerm)
+	if err == nil {
+		err = os.Rename(tmpReport, report)
+	}
+	if err != nil {
+		os.Remove(tmpReport)
+		fs.Logger.Warnf("Could not move the overflow report %v to %v: %v", tmpReport, report, err)
+	}
+}
+
+// Moves the locked file of a finished job to the done directory and writes
+// the metadata of its status in the metadata file of the job. It is also
+// attached to the done file as extended attributes when the filesystem
+// supports them. Failing to write the metadata is not an error.
+func (fs *FsWatcher) moveToDone(job *Job, status Status) error {
+
+	doneFile := job.DoneFile(status)
+	if err := fs.moveLocked(job, doneFile); err != nil {
+		return err
+	}
+
+	if err := writeDoneMetadata(job, status); err != nil {
+		fs.Logger.Warnf("Could not write the metadata of the done file: %v", err)
+	}
+
+	if err := setDoneMetadata(doneFile, status); err != nil {
+		fs.Logger.Debugf("Could not attach the metadata to the done file: %v", err)
+	}
+
+	return nil
+}
+
+// Renames the locked file of the job. When that fails, the only thing left
+// to do is to report the error and let the inprogress file where it is. It
+// will likely require a human intervention.
+func (fs *FsWatcher) moveLocked(job *Job, to string) error {
+	if err := os.Rename(job.InProgressPath(), to); err != nil {
+		return fmt.Errorf("error renaming %v to %v: %w", job.InProgressPath(), to, err)
+	}
+	return nil
+}
+
+// parseLockedFile parses a file name of the form
+// `<original>.<inProgress>.<owner>` and returns false if the name is not of
+// this form.
+func parseLockedFile(name, inProgress string) (orig, owner string, ok bool) {
+	sep := "." + inProgress + "."
+	pos := strings.LastIndex(name, sep)
+	if pos <= 0 || pos+len(sep) == len(name) {
+		return "", "", false
+	}
+	return name[:pos], name[pos+len(sep):], true
+}
+
 // Returns the list of the entries name in the given directory. Returns up to
 // `n` result, ignore the errors for each files. Returns an error if it cannot
 // open the directory.
@@ -1,9 +1,15 @@
 package controller
 
 import (
+	"os"
+	"path"
 	"testing"
+	"time"
 
+	"github.com/sirupsen/logrus"
+	logtest "github.com/sirupsen/logrus/hooks/test"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestLsName(t *testing.T) {
@@ -24,3 +30,273 @@ func TestLsName(t *testing.T) {
 	assert.NoErrorf(t, err, "error on tmp directory")
 	assert.Emptyf(t, ls, "non empty dir")
 }
+
+func TestReclaimStaleLock(t *testing.T) {
+
+	confM, _ := setupFsTest(t)
+	confM.Controller.StaleLockTTL = 60
+
+	var (
+		eFrom     = confM.Execution.DirFrom()
+		fsWatcher = NewFsWatcher(confM)
+		stale     = createTestInputFile(eFrom, 0, 1, execJob, 0)
+		fresh     = createTestInputFile(eFrom, 1, 2, execJob, 0)
+		staleLock = path.Join(eFrom, stale+".inprogress.killed-prover")
+		freshLock = path.Join(eFrom, fresh+".inprogress.other-prover")
+		old       = time.Now().Add(-2 * time.Minute)
+	)
+
+	require.NoError(t, os.Rename(path.Join(eFrom, stale), staleLock))
+	require.NoError(t, os.Chtimes(staleLock, old, old))
+	require.NoError(t, os.Rename(path.Join(eFrom, fresh), freshLock))
+
+	// The stale lock is put back in the queue and picked up by the watcher
+	// straight away, despite the settle delay.
+	fsWatcher.SettleDelay = time.Minute
+	job := fsWatcher.GetBest()
+	require.NotNil(t, job)
+	assert.Equal(t, stale, job.OriginalFile)
+	assert.NoFileExists(t, staleLock)
+	assert.FileExists(t, job.InProgressPath())
+
+	// The fresh lock is left untouched
+	assert.Nil(t, fsWatcher.GetBest())
+	assert.FileExists(t, freshLock)
+}
+
+func TestKeepOverflowReport(t *testing.T) {
+
+	confM, _ := setupFsTest(t)
+
+	var (
+		eFrom     = confM.Execution.DirFrom()
+		fsWatcher = NewFsWatcher(confM)
+		status    = Status{ExitCode: CodeTraceLimit}
+	)
+
+	createTestInputFile(eFrom, 0, 1, execJob, 0)
+	createTestInputFile(eFrom, 1, 2, execJob, 0)
+
+	// The reports written by the prover follow the requests, whether they are
+	// deferred to the large prover or failed.
+	for _, deferToLarge := range []bool{true, false} {
+		job := fsWatcher.GetBest()
+		require.NotNil(t, job)
+
+		tmpReport := job.TmpOverflowReportFile(confM)
+		require.NoError(t, os.WriteFile(tmpReport, []byte(`{"overflows":[]}`), 0600))
+
+		to := job.DoneFile(status)
+		if deferToLarge {
+			var err error
+			to, err = job.DeferToLargeFile(status)
+			require.NoError(t, err)
+			require.NoError(t, fsWatcher.DeferToLarge(job, status))
+		} else {
+			require.NoError(t, fsWatcher.Fail(job, status))
+		}
+
+		assert.NoFileExists(t, tmpReport)
+		assert.FileExists(t, job.OverflowReportFile(to))
+	}
+}
+
+func TestHeartbeat(t *testing.T) {
+
+	confM, _ := setupFsTest(t)
+
+	var (
+		eFrom     = confM.Execution.DirFrom()
+		fsWatcher = NewFsWatcher(confM)
+		old       = time.Now().Add(-time.Hour)
+	)
+
+	fsWatcher.HeartbeatPeriod = 10 * time.Millisecond
+	createTestInputFile(eFrom, 0, 1, execJob, 0)
+
+	job := fsWatcher.GetBest()
+	require.NotNil(t, job)
+
+	// Locking the file refreshes its modification time
+	finfo, err := os.Stat(job.InProgressPath())
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now(), finfo.ModTime(), time.Minute)
+
+	require.NoError(t, os.Chtimes(job.InProgressPath(), old, old))
+
+	stop := fsWatcher.StartHeartbeat(job)
+	time.Sleep(100 * time.Millisecond)
+	stop()
+
+	finfo, err = os.Stat(job.InProgressPath())
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now(), finfo.ModTime(), time.Minute)
+}
+
+func TestParseLockedFile(t *testing.T) {
+
+	orig, owner, ok := parseLockedFile("0-1-getZkProof.json.inprogress.prover-1.local", "inprogress")
+	assert.True(t, ok)
+	assert.Equal(t, "0-1-getZkProof.json", orig)
+	assert.Equal(t, "prover-1.local", owner)
+
+	_, _, ok = parseLockedFile("0-1-getZkProof.json", "inprogress")
+	assert.False(t, ok)
+
+	_, _, ok = parseLockedFile("0-1-getZkProof.json.inprogress.", "inprogress")
+	assert.False(t, ok)
+}
+
+func TestWatch(t *testing.T) {
+
+	confM, _ := setupFsTest(t)
+
+	var (
+		eFrom     = confM.Execution.DirFrom()
+		fsWatcher = NewFsWatcher(confM)
+	)
+
+	wakeup, stop, err := fsWatcher.Watch()
+	require.NoError(t, err)
+	defer stop()
+
+	// Files that are not requests do not wake up the controller
+	require.NoError(t, os.WriteFile(path.Join(eFrom, "not-a-request.json"), nil, 0600))
+	select {
+	case <-wakeup:
+		t.Fatal("woken up by a file that is not a request")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	createTestInputFile(eFrom, 0, 1, execJob, 0)
+	select {
+	case <-wakeup:
+	case <-time.After(5 * time.Second):
+		t.Fatal("not woken up by a new request")
+	}
+
+	job := fsWatcher.GetBest()
+	require.NotNil(t, job)
+
+	// Locking a file does not wake up the controller
+	select {
+	case <-wakeup:
+		t.Fatal("woken up by a locked file")
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestDependencies(t *testing.T) {
+
+	confM, _ := setupFsTest(t)
+
+	var (
+		aFrom     = confM.Aggregation.DirFrom()
+		fsWatcher = NewFsWatcher(confM)
+		jobAPI    = newTestJobAPI(t, confM)
+		file      = createTestInputFile(aFrom, 0, 2, aggregationJob, 0)
+		execResp  = path.Join(confM.Execution.DirTo(), "0-2-getZkProof.json")
+		compResp  = path.Join(confM.BlobDecompression.DirTo(), "0-2-getZkBlobCompressionProof.json")
+		request   = `{
+			"executionProofs": ["0-2-getZkProof.json"],
+			"compressionProofs": ["0-2-getZkBlobCompressionProof.json"]
+		}`
+	)
+
+	require.NoError(t, os.WriteFile(path.Join(aFrom, file), []byte(request), 0600))
+
+	// The aggregation waits for the responses it aggregates
+	assert.Nil(t, fsWatcher.GetBest())
+
+	st, found, err := jobAPI.find(jobAPI.definition(jobNameAggregation), file)
+	require.NoError(t, err)
+	require.True(t, found)
+	assert.Equal(t, JobBlocked, st.Status)
+	assert.Equal(t, []string{"0-2-getZkProof.json", "0-2-getZkBlobCompressionProof.json"}, st.MissingDependencies)
+
+	require.NoError(t, os.WriteFile(execResp, []byte("{}"), 0600))
+	assert.Nil(t, fsWatcher.GetBest())
+
+	// Once all the responses are there, the job is picked up
+	require.NoError(t, os.WriteFile(compResp, []byte("{}"), 0600))
+	job := fsWatcher.GetBest()
+	require.NotNil(t, job)
+	assert.Equal(t, file, job.OriginalFile)
+}
+
+// A job blocked for longer than the warn delay is reported once
+func TestBlockedJobs(t *testing.T) {
+
+	var (
+		logger, hook = logtest.NewNullLogger()
+		entry        = logrus.NewEntry(logger)
+		blocked      = blockedJobs{}
+		missing      = map[string][]string{"0-2-getZkAggregatedProof.json": {"0-2-getZkProof.json"}}
+		numWarnings  = func() (n int) {
+			for _, e := range hook.AllEntries() {
+				if e.Level == logrus.WarnLevel {
+					n++
+				}
+			}
+			return n
+		}
+	)
+
+	assert.Less(t, blocked.update(entry, jobNameAggregation, missing, time.Hour), time.Second)
+	assert.Equal(t, 0, numWarnings())
+
+	// Simulate that the job was first found blocked two hours ago
+	blocked.jobs[jobNameAggregation]["0-2-getZkAggregatedProof.json"].since = time.Now().Add(-2 * time.Hour)
+
+	assert.GreaterOrEqual(t, blocked.update(entry, jobNameAggregation, missing, time.Hour), 2*time.Hour)
+	assert.Equal(t, 1, numWarnings())
+	assert.GreaterOrEqual(t, blocked.update(entry, jobNameAggregation, missing, time.Hour), 2*time.Hour)
+	assert.Equal(t, 1, numWarnings())
+
+	// Once unblocked, the job is forgotten
+	assert.Zero(t, blocked.update(entry, jobNameAggregation, nil, time.Hour))
+	assert.Less(t, blocked.update(entry, jobNameAggregation, missing, time.Hour), time.Second)
+	assert.Equal(t, 1, numWarnings())
+}
+
+func TestWatchPartialWrite(t *testing.T) {
+
+	confM, _ := setupFsTest(t)
+
+	var (
+		eFrom     = confM.Execution.DirFrom()
+		fsWatcher = NewFsWatcher(confM)
+		fname     = path.Join(eFrom, "0-1-etv0.1.2-stv1.2.3-getZkProof.json")
+	)
+
+	fsWatcher.SettleDelay = 300 * time.Millisecond
+
+	wakeup, stop, err := fsWatcher.Watch()
+	require.NoError(t, err)
+	defer stop()
+
+	// While the file is being written, the controller is neither woken up
+	// nor able to lock it.
+	f, err := os.Create(fname)
+	require.NoError(t, err)
+	for i := 0; i < 3; i++ {
+		_, err = f.WriteString("exit 0\n")
+		require.NoError(t, err)
+		select {
+		case <-wakeup:
+			t.Fatal("woken up by a file being written")
+		case <-time.After(100 * time.Millisecond):
+		}
+		assert.Nil(t, fsWatcher.GetBest())
+	}
+	require.NoError(t, f.Close())
+
+	select {
+	case <-wakeup:
+	case <-time.After(5 * time.Second):
+		t.Fatal("not woken up once the file is complete")
+	}
+
+	job := fsWatcher.GetBest()
+	require.NotNil(t, job)
+}
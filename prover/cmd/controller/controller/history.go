@@ -0,0 +1,247 @@
+package controller
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/consensys/linea-monorepo/prover/config"
+	"golang.org/x/exp/slices"
+)
+
+// Outcome of a run deferred to the large prover, as recorded in the history.
+// The other runs are recorded as JobSuccess or JobFailure.
+const JobDeferredToLarge = "deferred-to-large"
+
+// HistoryRecord is the record of a run of a job appended to the history
+type HistoryRecord struct {
+	// Name of the job definition, e.g. "execution"
+	Type string `json:"type"`
+	// Name of the request file when it was picked up
+	File  string `json:"file"`
+	Start int    `json:"start"`
+	End   int    `json:"end"`
+
+	VersionExecutionTracer string `json:"versionExecutionTracer,omitempty"`
+	VersionStateManager    string `json:"versionStateManager,omitempty"`
+	VersionCompressor      string `json:"versionCompressor,omitempty"`
+
+	// One of JobSuccess, JobFailure or JobDeferredToLarge
+	Status   string `json:"status"`
+	ExitCode int    `json:"exitCode"`
+	// Explanation of the failure, if any
+	What string `json:"what,omitempty"`
+
+	StartedAt time.Time `json:"startedAt"`
+	// Duration of the run in seconds, including the local retry in large
+	// mode if any.
+	Duration float64 `json:"duration"`
+	// Number of the attempt, starting from one. The previous attempts are
+	// counted from the failure suffixes of the request file.
+	Attempt int `json:"attempt"`
+	// Set if the job ran with the large command
+	Large bool `json:"large,omitempty"`
+	// Set if the large command ran as a local retry of a failed run
+	RetriedLarge bool `json:"retriedLarge,omitempty"`
+
+	// Resources used by the prover process, see [Usage]
+	MaxRSS          int64   `json:"maxRssBytes"`
+	UserCPU         float64 `json:"userCpuSeconds"`
+	SysCPU          float64 `json:"sysCpuSeconds"`
+	MajorPageFaults int64   `json:"majorPageFaults"`
+
+	// Hostname of the machine and LocalID of the controller
+	Host    string `json:"host"`
+	LocalID string `json:"localId"`
+}
+
+// History is the journal of the runs of a controller. It is a JSONL file
+// local to the controller so that the controllers sharing a history directory
+// never write in the same file.
+type History struct {
+	// Path of the journal
+	Path string
+
+	host, localID string
+	// The controller can run several jobs concurrently
+	mu sync.Mutex
+}
+
+// NewHistory returns the history configured in `controller.history_dir` or
+// nil if the history is disabled.
+func NewHistory(conf *config.Config) (*History, error) {
+
+	dir := conf.Controller.HistoryDir
+	if len(dir) == 0 {
+		return nil, nil
+	}
+
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return nil, fmt.Errorf("could not create the history directory %v: %w", dir, err)
+	}
+
+	host, err := os.Hostname()
+	if err != nil {
+		host = "unknown"
+	}
+
+	return &History{
+		Path:    filepath.Join(dir, conf.Controller.LocalID+".jsonl"),
+		host:    host,
+		localID: conf.Controller.LocalID,
+	}, nil
+}
+
+// Record appends the record of a run of the job to the journal
+func (h *History) Record(job *Job, status Status, outcome string, startedAt time.Time) error {
+
+	rec := HistoryRecord{
+		Type:                   job.Def.Name,
+		File:                   job.OriginalFile,
+		Start:                  job.Start,
+		End:                    job.End,
+		VersionExecutionTracer: job.VersionExecutionTracer,
+		VersionStateManager:    job.VersionStateManager,
+		VersionCompressor:      job.VersionCompressor,
+		Status:                 outcome,
+		ExitCode:               status.ExitCode,
+		StartedAt:              startedAt.UTC(),
+		Duration:               time.Since(startedAt).Seconds(),
+		Attempt:                1 + numFailureSuffixes(job),
+		Large:                  status.Large,
+		RetriedLarge:           status.RetriedLarge,
+		MaxRSS:                 status.Usage.MaxRSS,
+		UserCPU:                status.Usage.UserTime.Seconds(),
+		SysCPU:                 status.Usage.SysTime.Seconds(),
+		MajorPageFaults:        status.Usage.MajorFaults,
+		Host:                   h.host,
+		LocalID:                h.localID,
+	}
+
+	if outcome != JobSuccess {
+		rec.What = status.What
+		if status.Err != nil {
+			rec.What = fmt.Sprintf("%v: %v", status.What, status.Err)
+		}
+	}
+
+	line, err := json.Marshal(rec)
+	if err != nil {
+		return fmt.Errorf("could not marshal the history record: %w", err)
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	// The file is opened for each record so that the journal can be rotated
+	// while the controller runs.
+	f, err := os.OpenFile(h.Path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		return fmt.Errorf("could not open the history %v: %w", h.Path, err)
+	}
+
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		f.Close()
+		return fmt.Errorf("could not write in the history %v: %w", h.Path, err)
+	}
+
+	return f.Close()
+}
+
+// Returns the number of failure suffixes in the name of the request file of
+// the job. Each of them was appended by a failed run.
+func numFailureSuffixes(job *Job) (n int) {
+	match, err := job.Def.FailureSuffix.FindStringMatch(job.OriginalFile)
+	for ; err == nil && match != nil; match, err = job.Def.FailureSuffix.FindNextMatch(match) {
+		n++
+	}
+	return n
+}
+
+// HistoryFilter selects the records of the history. The zero values do not
+// filter anything.
+type HistoryFilter struct {
+	// Name of the job definition
+	Type string
+	// One of JobSuccess, JobFailure or JobDeferredToLarge
+	Status string
+	// Selects the jobs whose range overlaps [From, To]
+	From, To int
+	// Selects the runs started in [Since, Until]
+	Since, Until time.Time
+}
+
+// Returns true if the record is selected by the filter
+func (f *HistoryFilter) match(rec *HistoryRecord) bool {
+	switch {
+	case len(f.Type) > 0 && rec.Type != f.Type:
+		return false
+	case len(f.Status) > 0 && rec.Status != f.Status:
+		return false
+	case f.From > 0 && rec.End < f.From:
+		return false
+	case f.To > 0 && rec.Start > f.To:
+		return false
+	case !f.Since.IsZero() && rec.StartedAt.Before(f.Since):
+		return false
+	case !f.Until.IsZero() && rec.StartedAt.After(f.Until):
+		return false
+	}
+	return true
+}
+
+// ReadHistory returns the records of all the journals of the history
+// directory selected by the filter, sorted by start time.
+func ReadHistory(dir string, filter HistoryFilter) ([]HistoryRecord, error) {
+
+	journals, err := filepath.Glob(filepath.Join(dir, "*.jsonl"))
+	if err != nil {
+		return nil, err
+	}
+
+	res := []HistoryRecord{}
+	for _, journal := range journals {
+		if res, err = appendHistory(journal, filter, res); err != nil {
+			return nil, err
+		}
+	}
+
+	slices.SortStableFunc(res, func(a, b HistoryRecord) int {
+		return a.StartedAt.Compare(b.StartedAt)
+	})
+
+	return res, nil
+}
+
+// Appends the records of a journal selected by the filter
+func appendHistory(journal string, filter HistoryFilter, res []HistoryRecord) ([]HistoryRecord, error) {
+
+	f, err := os.Open(journal)
+	if err != nil {
+		return nil, fmt.Errorf("could not open the history %v: %w", journal, err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var rec HistoryRecord
+		// A truncated line may be left by a controller killed while writing
+		// it. It is skipped.
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			continue
+		}
+		if filter.match(&rec) {
+			res = append(res, rec)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("could not read the history %v: %w", journal, err)
+	}
+
+	return res, nil
+}
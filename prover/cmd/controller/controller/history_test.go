@@ -0,0 +1,92 @@
+package controller
+
+import (
+	"context"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHistory(t *testing.T) {
+
+	confM, confL := setupFsTest(t)
+	confM.Controller.HistoryDir = t.TempDir()
+	confL.Controller.HistoryDir = confM.Controller.HistoryDir
+
+	var (
+		eFrom = confM.Execution.DirFrom()
+		aFrom = confM.Aggregation.DirFrom()
+	)
+
+	// The first job is deferred to the large prover which succeeds, the
+	// second one is retried locally in large mode.
+	createTestInputFile(eFrom, 0, 1, execJob, 12)
+	createTestInputFile(eFrom, 2, 3, execJob, 10)
+	createTestInputFile(aFrom, 0, 2, aggregationJob, 2)
+
+	ctxM, stopM := context.WithCancel(context.Background())
+	ctxL, stopL := context.WithCancel(context.Background())
+	go runController(ctxM, confM)
+	go runController(ctxL, confL)
+	<-time.After(2 * time.Second)
+	stopM()
+	stopL()
+
+	assert.FileExists(t, path.Join(confM.Controller.HistoryDir, confM.Controller.LocalID+".jsonl"))
+	assert.FileExists(t, path.Join(confL.Controller.HistoryDir, confL.Controller.LocalID+".jsonl"))
+
+	all, err := ReadHistory(confM.Controller.HistoryDir, HistoryFilter{})
+	require.NoError(t, err)
+	assert.Len(t, all, 4)
+
+	// The two attempts of the job deferred to the large prover
+	runs, err := ReadHistory(confM.Controller.HistoryDir, HistoryFilter{Type: jobNameExecution, To: 1})
+	require.NoError(t, err)
+	require.Len(t, runs, 2)
+
+	assert.Equal(t, JobDeferredToLarge, runs[0].Status)
+	assert.Equal(t, 12, runs[0].ExitCode)
+	assert.Equal(t, 1, runs[0].Attempt)
+	assert.Equal(t, confM.Controller.LocalID, runs[0].LocalID)
+	assert.Equal(t, "0.1.2", runs[0].VersionExecutionTracer)
+
+	assert.Equal(t, JobSuccess, runs[1].Status)
+	assert.Equal(t, 2, runs[1].Attempt)
+	assert.True(t, runs[1].Large)
+	assert.False(t, runs[1].RetriedLarge)
+	assert.Equal(t, confL.Controller.LocalID, runs[1].LocalID)
+
+	// The job retried locally in large mode
+	runs, err = ReadHistory(confM.Controller.HistoryDir, HistoryFilter{From: 2, Type: jobNameExecution})
+	require.NoError(t, err)
+	require.Len(t, runs, 1)
+	assert.Equal(t, JobSuccess, runs[0].Status)
+	assert.True(t, runs[0].RetriedLarge)
+
+	// The failed aggregation
+	runs, err = ReadHistory(confM.Controller.HistoryDir, HistoryFilter{Status: JobFailure})
+	require.NoError(t, err)
+	require.Len(t, runs, 1)
+	assert.Equal(t, jobNameAggregation, runs[0].Type)
+	assert.Equal(t, 2, runs[0].ExitCode)
+
+	// The time window
+	runs, err = ReadHistory(confM.Controller.HistoryDir, HistoryFilter{Since: time.Now()})
+	require.NoError(t, err)
+	assert.Empty(t, runs)
+
+	// A truncated record is skipped
+	f, err := os.OpenFile(path.Join(confM.Controller.HistoryDir, confM.Controller.LocalID+".jsonl"), os.O_WRONLY|os.O_APPEND, 0)
+	require.NoError(t, err)
+	_, err = f.WriteString(`{"type":"execution","fi`)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	all, err = ReadHistory(confM.Controller.HistoryDir, HistoryFilter{})
+	require.NoError(t, err)
+	assert.Len(t, all, 4)
+}
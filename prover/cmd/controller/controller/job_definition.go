@@ -26,6 +26,9 @@ type JobDefinition struct {
 	// Name of the job
 	Name string
 
+	// Type of the job passed to the prover with --job-type
+	ProverJobType string
+
 	// The regexp to use to match input files. For instance,
 	//
 	// 	`^\d+-\d+-etv0.1.2-stv\d.\d.\d-getZkProof.json$`
@@ -80,7 +83,8 @@ func ExecutionDefinition(conf *config.Config) JobDefinition {
 		RequestsRootDir: conf.Execution.RequestsRootDir,
 
 		// Name of the job
-		Name: jobNameExecution,
+		Name:          jobNameExecution,
+		ProverJobType: "execution",
 
 		// This will panic at startup if the regexp is invalid
 		InputFileRegexp: regexp2.MustCompile(
@@ -133,7 +137,8 @@ func CompressionDefinition(conf *config.Config) JobDefinition {
 		RequestsRootDir: conf.BlobDecompression.RequestsRootDir,
 
 		// Name of the job
-		Name: jobNameBlobDecompression,
+		Name:          jobNameBlobDecompression,
+		ProverJobType: "blob-decompression",
 
 		// This will panic at startup if the regexp is invalid
 		InputFileRegexp: regexp2.MustCompile(
@@ -185,7 +190,8 @@ func AggregatedDefinition(conf *config.Config) JobDefinition {
 		RequestsRootDir: conf.Aggregation.RequestsRootDir,
 
 		// Name of the job
-		Name: jobNameAggregation,
+		Name:          jobNameAggregation,
+		ProverJobType: "aggregation",
 
 		// This will panic at startup if the regexp is invalid
 		InputFileRegexp: regexp2.MustCompile(
@@ -228,6 +234,24 @@ func AggregatedDefinition(conf *config.Config) JobDefinition {
 	}
 }
 
+// Returns the definitions of the jobs enabled in the configuration
+func enabledDefinitions(conf *config.Config) (defs []JobDefinition) {
+
+	if conf.Controller.EnableExecution {
+		defs = append(defs, ExecutionDefinition(conf))
+	}
+
+	if conf.Controller.EnableBlobDecompression {
+		defs = append(defs, CompressionDefinition(conf))
+	}
+
+	if conf.Controller.EnableAggregation {
+		defs = append(defs, AggregatedDefinition(conf))
+	}
+
+	return defs
+}
+
 // Version prefix template
 func matchVersionWithPrefix(pre string) *regexp2.Regexp {
 	return regexp2.MustCompile(
@@ -263,6 +287,10 @@ func (jd *JobDefinition) dirDone() string {
 	return filepath.Join(jd.RequestsRootDir, config.RequestsDoneSubDir)
 }
 
+func (jd *JobDefinition) dirDoneMetadata() string {
+	return filepath.Join(jd.RequestsRootDir, config.RequestsDoneMetadataSubDir)
+}
+
 func (jd *JobDefinition) dirTo() string {
 	return filepath.Join(jd.RequestsRootDir, config.RequestsToSubDir)
 }
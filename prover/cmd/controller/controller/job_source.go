@@ -0,0 +1,90 @@
+package controller
+
+import (
+	"fmt"
+
+	"github.com/consensys/linea-monorepo/prover/config"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/exp/slices"
+)
+
+// JobSource is the queue from which the controller fetches its jobs. The
+// controller picks the best candidate it can lock, runs the prover on the
+// file at `job.InProgressPath()` and then reports the outcome of the job to
+// the source which moves the request accordingly.
+type JobSource interface {
+	// Candidates returns the jobs that are available in the queue. Their
+	// order does not matter.
+	Candidates() []*Job
+	// Lock attempts to take ownership of the job so that no other
+	// controller processes it. If successful, the request file is available
+	// at `job.InProgressPath()`.
+	Lock(job *Job) bool
+	// StartHeartbeat keeps the lock of the job alive until the returned
+	// function is called.
+	StartHeartbeat(job *Job) (stop func())
+	// Complete publishes the response of a successful job, written by the
+	// prover at `job.TmpResponseFile()`, and marks the request as done.
+	Complete(job *Job, status Status) error
+	// Fail marks the request of a failed job as done
+	Fail(job *Job, status Status) error
+	// DeferToLarge puts the request of the job back in the queue so that
+	// it is picked up by a large prover.
+	DeferToLarge(job *Job, status Status) error
+}
+
+// EventSource is implemented by the job sources that can signal new jobs as
+// soon as they appear in the queue, sparing the controller from waiting for
+// its next poll.
+type EventSource interface {
+	// Watch returns a channel receiving a value when a new job may be
+	// available. The watch runs until stop is called.
+	Watch() (wakeup <-chan struct{}, stop func(), err error)
+}
+
+// NewJobSource returns the job source selected in the configuration
+func NewJobSource(conf *config.Config) (JobSource, error) {
+	switch conf.Controller.JobSource {
+	case config.JobSourceFilesystem, "":
+		return NewFsWatcher(conf), nil
+	case config.JobSourceObjectStore:
+		return NewObjectStoreSource(conf)
+	default:
+		return nil, fmt.Errorf("unknown job source %q", conf.Controller.JobSource)
+	}
+}
+
+// Returns the job with the highest priority that could be locked from the
+// source. Returns nil if there are none. If fits is provided and the job
+// with the highest priority that is not locked yet does not fit, nil is
+// returned so that the jobs with a lower priority do not overtake it.
+func getBest(src JobSource, logger *logrus.Entry, fits func(*Job) bool) *Job {
+
+	jobs := src.Candidates()
+	if len(jobs) == 0 {
+		logger.Debugf("The queue is empty")
+		return nil
+	}
+
+	// Sort the jobs by scores in ascending order. Lower scores mean more
+	// priority.
+	slices.SortStableFunc(jobs, func(a, b *Job) int {
+		return a.Score() - b.Score()
+	})
+
+	for _, job := range jobs {
+		if fits != nil && !fits(job) {
+			logger.Debugf("Not enough free slots to run %v, waiting", job.OriginalFile)
+			return nil
+		}
+		if src.Lock(job) {
+			return job
+		}
+	}
+
+	logger.Infof(
+		"Found %v jobs in the queue. They were all locked before we could pick one",
+		len(jobs),
+	)
+	return nil
+}
@@ -98,9 +98,47 @@ func (j *Job) ResponseFile() (s string, err error) {
 	return s, nil
 }
 
-// Returns the name of the output file for the job
+// Returns the name of the file in which the prover writes the response before
+// it is moved to the response file. It is unique per job since the controller
+// can run several jobs concurrently.
 func (j *Job) TmpResponseFile(c *config.Config) (s string) {
-	return path.Join(j.Def.dirTo(), "tmp-response-file."+c.Controller.LocalID+".json")
+	return path.Join(j.Def.dirTo(), "tmp-response-file."+c.Controller.LocalID+"."+j.OriginalFile)
+}
+
+// Returns the file in which the prover writes the report of the traces of an
+// execution job overflowing the limits. The prover derives it from the file
+// of the response, see `overflowReportPath` in the prover command.
+func (j *Job) TmpOverflowReportFile(c *config.Config) string {
+	tmpRespFile := j.TmpResponseFile(c)
+	return strings.TrimSuffix(tmpRespFile, filepath.Ext(tmpRespFile)) + ".overflow.json"
+}
+
+// Returns the file of the `requests-done-metadata` directory in which the
+// overflow report of the job is kept once its request is moved to `to`.
+func (j *Job) OverflowReportFile(to string) string {
+	return filepath.Join(j.Def.dirDoneMetadata(), filepath.Base(to)+".overflow.json")
+}
+
+// Returns the number of slots of the controller occupied by the job. See
+// [config.Controller.Slots].
+func (j *Job) Weight(c *config.Config) int {
+
+	slots := max(c.Controller.Slots, 1)
+
+	var weight int
+	switch j.Def.Name {
+	case jobNameExecution:
+		weight = c.Controller.ExecutionWeight
+	case jobNameBlobDecompression:
+		weight = c.Controller.BlobDecompressionWeight
+	case jobNameAggregation:
+		weight = c.Controller.AggregationWeight
+	}
+
+	if weight <= 0 || weight > slots {
+		return slots
+	}
+	return weight
 }
 
 // Returns the name of the input file modified so that it is retried in
@@ -177,6 +215,13 @@ func (j *Job) DoneFile(status Status) string {
 	}
 }
 
+// Returns the file holding the metadata of the done file of a job, in the
+// `requests-done-metadata` directory. Unlike the extended attributes, it can
+// be written on every filesystem, including NFS.
+func (j *Job) DoneMetadataFile(status Status) string {
+	return filepath.Join(j.Def.dirDoneMetadata(), filepath.Base(j.DoneFile(status))+".json")
+}
+
 // Returns the score of a JOB. The score is obtained as 100*job.Stop + P, where
 // P is 1 if the job is an execution job, 2 if the job is a compression job and
 // 3 if the job is an aggregation job. The lower the score the higher will be
@@ -26,6 +26,66 @@ func CollectFS(jobType string, numDirEntries, numMatched int) {
 		Set(float64(numMatched))
 }
 
+// Collect the number of jobs in the queue waiting for the responses of the
+// jobs they depend on, and the time for which the oldest of them has been
+// waiting
+func CollectBlocked(jobType string, numBlocked int, maxWait time.Duration) {
+
+	if globalRegistry == nil {
+		logrus.Tracef("No global registry found, not collecting")
+		return
+	}
+
+	globalRegistry.NumBlockedJobs.
+		With(jobLab(jobType)).
+		Set(float64(numBlocked))
+
+	globalRegistry.BlockedJobsMaxWait.
+		With(jobLab(jobType)).
+		Set(maxWait.Seconds())
+}
+
+// Collect metrics relative to a job deferred to the large prover because its
+// traces were predicted to overflow the limits of the normal prover
+func CollectPredictedLarge(jobType string) {
+
+	if globalRegistry == nil {
+		logrus.Tracef("No global registry found, not collecting")
+		return
+	}
+
+	globalRegistry.NumPredictedLarge.
+		With(jobLab(jobType)).
+		Inc()
+}
+
+// Collect metrics relative to a stale lock that was reclaimed
+func CollectReclaimedLock(jobType string) {
+
+	if globalRegistry == nil {
+		logrus.Tracef("No global registry found, not collecting")
+		return
+	}
+
+	globalRegistry.NumReclaimedLocks.
+		With(jobLab(jobType)).
+		Inc()
+}
+
+// Collect metrics relative to a job that was just locked. The latency is the
+// time elapsed since the request file was written.
+func CollectPickup(jobType string, latency time.Duration) {
+
+	if globalRegistry == nil {
+		logrus.Tracef("No global registry found, not collecting")
+		return
+	}
+
+	globalRegistry.PickupLatency.
+		With(jobLab(jobType)).
+		Observe(latency.Seconds())
+}
+
 // Collect metrics relative to a job we are about to run. Retry means that
 // this is a file that we are retrying locally.
 func CollectPreProcess(jobType string, start, end int, retry bool) {
@@ -53,7 +113,7 @@ func CollectPreProcess(jobType string, start, end int, retry bool) {
 
 	globalRegistry.IsActive.
 		With(jobLab(jobType)).
-		Set(1)
+		Inc()
 }
 
 // Collect metrics from jobs for which the processing has completed
@@ -74,7 +134,7 @@ func CollectPostProcess(jobType string, code int, t time.Duration, retry bool) {
 
 	globalRegistry.IsActive.
 		With(jobLab(jobType)).
-		Set(0)
+		Dec()
 
 	globalRegistry.NumProcessed.
 		With(jobAndStatusLabs(jobType, code)).
@@ -85,6 +145,33 @@ func CollectPostProcess(jobType string, code int, t time.Duration, retry bool) {
 		Observe(t.Seconds())
 }
 
+// Collect the resources used by the prover process of a job that completed.
+// Retry means that this is a file that we retried locally.
+func CollectUsage(
+	jobType string, code int, retry bool,
+	maxRSSBytes int64, userTime, sysTime time.Duration, majorFaults int64,
+) {
+
+	if globalRegistry == nil {
+		logrus.Tracef("No global registry found, not collecting")
+		return
+	}
+
+	if retry {
+		// alter the job type so that we do not "pollute" non retried metric
+		jobType = strings.Join(
+			[]string{jobType, "retry", "large", "locally"},
+			"_",
+		)
+	}
+
+	labels := jobAndStatusLabs(jobType, code)
+	globalRegistry.MaxRSS.With(labels).Observe(float64(maxRSSBytes))
+	globalRegistry.UserCPUTime.With(labels).Observe(userTime.Seconds())
+	globalRegistry.SysCPUTime.With(labels).Observe(sysTime.Seconds())
+	globalRegistry.MajorPageFaults.With(labels).Observe(float64(majorFaults))
+}
+
 // helper function that returns a label map for some job type
 func jobLab(jobType string) prometheus.Labels {
 	return prometheus.Labels{
@@ -44,7 +44,7 @@ func initRegistry(worker_id string) {
 					Subsystem:   metricSubsystem,
 					ConstLabels: map[string]string{labelWorkerID: worker_id},
 					Name:        "processing_jobs_count",
-					Help: "One when the prover is processing jobs, and zero" +
+					Help: "Number of jobs the prover is processing, zero" +
 						" when the prover is idle.",
 				},
 				[]string{labelJobType},
@@ -64,6 +64,58 @@ func initRegistry(worker_id string) {
 				[]string{labelExitCode, labelJobType},
 			),
 
+			MaxRSS: promauto.NewHistogramVec(
+				prometheus.HistogramOpts{
+					Namespace:   metricNamespace,
+					Subsystem:   metricSubsystem,
+					ConstLabels: map[string]string{labelWorkerID: worker_id},
+					Name:        "job_max_rss_bytes",
+					Help:        "Peak resident set size of the prover process of the jobs",
+					// From 1GiB to 1TiB
+					Buckets: prometheus.ExponentialBuckets(1<<30, 2, 11),
+				},
+				[]string{labelExitCode, labelJobType},
+			),
+
+			UserCPUTime: promauto.NewHistogramVec(
+				prometheus.HistogramOpts{
+					Namespace:   metricNamespace,
+					Subsystem:   metricSubsystem,
+					ConstLabels: map[string]string{labelWorkerID: worker_id},
+					Name:        "job_user_cpu_seconds",
+					Help:        "User CPU time of the prover process of the jobs",
+					// From 1min to ~68 hours
+					Buckets: prometheus.ExponentialBuckets(60, 2, 13),
+				},
+				[]string{labelExitCode, labelJobType},
+			),
+
+			SysCPUTime: promauto.NewHistogramVec(
+				prometheus.HistogramOpts{
+					Namespace:   metricNamespace,
+					Subsystem:   metricSubsystem,
+					ConstLabels: map[string]string{labelWorkerID: worker_id},
+					Name:        "job_system_cpu_seconds",
+					Help:        "System CPU time of the prover process of the jobs",
+					// From 1sec to ~73 hours
+					Buckets: prometheus.ExponentialBuckets(1, 4, 10),
+				},
+				[]string{labelExitCode, labelJobType},
+			),
+
+			MajorPageFaults: promauto.NewHistogramVec(
+				prometheus.HistogramOpts{
+					Namespace:   metricNamespace,
+					Subsystem:   metricSubsystem,
+					ConstLabels: map[string]string{labelWorkerID: worker_id},
+					Name:        "job_major_page_faults",
+					Help: "Number of major page faults of the prover process of the" +
+						" jobs. A high value indicates that the host is swapping",
+					Buckets: prometheus.ExponentialBuckets(1, 10, 8),
+				},
+				[]string{labelExitCode, labelJobType},
+			),
+
 			JobRangeStartAt: promauto.NewGaugeVec(
 				prometheus.GaugeOpts{
 					Namespace:   metricNamespace,
@@ -101,6 +153,67 @@ func initRegistry(worker_id string) {
 				[]string{labelJobType},
 			),
 
+			NumBlockedJobs: promauto.NewGaugeVec(
+				prometheus.GaugeOpts{
+					Namespace:   metricNamespace,
+					Subsystem:   metricSubsystem,
+					ConstLabels: map[string]string{labelWorkerID: worker_id},
+					Name:        "num_blocked_jobs_in_queue",
+					Help: "Number of jobs in the queue waiting for the responses" +
+						" of the jobs they depend on. Segmented by type of job",
+				},
+				[]string{labelJobType},
+			),
+
+			BlockedJobsMaxWait: promauto.NewGaugeVec(
+				prometheus.GaugeOpts{
+					Namespace:   metricNamespace,
+					Subsystem:   metricSubsystem,
+					ConstLabels: map[string]string{labelWorkerID: worker_id},
+					Name:        "blocked_jobs_max_wait_seconds",
+					Help: "Time for which the oldest blocked job in the queue has" +
+						" been waiting for its dependencies. Segmented by type of job",
+				},
+				[]string{labelJobType},
+			),
+
+			NumReclaimedLocks: promauto.NewCounterVec(
+				prometheus.CounterOpts{
+					Namespace:   metricNamespace,
+					Subsystem:   metricSubsystem,
+					ConstLabels: map[string]string{labelWorkerID: worker_id},
+					Name:        "reclaimed_locks_count",
+					Help: "Count the number of stale locked files that were put" +
+						" back in the queue. Broken down by job types",
+				},
+				[]string{labelJobType},
+			),
+
+			NumPredictedLarge: promauto.NewCounterVec(
+				prometheus.CounterOpts{
+					Namespace:   metricNamespace,
+					Subsystem:   metricSubsystem,
+					ConstLabels: map[string]string{labelWorkerID: worker_id},
+					Name:        "predicted_large_jobs_count",
+					Help: "Count the number of jobs deferred to the large prover" +
+						" without running the normal one because their traces were" +
+						" predicted to overflow. Broken down by job types",
+				},
+				[]string{labelJobType},
+			),
+
+			PickupLatency: promauto.NewSummaryVec(
+				prometheus.SummaryOpts{
+					Namespace:   metricNamespace,
+					Subsystem:   metricSubsystem,
+					ConstLabels: map[string]string{labelWorkerID: worker_id},
+					Name:        "pickup_latency_seconds",
+					Help: "Returns the time between the last modification of a" +
+						" request file and the moment it is locked by the prover",
+				},
+				[]string{labelJobType},
+			),
+
 			NumEntriesInDirectory: promauto.NewGaugeVec(
 				prometheus.GaugeOpts{
 					Namespace:   metricNamespace,
@@ -130,6 +243,13 @@ type Registry struct {
 	// The time it took to complete the job
 	ProcessingTime *prometheus.SummaryVec
 
+	// The resources used by the prover process of the jobs, as reported by
+	// the rusage of the process. They are used to size the instances.
+	MaxRSS          *prometheus.HistogramVec
+	UserCPUTime     *prometheus.HistogramVec
+	SysCPUTime      *prometheus.HistogramVec
+	MajorPageFaults *prometheus.HistogramVec
+
 	// The height at which the jobs are read. For instance, for a conflation
 	// these will give respectively the initial and the final l2 block of the
 	// conflation. They can be used to compute the range of the jobs also so
@@ -140,4 +260,27 @@ type Registry struct {
 	// The span of the job (i.e)
 	NumFilesInQueue       *prometheus.GaugeVec
 	NumEntriesInDirectory *prometheus.GaugeVec
+
+	// Number of jobs in the queue that are held back because the responses
+	// they depend on are not available yet, e.g. an aggregation waiting for
+	// the execution proofs. These are counted in NumFilesInQueue too.
+	NumBlockedJobs *prometheus.GaugeVec
+
+	// The time for which the oldest blocked job has been waiting for the
+	// responses it depends on, as seen by this controller. A value growing
+	// without bound means that a response never arrives.
+	BlockedJobsMaxWait *prometheus.GaugeVec
+
+	// Total number of stale locked files that were put back in the queue
+	// because their owner stopped refreshing their heartbeat.
+	NumReclaimedLocks *prometheus.CounterVec
+
+	// Total number of jobs deferred to the large prover by the prediction of
+	// the precheck command
+	NumPredictedLarge *prometheus.CounterVec
+
+	// The time between the moment a request file is written in the queue and
+	// the moment it is locked. It measures how fast the controller reacts to
+	// new jobs when it is idle.
+	PickupLatency *prometheus.SummaryVec
 }
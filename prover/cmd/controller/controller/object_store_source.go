@@ -0,0 +1,719 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/consensys/linea-monorepo/prover/cmd/controller/controller/metrics"
+	"github.com/consensys/linea-monorepo/prover/cmd/controller/controller/objstore"
+	"github.com/consensys/linea-monorepo/prover/config"
+	"github.com/sirupsen/logrus"
+)
+
+// Maximal duration of a single operation on the object store. It must allow
+// uploading and downloading the largest request and response files.
+const objectStoreTimeout = 10 * time.Minute
+
+// ObjectStoreSource is a [JobSource] backed by a bucket of an S3-compatible
+// object store, allowing controllers that do not share a filesystem to share
+// a queue. The objects are laid out as the requests directories: the
+// requests of a job type are under `<prefix>/<job type>/requests/`, etc.
+//
+// A job is locked by creating the object `<request>.inprogress` with a
+// conditional write that fails if the object already exists, so that a
+// single controller can lock a given request. The heartbeat rewrites the
+// lock object, conditionally on its ETag, and a stale lock is taken over with
+// a write conditioned on the ETag of the stale lock. The age of a lock is
+// measured with the clock of the store, using a probe object
+// `<prefix>/.clock/<local id>` rewritten at every listing. The requests are
+// downloaded in a local staging directory where the prover reads them and
+// writes its response. As the [FsWatcher], it holds back the aggregation jobs
+// until the responses they depend on are in the bucket; they are downloaded
+// in the local responses directories when the job is locked.
+type ObjectStoreSource struct {
+	// Unique ID of the container. Used to identify the owner of a lock
+	LocalID string
+	// List of jobs that we are currently matching. Their requests root
+	// directories are in the staging directory.
+	JobToWatch []JobDefinition
+	// Suffix of the lock objects
+	InProgress string
+	// Prefix of the keys of the queue
+	Prefix string
+	// Client to the bucket
+	Client *objstore.Client
+	// Period at which the lock objects are refreshed. Zero disables the
+	// heartbeat.
+	HeartbeatPeriod time.Duration
+	// Age of the heartbeat after which a lock is considered stale and
+	// taken over. Zero disables the recovery of stale locks.
+	StaleLockTTL time.Duration
+	// Time after which a job still waiting for the responses it depends on
+	// is reported with a warning. Zero disables the warning.
+	BlockedWarnDelay time.Duration
+	// Logger specific to the object store source
+	Logger *logrus.Entry
+	// Configuration of the controller, used to locate the response files
+	Config *config.Config
+
+	mu sync.Mutex
+	// ETags of the lock objects owned by this controller
+	locks map[string]string
+	// ETags of the stale lock objects found when listing the candidates
+	staleLocks map[string]string
+	// Dependencies of the requests found when listing the candidates,
+	// indexed by the key of the request
+	deps map[string]cachedDependencies
+
+	blocked blockedJobs
+}
+
+// The dependencies parsed from a version of a request object
+type cachedDependencies struct {
+	etag string
+	deps []dependency
+}
+
+// NewObjectStoreSource returns a source watching the bucket configured in
+// `controller.object_store`.
+func NewObjectStoreSource(conf *config.Config) (*ObjectStoreSource, error) {
+
+	storeConf := conf.Controller.ObjectStore
+	if len(storeConf.StagingDir) == 0 {
+		return nil, errors.New("no staging directory provided for the object store")
+	}
+
+	client, err := objstore.New(context.Background(), storeConf.Endpoint, storeConf.Region, storeConf.Bucket, "", "")
+	if err != nil {
+		return nil, fmt.Errorf("could not create the object store client: %w", err)
+	}
+
+	src := &ObjectStoreSource{
+		LocalID:          conf.Controller.LocalID,
+		JobToWatch:       enabledDefinitions(conf),
+		InProgress:       config.InProgressSufix,
+		Prefix:           storeConf.Prefix,
+		Client:           client,
+		HeartbeatPeriod:  time.Duration(conf.Controller.HeartbeatPeriod) * time.Second,
+		StaleLockTTL:     time.Duration(conf.Controller.StaleLockTTL) * time.Second,
+		BlockedWarnDelay: time.Duration(conf.Controller.BlockedWarnDelay) * time.Second,
+		Logger:           conf.Logger().WithField("component", "object-store-source"),
+		Config:           conf,
+		locks:            map[string]string{},
+		staleLocks:       map[string]string{},
+		deps:             map[string]cachedDependencies{},
+	}
+
+	// The prover reads and writes local files: the requests directories of
+	// the job definitions are replaced by the staging directory.
+	for i := range src.JobToWatch {
+		jdef := &src.JobToWatch[i]
+		jdef.RequestsRootDir = filepath.Join(storeConf.StagingDir, jdef.Name)
+		for _, dir := range []string{jdef.dirFrom(), jdef.dirTo()} {
+			if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+				return nil, fmt.Errorf("could not create the staging directory %s: %w", dir, err)
+			}
+		}
+	}
+
+	return src, nil
+}
+
+// Candidates returns the jobs found in the bucket that are not locked or
+// whose lock is stale. Implements [JobSource].
+func (s *ObjectStoreSource) Candidates() []*Job {
+
+	jobs := []*Job{}
+
+	if len(s.JobToWatch) == 0 {
+		s.Logger.Errorf("No job definition to watch")
+		return nil
+	}
+
+	// A zero time disables the detection of the stale locks for this
+	// listing.
+	var now time.Time
+	if s.StaleLockTTL > 0 {
+		var err error
+		if now, err = s.storeNow(); err != nil {
+			s.Logger.Errorf("Could not read the clock of the object store, not looking for stale locks: %v", err)
+		}
+	}
+
+	for i := range s.JobToWatch {
+		jdef := &s.JobToWatch[i]
+		if err := s.appendJobFromDef(jdef, now, &jobs); err != nil {
+			s.Logger.Errorf(
+				"Got an error trying to fetch job `%v` from the object store: %v",
+				jdef.Name, err,
+			)
+		}
+	}
+
+	return jobs
+}
+
+// Appends the jobs of a job definition found in the bucket. now is the time
+// of the store, the locks older than the stale lock TTL are taken over.
+func (s *ObjectStoreSource) appendJobFromDef(jdef *JobDefinition, now time.Time, jobs *[]*Job) error {
+
+	ctx, cancel := context.WithTimeout(context.Background(), objectStoreTimeout)
+	defer cancel()
+
+	dirPrefix := s.key(jdef, config.RequestsFromSubDir, "") + "/"
+	objects, err := s.Client.List(ctx, dirPrefix)
+	if err != nil {
+		return err
+	}
+
+	// Index the lock objects by the name of the request they lock and collect
+	// the requests
+	var (
+		locks    = map[string]objstore.Object{}
+		requests = []objstore.Object{}
+		listed   = map[string]bool{}
+	)
+
+	for _, obj := range objects {
+		name := strings.TrimPrefix(obj.Key, dirPrefix)
+		if strings.Contains(name, "/") {
+			continue
+		}
+
+		if orig, isLock := strings.CutSuffix(name, "."+s.InProgress); isLock {
+			locks[orig] = obj
+			continue
+		}
+		requests = append(requests, obj)
+		listed[obj.Key] = true
+	}
+
+	var (
+		numMatched = 0
+		blocked    = map[string][]string{}
+		staleLocks = map[string]string{}
+	)
+
+	for _, obj := range requests {
+
+		name := strings.TrimPrefix(obj.Key, dirPrefix)
+
+		job, err := NewJob(jdef, name)
+		if err != nil {
+			s.Logger.Debugf("Found invalid object `%v` : %v", name, err)
+			continue
+		}
+
+		if lock, isLocked := locks[name]; isLocked {
+			if now.IsZero() || now.Sub(lock.LastModified) <= s.StaleLockTTL {
+				continue
+			}
+
+			// The lock is stale, the job is a candidate. Locking it will
+			// take over the stale lock.
+			staleLocks[lock.Key] = lock.ETag
+		}
+
+		numMatched++
+
+		// The job is held back until the responses it depends on are
+		// available. Otherwise, it would fail.
+		if missing := s.missingResponses(job, obj.ETag); len(missing) > 0 {
+			blocked[job.OriginalFile] = missing
+			continue
+		}
+
+		*jobs = append(*jobs, job)
+	}
+
+	// The stale locks of the definition are replaced by the ones of this
+	// listing, so that the locks that disappeared or were refreshed since
+	// the previous listing are forgotten.
+	s.mu.Lock()
+	for key := range s.staleLocks {
+		if strings.HasPrefix(key, dirPrefix) {
+			delete(s.staleLocks, key)
+		}
+	}
+	for key, etag := range staleLocks {
+		s.staleLocks[key] = etag
+	}
+	// The dependencies of the requests that left the queue are forgotten
+	for key := range s.deps {
+		if strings.HasPrefix(key, dirPrefix) && !listed[key] {
+			delete(s.deps, key)
+		}
+	}
+	s.mu.Unlock()
+
+	metrics.CollectFS(jdef.Name, len(objects), numMatched)
+	if hasDependencies(jdef) {
+		maxWait := s.blocked.update(s.Logger, jdef.Name, blocked, s.BlockedWarnDelay)
+		metrics.CollectBlocked(jdef.Name, len(blocked), maxWait)
+	}
+
+	return nil
+}
+
+// Returns the current time of the object store: the modification time of a
+// probe object written for this purpose. The modification times of the locks
+// are set by the store, so comparing them with the local clock would make
+// the detection of the stale locks depend on the clock skew between the
+// controller and the store.
+func (s *ObjectStoreSource) storeNow() (time.Time, error) {
+
+	ctx, cancel := context.WithTimeout(context.Background(), objectStoreTimeout)
+	defer cancel()
+
+	var (
+		key     = path.Join(s.Prefix, ".clock", s.LocalID)
+		content = s.LocalID
+	)
+
+	if _, err := s.Client.Put(ctx, key, strings.NewReader(content), int64(len(content)), objstore.PutOptions{}); err != nil {
+		return time.Time{}, err
+	}
+
+	obj, err := s.Client.Head(ctx, key)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return obj.LastModified, nil
+}
+
+// Returns the responses the job depends on that are not in the bucket yet.
+// The job is blocked if there are any. etag is the ETag of the request
+// object found by the listing.
+func (s *ObjectStoreSource) missingResponses(job *Job, etag string) []string {
+
+	if !hasDependencies(job.Def) {
+		return nil
+	}
+
+	deps, err := s.dependencies(s.requestKey(job), etag)
+	if err != nil {
+		s.Logger.Warnf("Could not check the dependencies of %v, not waiting for them: %v", job.OriginalFile, err)
+		return nil
+	}
+
+	missing := []string{}
+	for _, dep := range deps {
+		exists, err := s.exists(s.dependencyKey(dep))
+		if err != nil {
+			s.Logger.Warnf("Could not check the dependencies of %v, not waiting for them: %v", job.OriginalFile, err)
+			return nil
+		}
+		if !exists {
+			missing = append(missing, dep.file)
+		}
+	}
+
+	return missing
+}
+
+// Returns the responses the aggregation request stored under the given key
+// depends on. The request is only downloaded and parsed if it is not in the
+// cache with the given ETag, so that a blocked job costs a single listing per
+// dependency at every poll.
+func (s *ObjectStoreSource) dependencies(key, etag string) ([]dependency, error) {
+
+	s.mu.Lock()
+	cached, ok := s.deps[key]
+	s.mu.Unlock()
+
+	if ok && cached.etag == etag {
+		return cached.deps, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), objectStoreTimeout)
+	defer cancel()
+
+	r, obj, err := s.Client.Get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	deps, err := requestDependencies(s.Config, r)
+	if err != nil {
+		return nil, err
+	}
+
+	// The ETag of the downloaded version is the one the next listings will
+	// return, even if the request was rewritten since the listing.
+	s.mu.Lock()
+	s.deps[key] = cachedDependencies{etag: obj.ETag, deps: deps}
+	s.mu.Unlock()
+
+	return deps, nil
+}
+
+// Downloads the responses the locked job depends on in the local directories
+// in which the prover looks for them, unless they are already there.
+func (s *ObjectStoreSource) downloadDependencies(job *Job) error {
+
+	if !hasDependencies(job.Def) {
+		return nil
+	}
+
+	f, err := os.Open(job.InProgressPath())
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	deps, err := requestDependencies(s.Config, f)
+	if err != nil {
+		return err
+	}
+
+	for _, dep := range deps {
+		dst := filepath.Join(dep.dir, dep.file)
+		if _, err := os.Stat(dst); err == nil {
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(dst), os.ModePerm); err != nil {
+			return err
+		}
+		if err := s.download(s.dependencyKey(dep), dst); err != nil {
+			return fmt.Errorf("could not download the response %v: %w", dep.file, err)
+		}
+	}
+
+	return nil
+}
+
+// Returns true if an object exists under the exact given key
+func (s *ObjectStoreSource) exists(key string) (bool, error) {
+
+	ctx, cancel := context.WithTimeout(context.Background(), objectStoreTimeout)
+	defer cancel()
+
+	objects, err := s.Client.List(ctx, key)
+	if err != nil {
+		return false, err
+	}
+
+	for _, obj := range objects {
+		if obj.Key == key {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// Lock creates the lock object of the job and downloads the request in the
+// staging directory. Implements [JobSource].
+func (s *ObjectStoreSource) Lock(job *Job) bool {
+
+	var (
+		lockKey = s.lockKey(job)
+		opts    = objstore.PutOptions{IfNoneMatch: true}
+	)
+
+	s.mu.Lock()
+	staleETag, isStale := s.staleLocks[lockKey]
+	delete(s.staleLocks, lockKey)
+	s.mu.Unlock()
+
+	// Taking over a stale lock only succeeds if no one refreshed it or took
+	// it over in the meantime.
+	if isStale {
+		opts = objstore.PutOptions{IfMatch: staleETag}
+	}
+
+	etag, err := s.putLock(lockKey, opts)
+	if err != nil {
+		if !errors.Is(err, objstore.ErrPreconditionFailed) {
+			s.Logger.Errorf("could not lock %v: %v", job.OriginalFile, err)
+		}
+		return false
+	}
+
+	if isStale {
+		s.Logger.Warnf("Took over the stale lock %v: the job is processed again", lockKey)
+		metrics.CollectReclaimedLock(job.Def.Name)
+	}
+
+	job.LockedFile = strings.Join([]string{job.OriginalFile, s.InProgress, s.LocalID}, ".")
+
+	if err := s.download(s.requestKey(job), job.InProgressPath()); err != nil {
+		// The request may have been completed by the owner of a lock that
+		// we wrongly considered stale.
+		s.Logger.Errorf("could not download the request %v: %v", job.OriginalFile, err)
+		s.release(lockKey, etag)
+		return false
+	}
+
+	// The responses it depends on were found in the bucket when listing the
+	// candidates. The prover reads them from the local responses directories.
+	if err := s.downloadDependencies(job); err != nil {
+		s.Logger.Errorf("could not download the dependencies of %v: %v", job.OriginalFile, err)
+		os.Remove(job.InProgressPath())
+		s.release(lockKey, etag)
+		return false
+	}
+
+	s.mu.Lock()
+	s.locks[lockKey] = etag
+	s.mu.Unlock()
+
+	return true
+}
+
+// StartHeartbeat rewrites the lock object of the job at every heartbeat
+// period so that the other controllers do not consider it stale. The
+// heartbeat runs until the returned function is called. Implements
+// [JobSource].
+func (s *ObjectStoreSource) StartHeartbeat(job *Job) (stop func()) {
+
+	if s.HeartbeatPeriod <= 0 {
+		return func() {}
+	}
+
+	var (
+		lockKey = s.lockKey(job)
+		done    = make(chan struct{})
+		stopped = make(chan struct{})
+		ticker  = time.NewTicker(s.HeartbeatPeriod)
+	)
+
+	go func() {
+		defer close(stopped)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				s.mu.Lock()
+				etag := s.locks[lockKey]
+				s.mu.Unlock()
+
+				newETag, err := s.putLock(lockKey, objstore.PutOptions{IfMatch: etag})
+				if err != nil {
+					// This happens if the lock was taken over by another
+					// controller. Nothing can be done at this point.
+					s.Logger.Errorf("could not refresh the heartbeat of %v: %v", lockKey, err)
+					continue
+				}
+
+				s.mu.Lock()
+				s.locks[lockKey] = newETag
+				s.mu.Unlock()
+			}
+		}
+	}()
+
+	return func() {
+		close(done)
+		<-stopped
+	}
+}
+
+// Complete uploads the response file written by the prover and moves the
+// request to the done prefix with the success suffix. If the upload fails,
+// the job is left locked and will be processed again once its lock is
+// stale. Implements [JobSource].
+func (s *ObjectStoreSource) Complete(job *Job, status Status) error {
+
+	respFile, err := job.ResponseFile()
+	if err != nil {
+		return fmt.Errorf("could not generate the response file name: %w", err)
+	}
+
+	tmpRespFile := job.TmpResponseFile(s.Config)
+	defer os.Remove(tmpRespFile)
+
+	respKey := s.key(job.Def, config.RequestsToSubDir, filepath.Base(respFile))
+	s.Logger.Infof("Uploading the response file `%v` to `%v`", tmpRespFile, respKey)
+
+	if err := s.upload(tmpRespFile, respKey); err != nil {
+		os.Remove(job.InProgressPath())
+		return fmt.Errorf("could not upload the response: %w", err)
+	}
+
+	return s.moveLocked(job, s.key(job.Def, config.RequestsDoneSubDir, filepath.Base(job.DoneFile(status))), status.metadata())
+}
+
+// Fail moves the request to the done prefix with a failure suffix for the
+// exit code. Implements [JobSource].
+func (s *ObjectStoreSource) Fail(job *Job, status Status) error {
+
+	s.Logger.Infof(
+		"Moving %v to the done requests with a failure suffix for code %v",
+		job.OriginalFile, status.ExitCode,
+	)
+
+	s.keepOverflowReport(job, job.DoneFile(status))
+	return s.moveLocked(job, s.key(job.Def, config.RequestsDoneSubDir, filepath.Base(job.DoneFile(status))), status.metadata())
+}
+
+// DeferToLarge moves the request back to the requests prefix with the suffix
+// of the large prover. Implements [JobSource].
+func (s *ObjectStoreSource) DeferToLarge(job *Job, status Status) error {
+
+	s.Logger.Infof("Renaming %v for the large prover", job.OriginalFile)
+
+	toLargePath, err := job.DeferToLargeFile(status)
+	if err != nil {
+		return fmt.Errorf("error deriving the to-large-name of %v: %w", job.OriginalFile, err)
+	}
+
+	s.keepOverflowReport(job, toLargePath)
+	return s.moveLocked(job, s.key(job.Def, config.RequestsFromSubDir, filepath.Base(toLargePath)), nil)
+}
+
+// Uploads the overflow report written by the prover, if any, under the
+// metadata prefix with the name of the file its request is moved to. Failing
+// to do so is not an error.
+func (s *ObjectStoreSource) keepOverflowReport(job *Job, to string) {
+
+	tmpReport := job.TmpOverflowReportFile(s.Config)
+	if _, err := os.Stat(tmpReport); err != nil {
+		return
+	}
+	defer os.Remove(tmpReport)
+
+	key := s.key(job.Def, config.RequestsDoneMetadataSubDir, filepath.Base(job.OverflowReportFile(to)))
+	if err := s.upload(tmpReport, key); err != nil {
+		s.Logger.Warnf("Could not upload the overflow report %v to %v: %v", tmpReport, key, err)
+	}
+}
+
+// Moves the request of a locked job to the given key and releases the lock.
+// The object store has no rename operation so the request is copied and then
+// deleted. If the copy fails, the job is left locked. The metadata, if
+// non-nil, is attached to the copy.
+func (s *ObjectStoreSource) moveLocked(job *Job, to string, metadata map[string]string) error {
+
+	ctx, cancel := context.WithTimeout(context.Background(), objectStoreTimeout)
+	defer cancel()
+
+	// The local copy of the request is not needed anymore
+	os.Remove(job.InProgressPath())
+
+	reqKey := s.requestKey(job)
+	if err := s.Client.Copy(ctx, reqKey, to, metadata); err != nil {
+		return err
+	}
+
+	errDel := s.Client.Delete(ctx, reqKey)
+
+	lockKey := s.lockKey(job)
+	s.mu.Lock()
+	etag := s.locks[lockKey]
+	s.mu.Unlock()
+	s.release(lockKey, etag)
+
+	return errDel
+}
+
+// Deletes a lock object and forgets about it. The lock is only deleted if its
+// ETag is the one of our last write: otherwise, it was taken over by another
+// controller and is left to it.
+func (s *ObjectStoreSource) release(lockKey, etag string) {
+
+	ctx, cancel := context.WithTimeout(context.Background(), objectStoreTimeout)
+	defer cancel()
+
+	err := s.Client.DeleteIfMatch(ctx, lockKey, etag)
+	switch {
+	case errors.Is(err, objstore.ErrPreconditionFailed):
+		s.Logger.Warnf("did not release the lock %v: it was taken over by another controller", lockKey)
+	case err != nil:
+		s.Logger.Errorf("could not release the lock %v: %v", lockKey, err)
+	}
+
+	s.mu.Lock()
+	delete(s.locks, lockKey)
+	s.mu.Unlock()
+}
+
+// Writes a lock object. The content identifies the owner and is unique so
+// that every write yields a new ETag.
+func (s *ObjectStoreSource) putLock(lockKey string, opts objstore.PutOptions) (etag string, err error) {
+
+	ctx, cancel := context.WithTimeout(context.Background(), objectStoreTimeout)
+	defer cancel()
+
+	content := fmt.Sprintf("%v %v", s.LocalID, time.Now().UnixNano())
+	return s.Client.Put(ctx, lockKey, strings.NewReader(content), int64(len(content)), opts)
+}
+
+// Downloads an object in a local file
+func (s *ObjectStoreSource) download(key, dst string) error {
+
+	ctx, cancel := context.WithTimeout(context.Background(), objectStoreTimeout)
+	defer cancel()
+
+	r, _, err := s.Client.Get(ctx, key)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	f, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+
+	_, errCopy := io.Copy(f, r)
+	if err := errors.Join(errCopy, f.Close()); err != nil {
+		os.Remove(dst)
+		return err
+	}
+
+	return nil
+}
+
+// Uploads a local file as an object
+func (s *ObjectStoreSource) upload(src, key string) error {
+
+	ctx, cancel := context.WithTimeout(context.Background(), objectStoreTimeout)
+	defer cancel()
+
+	f, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	finfo, err := f.Stat()
+	if err != nil {
+		return err
+	}
+
+	_, err = s.Client.Put(ctx, key, f, finfo.Size(), objstore.PutOptions{})
+	return err
+}
+
+// Returns the key of a file of a job definition. subDir is one of the
+// requests sub-directories.
+func (s *ObjectStoreSource) key(jdef *JobDefinition, subDir, name string) string {
+	return path.Join(s.Prefix, jdef.Name, subDir, name)
+}
+
+func (s *ObjectStoreSource) requestKey(job *Job) string {
+	return s.key(job.Def, config.RequestsFromSubDir, job.OriginalFile)
+}
+
+func (s *ObjectStoreSource) lockKey(job *Job) string {
+	return s.requestKey(job) + "." + s.InProgress
+}
+
+// Returns the key of a response a request depends on. It is uploaded there
+// by the [ObjectStoreSource.Complete] of the job producing it.
+func (s *ObjectStoreSource) dependencyKey(dep dependency) string {
+	return path.Join(s.Prefix, dep.jobName, config.RequestsToSubDir, dep.file)
+}
@@ -0,0 +1,270 @@
+package controller
+
+import (
+	"context"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/consensys/linea-monorepo/prover/cmd/controller/controller/objstore/objstoretest"
+	"github.com/consensys/linea-monorepo/prover/config"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestObjectStore starts an in-memory object store holding the bucket
+// "prover-queue". The credentials are given to the default chain of the SDK
+// through the environment.
+func newTestObjectStore(t *testing.T) *objstoretest.Server {
+	t.Setenv("AWS_ACCESS_KEY_ID", "test-key")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "test-secret")
+	t.Setenv("AWS_EC2_METADATA_DISABLED", "true")
+	srv := objstoretest.NewServer("prover-queue")
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestObjectStoreSource(t *testing.T) {
+
+	srv := newTestObjectStore(t)
+
+	newSource := func(localID string) *ObjectStoreSource {
+		confM, _ := setupFsTest(t)
+		confM.Controller.LocalID = localID
+		confM.Controller.StaleLockTTL = 60
+		confM.Controller.JobSource = config.JobSourceObjectStore
+		confM.Controller.ObjectStore = config.ObjectStore{
+			Endpoint:   srv.URL,
+			Bucket:     "prover-queue",
+			Prefix:     "queue",
+			StagingDir: t.TempDir(),
+		}
+		src, err := NewJobSource(confM)
+		require.NoError(t, err)
+		return src.(*ObjectStoreSource)
+	}
+
+	const (
+		reqs = "queue/execution/requests/"
+		resp = "queue/execution/responses/"
+		done = "queue/execution/requests-done/"
+		meta = "queue/execution/requests-done-metadata/"
+	)
+
+	var (
+		logger = logrus.NewEntry(logrus.StandardLogger())
+		srcA   = newSource("prover-a")
+		srcB   = newSource("prover-b")
+		file0  = "0-1-etv0.1.2-stv1.2.3-getZkProof.json"
+		file1  = "1-2-etv0.1.2-stv1.2.3-getZkProof.json"
+		file2  = "2-3-etv0.1.2-stv1.2.3-getZkProof.json"
+	)
+
+	srv.SetObject(reqs+file0, []byte("request-0"))
+	srv.SetObject(reqs+file1, []byte("request-1"))
+
+	// The controllers lock distinct jobs, by priority
+	jobA := getBest(srcA, logger, nil)
+	require.NotNil(t, jobA)
+	assert.Equal(t, file0, jobA.OriginalFile)
+
+	content, err := os.ReadFile(jobA.InProgressPath())
+	require.NoError(t, err)
+	assert.Equal(t, "request-0", string(content))
+
+	jobB := getBest(srcB, logger, nil)
+	require.NotNil(t, jobB)
+	assert.Equal(t, file1, jobB.OriginalFile)
+
+	assert.Nil(t, getBest(srcA, logger, nil))
+
+	// The heartbeat keeps the lock alive
+	srcA.HeartbeatPeriod = 10 * time.Millisecond
+	srv.SetLastModified(reqs+file0+".inprogress", time.Now().Add(-time.Hour))
+	stop := srcA.StartHeartbeat(jobA)
+	time.Sleep(100 * time.Millisecond)
+	stop()
+	assert.Nil(t, getBest(srcB, logger, nil))
+
+	// Completion of a job
+	require.NoError(t, os.WriteFile(jobA.TmpResponseFile(srcA.Config), []byte("proof"), 0600))
+	require.NoError(t, srcA.Complete(jobA, Status{ExitCode: CodeSuccess}))
+
+	proof, ok := srv.Object(resp + "0-1-getZkProof.json")
+	require.True(t, ok)
+	assert.Equal(t, "proof", string(proof))
+	assert.Equal(t, []string{done + file0 + ".success"}, srv.Keys(done))
+	metadata, _ := srv.Metadata(done + file0 + ".success")
+	assert.Equal(t, "0", metadata["exit-code"])
+	assert.NoFileExists(t, jobA.InProgressPath())
+	assert.NoFileExists(t, jobA.TmpResponseFile(srcA.Config))
+
+	// Deferral to the large prover, with the overflow report of the prover
+	require.NoError(t, os.WriteFile(jobB.TmpOverflowReportFile(srcB.Config), []byte(`{"overflows":[]}`), 0600))
+	require.NoError(t, srcB.DeferToLarge(jobB, Status{ExitCode: 137}))
+	assert.Equal(t, []string{reqs + file1 + ".large.failure.code_137"}, srv.Keys(reqs))
+	assert.Equal(t, []string{meta + file1 + ".large.failure.code_137.overflow.json"}, srv.Keys(meta))
+	assert.NoFileExists(t, jobB.TmpOverflowReportFile(srcB.Config))
+
+	// Takeover of a stale lock left by a killed controller
+	srv.SetObject(reqs+file2, []byte("request-2"))
+	srv.SetObject(reqs+file2+".inprogress", []byte("killed-prover"))
+	assert.Nil(t, getBest(srcA, logger, nil))
+
+	srv.SetLastModified(reqs+file2+".inprogress", time.Now().Add(-2*time.Minute))
+	jobA = getBest(srcA, logger, nil)
+	require.NotNil(t, jobA)
+	assert.Equal(t, file2, jobA.OriginalFile)
+
+	// Failure of a job
+	require.NoError(t, srcA.Fail(jobA, Status{ExitCode: 2, Usage: Usage{MaxRSS: 1 << 30}}))
+	metadata, _ = srv.Metadata(done + file2 + ".failure.code_2")
+	assert.Equal(t, "2", metadata["exit-code"])
+	assert.Equal(t, "1073741824", metadata["max-rss-bytes"])
+	assert.Equal(t, []string{done + file0 + ".success", done + file2 + ".failure.code_2"}, srv.Keys(done))
+	assert.Equal(t, []string{reqs + file1 + ".large.failure.code_137"}, srv.Keys(reqs))
+
+	// The stale locks that disappear from the bucket are forgotten
+	file3 := "3-4-etv0.1.2-stv1.2.3-getZkProof.json"
+	srv.SetObject(reqs+file3, []byte("request-3"))
+	srv.SetObject(reqs+file3+".inprogress", []byte("killed-prover"))
+	srv.SetLastModified(reqs+file3+".inprogress", time.Now().Add(-2*time.Minute))
+	assert.Len(t, srcB.Candidates(), 1)
+	assert.Contains(t, srcB.staleLocks, reqs+file3+".inprogress")
+
+	require.NoError(t, srcB.Client.Delete(context.Background(), reqs+file3+".inprogress"))
+	assert.Len(t, srcB.Candidates(), 1)
+	assert.Empty(t, srcB.staleLocks)
+}
+
+func TestObjectStoreDependencies(t *testing.T) {
+
+	srv := newTestObjectStore(t)
+
+	confM, _ := setupFsTest(t)
+	confM.Controller.JobSource = config.JobSourceObjectStore
+	confM.Controller.ObjectStore = config.ObjectStore{
+		Endpoint:   srv.URL,
+		Bucket:     "prover-queue",
+		Prefix:     "queue",
+		StagingDir: t.TempDir(),
+	}
+	src, err := NewJobSource(confM)
+	require.NoError(t, err)
+
+	var (
+		logger   = logrus.NewEntry(logrus.StandardLogger())
+		file     = createTestInputFile(t.TempDir(), 0, 2, aggregationJob, 0)
+		execResp = "0-2-getZkProof.json"
+		compResp = "0-2-getZkBlobCompressionProof.json"
+		request  = `{
+			"executionProofs": ["0-2-getZkProof.json"],
+			"compressionProofs": ["0-2-getZkBlobCompressionProof.json"]
+		}`
+	)
+
+	srv.SetObject("queue/aggregation/requests/"+file, []byte(request))
+
+	// The aggregation waits for the responses it aggregates. The request is
+	// only downloaded once while it is blocked.
+	assert.Nil(t, getBest(src, logger, nil))
+	assert.Nil(t, getBest(src, logger, nil))
+	assert.Equal(t, 1, srv.NumGets("queue/aggregation/requests/"+file))
+
+	srv.SetObject("queue/execution/responses/"+execResp, []byte("exec-proof"))
+	assert.Nil(t, getBest(src, logger, nil))
+
+	// A rewritten request is downloaded again
+	srv.SetObject("queue/aggregation/requests/"+file, []byte(request+" "))
+	assert.Nil(t, getBest(src, logger, nil))
+	assert.Equal(t, 2, srv.NumGets("queue/aggregation/requests/"+file))
+
+	// Once all the responses are in the bucket, the job is picked up and the
+	// responses are downloaded where the prover reads them.
+	srv.SetObject("queue/compression/responses/"+compResp, []byte("comp-proof"))
+	job := getBest(src, logger, nil)
+	require.NotNil(t, job)
+	assert.Equal(t, file, job.OriginalFile)
+
+	content, err := os.ReadFile(path.Join(confM.Execution.DirTo(), execResp))
+	require.NoError(t, err)
+	assert.Equal(t, "exec-proof", string(content))
+	content, err = os.ReadFile(path.Join(confM.BlobDecompression.DirTo(), compResp))
+	require.NoError(t, err)
+	assert.Equal(t, "comp-proof", string(content))
+}
+
+// The age of the locks is measured with the clock of the store, so a skew
+// between the clocks of the controller and of the store does not make a
+// fresh lock look stale.
+func TestObjectStoreStaleLockClockSkew(t *testing.T) {
+
+	srv := newTestObjectStore(t)
+
+	confM, _ := setupFsTest(t)
+	confM.Controller.StaleLockTTL = 60
+	confM.Controller.JobSource = config.JobSourceObjectStore
+	confM.Controller.ObjectStore = config.ObjectStore{
+		Endpoint:   srv.URL,
+		Bucket:     "prover-queue",
+		Prefix:     "queue",
+		StagingDir: t.TempDir(),
+	}
+	src, err := NewJobSource(confM)
+	require.NoError(t, err)
+
+	var (
+		logger = logrus.NewEntry(logrus.StandardLogger())
+		reqs   = "queue/execution/requests/"
+		file   = "0-1-etv0.1.2-stv1.2.3-getZkProof.json"
+	)
+
+	// The clock of the store is an hour late: the lock was written just now
+	srv.ClockSkew = -time.Hour
+	srv.SetObject(reqs+file, []byte("request"))
+	srv.SetObject(reqs+file+".inprogress", []byte("other-prover"))
+	assert.Nil(t, getBest(src, logger, nil))
+
+	// The lock is stale according to the store
+	srv.SetLastModified(reqs+file+".inprogress", time.Now().Add(-time.Hour-2*time.Minute))
+	job := getBest(src, logger, nil)
+	require.NotNil(t, job)
+	assert.Equal(t, file, job.OriginalFile)
+}
+
+// A controller does not release a lock that another controller took over.
+func TestObjectStoreReleaseTakenOverLock(t *testing.T) {
+
+	srv := newTestObjectStore(t)
+
+	confM, _ := setupFsTest(t)
+	confM.Controller.JobSource = config.JobSourceObjectStore
+	confM.Controller.ObjectStore = config.ObjectStore{
+		Endpoint:   srv.URL,
+		Bucket:     "prover-queue",
+		Prefix:     "queue",
+		StagingDir: t.TempDir(),
+	}
+	src, err := NewJobSource(confM)
+	require.NoError(t, err)
+
+	var (
+		logger = logrus.NewEntry(logrus.StandardLogger())
+		reqs   = "queue/execution/requests/"
+		file   = "0-1-etv0.1.2-stv1.2.3-getZkProof.json"
+	)
+
+	srv.SetObject(reqs+file, []byte("request"))
+	job := getBest(src, logger, nil)
+	require.NotNil(t, job)
+
+	// Another controller considered the lock stale and took it over
+	srv.SetObject(reqs+file+".inprogress", []byte("other-prover"))
+
+	require.NoError(t, src.Fail(job, Status{ExitCode: 2}))
+	lock, ok := srv.Object(reqs + file + ".inprogress")
+	require.True(t, ok)
+	assert.Equal(t, "other-prover", string(lock))
+}
@@ -0,0 +1,292 @@
+// Package objstore implements a minimal client for S3-compatible object
+// stores on top of the AWS SDK. It only covers the operations needed by the
+// controller to use a bucket as a job queue: listing, reading, writing,
+// copying and deleting objects. The writes can be made conditional on the
+// ETag of the object so that concurrent writers can be arbitrated by the
+// store.
+package objstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+var (
+	// ErrNotFound is returned when the object does not exist
+	ErrNotFound = errors.New("object not found")
+	// ErrPreconditionFailed is returned when the condition of a write is not
+	// met: the object already exists or its ETag changed.
+	ErrPreconditionFailed = errors.New("precondition failed")
+)
+
+// Client to a bucket of an S3-compatible object store
+type Client struct {
+	Bucket string
+	S3     *s3.Client
+}
+
+// Object describes an object of the bucket
+type Object struct {
+	Key          string
+	ETag         string
+	Size         int64
+	LastModified time.Time
+}
+
+// PutOptions are the conditions of a write. At most one of them should be
+// set.
+type PutOptions struct {
+	// Only write the object if it does not already exist
+	IfNoneMatch bool
+	// Only write the object if its current ETag is the given one
+	IfMatch string
+}
+
+// New returns a client to the bucket. An empty endpoint selects the AWS S3
+// endpoint of the region. Otherwise, the bucket is addressed path-style, i.e.
+// as `<endpoint>/<bucket>/<key>`, which the self-hosted stores support.
+//
+// If the credentials are left empty, they are resolved by the default chain
+// of the AWS SDK: the environment variables, the shared configuration files,
+// the web identity token of IRSA, the container and the instance profile
+// credentials. The temporary credentials are refreshed before they expire.
+func New(ctx context.Context, endpoint, region, bucket, accessKeyID, secretAccessKey string) (*Client, error) {
+
+	if len(bucket) == 0 {
+		return nil, errors.New("no bucket provided")
+	}
+
+	if len(endpoint) > 0 {
+		u, err := url.Parse(endpoint)
+		if err != nil {
+			return nil, fmt.Errorf("invalid endpoint %q: %w", endpoint, err)
+		}
+		if u.Scheme != "http" && u.Scheme != "https" {
+			return nil, fmt.Errorf("invalid endpoint %q: the scheme must be http or https", endpoint)
+		}
+	}
+
+	if len(region) == 0 {
+		region = "us-east-1"
+	}
+
+	loadOpts := []func(*awsconfig.LoadOptions) error{awsconfig.WithRegion(region)}
+	if len(accessKeyID) > 0 || len(secretAccessKey) > 0 {
+		loadOpts = append(loadOpts, awsconfig.WithCredentialsProvider(
+			credentials.NewStaticCredentialsProvider(accessKeyID, secretAccessKey, ""),
+		))
+	}
+
+	cfg, err := awsconfig.LoadDefaultConfig(ctx, loadOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("could not load the AWS configuration: %w", err)
+	}
+
+	client := s3.NewFromConfig(cfg, func(o *s3.Options) {
+		if len(endpoint) > 0 {
+			o.BaseEndpoint = aws.String(endpoint)
+			o.UsePathStyle = true
+		}
+		// The default checksums are sent in trailers of chunked uploads,
+		// which not every self-hosted store supports.
+		o.RequestChecksumCalculation = aws.RequestChecksumCalculationWhenRequired
+		o.ResponseChecksumValidation = aws.ResponseChecksumValidationWhenRequired
+	})
+
+	return &Client{Bucket: bucket, S3: client}, nil
+}
+
+// List returns all the objects whose key starts with the prefix
+func (c *Client) List(ctx context.Context, prefix string) ([]Object, error) {
+
+	var (
+		res       []Object
+		paginator = s3.NewListObjectsV2Paginator(c.S3, &s3.ListObjectsV2Input{
+			Bucket: aws.String(c.Bucket),
+			Prefix: aws.String(prefix),
+		})
+	)
+
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("could not list %q: %w", prefix, convertError(err))
+		}
+
+		for _, o := range page.Contents {
+			res = append(res, Object{
+				Key:          aws.ToString(o.Key),
+				ETag:         aws.ToString(o.ETag),
+				Size:         aws.ToInt64(o.Size),
+				LastModified: aws.ToTime(o.LastModified),
+			})
+		}
+	}
+
+	return res, nil
+}
+
+// Get returns the content of an object. The caller is responsible for closing
+// the returned reader.
+func (c *Client) Get(ctx context.Context, key string) (io.ReadCloser, Object, error) {
+
+	resp, err := c.S3.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(c.Bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return nil, Object{}, fmt.Errorf("could not get %q: %w", key, convertError(err))
+	}
+
+	return resp.Body, Object{
+		Key:          key,
+		ETag:         aws.ToString(resp.ETag),
+		Size:         aws.ToInt64(resp.ContentLength),
+		LastModified: aws.ToTime(resp.LastModified),
+	}, nil
+}
+
+// Head returns the description of an object without its content
+func (c *Client) Head(ctx context.Context, key string) (Object, error) {
+
+	resp, err := c.S3.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(c.Bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return Object{}, fmt.Errorf("could not head %q: %w", key, convertError(err))
+	}
+
+	return Object{
+		Key:          key,
+		ETag:         aws.ToString(resp.ETag),
+		Size:         aws.ToInt64(resp.ContentLength),
+		LastModified: aws.ToTime(resp.LastModified),
+	}, nil
+}
+
+// Put writes an object of the given size and returns its new ETag. Over
+// plain HTTP, the body must be an [io.Seeker] so that the payload can be
+// signed.
+func (c *Client) Put(ctx context.Context, key string, body io.Reader, size int64, opts PutOptions) (etag string, err error) {
+
+	input := &s3.PutObjectInput{
+		Bucket:        aws.String(c.Bucket),
+		Key:           aws.String(key),
+		Body:          body,
+		ContentLength: aws.Int64(size),
+	}
+
+	if opts.IfNoneMatch {
+		input.IfNoneMatch = aws.String("*")
+	}
+	if len(opts.IfMatch) > 0 {
+		input.IfMatch = aws.String(opts.IfMatch)
+	}
+
+	resp, err := c.S3.PutObject(ctx, input)
+	if err != nil {
+		return "", fmt.Errorf("could not put %q: %w", key, convertError(err))
+	}
+
+	return aws.ToString(resp.ETag), nil
+}
+
+// Copy copies the object at src to dst within the bucket. If metadata is
+// non-nil, it replaces the user metadata of the copy. Otherwise, the copy
+// keeps the metadata of the source.
+func (c *Client) Copy(ctx context.Context, src, dst string, metadata map[string]string) error {
+
+	input := &s3.CopyObjectInput{
+		Bucket:     aws.String(c.Bucket),
+		Key:        aws.String(dst),
+		CopySource: aws.String(url.PathEscape(c.Bucket) + "/" + (&url.URL{Path: src}).EscapedPath()),
+	}
+
+	if metadata != nil {
+		input.MetadataDirective = types.MetadataDirectiveReplace
+		input.Metadata = metadata
+	}
+
+	if _, err := c.S3.CopyObject(ctx, input); err != nil {
+		return fmt.Errorf("could not copy %q to %q: %w", src, dst, convertError(err))
+	}
+
+	return nil
+}
+
+// Delete deletes an object. Deleting an object that does not exist is not an
+// error.
+func (c *Client) Delete(ctx context.Context, key string) error {
+
+	_, err := c.S3.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(c.Bucket),
+		Key:    aws.String(key),
+	})
+	if err = convertError(err); err != nil && !errors.Is(err, ErrNotFound) {
+		return fmt.Errorf("could not delete %q: %w", key, err)
+	}
+
+	return nil
+}
+
+// DeleteIfMatch deletes an object only if its ETag is the given one and
+// returns [ErrPreconditionFailed] otherwise. Deleting an object that does not
+// exist is not an error. The delete request is conditional but not all the
+// stores honour the condition, so the ETag is checked beforehand as well.
+func (c *Client) DeleteIfMatch(ctx context.Context, key, etag string) error {
+
+	obj, err := c.Head(ctx, key)
+	if errors.Is(err, ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if obj.ETag != etag {
+		return fmt.Errorf("could not delete %q: %w: its ETag is %v", key, ErrPreconditionFailed, obj.ETag)
+	}
+
+	_, err = c.S3.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket:  aws.String(c.Bucket),
+		Key:     aws.String(key),
+		IfMatch: aws.String(etag),
+	})
+	if err = convertError(err); err != nil && !errors.Is(err, ErrNotFound) {
+		return fmt.Errorf("could not delete %q: %w", key, err)
+	}
+
+	return nil
+}
+
+// Wraps the errors of the SDK in [ErrNotFound] and [ErrPreconditionFailed]
+// depending on the status of the response.
+func convertError(err error) error {
+
+	var respErr *awshttp.ResponseError
+	if err == nil || !errors.As(err, &respErr) {
+		return err
+	}
+
+	switch respErr.HTTPStatusCode() {
+	case http.StatusNotFound:
+		return fmt.Errorf("%w: %w", ErrNotFound, err)
+	case http.StatusPreconditionFailed, http.StatusConflict:
+		// Concurrent conditional writes can be answered with a conflict
+		return fmt.Errorf("%w: %w", ErrPreconditionFailed, err)
+	}
+
+	return err
+}
@@ -0,0 +1,91 @@
+package objstore_test
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/consensys/linea-monorepo/prover/cmd/controller/controller/objstore"
+	"github.com/consensys/linea-monorepo/prover/cmd/controller/controller/objstore/objstoretest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient(t *testing.T) {
+
+	srv := objstoretest.NewServer("bucket")
+	defer srv.Close()
+	srv.PageSize = 2
+
+	c, err := objstore.New(context.Background(), srv.URL, "", "bucket", "key", "secret")
+	require.NoError(t, err)
+
+	var (
+		ctx = context.Background()
+		put = func(key, body string, opts objstore.PutOptions) (string, error) {
+			return c.Put(ctx, key, strings.NewReader(body), int64(len(body)), opts)
+		}
+	)
+
+	// Conditional creation
+	etag, err := put("q/a b.json", "a", objstore.PutOptions{IfNoneMatch: true})
+	require.NoError(t, err)
+	_, err = put("q/a b.json", "b", objstore.PutOptions{IfNoneMatch: true})
+	assert.ErrorIs(t, err, objstore.ErrPreconditionFailed)
+
+	// Conditional update
+	_, err = put("q/a b.json", "b", objstore.PutOptions{IfMatch: `"not-the-etag"`})
+	assert.ErrorIs(t, err, objstore.ErrPreconditionFailed)
+	_, err = put("q/a b.json", "b", objstore.PutOptions{IfMatch: etag})
+	require.NoError(t, err)
+
+	r, obj, err := c.Get(ctx, "q/a b.json")
+	require.NoError(t, err)
+	content, err := io.ReadAll(r)
+	r.Close()
+	require.NoError(t, err)
+	assert.Equal(t, "b", string(content))
+	assert.NotEqual(t, etag, obj.ETag)
+
+	head, err := c.Head(ctx, "q/a b.json")
+	require.NoError(t, err)
+	assert.Equal(t, obj.ETag, head.ETag)
+	assert.Equal(t, int64(1), head.Size)
+	assert.WithinDuration(t, time.Now(), head.LastModified, time.Minute)
+
+	_, err = c.Head(ctx, "q/missing")
+	assert.ErrorIs(t, err, objstore.ErrNotFound)
+
+	_, _, err = c.Get(ctx, "q/missing")
+	assert.ErrorIs(t, err, objstore.ErrNotFound)
+
+	// Copy and paginated listing
+	require.NoError(t, c.Copy(ctx, "q/a b.json", "q/c+d.json", nil))
+	_, err = put("q/e.json", "e", objstore.PutOptions{})
+	require.NoError(t, err)
+	_, err = put("other/f.json", "f", objstore.PutOptions{})
+	require.NoError(t, err)
+
+	objs, err := c.List(ctx, "q/")
+	require.NoError(t, err)
+	keys := []string{}
+	for _, o := range objs {
+		keys = append(keys, o.Key)
+	}
+	assert.Equal(t, []string{"q/a b.json", "q/c+d.json", "q/e.json"}, keys)
+
+	// Deletion, including of missing objects
+	require.NoError(t, c.Delete(ctx, "q/a b.json"))
+	require.NoError(t, c.Delete(ctx, "q/a b.json"))
+	assert.Equal(t, []string{"q/c+d.json", "q/e.json"}, srv.Keys("q/"))
+
+	// Conditional deletion
+	etag, err = put("q/e.json", "e2", objstore.PutOptions{})
+	require.NoError(t, err)
+	assert.ErrorIs(t, c.DeleteIfMatch(ctx, "q/e.json", `"not-the-etag"`), objstore.ErrPreconditionFailed)
+	require.NoError(t, c.DeleteIfMatch(ctx, "q/e.json", etag))
+	require.NoError(t, c.DeleteIfMatch(ctx, "q/e.json", etag))
+	assert.Equal(t, []string{"q/c+d.json"}, srv.Keys("q/"))
+}
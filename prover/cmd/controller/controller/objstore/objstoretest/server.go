@@ -0,0 +1,302 @@
+// Package objstoretest provides an in-memory stand-in of an S3-compatible
+// object store for testing the users of the objstore package. It only
+// implements the subset of the API used by the objstore client, including the
+// conditional writes, and does not check the signatures.
+package objstoretest
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Server is an in-memory object store holding a single bucket
+type Server struct {
+	*httptest.Server
+	Bucket string
+	// Number of keys returned per page of a listing. Zero means 1000 as in
+	// S3.
+	PageSize int
+	// Offset of the clock of the store with respect to the local clock. It
+	// applies to the modification time of the objects written afterwards.
+	ClockSkew time.Duration
+
+	mu      sync.Mutex
+	objects map[string]*object
+	gets    map[string]int
+}
+
+type object struct {
+	data         []byte
+	etag         string
+	lastModified time.Time
+	metadata     map[string]string
+}
+
+// NewServer starts a new server. It must be closed by the caller.
+func NewServer(bucket string) *Server {
+	s := &Server{
+		Bucket:  bucket,
+		objects: map[string]*object{},
+		gets:    map[string]int{},
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.handle))
+	return s
+}
+
+// Keys returns the sorted keys of the objects starting with the prefix
+func (s *Server) Keys(prefix string) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	keys := []string{}
+	for k := range s.objects {
+		if strings.HasPrefix(k, prefix) {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Object returns the content of an object and false if it does not exist
+func (s *Server) Object(key string) ([]byte, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	o, ok := s.objects[key]
+	if !ok {
+		return nil, false
+	}
+	return o.data, true
+}
+
+// Metadata returns the user metadata of an object, without the
+// `x-amz-meta-` prefix and with lowercase keys, and false if it does not
+// exist.
+func (s *Server) Metadata(key string) (map[string]string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	o, ok := s.objects[key]
+	if !ok {
+		return nil, false
+	}
+	return o.metadata, true
+}
+
+// NumGets returns the number of times the content of an object was read
+func (s *Server) NumGets(key string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.gets[key]
+}
+
+// SetObject writes an object
+func (s *Server) SetObject(key string, data []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.put(key, data)
+}
+
+// SetLastModified overrides the modification time of an object, e.g. to
+// simulate that it was written a long time ago.
+func (s *Server) SetLastModified(key string, t time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if o, ok := s.objects[key]; ok {
+		o.lastModified = t
+	}
+}
+
+func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
+
+	bucket, key, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/"), "/")
+	if bucket != s.Bucket {
+		writeError(w, http.StatusNotFound, "NoSuchBucket")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch {
+	case r.Method == http.MethodGet && len(key) == 0:
+		s.list(w, r)
+	case r.Method == http.MethodGet:
+		s.gets[key]++
+		s.get(w, key)
+	case r.Method == http.MethodHead:
+		// The body of the responses to HEAD requests is discarded by the
+		// HTTP server.
+		s.get(w, key)
+	case r.Method == http.MethodPut && len(r.Header.Get("X-Amz-Copy-Source")) > 0:
+		s.copy(w, r, key)
+	case r.Method == http.MethodPut:
+		s.putObject(w, r, key)
+	case r.Method == http.MethodDelete:
+		if o, ok := s.objects[key]; ok && len(r.Header.Get("If-Match")) > 0 && o.etag != r.Header.Get("If-Match") {
+			writeError(w, http.StatusPreconditionFailed, "PreconditionFailed")
+			return
+		}
+		delete(s.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "MethodNotAllowed")
+	}
+}
+
+func (s *Server) list(w http.ResponseWriter, r *http.Request) {
+
+	var (
+		query    = r.URL.Query()
+		prefix   = query.Get("prefix")
+		after    = query.Get("continuation-token")
+		pageSize = s.PageSize
+		keys     = []string{}
+	)
+
+	if pageSize <= 0 {
+		pageSize = 1000
+	}
+
+	for k := range s.objects {
+		if strings.HasPrefix(k, prefix) && k > after {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	type content struct {
+		Key          string
+		ETag         string
+		Size         int
+		LastModified string
+	}
+
+	res := struct {
+		XMLName               xml.Name `xml:"ListBucketResult"`
+		Contents              []content
+		IsTruncated           bool
+		NextContinuationToken string `xml:",omitempty"`
+	}{}
+
+	if len(keys) > pageSize {
+		keys = keys[:pageSize]
+		res.IsTruncated = true
+		res.NextContinuationToken = keys[len(keys)-1]
+	}
+
+	for _, k := range keys {
+		o := s.objects[k]
+		res.Contents = append(res.Contents, content{
+			Key:          k,
+			ETag:         o.etag,
+			Size:         len(o.data),
+			LastModified: o.lastModified.UTC().Format(time.RFC3339Nano),
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/xml")
+	xml.NewEncoder(w).Encode(res)
+}
+
+func (s *Server) get(w http.ResponseWriter, key string) {
+
+	o, ok := s.objects[key]
+	if !ok {
+		writeError(w, http.StatusNotFound, "NoSuchKey")
+		return
+	}
+
+	w.Header().Set("ETag", o.etag)
+	w.Header().Set("Last-Modified", o.lastModified.UTC().Format(http.TimeFormat))
+	w.Header().Set("Content-Length", strconv.Itoa(len(o.data)))
+	w.Write(o.data)
+}
+
+func (s *Server) putObject(w http.ResponseWriter, r *http.Request, key string) {
+
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "IncompleteBody")
+		return
+	}
+
+	o, exists := s.objects[key]
+
+	if r.Header.Get("If-None-Match") == "*" && exists {
+		writeError(w, http.StatusPreconditionFailed, "PreconditionFailed")
+		return
+	}
+
+	if ifMatch := r.Header.Get("If-Match"); len(ifMatch) > 0 && (!exists || o.etag != ifMatch) {
+		if !exists {
+			writeError(w, http.StatusNotFound, "NoSuchKey")
+			return
+		}
+		writeError(w, http.StatusPreconditionFailed, "PreconditionFailed")
+		return
+	}
+
+	w.Header().Set("ETag", s.put(key, data).etag)
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) copy(w http.ResponseWriter, r *http.Request, key string) {
+
+	src, err := url.PathUnescape(r.Header.Get("X-Amz-Copy-Source"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "InvalidArgument")
+		return
+	}
+
+	src = strings.TrimPrefix(strings.TrimPrefix(src, "/"), s.Bucket+"/")
+	o, ok := s.objects[src]
+	if !ok {
+		writeError(w, http.StatusNotFound, "NoSuchKey")
+		return
+	}
+
+	metadata := o.metadata
+	if r.Header.Get("X-Amz-Metadata-Directive") == "REPLACE" {
+		metadata = map[string]string{}
+		for k := range r.Header {
+			if name, isMeta := strings.CutPrefix(strings.ToLower(k), "x-amz-meta-"); isMeta {
+				metadata[name] = r.Header.Get(k)
+			}
+		}
+	}
+
+	dst := s.put(key, o.data)
+	dst.metadata = metadata
+	fmt.Fprintf(w, "<CopyObjectResult><ETag>%v</ETag></CopyObjectResult>", dst.etag)
+}
+
+func (s *Server) put(key string, data []byte) *object {
+	sum := md5.Sum(data)
+	o := &object{
+		data:         append([]byte{}, data...),
+		etag:         `"` + hex.EncodeToString(sum[:]) + `"`,
+		lastModified: time.Now().Add(s.ClockSkew),
+	}
+	s.objects[key] = o
+	return o
+}
+
+func writeError(w http.ResponseWriter, status int, code string) {
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(status)
+	fmt.Fprintf(w, "<Error><Code>%v</Code><Message>%v</Message></Error>", code, code)
+}
@@ -0,0 +1,129 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/consensys/linea-monorepo/prover/cmd/controller/controller/metrics"
+	"github.com/consensys/linea-monorepo/prover/config"
+)
+
+// Targets of the report of the precheck command for which the job is sent to
+// the large prover. These are the values of `limitcheck.Target`.
+const (
+	precheckTargetLarge  = "large"
+	precheckTargetReject = "reject"
+)
+
+// The part of the report of `prover check-limits --json` used by the
+// controller.
+type precheckReport struct {
+	Target string `json:"target"`
+}
+
+// PredictLarge runs the precheck command on an execution job and returns true
+// if its traces are predicted to overflow the limits of the normal prover. It
+// only applies to the controllers that cannot run the large prover
+// themselves. If the prediction is disabled or fails, it returns false and
+// the job runs as usual.
+//
+// The prediction is as good as the counters of the precheck, see the
+// limitcheck package: they cover the modules and the precompiles but not the
+// keccak permutations, the L1 size and the L2 to L1 logs of the blocks. An
+// overflow of these is only detected when the normal prover fails, and the
+// job is then deferred as usual.
+func (e *Executor) PredictLarge(job *Job) bool {
+
+	var (
+		ctrl = &e.Config.Controller
+		log  = e.Logger.WithField("job", job.OriginalFile)
+	)
+
+	if ctrl.PrecheckCmdTmpl == nil ||
+		job.Def.Name != jobNameExecution ||
+		e.Config.Execution.CanRunFullLarge ||
+		strings.Contains(job.OriginalFile, config.LargeSuffix) {
+		return false
+	}
+
+	w := &strings.Builder{}
+	resource := Resource{ConfFile: fConfig, InFile: job.InProgressPath(), JobType: job.Def.ProverJobType}
+	if err := ctrl.PrecheckCmdTmpl.Execute(w, resource); err != nil {
+		log.Errorf("could not generate the precheck command, running the normal prover: %v", err)
+		return false
+	}
+
+	start := time.Now()
+	report, err := runPrecheck(w.String(), time.Duration(ctrl.PrecheckTimeout)*time.Second)
+	if err != nil {
+		// A non-zero exit code means that the precheck is broken or that the
+		// request is invalid, unlike a timeout which only means that it was
+		// slow.
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			log.Errorf("the precheck exited with code %v, running the normal prover: %v", exitErr.ExitCode(), err)
+		} else {
+			log.Warnf("the precheck failed, running the normal prover: %v", err)
+		}
+		return false
+	}
+
+	log.Infof("the precheck predicted the target `%v` in %v", report.Target, time.Since(start).Round(time.Millisecond))
+
+	switch report.Target {
+	case precheckTargetLarge:
+		metrics.CollectPredictedLarge(job.Def.Name)
+		return true
+	case precheckTargetReject:
+		// The large prover is expected to fail as well but the estimation
+		// may be off, so it is left to decide.
+		log.Warnf("the traces are predicted to overflow the limits of the large prover too")
+		metrics.CollectPredictedLarge(job.Def.Name)
+		return true
+	default:
+		return false
+	}
+}
+
+// Runs the precheck command and parses the report it prints on its standard
+// output. The whole process group of the command is killed on timeout.
+func runPrecheck(cmdStr string, timeout time.Duration) (*precheckReport, error) {
+
+	ctx := context.Background()
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	cmd := exec.CommandContext(ctx, "sh", "-c", cmdStr)
+	cmd.Stderr = os.Stderr
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.Cancel = func() error {
+		return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	}
+	// Do not wait for the processes of the group holding the output
+	cmd.WaitDelay = time.Second
+
+	out, err := cmd.Output()
+	if ctx.Err() != nil {
+		return nil, fmt.Errorf("timed out after %v", timeout)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("command `%v`: %w", cmdStr, err)
+	}
+
+	report := &precheckReport{}
+	if err := json.Unmarshal(out, report); err != nil {
+		return nil, fmt.Errorf("could not parse the report: %w", err)
+	}
+
+	return report, nil
+}
@@ -0,0 +1,106 @@
+package controller
+
+import (
+	"context"
+	"os"
+	"path"
+	"testing"
+	"text/template"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	logtest "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPredictLarge(t *testing.T) {
+
+	confM, confL := setupFsTest(t)
+	confM.Controller.HistoryDir = t.TempDir()
+
+	// The jobs whose request contains "overflow" are predicted to overflow
+	confM.Controller.PrecheckCmdTmpl = template.Must(template.New("precheck").Parse(
+		`grep -q overflow {{.InFile}} && echo '{"target":"large"}' || echo '{"target":"normal"}'`,
+	))
+	// The large controller never runs the precheck
+	confL.Controller.PrecheckCmdTmpl = template.Must(template.New("precheck").Parse("exit 1"))
+
+	var (
+		eFrom   = confM.Execution.DirFrom()
+		eDone   = confM.Execution.DirDone()
+		large   = createTestInputFile(eFrom, 0, 1, execJob, 0)
+		regular = createTestInputFile(eFrom, 1, 2, execJob, 0)
+	)
+
+	// The normal prover would fail on the first job. The large one, which
+	// subtracts 12 from the exit code, succeeds.
+	require.NoError(t, os.WriteFile(path.Join(eFrom, large), []byte("#!/bin/sh\n# overflow\nexit 12"), 0600))
+
+	ctxM, stopM := context.WithCancel(context.Background())
+	ctxL, stopL := context.WithCancel(context.Background())
+	go runController(ctxM, confM)
+	go runController(ctxL, confL)
+	<-time.After(2 * time.Second)
+	stopM()
+	stopL()
+
+	assert.FileExists(t, path.Join(eDone, large+".large.success"))
+	assert.FileExists(t, path.Join(eDone, regular+".success"))
+
+	// The normal prover did not run the first job
+	runs, err := ReadHistory(confM.Controller.HistoryDir, HistoryFilter{Status: JobDeferredToLarge})
+	require.NoError(t, err)
+	require.Len(t, runs, 1)
+	assert.Equal(t, large, runs[0].File)
+	assert.Equal(t, CodeTraceLimit, runs[0].ExitCode)
+}
+
+func TestPredictLargePrecheckError(t *testing.T) {
+
+	conf, _ := setupFsTest(t)
+
+	var (
+		logger, hook = logtest.NewNullLogger()
+		e            = &Executor{Config: conf, Logger: logrus.NewEntry(logger)}
+		def          = ExecutionDefinition(conf)
+	)
+
+	job, err := NewJob(&def, createTestInputFile(conf.Execution.DirFrom(), 0, 1, execJob, 0))
+	require.NoError(t, err)
+
+	// A failing precheck sends the job to the normal prover and is logged as
+	// an error
+	conf.Controller.PrecheckCmdTmpl = template.Must(template.New("precheck").Parse("exit 3"))
+	assert.False(t, e.PredictLarge(job))
+	require.NotNil(t, hook.LastEntry())
+	assert.Equal(t, logrus.ErrorLevel, hook.LastEntry().Level)
+	assert.Contains(t, hook.LastEntry().Message, "exited with code 3")
+
+	// A timeout is only a warning
+	hook.Reset()
+	conf.Controller.PrecheckTimeout = 1
+	conf.Controller.PrecheckCmdTmpl = template.Must(template.New("precheck").Parse("sleep 30"))
+	assert.False(t, e.PredictLarge(job))
+	require.NotNil(t, hook.LastEntry())
+	assert.Equal(t, logrus.WarnLevel, hook.LastEntry().Level)
+}
+
+func TestRunPrecheck(t *testing.T) {
+
+	report, err := runPrecheck(`echo '{"target":"reject","usages":[]}'`, time.Second)
+	require.NoError(t, err)
+	assert.Equal(t, precheckTargetReject, report.Target)
+
+	_, err = runPrecheck("echo not-json", time.Second)
+	assert.Error(t, err)
+
+	_, err = runPrecheck("exit 2", time.Second)
+	assert.Error(t, err)
+
+	// The timeout also kills the children of the shell
+	start := time.Now()
+	_, err = runPrecheck("sleep 30; echo '{}'", 100*time.Millisecond)
+	assert.Error(t, err)
+	assert.Less(t, time.Since(start), 5*time.Second)
+}
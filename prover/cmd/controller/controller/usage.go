@@ -0,0 +1,95 @@
+package controller
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"syscall"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// Prefix of the extended attributes of the done files
+const xattrPrefix = "user.prover."
+
+// Usage holds the resources used by the prover process and by its waited-for
+// children, as reported by the rusage of the process.
+type Usage struct {
+	// Peak resident set size in bytes
+	MaxRSS      int64
+	UserTime    time.Duration
+	SysTime     time.Duration
+	MajorFaults int64
+}
+
+// Returns the usage of a process that has exited. It returns the zero value
+// if the rusage is not available on the platform.
+func usageOf(pstate *os.ProcessState) Usage {
+
+	rusage, ok := pstate.SysUsage().(*syscall.Rusage)
+	if !ok || rusage == nil {
+		return Usage{}
+	}
+
+	// The peak RSS is given in KiB on Linux and in bytes on macOS
+	maxRSS := int64(rusage.Maxrss)
+	if runtime.GOOS != "darwin" {
+		maxRSS *= 1024
+	}
+
+	return Usage{
+		MaxRSS:      maxRSS,
+		UserTime:    time.Duration(rusage.Utime.Nano()),
+		SysTime:     time.Duration(rusage.Stime.Nano()),
+		MajorFaults: int64(rusage.Majflt),
+	}
+}
+
+// Returns the metadata attached to the done file of a job: the exit code of
+// the prover, whether it ran in large mode and the resources it used.
+func (s Status) metadata() map[string]string {
+	return map[string]string{
+		"exit-code":         strconv.Itoa(s.ExitCode),
+		"large":             strconv.FormatBool(s.Large),
+		"max-rss-bytes":     strconv.FormatInt(s.Usage.MaxRSS, 10),
+		"user-cpu-seconds":  strconv.FormatFloat(s.Usage.UserTime.Seconds(), 'f', 3, 64),
+		"sys-cpu-seconds":   strconv.FormatFloat(s.Usage.SysTime.Seconds(), 'f', 3, 64),
+		"major-page-faults": strconv.FormatInt(s.Usage.MajorFaults, 10),
+	}
+}
+
+// Writes the metadata of the status of a job as a JSON object in its
+// metadata file.
+func writeDoneMetadata(job *Job, status Status) error {
+
+	content, err := json.MarshalIndent(status.metadata(), "", "  ")
+	if err != nil {
+		return err
+	}
+
+	file := job.DoneMetadataFile(status)
+	if err := os.MkdirAll(filepath.Dir(file), os.ModePerm); err != nil {
+		return fmt.Errorf("could not create the directory of %v: %w", file, err)
+	}
+
+	if err := os.WriteFile(file, content, 0644); err != nil {
+		return fmt.Errorf("could not write the metadata file %v: %w", file, err)
+	}
+	return nil
+}
+
+// Attaches the metadata of the status to a done file as extended attributes
+// `user.prover.<key>`. Some filesystems do not support them, in which case an
+// error is returned and the done file is left as is.
+func setDoneMetadata(file string, status Status) error {
+	for key, value := range status.metadata() {
+		if err := unix.Setxattr(file, xattrPrefix+key, []byte(value), 0); err != nil {
+			return fmt.Errorf("could not set the attribute %v of %v: %w", key, file, err)
+		}
+	}
+	return nil
+}
@@ -3,6 +3,8 @@ package main
 import (
 	"fmt"
 	"os"
+	"runtime"
+	"syscall"
 
 	"github.com/consensys/linea-monorepo/prover/protocol/compiler/dummy"
 	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
@@ -23,6 +25,10 @@ func main() {
 		vErr  = wizard.Verify(comp, proof)
 	)
 
+	// The peak RSS is that of reading, expanding and assigning the traces
+	// since the dummy compiler adds no prover work.
+	fmt.Printf("peak RSS = %v MiB\n", maxRSS()>>20)
+
 	if vErr == nil {
 		fmt.Printf("PASSED\n")
 	}
@@ -34,3 +40,20 @@ func main() {
 	}
 
 }
+
+// maxRSS returns the peak resident set size of the process in bytes, or 0 if
+// it is not available.
+func maxRSS() int64 {
+
+	var rusage syscall.Rusage
+	if err := syscall.Getrusage(syscall.RUSAGE_SELF, &rusage); err != nil {
+		return 0
+	}
+
+	// The peak RSS is given in KiB on Linux and in bytes on macOS
+	res := int64(rusage.Maxrss)
+	if runtime.GOOS != "darwin" {
+		res *= 1024
+	}
+	return res
+}
@@ -10,11 +10,13 @@ import (
 var (
 	configFPathCLI string
 	traceFPathCLI  string
+	largeCLI       bool
 )
 
 func init() {
 	flag.StringVar(&configFPathCLI, "config", "", "path to the config file. Only the trace limits are read")
 	flag.StringVar(&traceFPathCLI, "trace-file", "", "path to the `.lt` trace file")
+	flag.BoolVar(&largeCLI, "large", false, "use the large traces limits of the config")
 	flag.Parse()
 }
 
@@ -34,5 +36,9 @@ func getParamsFromCLI() (cfg *config.Config, traceFPath string, err error) {
 		return nil, "", fmt.Errorf("could not parse the config: %w", err)
 	}
 
+	if largeCLI {
+		cfg.TracesLimits = cfg.TracesLimitsLarge
+	}
+
 	return cfg, traceFPathCLI, nil
 }
@@ -3,6 +3,7 @@ package main
 import (
 	"github.com/consensys/linea-monorepo/prover/config"
 	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
+	"github.com/consensys/linea-monorepo/prover/utils"
 	"github.com/consensys/linea-monorepo/prover/zkevm/arithmetization"
 )
 
@@ -17,6 +18,10 @@ func MakeDefine(cfg *config.Config) wizard.DefineFunc {
 
 func MakeProver(traceFile string) wizard.ProverStep {
 	return func(run *wizard.ProverRuntime) {
-		globalArith.Assign(run, traceFile)
+		expTraces, err := globalArith.ReadTraces(traceFile)
+		if err != nil {
+			utils.Panic("could not read the traces: %v", err)
+		}
+		globalArith.Assign(run, expTraces)
 	}
 }
@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/consensys/linea-monorepo/prover/backend/execution"
+	"github.com/consensys/linea-monorepo/prover/backend/execution/limitcheck"
+	"github.com/consensys/linea-monorepo/prover/config"
+)
+
+type CheckLimitsArgs struct {
+	Input      string
+	JSON       bool
+	ConfigFile string
+}
+
+// CheckLimits compares the traces of an execution request with the normal and
+// the large traces limits and prints the prover that the request should be
+// sent to. The wizard of the zkEVM is not built.
+func CheckLimits(args CheckLimitsArgs) error {
+	const cmdName = "check-limits"
+
+	cfg, err := config.NewConfigFromFile(args.ConfigFile)
+	if err != nil {
+		return fmt.Errorf("%s failed to read config file: %w", cmdName, err)
+	}
+
+	req := &execution.Request{}
+	if err := readRequest(args.Input, req); err != nil {
+		return fmt.Errorf("could not read the input file (%v): %w", args.Input, err)
+	}
+
+	report, err := limitcheck.Check(cfg, req)
+	if err != nil {
+		return fmt.Errorf("%s could not check the request: %w", cmdName, err)
+	}
+
+	if args.JSON {
+		return json.NewEncoder(os.Stdout).Encode(report)
+	}
+
+	return printLimitReport(os.Stdout, report)
+}
+
+func printLimitReport(w io.Writer, report *limitcheck.Report) error {
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "NAME\tCOUNT\tLIMIT\tLIMIT_LARGE\tRATIO\tRATIO_LARGE")
+
+	for _, u := range report.Usages {
+		if u.Unknown {
+			fmt.Fprintf(tw, "%v\t%v\tunknown\tunknown\t-\t-\n", u.Name, u.Count)
+			continue
+		}
+		fmt.Fprintf(tw, "%v\t%v\t%v\t%v\t%v\t%v\n",
+			u.Name, u.Count, u.Limit, u.LimitLarge,
+			limitRatio(u.Count, u.Limit), limitRatio(u.Count, u.LimitLarge),
+		)
+	}
+
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	for _, u := range report.Overflows() {
+		fmt.Fprintf(w, "overflow: %v count=%v limit=%v limit-large=%v\n", u.Name, u.Count, u.Limit, u.LimitLarge)
+	}
+
+	_, err := fmt.Fprintf(w, "recommended target: %v\n", report.Target)
+	return err
+}
+
+// limitRatio formats the ratio of a count over its limit. The ratio is not
+// defined when the limit is zero.
+func limitRatio(count, limit int) string {
+	if limit == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.3f", float64(count)/float64(limit))
+}
@@ -0,0 +1,33 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/consensys/linea-monorepo/prover/backend/execution/limitcheck"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrintLimitReport(t *testing.T) {
+
+	report := &limitcheck.Report{
+		Usages: []limitcheck.Usage{
+			{Name: "ecdata", Count: 8, Limit: 16, LimitLarge: 32},
+			{Name: "newmodule", Count: 4, Unknown: true},
+			{Name: "PrecompileBlsG1MsmScalarMuls", Count: 0},
+		},
+		Target: limitcheck.TargetReject,
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, printLimitReport(&buf, report))
+
+	out := buf.String()
+	assert.Contains(t, out, "0.500")
+	assert.Contains(t, out, "unknown")
+	assert.NotContains(t, out, "Inf")
+	assert.NotContains(t, out, "NaN")
+	assert.Contains(t, out, "overflow: newmodule count=4 limit=0 limit-large=0")
+	assert.Contains(t, out, "recommended target: reject")
+}
@@ -0,0 +1,135 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/consensys/linea-monorepo/prover/config"
+	"github.com/sirupsen/logrus"
+)
+
+// The types of jobs run by the prover, as passed to --job-type
+const (
+	jobTypeExecution         = "execution"
+	jobTypeBlobDecompression = "blob-decompression"
+	jobTypeAggregation       = "aggregation"
+)
+
+// The traces profiles of the execution prover, as passed to --traces-profile
+const (
+	tracesProfileNormal = "normal"
+	tracesProfileLarge  = "large"
+)
+
+// resolveJobType returns the type of the job of the request. The type passed
+// with --job-type takes precedence; otherwise, it is derived from the file
+// name of the request as the controller names them, and from the fields of the
+// request as a fallback. An error is returned when the content of the request
+// does not match the type given by the flag or by the file name.
+func resolveJobType(args ProverArgs, request []byte) (string, error) {
+
+	fromContent, errContent := jobTypeFromContent(request)
+
+	if args.JobType != "" {
+		switch args.JobType {
+		case jobTypeExecution, jobTypeBlobDecompression, jobTypeAggregation:
+		default:
+			return "", fmt.Errorf("unknown job type %q, expected one of %v", args.JobType,
+				[]string{jobTypeExecution, jobTypeBlobDecompression, jobTypeAggregation})
+		}
+		if errContent == nil && fromContent != args.JobType {
+			return "", fmt.Errorf("the job type is %v but the request in %v is of type %v", args.JobType, args.Input, fromContent)
+		}
+		return args.JobType, nil
+	}
+
+	fromName := jobTypeFromName(args.Input)
+
+	switch {
+	case fromName != "" && errContent == nil && fromName != fromContent:
+		return "", fmt.Errorf("the file name of %v indicates the job type %v but the request is of type %v", args.Input, fromName, fromContent)
+	case fromName != "":
+		return fromName, nil
+	case errContent == nil:
+		logrus.Warnf("the job type could not be derived from the file name of %v, running it as %v after its content", args.Input, fromContent)
+		return fromContent, nil
+	default:
+		return "", fmt.Errorf("unknown job type: the file name of %v does not indicate it and %w", args.Input, errContent)
+	}
+}
+
+// jobTypeFromName returns the type of the job from the file name of the
+// request, or an empty string if the name does not indicate it.
+func jobTypeFromName(path string) string {
+	name := filepath.Base(path)
+	switch {
+	case strings.Contains(name, "getZkProof"):
+		return jobTypeExecution
+	case strings.Contains(name, "getZkBlobCompressionProof"):
+		return jobTypeBlobDecompression
+	case strings.Contains(name, "getZkAggregatedProof"):
+		return jobTypeAggregation
+	default:
+		return ""
+	}
+}
+
+// jobTypeFromContent returns the type of the job from the fields of the JSON
+// object holding the request.
+func jobTypeFromContent(b []byte) (string, error) {
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return "", fmt.Errorf("could not decode the JSON object: %w", err)
+	}
+
+	has := func(keys ...string) bool {
+		for _, k := range keys {
+			if _, ok := fields[k]; !ok {
+				return false
+			}
+		}
+		return true
+	}
+
+	switch {
+	case has("executionProofs", "compressionProofs"):
+		return jobTypeAggregation, nil
+	case has("compressedData", "expectedX", "snarkHash"):
+		return jobTypeBlobDecompression, nil
+	case has("zkParentStateRootHash", "conflatedExecutionTracesFile", "blocksData"):
+		return jobTypeExecution, nil
+	default:
+		return "", errors.New("the request is neither an execution, a blob decompression nor an aggregation request")
+	}
+}
+
+// resolveLargeTraces returns whether the execution is proven with the large
+// traces limits. The profile passed with --traces-profile takes precedence,
+// then --large. Otherwise, the large limits are used for the requests with the
+// large suffix, as long as the prover can run them.
+func resolveLargeTraces(cfg *config.Config, args ProverArgs) (bool, error) {
+	switch args.TracesProfile {
+	case tracesProfileLarge:
+		return true, nil
+	case tracesProfileNormal:
+		if args.Large {
+			return false, fmt.Errorf("--large contradicts the %v traces profile", tracesProfileNormal)
+		}
+		return false, nil
+	case "":
+		if args.Large {
+			return true, nil
+		}
+		if strings.Contains(filepath.Base(args.Input), "large") && cfg.Execution.CanRunFullLarge {
+			logrus.Warnf("no traces profile given, using the %v one after the file name of %v", tracesProfileLarge, args.Input)
+			return true, nil
+		}
+		return false, nil
+	default:
+		return false, fmt.Errorf("unknown traces profile %q, expected %v or %v", args.TracesProfile, tracesProfileNormal, tracesProfileLarge)
+	}
+}
@@ -0,0 +1,132 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/consensys/linea-monorepo/prover/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testExecutionRequest   = `{"zkParentStateRootHash": "0x", "conflatedExecutionTracesFile": "", "blocksData": []}`
+	testBlobRequest        = `{"compressedData": "", "expectedX": "0x", "snarkHash": "0x"}`
+	testAggregationRequest = `{"executionProofs": [], "compressionProofs": []}`
+)
+
+func TestJobTypeFromContent(t *testing.T) {
+
+	cases := []struct {
+		name    string
+		request string
+		jobType string
+	}{
+		{"execution", testExecutionRequest, jobTypeExecution},
+		{"blob-decompression", testBlobRequest, jobTypeBlobDecompression},
+		{"aggregation", testAggregationRequest, jobTypeAggregation},
+		{"missing-field", `{"zkParentStateRootHash": "0x", "blocksData": []}`, ""},
+		{"empty-object", `{}`, ""},
+		{"not-an-object", `[]`, ""},
+		{"not-json", `getZkProof`, ""},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			jobType, err := jobTypeFromContent([]byte(c.request))
+			if c.jobType == "" {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, c.jobType, jobType)
+		})
+	}
+}
+
+func TestResolveJobType(t *testing.T) {
+
+	const (
+		executionFile   = "1-2-etv0.2.3-stv1.2.3-getZkProof.json"
+		blobFile        = "1-2-bcv0.0-ccv0.0-getZkBlobCompressionProof.json"
+		aggregationFile = "1-2-getZkAggregatedProof.json"
+	)
+
+	cases := []struct {
+		name    string
+		input   string
+		jobType string
+		request string
+		// The expected job type, or an empty string if an error is expected
+		expected string
+	}{
+		{"flag", "request.json", jobTypeBlobDecompression, testBlobRequest, jobTypeBlobDecompression},
+		{"flag-over-name", executionFile, jobTypeAggregation, testAggregationRequest, jobTypeAggregation},
+		{"flag-unreadable-content", "request.json", jobTypeExecution, `{}`, jobTypeExecution},
+		{"flag-unknown", executionFile, "compression", testExecutionRequest, ""},
+		{"flag-mismatch", "request.json", jobTypeExecution, testAggregationRequest, ""},
+		{"name-execution", executionFile, "", testExecutionRequest, jobTypeExecution},
+		{"name-blob-decompression", blobFile, "", testBlobRequest, jobTypeBlobDecompression},
+		{"name-aggregation", "requests/" + aggregationFile, "", testAggregationRequest, jobTypeAggregation},
+		{"name-unreadable-content", executionFile, "", `{}`, jobTypeExecution},
+		{"name-mismatch", blobFile, "", testExecutionRequest, ""},
+		{"content", "request.json", "", testAggregationRequest, jobTypeAggregation},
+		{"unknown", "request.json", "", `{}`, ""},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			args := ProverArgs{Input: c.input, JobType: c.jobType}
+			jobType, err := resolveJobType(args, []byte(c.request))
+			if c.expected == "" {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, c.expected, jobType)
+		})
+	}
+}
+
+func TestResolveLargeTraces(t *testing.T) {
+
+	const (
+		normalFile = "1-2-etv0.2.3-stv1.2.3-getZkProof.json"
+		largeFile  = "1-2-etv0.2.3-stv1.2.3-getZkProof.json.large"
+	)
+
+	cases := []struct {
+		name            string
+		input           string
+		large           bool
+		tracesProfile   string
+		canRunFullLarge bool
+		expected        bool
+		expectErr       bool
+	}{
+		{name: "default", input: normalFile},
+		{name: "default-large-suffix", input: largeFile, canRunFullLarge: true, expected: true},
+		{name: "default-large-suffix-cannot-run", input: largeFile},
+		{name: "large-flag", input: normalFile, large: true, expected: true},
+		{name: "profile-large", input: normalFile, tracesProfile: tracesProfileLarge, expected: true},
+		{name: "profile-large-and-large-flag", input: normalFile, large: true, tracesProfile: tracesProfileLarge, expected: true},
+		{name: "profile-normal", input: largeFile, tracesProfile: tracesProfileNormal, canRunFullLarge: true},
+		{name: "profile-normal-and-large-flag", input: normalFile, large: true, tracesProfile: tracesProfileNormal, expectErr: true},
+		{name: "profile-unknown", input: normalFile, tracesProfile: "huge", expectErr: true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			cfg := &config.Config{}
+			cfg.Execution.CanRunFullLarge = c.canRunFullLarge
+			args := ProverArgs{Input: c.input, Large: c.large, TracesProfile: c.tracesProfile}
+
+			large, err := resolveLargeTraces(cfg, args)
+			if c.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, c.expected, large)
+		})
+	}
+}
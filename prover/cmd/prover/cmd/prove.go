@@ -1,30 +1,39 @@
 package cmd
 
 import (
+	"context"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"os"
+	"os/signal"
+	"path/filepath"
 	"strings"
+	"syscall"
+	"time"
 
 	"github.com/consensys/linea-monorepo/prover/backend/aggregation"
 	"github.com/consensys/linea-monorepo/prover/backend/blobdecompression"
 	"github.com/consensys/linea-monorepo/prover/backend/execution"
 	"github.com/consensys/linea-monorepo/prover/backend/files"
+	"github.com/consensys/linea-monorepo/prover/circuits"
 	"github.com/consensys/linea-monorepo/prover/config"
+	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
+	"github.com/consensys/linea-monorepo/prover/zkevm/arithmetization"
+	"github.com/sirupsen/logrus"
 )
 
 type ProverArgs struct {
-	Input      string
-	Output     string
-	Large      bool
-	ConfigFile string
+	Input         string
+	Output        string
+	Large         bool
+	JobType       string
+	TracesProfile string
+	CheckSetup    bool
+	ConfigFile    string
 }
 
 func Prove(args ProverArgs) error {
 	const cmdName = "prove"
-	// TODO @gbotrel with a specific flag, we could compile the circuit and compare with the checksum of the
-	// asset we deserialize, to make sure we are using the circuit associated with the compiled binary and the setup.
 
 	// read config
 	cfg, err := config.NewConfigFromFile(args.ConfigFile)
@@ -32,50 +41,104 @@ func Prove(args ProverArgs) error {
 		return fmt.Errorf("%s failed to read config file: %w", cmdName, err)
 	}
 
-	// discover the type of the job from the input file name
-	jobExecution := strings.Contains(args.Input, "getZkProof")
-	jobBlobDecompression := strings.Contains(args.Input, "getZkBlobCompressionProof")
-	jobAggregation := strings.Contains(args.Input, "getZkAggregatedProof")
+	b, err := os.ReadFile(args.Input)
+	if err != nil {
+		return fmt.Errorf("%s could not read the input file: %w", cmdName, err)
+	}
+
+	jobType, err := resolveJobType(args, b)
+	if err != nil {
+		return fmt.Errorf("%s %w", cmdName, err)
+	}
+
+	// The setup check and the execution prover are aborted when the process
+	// is asked to terminate. The blob decompression and the aggregation
+	// provers cannot be interrupted: the handler is removed before they start
+	// so that the signal terminates the process as it would by default.
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, os.Interrupt)
+	defer stop()
 
-	if jobExecution {
+	switch jobType {
+	case jobTypeExecution:
 		req := &execution.Request{}
-		if err := readRequest(args.Input, req); err != nil {
-			return fmt.Errorf("could not read the input file (%v): %w", args.Input, err)
+		if err := json.Unmarshal(b, req); err != nil {
+			return fmt.Errorf("could not decode the input file (%v): %w", args.Input, err)
+		}
+
+		large, err := resolveLargeTraces(cfg, args)
+		if err != nil {
+			return fmt.Errorf("%s %w", cmdName, err)
 		}
 
-		// we use the large traces in 2 cases;
-		// 1. the user explicitly asked for it (args.Large)
-		// 2. the job contains the large suffix and we are a large machine (cfg.Execution.CanRunLarge)
-		large := args.Large || (strings.Contains(args.Input, "large") && cfg.Execution.CanRunFullLarge)
+		if args.CheckSetup && cfg.Execution.ProverMode == config.ProverModeFull {
+			c := circuits.ExecutionCircuitID
+			if large {
+				c = circuits.ExecutionLargeCircuitID
+			}
+			if err := checkSetup(ctx, args, c); err != nil {
+				return err
+			}
+		}
 
-		resp, err := execution.Prove(cfg, req, large)
+		resp, err := execution.ProveContext(ctx, cfg, req, execution.ProveOptions{
+			Large:    large,
+			Progress: logProgress,
+		})
 		if err != nil {
+			if arithmetization.IsTraceOverflow(err) {
+				if errReport := writeOverflowReport(args.Output, err); errReport != nil {
+					logrus.Errorf("could not write the overflow report: %v", errReport)
+				}
+			}
 			return fmt.Errorf("could not prove the execution: %w", err)
 		}
 
 		return writeResponse(args.Output, resp)
-	}
 
-	if jobBlobDecompression {
+	case jobTypeBlobDecompression:
 		req := &blobdecompression.Request{}
-		if err := readRequest(args.Input, req); err != nil {
-			return fmt.Errorf("could not read the input file (%v): %w", args.Input, err)
+		if err := json.Unmarshal(b, req); err != nil {
+			return fmt.Errorf("could not decode the input file (%v): %w", args.Input, err)
+		}
+
+		if args.CheckSetup && cfg.BlobDecompression.ProverMode != config.ProverModeDev {
+			_, c, err := blobdecompression.PublicInput(cfg, req)
+			if err != nil {
+				return fmt.Errorf("the setup check failed: could not find the decompression circuit of the request: %w", err)
+			}
+			if err := checkSetup(ctx, args, c); err != nil {
+				return err
+			}
 		}
 
+		stop()
 		resp, err := blobdecompression.Prove(cfg, req)
 		if err != nil {
 			return fmt.Errorf("could not prove the blob decompression: %w", err)
 		}
 
 		return writeResponse(args.Output, resp)
-	}
 
-	if jobAggregation {
+	case jobTypeAggregation:
 		req := &aggregation.Request{}
-		if err := readRequest(args.Input, req); err != nil {
-			return fmt.Errorf("could not read the input file (%v): %w", args.Input, err)
+		if err := json.Unmarshal(b, req); err != nil {
+			return fmt.Errorf("could not decode the input file (%v): %w", args.Input, err)
+		}
+
+		if args.CheckSetup && cfg.Aggregation.ProverMode != config.ProverModeDev {
+			// Only the aggregation circuit the request is proven with is
+			// checked. The emulation circuit is left out as checking it means
+			// checking all the aggregation circuits it verifies.
+			c, err := aggregation.RequestCircuitID(cfg, req)
+			if err != nil {
+				return fmt.Errorf("the setup check failed: could not find the aggregation circuit of the request: %w", err)
+			}
+			if err := checkSetup(ctx, args, circuits.PublicInputInterconnectionCircuitID, c); err != nil {
+				return err
+			}
 		}
 
+		stop()
 		resp, err := aggregation.Prove(cfg, req)
 		if err != nil {
 			return fmt.Errorf("could not prove the aggregation: %w", err)
@@ -84,7 +147,39 @@ func Prove(args ProverArgs) error {
 		return writeResponse(args.Output, resp)
 	}
 
-	return errors.New("unknown job type")
+	return fmt.Errorf("%s unknown job type %v", cmdName, jobType)
+}
+
+// checkSetup compiles the circuits used by the job and compares them with their
+// setup on disk, so that a binary that does not match the assets is caught
+// before it produces invalid proofs.
+func checkSetup(ctx context.Context, args ProverArgs, ids ...circuits.CircuitID) error {
+	list := make([]string, len(ids))
+	for i := range ids {
+		list[i] = string(ids[i])
+	}
+
+	err := Setup(ctx, SetupArgs{
+		Verify:     true,
+		Circuits:   strings.Join(list, ","),
+		ConfigFile: args.ConfigFile,
+	})
+	if err != nil {
+		return fmt.Errorf("the setup check failed: %w", err)
+	}
+	return nil
+}
+
+// logProgress logs the progress of the inner-prover of the execution. The
+// steps shorter than a second are only logged in debug level as there are
+// many of them.
+func logProgress(p wizard.ProverProgress) {
+	level := logrus.DebugLevel
+	if p.Duration >= time.Second {
+		level = logrus.InfoLevel
+	}
+	logrus.StandardLogger().Logf(level, "inner-prover progress: round %v/%v, %v done in %v (elapsed %v)",
+		p.Round+1, p.NumRounds, p.Action, p.Duration.Round(time.Millisecond), p.Elapsed.Round(time.Second))
 }
 
 func readRequest(path string, into any) error {
@@ -111,3 +206,23 @@ func writeResponse(path string, from any) error {
 
 	return nil
 }
+
+// overflowReport is written next to the response file when the traces of an
+// execution request overflow the limits of the arithmetization. The controller
+// then moves it in the `requests-done-metadata` directory, along with the
+// request.
+type overflowReport struct {
+	Overflows []*arithmetization.TraceOverflowError `json:"overflows"`
+}
+
+// overflowReportPath returns the path of the overflow report corresponding to
+// the response file: the extension of the response file is replaced by
+// ".overflow.json".
+func overflowReportPath(responsePath string) string {
+	return strings.TrimSuffix(responsePath, filepath.Ext(responsePath)) + ".overflow.json"
+}
+
+func writeOverflowReport(responsePath string, err error) error {
+	report := overflowReport{Overflows: arithmetization.TraceOverflows(err)}
+	return writeResponse(overflowReportPath(responsePath), report)
+}
@@ -31,6 +31,7 @@ import (
 
 type SetupArgs struct {
 	Force      bool
+	Verify     bool
 	Circuits   string
 	DictPath   string
 	AssetsDir  string
@@ -48,6 +49,9 @@ var AllCircuits = []circuits.CircuitID{
 	circuits.EmulationDummyCircuitID, // we want to generate Verifier.sol for this one
 }
 
+// Setup generates the assets of the circuits. With args.Verify, the circuits
+// are compiled and compared with the setup on disk instead, and an error is
+// returned if any of them drifted.
 func Setup(context context.Context, args SetupArgs) error {
 	const cmdName = "setup"
 	// read config
@@ -63,11 +67,50 @@ func Setup(context context.Context, args SetupArgs) error {
 		}
 	}
 
+	// srs provider
+	var srsProvider circuits.SRSProvider
+	srsProvider, err = circuits.NewSRSStore(cfg.PathForSRS())
+	if err != nil {
+		return fmt.Errorf("%s failed to create SRS provider: %w", cmdName, err)
+	}
+
+	if args.Verify {
+		drift := &setupDrift{}
+		if err := setupCircuits(context, cfg, args, srsProvider, drift.checker(cfg)); err != nil {
+			return err
+		}
+		return drift.report()
+	}
+
+	// create assets dir if needed (example; efs://prover-assets/v0.1.0/)
+	os.MkdirAll(filepath.Join(cfg.AssetsDir, cfg.Version), 0755)
+
+	return setupCircuits(context, cfg, args, srsProvider, func(c circuits.CircuitID, builder circuits.Builder, extraFlags map[string]any) error {
+		return updateSetup(context, cfg, args.Force, srsProvider, c, builder, extraFlags)
+	})
+}
+
+// setupStep sets up a circuit given its builder and the extra flags of its
+// manifest, or checks its setup in verify mode.
+type setupStep func(c circuits.CircuitID, builder circuits.Builder, extraFlags map[string]any) error
+
+// setupCircuits runs the setup step on every circuit requested in args. The
+// aggregation and the emulation circuits depend on the verifying keys of the
+// circuits they verify, which are read from the disk. The `aggregation` and
+// the `emulation` circuits stand for all the aggregation circuits of the
+// config and the emulation circuit. A single aggregation circuit can be
+// requested with its ID, e.g. `aggregation-10`.
+func setupCircuits(context context.Context, cfg *config.Config, args SetupArgs, srsProvider circuits.SRSProvider, step setupStep) error {
+	const cmdName = "setup"
+
 	// parse inCircuits
 	inCircuits := make(map[circuits.CircuitID]bool)
 	for _, c := range AllCircuits {
 		inCircuits[circuits.CircuitID(c)] = false
 	}
+	for _, numProofs := range cfg.Aggregation.NumProofs {
+		inCircuits[aggregationCircuitID(numProofs)] = false
+	}
 	_inCircuits := strings.Split(args.Circuits, ",")
 	for _, c := range _inCircuits {
 		if _, ok := inCircuits[circuits.CircuitID(c)]; !ok {
@@ -76,16 +119,6 @@ func Setup(context context.Context, args SetupArgs) error {
 		inCircuits[circuits.CircuitID(c)] = true
 	}
 
-	// create assets dir if needed (example; efs://prover-assets/v0.1.0/)
-	os.MkdirAll(filepath.Join(cfg.AssetsDir, cfg.Version), 0755)
-
-	// srs provider
-	var srsProvider circuits.SRSProvider
-	srsProvider, err = circuits.NewSRSStore(cfg.PathForSRS())
-	if err != nil {
-		return fmt.Errorf("%s failed to create SRS provider: %w", cmdName, err)
-	}
-
 	// for each circuit, we start by compiling the circuit
 	// then we do a sha sum and compare against the one in the manifest.json
 	for _, c := range AllCircuits {
@@ -112,7 +145,13 @@ func Setup(context context.Context, args SetupArgs) error {
 			zkEvm := zkevm.FullZkEvm(&limits)
 			builder = execution.NewBuilder(zkEvm)
 		case circuits.BlobDecompressionV0CircuitID, circuits.BlobDecompressionV1CircuitID:
-			dict, err = os.ReadFile(args.DictPath)
+			dictPath := args.DictPath
+			if args.Verify && dictPath == "" {
+				// check against the dictionary stored with the setup
+				dictPath = cfg.BlobDecompressionDictPath(string(c))
+			}
+			var err error
+			dict, err = os.ReadFile(dictPath)
 			if err != nil {
 				return fmt.Errorf("%s failed to read dictionary file: %w", cmdName, err)
 			}
@@ -135,10 +174,10 @@ func Setup(context context.Context, args SetupArgs) error {
 			continue // dummy, aggregation, emulation or public input circuits are handled later
 		}
 
-		if err := updateSetup(context, cfg, args.Force, srsProvider, c, builder, extraFlags); err != nil {
+		if err := step(c, builder, extraFlags); err != nil {
 			return err
 		}
-		if dict != nil {
+		if dict != nil && !args.Verify {
 			// we save the dictionary to disk
 			dictPath := cfg.BlobDecompressionDictPath(string(c))
 			if err := os.WriteFile(dictPath, dict, 0600); err != nil {
@@ -148,7 +187,15 @@ func Setup(context context.Context, args SetupArgs) error {
 
 	}
 
-	if !(inCircuits[circuits.AggregationCircuitID] || inCircuits[circuits.EmulationCircuitID]) {
+	var (
+		allAggregations = inCircuits[circuits.AggregationCircuitID] || inCircuits[circuits.EmulationCircuitID]
+		someAggregation = false
+	)
+	for _, numProofs := range cfg.Aggregation.NumProofs {
+		someAggregation = someAggregation || inCircuits[aggregationCircuitID(numProofs)]
+	}
+
+	if !(allAggregations || someAggregation) {
 		// we are done
 		return nil
 	}
@@ -209,11 +256,14 @@ func Setup(context context.Context, args SetupArgs) error {
 	// now for each aggregation circuit, we update the setup if needed, and collect the verifying keys
 	allowedVkForEmulation := make([]plonk.VerifyingKey, 0, len(cfg.Aggregation.NumProofs))
 	for _, numProofs := range cfg.Aggregation.NumProofs {
-		c := circuits.CircuitID(fmt.Sprintf("%s-%d", string(circuits.AggregationCircuitID), numProofs))
+		c := aggregationCircuitID(numProofs)
+		if !allAggregations && !inCircuits[c] {
+			continue
+		}
 		logrus.Infof("setting up %s (numProofs=%d)", c, numProofs)
 
 		builder := aggregation.NewBuilder(numProofs, cfg.Aggregation.AllowedInputs, piSetup, allowedVkForAggregation)
-		if err := updateSetup(context, cfg, args.Force, srsProvider, c, builder, extraFlagsForAggregationCircuit); err != nil {
+		if err := step(c, builder, extraFlagsForAggregationCircuit); err != nil {
 			return err
 		}
 
@@ -228,12 +278,23 @@ func Setup(context context.Context, args SetupArgs) error {
 		allowedVkForEmulation = append(allowedVkForEmulation, vk)
 	}
 
+	if !allAggregations {
+		// the emulation circuit verifies all the aggregation circuits
+		return nil
+	}
+
 	// now we can update the final (emulation) circuit
 	c := circuits.EmulationCircuitID
 	logrus.Infof("setting up %s", c)
 	builder := emulation.NewBuilder(allowedVkForEmulation)
-	return updateSetup(context, cfg, args.Force, srsProvider, c, builder, nil)
+	return step(c, builder, nil)
+
+}
 
+// aggregationCircuitID returns the ID of the aggregation circuit aggregating
+// numProofs proofs.
+func aggregationCircuitID(numProofs int) circuits.CircuitID {
+	return circuits.CircuitID(fmt.Sprintf("%s-%d", string(circuits.AggregationCircuitID), numProofs))
 }
 
 func isDummyCircuit(cID string) bool {
@@ -309,6 +370,57 @@ func updateSetup(ctx context.Context, cfg *config.Config, force bool, srsProvide
 	return setup.WriteTo(setupPath)
 }
 
+// setupDrift collects the circuits whose setup on disk does not match the
+// circuit compiled by the binary.
+type setupDrift struct {
+	circuits []circuits.CircuitID
+}
+
+// checker returns the setup step used in verify mode. It compiles the circuit
+// and compares it, along with the extra flags, with the manifest on disk.
+func (d *setupDrift) checker(cfg *config.Config) setupStep {
+	return func(c circuits.CircuitID, builder circuits.Builder, extraFlags map[string]any) error {
+
+		logrus.Infof("compiling %s", c)
+		ccs, err := builder.Compile()
+		if err != nil {
+			return fmt.Errorf("failed to compile circuit %s: %w", c, err)
+		}
+
+		manifestPath := filepath.Join(cfg.PathForSetup(string(c)), config.ManifestFileName)
+		manifest, err := circuits.ReadSetupManifest(manifestPath)
+		if err != nil {
+			logrus.Errorf("%s: could not read the manifest of the setup: %v", c, err)
+			d.circuits = append(d.circuits, c)
+			return nil
+		}
+
+		drift, err := manifest.Drift(ccs, extraFlags)
+		if err != nil {
+			return fmt.Errorf("failed to compare circuit %s with its setup: %w", c, err)
+		}
+
+		if len(drift) == 0 {
+			logrus.Infof("%s matches its setup", c)
+			return nil
+		}
+
+		for _, diff := range drift {
+			logrus.Errorf("%s: %s", c, diff)
+		}
+		d.circuits = append(d.circuits, c)
+		return nil
+	}
+}
+
+func (d *setupDrift) report() error {
+	if len(d.circuits) > 0 {
+		return fmt.Errorf("the setup of %v does not match the circuits compiled by the binary", d.circuits)
+	}
+	logrus.Infof("the setups match the circuits compiled by the binary")
+	return nil
+}
+
 // listOfChecksums Computes a list of SHA256 checksums for a list of assets, the result is given
 // in hexstring.
 func listOfChecksums[T io.WriterTo](assets []T) []string {
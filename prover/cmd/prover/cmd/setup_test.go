@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/consensys/linea-monorepo/prover/circuits"
+	"github.com/consensys/linea-monorepo/prover/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetupVerify(t *testing.T) {
+
+	cfgFile, cfg := testConfigFile(t)
+
+	var (
+		ctx          = context.Background()
+		args         = SetupArgs{Circuits: string(circuits.EmulationDummyCircuitID), ConfigFile: cfgFile}
+		verifyArgs   = SetupArgs{Circuits: args.Circuits, ConfigFile: cfgFile, Verify: true}
+		manifestPath = filepath.Join(cfg.PathForSetup(string(circuits.EmulationDummyCircuitID)), config.ManifestFileName)
+	)
+
+	// There is no setup to check against yet
+	assert.ErrorContains(t, Setup(ctx, verifyArgs), "does not match")
+
+	require.NoError(t, Setup(ctx, args))
+	require.NoError(t, Setup(ctx, verifyArgs))
+
+	// The circuit drifted from its setup
+	b, err := os.ReadFile(manifestPath)
+	require.NoError(t, err)
+	manifest := map[string]any{}
+	require.NoError(t, json.Unmarshal(b, &manifest))
+	manifest["nbConstraints"] = manifest["nbConstraints"].(float64) + 1
+	b, err = json.Marshal(manifest)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(manifestPath, b, 0600))
+
+	err = Setup(ctx, verifyArgs)
+	assert.ErrorContains(t, err, "does not match")
+	assert.ErrorContains(t, err, string(circuits.EmulationDummyCircuitID))
+
+	// A single aggregation circuit can only be requested if it is in the
+	// config. Checking it requires the setup of the public input circuit.
+	verifyArgs.Circuits = "aggregation-3"
+	assert.ErrorContains(t, Setup(ctx, verifyArgs), "unknown circuit")
+	verifyArgs.Circuits = "aggregation-10"
+	assert.ErrorContains(t, Setup(ctx, verifyArgs), "failed to load public input interconnection setup")
+}
+
+// testConfigFile writes a copy of the development config whose assets are in
+// a temporary directory, along with an unsafe SRS for the dummy emulation
+// circuit.
+func testConfigFile(t *testing.T) (string, *config.Config) {
+
+	b, err := os.ReadFile("../../../config/config-integration-development.toml")
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	b = regexp.MustCompile(`(?m)^assets_dir = .*$`).ReplaceAll(b, []byte(`assets_dir = "`+dir+`"`))
+
+	writeUnsafeSRS(t, &config.Config{AssetsDir: dir}, circuits.MockCircuitIDEmulation)
+
+	cfgFile := filepath.Join(dir, "config.toml")
+	require.NoError(t, os.WriteFile(cfgFile, b, 0600))
+
+	cfg, err := config.NewConfigFromFile(cfgFile)
+	require.NoError(t, err)
+	require.Equal(t, dir, cfg.AssetsDir)
+
+	return cfgFile, cfg
+}
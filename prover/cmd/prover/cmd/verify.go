@@ -0,0 +1,462 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	fr377 "github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+	fr254 "github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/plonk"
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/frontend"
+	emPlonk "github.com/consensys/gnark/std/recursion/plonk"
+	"github.com/consensys/linea-monorepo/prover/backend/aggregation"
+	"github.com/consensys/linea-monorepo/prover/backend/blobdecompression"
+	"github.com/consensys/linea-monorepo/prover/backend/execution"
+	"github.com/consensys/linea-monorepo/prover/circuits"
+	"github.com/consensys/linea-monorepo/prover/circuits/dummy"
+	"github.com/consensys/linea-monorepo/prover/config"
+	"github.com/consensys/linea-monorepo/prover/utils"
+)
+
+type VerifyArgs struct {
+	Input      string
+	Request    string
+	ConfigFile string
+}
+
+// The kinds of responses recognized by [Verify]
+const (
+	responseExecution         = "execution"
+	responseBlobDecompression = "blob-decompression"
+	responseAggregation       = "aggregation"
+)
+
+// verifyCheck is a line of the report printed by [Verify]
+type verifyCheck struct {
+	Name   string
+	Err    error
+	Detail string
+}
+
+// verifyingKey is a verifying key along with the circuit it belongs to
+type verifyingKey struct {
+	circuitID circuits.CircuitID
+	vk        plonk.VerifyingKey
+	digest    string
+}
+
+// Verify checks the proof of a response of the execution, the blob
+// decompression or the aggregation prover. It recomputes the public input
+// from the fields of the response, finds the verifying key the proof was
+// generated for and verifies the proof against the recomputed public input.
+// A report of the checks is printed and an error is returned if any of them
+// failed.
+func Verify(args VerifyArgs) error {
+	const cmdName = "verify"
+
+	cfg, err := config.NewConfigFromFile(args.ConfigFile)
+	if err != nil {
+		return fmt.Errorf("%s failed to read config file: %w", cmdName, err)
+	}
+
+	b, err := os.ReadFile(args.Input)
+	if err != nil {
+		return fmt.Errorf("%s could not read the input file: %w", cmdName, err)
+	}
+
+	kind, err := detectResponseKind(b)
+	if err != nil {
+		return fmt.Errorf("%s could not detect the kind of response of %v: %w", cmdName, args.Input, err)
+	}
+
+	var checks []verifyCheck
+	switch kind {
+	case responseExecution:
+		checks, err = verifyExecution(cfg, b)
+	case responseBlobDecompression:
+		checks, err = verifyBlobDecompression(cfg, b)
+	case responseAggregation:
+		req := &aggregation.Request{}
+		if args.Request == "" {
+			return fmt.Errorf("%s the public input of an aggregation proof depends on the parent aggregation, which is only found in the request: use --request", cmdName)
+		}
+		if err := readRequest(args.Request, req); err != nil {
+			return fmt.Errorf("could not read the request file (%v): %w", args.Request, err)
+		}
+		checks, err = verifyAggregation(cfg, req, b)
+	}
+
+	if err != nil {
+		return fmt.Errorf("%s could not verify the %v response %v: %w", cmdName, kind, args.Input, err)
+	}
+
+	if err := printVerifyReport(os.Stdout, kind, args.Input, checks); err != nil {
+		return err
+	}
+
+	var failed []string
+	for _, c := range checks {
+		if c.Err != nil {
+			failed = append(failed, c.Name)
+		}
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("%s the %v response %v did not pass the checks: %v", cmdName, kind, args.Input, strings.Join(failed, ", "))
+	}
+
+	return nil
+}
+
+// detectResponseKind returns the kind of response from the fields of the JSON
+// object it holds.
+func detectResponseKind(b []byte) (string, error) {
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return "", fmt.Errorf("could not decode the JSON object: %w", err)
+	}
+
+	has := func(keys ...string) bool {
+		for _, k := range keys {
+			if _, ok := fields[k]; !ok {
+				return false
+			}
+		}
+		return true
+	}
+
+	switch {
+	case has("aggregatedProof", "aggregatedProofPublicInput"):
+		return responseAggregation, nil
+	case has("decompressionProof", "compressedData"):
+		return responseBlobDecompression, nil
+	case has("proof", "blocksData"):
+		return responseExecution, nil
+	default:
+		return "", errors.New("it is neither an execution, a blob decompression nor an aggregation response")
+	}
+}
+
+func verifyExecution(cfg *config.Config, b []byte) ([]verifyCheck, error) {
+
+	resp := &execution.Response{}
+	if err := json.Unmarshal(b, resp); err != nil {
+		return nil, fmt.Errorf("could not decode the response: %w", err)
+	}
+
+	if resp.Proof == "" {
+		return nil, fmt.Errorf("the response has no proof (prover mode `%v`)", resp.ProverMode)
+	}
+
+	if len(resp.BlocksData) == 0 {
+		return nil, errors.New("the response has no blocks")
+	}
+
+	// The parent state root hash is decoded with a panic when collecting the
+	// functional inputs
+	var errFields error
+	utils.ValidateHexString(&errFields, resp.ParentStateRootHash, "parentStateRootHash : %w", 32)
+	if errFields != nil {
+		return nil, fmt.Errorf("could not recompute the public input: %w", errFields)
+	}
+
+	var (
+		x       = resp.FuncInput().SumAsField()
+		claimed fr377.Element
+	)
+	claimed.SetBytes(resp.PublicInput[:])
+
+	return verifyBls12377Proof(
+		cfg, resp.Proof, x, claimed, resp.VerifyingKeyShaSum,
+		circuits.ExecutionDummyCircuitID, circuits.MockCircuitIDExecution,
+		circuits.ExecutionCircuitID, circuits.ExecutionLargeCircuitID,
+	), nil
+}
+
+func verifyBlobDecompression(cfg *config.Config, b []byte) ([]verifyCheck, error) {
+
+	resp := &blobdecompression.Response{}
+	if err := json.Unmarshal(b, resp); err != nil {
+		return nil, fmt.Errorf("could not decode the response: %w", err)
+	}
+
+	if resp.DecompressionProof == "" {
+		return nil, errors.New("the response has no proof")
+	}
+
+	x, circuitID, err := blobdecompression.PublicInput(cfg, &resp.Request)
+	if err != nil {
+		return nil, fmt.Errorf("could not recompute the public input: %w", err)
+	}
+
+	var claimed fr377.Element
+	if _, err := claimed.SetString(resp.Debug.PublicInput); err != nil {
+		return nil, fmt.Errorf("could not parse the public input of the response `%v`: %w", resp.Debug.PublicInput, err)
+	}
+
+	return verifyBls12377Proof(
+		cfg, resp.DecompressionProof, x, claimed, resp.VerifyingKeyShaSum,
+		circuits.BlobDecompressionDummyCircuitID, circuits.MockCircuitIDDecompression,
+		circuitID,
+	), nil
+}
+
+// verifyBls12377Proof runs the checks of the execution and the decompression
+// proofs, which are both raw-serialized BLS12-377 proofs with a single public
+// input. The verifying key is looked up among the given circuits and the
+// dummy circuit using the digest claimed by the response.
+func verifyBls12377Proof(
+	cfg *config.Config,
+	proofHex string,
+	x, claimed fr377.Element,
+	claimedVkDigest string,
+	dummyID circuits.CircuitID,
+	mockID circuits.MockCircuitID,
+	candidates ...circuits.CircuitID,
+) []verifyCheck {
+
+	var (
+		xBytes, claimedBytes = x.Bytes(), claimed.Bytes()
+		checks               = []verifyCheck{checkPublicInput(xBytes[:], claimedBytes[:])}
+	)
+
+	vk, err := findVerifyingKey(cfg, claimedVkDigest, ecc.BLS12_377, dummyID, mockID, candidates...)
+	if err != nil {
+		return append(checks,
+			verifyCheck{Name: "verifying-key", Err: err},
+			verifyCheck{Name: "proof", Err: errors.New("skipped, no verifying key")},
+		)
+	}
+	checks = append(checks, verifyCheck{Name: "verifying-key", Detail: fmt.Sprintf("%v %v", vk.circuitID, vk.digest)})
+
+	verify := func(x fr377.Element) error {
+		proof, err := circuits.DeserializeProofRaw(proofHex, ecc.BLS12_377)
+		if err != nil {
+			return err
+		}
+		return verifyProof(proof, vk.vk, x, ecc.BLS12_377.ScalarField(),
+			emPlonk.GetNativeVerifierOptions(ecc.BW6_761.ScalarField(), ecc.BLS12_377.ScalarField()))
+	}
+
+	return append(checks, checkProof(verify, x, claimed, x.Equal(&claimed)))
+}
+
+func verifyAggregation(cfg *config.Config, req *aggregation.Request, b []byte) ([]verifyCheck, error) {
+
+	resp := &aggregation.Response{}
+	if err := json.Unmarshal(b, resp); err != nil {
+		return nil, fmt.Errorf("could not decode the response: %w", err)
+	}
+
+	if resp.AggregatedProof == "" {
+		return nil, errors.New("the response has no proof")
+	}
+
+	var (
+		x, claimed fr254.Element
+		funcInput  = resp.FuncInput(req.ParentAggregationLastL1RollingHash, uint(req.ParentAggregationLastL1RollingHashMessageNumber))
+	)
+
+	// The hexstrings are decoded with panics when hashing the public input
+	var errFields error
+	utils.ValidateHexString(&errFields, funcInput.ParentAggregationFinalShnarf, "parentAggregationFinalShnarf : %w", 32)
+	utils.ValidateHexString(&errFields, funcInput.FinalShnarf, "finalShnarf : %w", 32)
+	utils.ValidateHexString(&errFields, funcInput.LastFinalizedL1RollingHash, "parentAggregationLastL1RollingHash (request) : %w", 32)
+	utils.ValidateHexString(&errFields, funcInput.L1RollingHash, "l1RollingHash : %w", 32)
+	for i := range funcInput.L2MsgRootHashes {
+		utils.ValidateHexString(&errFields, funcInput.L2MsgRootHashes[i], fmt.Sprintf("l2MerkleRoots[%d] : ", i)+"%w", 32)
+	}
+	if errFields != nil {
+		return nil, fmt.Errorf("could not recompute the public input: %w", errFields)
+	}
+
+	if _, err := claimed.SetString(resp.AggregatedProofPublicInput); err != nil {
+		return nil, fmt.Errorf("could not parse the public input of the response `%v`: %w", resp.AggregatedProofPublicInput, err)
+	}
+	x.SetBytes(funcInput.Sum(nil))
+
+	var (
+		xBytes, claimedBytes = x.Bytes(), claimed.Bytes()
+		checks               = []verifyCheck{checkPublicInput(xBytes[:], claimedBytes[:])}
+	)
+
+	// The response does not tell which verifying key was used so it is
+	// deduced from the prover mode.
+	var (
+		vk  *verifyingKey
+		err error
+	)
+	if cfg.Aggregation.ProverMode == config.ProverModeDev {
+		vk, err = dummyVerifyingKey(cfg, circuits.EmulationDummyCircuitID, circuits.MockCircuitIDEmulation, ecc.BN254)
+	} else {
+		vk, err = loadVerifyingKey(cfg, circuits.EmulationCircuitID)
+	}
+
+	if err != nil {
+		return append(checks,
+			verifyCheck{Name: "verifying-key", Err: err},
+			verifyCheck{Name: "proof", Err: errors.New("skipped, no verifying key")},
+		), nil
+	}
+	checks = append(checks, verifyCheck{Name: "verifying-key", Detail: fmt.Sprintf("%v %v", vk.circuitID, vk.digest)})
+
+	verify := func(x fr254.Element) error {
+		pub, err := publicWitness(x, ecc.BN254.ScalarField())
+		if err != nil {
+			return err
+		}
+		proof, err := circuits.DeserializeProofSolidityBn254(resp.AggregatedProof, vk.vk, pub)
+		if err != nil {
+			return err
+		}
+		return plonk.Verify(proof, vk.vk, pub)
+	}
+
+	return append(checks, checkProof(verify, x, claimed, x.Equal(&claimed))), nil
+}
+
+// checkPublicInput reports whether the public input claimed by the response
+// matches the one recomputed from its fields.
+func checkPublicInput(recomputed, claimed []byte) verifyCheck {
+	if !bytes.Equal(recomputed, claimed) {
+		return verifyCheck{
+			Name: "public-input",
+			Err: fmt.Errorf("mismatch: the response claims %v but its fields hash to %v",
+				utils.HexEncodeToString(claimed), utils.HexEncodeToString(recomputed)),
+		}
+	}
+	return verifyCheck{Name: "public-input", Detail: utils.HexEncodeToString(recomputed)}
+}
+
+// checkProof verifies the proof against the recomputed public input. When it
+// fails and the claimed public input differs, the proof is also verified
+// against it to tell whether the proof or the fields of the response are
+// wrong.
+func checkProof[E any](verify func(E) error, x, claimed E, equal bool) verifyCheck {
+
+	err := verify(x)
+	if err == nil {
+		return verifyCheck{Name: "proof", Detail: "valid for the recomputed public input"}
+	}
+
+	err = fmt.Errorf("invalid for the recomputed public input: %w", err)
+	if !equal {
+		if verify(claimed) == nil {
+			err = fmt.Errorf("%w; it is valid for the public input claimed by the response", err)
+		} else {
+			err = fmt.Errorf("%w; it is invalid for the public input claimed by the response too", err)
+		}
+	}
+
+	return verifyCheck{Name: "proof", Err: err}
+}
+
+// findVerifyingKey returns the verifying key of the candidate circuits, or of
+// the dummy circuit, whose digest is the one claimed by the response.
+func findVerifyingKey(
+	cfg *config.Config,
+	claimed string,
+	curveID ecc.ID,
+	dummyID circuits.CircuitID,
+	mockID circuits.MockCircuitID,
+	candidates ...circuits.CircuitID,
+) (*verifyingKey, error) {
+
+	var tried []string
+
+	for _, c := range candidates {
+		vk, err := loadVerifyingKey(cfg, c)
+		if err != nil {
+			tried = append(tried, fmt.Sprintf("%v (%v)", c, err))
+			continue
+		}
+		if strings.EqualFold(vk.digest, claimed) {
+			return vk, nil
+		}
+		tried = append(tried, fmt.Sprintf("%v (%v)", c, vk.digest))
+	}
+
+	vk, err := dummyVerifyingKey(cfg, dummyID, mockID, curveID)
+	if err != nil {
+		tried = append(tried, fmt.Sprintf("%v (%v)", dummyID, err))
+	} else if strings.EqualFold(vk.digest, claimed) {
+		return vk, nil
+	} else {
+		tried = append(tried, fmt.Sprintf("%v (%v)", dummyID, vk.digest))
+	}
+
+	return nil, fmt.Errorf("mismatch: the response claims %v which is none of %v", claimed, strings.Join(tried, ", "))
+}
+
+func loadVerifyingKey(cfg *config.Config, circuitID circuits.CircuitID) (*verifyingKey, error) {
+	vk, manifest, err := circuits.LoadVerifyingKey(cfg, circuitID)
+	if err != nil {
+		return nil, err
+	}
+	return &verifyingKey{circuitID: circuitID, vk: vk, digest: manifest.Checksums.VerifyingKey}, nil
+}
+
+// dummyVerifyingKey returns the verifying key of the dummy circuit used in
+// the development mode. It is regenerated from the SRS as the prover does.
+func dummyVerifyingKey(cfg *config.Config, dummyID circuits.CircuitID, mockID circuits.MockCircuitID, curveID ecc.ID) (*verifyingKey, error) {
+
+	srsProvider, err := circuits.NewSRSStore(cfg.PathForSRS())
+	if err != nil {
+		return nil, fmt.Errorf("could not create the SRS store: %w", err)
+	}
+
+	setup, err := dummy.MakeUnsafeSetup(srsProvider, mockID, curveID.ScalarField())
+	if err != nil {
+		return nil, fmt.Errorf("could not make the dummy setup: %w", err)
+	}
+
+	return &verifyingKey{circuitID: dummyID, vk: setup.VerifyingKey, digest: setup.VerifyingKeyDigest()}, nil
+}
+
+// publicWitness returns the public witness of a circuit having x as its only
+// public input.
+func publicWitness(x any, field *big.Int) (witness.Witness, error) {
+	return frontend.NewWitness(dummy.Assign(0, x), field, frontend.PublicOnly())
+}
+
+func verifyProof(proof plonk.Proof, vk plonk.VerifyingKey, x any, field *big.Int, opts ...backend.VerifierOption) error {
+	pub, err := publicWitness(x, field)
+	if err != nil {
+		return err
+	}
+	return plonk.Verify(proof, vk, pub, opts...)
+}
+
+func printVerifyReport(w io.Writer, kind, path string, checks []verifyCheck) error {
+
+	buf := &bytes.Buffer{}
+	fmt.Fprintf(buf, "%v response %v\n", kind, path)
+
+	tw := tabwriter.NewWriter(buf, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "CHECK\tSTATUS\tDETAIL")
+	for _, c := range checks {
+		if c.Err != nil {
+			fmt.Fprintf(tw, "%v\tFAILED\t%v\n", c.Name, c.Err)
+		} else {
+			fmt.Fprintf(tw, "%v\tok\t%v\n", c.Name, c.Detail)
+		}
+	}
+
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
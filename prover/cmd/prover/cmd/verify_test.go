@@ -0,0 +1,186 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	fr254 "github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark-crypto/kzg"
+	"github.com/consensys/gnark/backend/plonk"
+	"github.com/consensys/gnark/test/unsafekzg"
+	"github.com/consensys/linea-monorepo/prover/backend/aggregation"
+	"github.com/consensys/linea-monorepo/prover/circuits"
+	"github.com/consensys/linea-monorepo/prover/circuits/dummy"
+	"github.com/consensys/linea-monorepo/prover/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectResponseKind(t *testing.T) {
+
+	cases := []struct {
+		name     string
+		response string
+		kind     string
+	}{
+		{"execution", `{"proof": "0x", "blocksData": []}`, responseExecution},
+		{"blob-decompression", `{"decompressionProof": "0x", "compressedData": ""}`, responseBlobDecompression},
+		{"aggregation", `{"aggregatedProof": "0x", "aggregatedProofPublicInput": "0x"}`, responseAggregation},
+		{"unknown", `{"proof": "0x"}`, ""},
+		{"not-an-object", `[]`, ""},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			kind, err := detectResponseKind([]byte(c.response))
+			if c.kind == "" {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, c.kind, kind)
+		})
+	}
+}
+
+func TestCheckProof(t *testing.T) {
+
+	const x, claimed = 1, 2
+
+	// validFor returns a verification function accepting the proof only for
+	// the given public inputs
+	validFor := func(valid ...int) func(int) error {
+		return func(pi int) error {
+			for _, v := range valid {
+				if pi == v {
+					return nil
+				}
+			}
+			return errors.New("invalid proof")
+		}
+	}
+
+	check := checkProof(validFor(x), x, claimed, false)
+	assert.NoError(t, check.Err)
+
+	check = checkProof(validFor(), x, x, true)
+	assert.ErrorContains(t, check.Err, "invalid for the recomputed public input")
+	assert.NotContains(t, check.Err.Error(), "claimed by the response")
+
+	check = checkProof(validFor(claimed), x, claimed, false)
+	assert.ErrorContains(t, check.Err, "it is valid for the public input claimed by the response")
+
+	check = checkProof(validFor(), x, claimed, false)
+	assert.ErrorContains(t, check.Err, "it is invalid for the public input claimed by the response too")
+}
+
+func TestPrintVerifyReport(t *testing.T) {
+
+	buf := &bytes.Buffer{}
+	require.NoError(t, printVerifyReport(buf, responseAggregation, "resp.json", []verifyCheck{
+		{Name: "public-input", Detail: "0x01"},
+		{Name: "proof", Err: errors.New("invalid")},
+	}))
+
+	assert.Equal(t,
+		"aggregation response resp.json\n"+
+			"CHECK         STATUS  DETAIL\n"+
+			"public-input  ok      0x01\n"+
+			"proof         FAILED  invalid\n",
+		buf.String(),
+	)
+}
+
+// Verifies an aggregation response of the development mode, whose proof is a
+// proof of the dummy circuit, and the same response with a tampered field.
+func TestVerifyAggregation(t *testing.T) {
+
+	const id = circuits.MockCircuitIDEmulation
+
+	cfg := &config.Config{AssetsDir: t.TempDir()}
+	cfg.Aggregation.ProverMode = config.ProverModeDev
+	writeUnsafeSRS(t, cfg, id)
+
+	srsProvider, err := circuits.NewSRSStore(cfg.PathForSRS())
+	require.NoError(t, err)
+	setup, err := dummy.MakeUnsafeSetup(srsProvider, id, ecc.BN254.ScalarField())
+	require.NoError(t, err)
+
+	var (
+		hash = func(b byte) string { return "0x" + fmt.Sprintf("%064x", b) }
+		req  = &aggregation.Request{
+			ParentAggregationLastL1RollingHash:              hash(1),
+			ParentAggregationLastL1RollingHashMessageNumber: 3,
+		}
+		resp = &aggregation.Response{
+			FinalShnarf:                         hash(2),
+			ParentAggregationFinalShnarf:        hash(3),
+			ParentStateRootHash:                 hash(4),
+			ParentAggregationLastBlockTimestamp: 100,
+			FinalTimestamp:                      200,
+			LastFinalizedBlockNumber:            10,
+			FinalBlockNumber:                    20,
+			L1RollingHash:                       hash(5),
+			L1RollingHashMessageNumber:          4,
+			L2MerkleRoots:                       []string{hash(6)},
+			L2MsgTreesDepth:                     5,
+		}
+		x fr254.Element
+	)
+
+	x.SetBytes(resp.FuncInput(req.ParentAggregationLastL1RollingHash, uint(req.ParentAggregationLastL1RollingHashMessageNumber)).Sum(nil))
+	resp.AggregatedProof = dummy.MakeProof(&setup, x, id)
+	resp.AggregatedProofPublicInput = x.String()
+
+	verify := func() []verifyCheck {
+		b, err := json.Marshal(resp)
+		require.NoError(t, err)
+		checks, err := verifyAggregation(cfg, req, b)
+		require.NoError(t, err)
+		require.Len(t, checks, 3)
+		return checks
+	}
+
+	for _, c := range verify() {
+		assert.NoErrorf(t, c.Err, "check %v", c.Name)
+	}
+
+	// The proof is still valid for the public input claimed by the response,
+	// but not for the one of its fields.
+	resp.FinalTimestamp++
+	checks := verify()
+	assert.ErrorContains(t, checks[0].Err, "mismatch")
+	assert.NoError(t, checks[1].Err)
+	assert.ErrorContains(t, checks[2].Err, "it is valid for the public input claimed by the response")
+}
+
+// Writes an unsafe SRS large enough for the dummy circuit in the SRS
+// directory of the config.
+func writeUnsafeSRS(t *testing.T, cfg *config.Config, id circuits.MockCircuitID) {
+
+	cs, err := dummy.MakeCS(id, ecc.BN254.ScalarField())
+	require.NoError(t, err)
+
+	canonicalSize, lagrangeSize := plonk.SRSSize(cs)
+	canonical, lagrange, err := unsafekzg.NewSRS(cs)
+	require.NoError(t, err)
+
+	srsDir := cfg.PathForSRS()
+	require.NoError(t, os.MkdirAll(srsDir, 0700))
+
+	for _, srs := range []struct {
+		kind string
+		size int
+		srs  kzg.Serializable
+	}{{"canonical", canonicalSize, canonical}, {"lagrange", lagrangeSize, lagrange}} {
+		f, err := os.Create(filepath.Join(srsDir, fmt.Sprintf("kzg_srs_%v_%v_bn254_aleo.memdump", srs.kind, srs.size)))
+		require.NoError(t, err)
+		require.NoError(t, errors.Join(srs.srs.WriteDump(f), f.Close()))
+	}
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/consensys/gnark/logger"
 	"github.com/consensys/linea-monorepo/prover/cmd/prover/cmd"
+	"github.com/consensys/linea-monorepo/prover/zkevm/arithmetization"
 	"github.com/rs/zerolog"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -34,12 +35,31 @@ var (
 		RunE:  cmdProve,
 	}
 	proverArgs cmd.ProverArgs
+
+	// checkLimitsCmd represents the check-limits command
+	checkLimitsCmd = &cobra.Command{
+		Use:   "check-limits",
+		Short: "check the traces of an execution request against the normal and the large traces limits without proving",
+		RunE:  cmdCheckLimits,
+	}
+	checkLimitsArgs cmd.CheckLimitsArgs
+
+	// verifyCmd represents the verify command
+	verifyCmd = &cobra.Command{
+		Use:   "verify",
+		Short: "verify the proof of an execution, blob decompression or aggregation response against the public input recomputed from the response",
+		RunE:  cmdVerify,
+	}
+	verifyArgs cmd.VerifyArgs
+
+	// exitCode is the exit code of the prover when the command fails. The
+	// commands may set it to a more specific value than 1.
+	exitCode = 1
 )
 
 func main() {
-	err := rootCmd.Execute()
-	if err != nil {
-		os.Exit(1)
+	if err := rootCmd.Execute(); err != nil {
+		os.Exit(exitCode)
 	}
 }
 
@@ -54,6 +74,7 @@ func init() {
 
 	rootCmd.AddCommand(setupCmd)
 	setupCmd.Flags().BoolVar(&setupArgs.Force, "force", false, "overwrites existing files")
+	setupCmd.Flags().BoolVar(&setupArgs.Verify, "verify", false, "compiles the circuits and reports how they drifted from the setup on disk instead of setting them up")
 	setupCmd.Flags().StringVar(&setupArgs.Circuits, "circuits", strings.Join(allCircuitList(), ","), "comma separated list of circuits to setup")
 	setupCmd.Flags().StringVar(&setupArgs.DictPath, "dict", "", "path to the dictionary file used in blob (de)compression")
 	setupCmd.Flags().StringVar(&setupArgs.AssetsDir, "assets-dir", "", "path to the directory where the assets are stored (override conf)")
@@ -65,6 +86,19 @@ func init() {
 	proveCmd.Flags().StringVar(&proverArgs.Input, "in", "", "input file")
 	proveCmd.Flags().StringVar(&proverArgs.Output, "out", "", "output file")
 	proveCmd.Flags().BoolVar(&proverArgs.Large, "large", false, "run the large execution circuit")
+	proveCmd.Flags().StringVar(&proverArgs.JobType, "job-type", "", "type of the job (execution, blob-decompression or aggregation), derived from the input file otherwise")
+	proveCmd.Flags().StringVar(&proverArgs.TracesProfile, "traces-profile", "", "traces limits of the execution (normal or large), derived from --large and the input file name otherwise")
+	proveCmd.Flags().BoolVar(&proverArgs.CheckSetup, "check-setup", false, "compiles the circuits of the job and checks them against the setup before proving")
+
+	rootCmd.AddCommand(checkLimitsCmd)
+
+	checkLimitsCmd.Flags().StringVar(&checkLimitsArgs.Input, "in", "", "input file")
+	checkLimitsCmd.Flags().BoolVar(&checkLimitsArgs.JSON, "json", false, "print the report as JSON")
+
+	rootCmd.AddCommand(verifyCmd)
+
+	verifyCmd.Flags().StringVar(&verifyArgs.Input, "in", "", "response file")
+	verifyCmd.Flags().StringVar(&verifyArgs.Request, "request", "", "request file of the response, required for aggregation responses")
 }
 
 func cmdSetup(_cmd *cobra.Command, _ []string) error {
@@ -74,7 +108,22 @@ func cmdSetup(_cmd *cobra.Command, _ []string) error {
 
 func cmdProve(*cobra.Command, []string) error {
 	proverArgs.ConfigFile = fConfigFile
-	return cmd.Prove(proverArgs)
+	err := cmd.Prove(proverArgs)
+	if arithmetization.IsTraceOverflow(err) {
+		// the controller expects this code to retry with the large prover
+		exitCode = arithmetization.TraceOverflowExitCode
+	}
+	return err
+}
+
+func cmdCheckLimits(*cobra.Command, []string) error {
+	checkLimitsArgs.ConfigFile = fConfigFile
+	return cmd.CheckLimits(checkLimitsArgs)
+}
+
+func cmdVerify(*cobra.Command, []string) error {
+	verifyArgs.ConfigFile = fConfigFile
+	return cmd.Verify(verifyArgs)
 }
 
 // allCircuitList returns the list [cmd.AllCircuits] where the circuit id
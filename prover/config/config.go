@@ -54,6 +54,12 @@ func NewConfigFromFile(path string) (*Config, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse worker_cmd_large template: %w", err)
 	}
+	if len(cfg.Controller.PrecheckCmd) > 0 {
+		cfg.Controller.PrecheckCmdTmpl, err = template.New("precheck_cmd").Parse(cfg.Controller.PrecheckCmd)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse precheck_cmd template: %w", err)
+		}
+	}
 
 	// Set the logging level
 	logrus.SetLevel(logrus.Level(cfg.LogLevel)) // #nosec G115 -- overflow not possible (uint8 -> uint32)
@@ -164,12 +170,90 @@ type Controller struct {
 	// Prometheus stores the configuration for the Prometheus metrics server.
 	Prometheus Prometheus
 
+	// API stores the configuration of the HTTP server allowing to submit
+	// jobs to the controller and to query their status.
+	API API `mapstructure:"api"`
+
+	// JobSource selects the queue from which the controller fetches its
+	// jobs: the requests directories or a bucket of an object store.
+	JobSource JobSource `mapstructure:"job_source" validate:"omitempty,oneof=filesystem object-store"`
+
+	// ObjectStore stores the configuration of the bucket used as a job queue
+	// when JobSource is "object-store".
+	ObjectStore ObjectStore `mapstructure:"object_store"`
+
+	// WatchEvents enables the subscription to the filesystem events of the
+	// requests directories so that the controller picks up a new request as
+	// soon as it is written. The directories are still polled with the
+	// RetryDelays as the events are not delivered on every filesystem, e.g.
+	// NFS.
+	WatchEvents bool `mapstructure:"watch_events"`
+
+	// The time, in seconds, during which a request file must have been left
+	// unmodified before the controller locks it, so that a file that is still
+	// being written is not picked up. Zero, the default, disables the check.
+	RequestSettleDelay int `mapstructure:"request_settle_delay" validate:"gte=0"`
+
+	// The time, in seconds, after which a job still waiting for the responses
+	// it depends on is reported with a warning, e.g. an aggregation whose
+	// execution proof was never produced. Zero disables the warning.
+	BlockedWarnDelay int `mapstructure:"blocked_warn_delay" validate:"gte=0"`
+
+	// HistoryDir is the directory of the job history. After each run, the
+	// controller appends a record to `<history_dir>/<local_id>.jsonl`. The
+	// history is queried with the `history` command. Empty disables the
+	// history.
+	HistoryDir string `mapstructure:"history_dir"`
+
 	// The delays at which we retry when we find no files in the queue. If this
 	// is set to [0, 1, 2, 3, 4, 5]. It will retry after 0 sec the first time it
 	// cannot find a file in the queue, 1 sec the second time and so on. Once it
 	// reaches the final value it keeps it as a final retry delay.
 	RetryDelays []int `mapstructure:"retry_delays"`
 
+	// The period, in seconds, at which the controller refreshes the
+	// modification time of the request file that it has locked. The
+	// modification time of a locked file serves as a heartbeat telling the
+	// other controllers that the job is still being processed. Zero disables
+	// the heartbeat, which is only allowed when StaleLockTTL is zero.
+	HeartbeatPeriod int `mapstructure:"heartbeat_period" validate:"required_with=StaleLockTTL,gte=0"`
+
+	// The time, in seconds, after which a locked file whose heartbeat has not
+	// been refreshed is considered stale: its owner is assumed to have been
+	// killed and the file is put back in the queue. It should be several
+	// times the heartbeat period. Zero, the default, disables the recovery of
+	// the stale locks: it must only be enabled once all the controllers
+	// sharing the requests directories send the heartbeat, or the jobs of the
+	// others are proved twice.
+	StaleLockTTL int `mapstructure:"stale_lock_ttl" validate:"gte=0"`
+
+	// The number of slots of the controller. The controller runs several
+	// jobs concurrently as long as the sum of their weights does not exceed
+	// the number of slots. Zero is the same as one.
+	Slots int `mapstructure:"slots" validate:"gte=0"`
+
+	// The number of slots occupied by a job of each type. They reflect the
+	// memory and the CPU used by the prover for the job relatively to the
+	// capacity of the host. Zero, or a value larger than Slots, means that the
+	// job occupies all the slots and runs alone.
+	ExecutionWeight         int `mapstructure:"execution_weight" validate:"gte=0"`
+	BlobDecompressionWeight int `mapstructure:"blob_decompression_weight" validate:"gte=0"`
+	AggregationWeight       int `mapstructure:"aggregation_weight" validate:"gte=0"`
+
+	// The wall-clock timeouts, in seconds, of the jobs by type. When a job
+	// runs for longer, the controller sends a SIGTERM to the prover, then a
+	// SIGKILL after KillGracePeriod seconds, and the job exits with a dedicated
+	// code that can be listed in DeferToOtherLargeCodes or
+	// RetryLocallyWithLargeCodes. Zero disables the timeout.
+	ExecutionTimeout         int `mapstructure:"execution_timeout" validate:"gte=0"`
+	ExecutionLargeTimeout    int `mapstructure:"execution_large_timeout" validate:"gte=0"`
+	BlobDecompressionTimeout int `mapstructure:"blob_decompression_timeout" validate:"gte=0"`
+	AggregationTimeout       int `mapstructure:"aggregation_timeout" validate:"gte=0"`
+
+	// The time, in seconds, left to the prover to exit after a SIGTERM
+	// before it is killed with a SIGKILL.
+	KillGracePeriod int `mapstructure:"kill_grace_period" validate:"gte=0"`
+
 	// List of exit codes for which the job will put back the job to be reexecuted in large mode.
 	DeferToOtherLargeCodes []int `mapstructure:"defer_to_other_large_codes"`
 
@@ -187,6 +271,21 @@ type Controller struct {
 	WorkerCmdLarge     string             `mapstructure:"worker_cmd_large_tmpl"`
 	WorkerCmdTmpl      *template.Template `mapstructure:"-"`
 	WorkerCmdLargeTmpl *template.Template `mapstructure:"-"`
+
+	// PrecheckCmd is the template of a command estimating whether an
+	// execution job fits the traces limits of the normal prover, e.g.
+	// `prover check-limits --config {{.ConfFile}} --in {{.InFile}} --json`.
+	// It must print the JSON report of `check-limits`. A controller that
+	// cannot run the large prover runs it before the prover and defers the
+	// job to the large prover right away if the traces are predicted to
+	// overflow, sparing a failed run. Empty disables the prediction.
+	PrecheckCmd     string             `mapstructure:"precheck_cmd_tmpl"`
+	PrecheckCmdTmpl *template.Template `mapstructure:"-"`
+
+	// The timeout of the precheck command in seconds. When it is reached,
+	// the job runs on the normal prover as if there was no prediction. Zero
+	// means no timeout.
+	PrecheckTimeout int `mapstructure:"precheck_timeout" validate:"gte=0"`
 }
 
 type Prometheus struct {
@@ -199,6 +298,42 @@ type Prometheus struct {
 	Route string
 }
 
+type API struct {
+	Enabled bool
+	// The host the server listens on. It defaults to localhost so that the
+	// API is only reachable from the host of the controller. Set it to
+	// "0.0.0.0" to serve on every interface.
+	Host string
+	// The underlying implementation defaults to :8080.
+	Port int
+	// Path of the file holding the token that the clients must send in the
+	// Authorization header as `Bearer <token>`. It is kept out of the
+	// configuration so that it can be mounted as a secret. The API does not
+	// start without it.
+	TokenFile string `mapstructure:"token_file"`
+	// The maximal size, in bytes, of a submitted request file. Zero means no
+	// limit.
+	MaxRequestSize int64 `mapstructure:"max_request_size" validate:"gte=0"`
+}
+
+type ObjectStore struct {
+	// URL of the S3-compatible endpoint, e.g. http://minio:9000, in which
+	// case the bucket is addressed path-style. Empty selects the AWS S3
+	// endpoint of the region. The credentials are resolved by the default
+	// chain of the AWS SDK: environment variables, shared configuration
+	// files, IRSA, container and instance profile credentials.
+	Endpoint string
+	Region   string
+	Bucket   string
+	// Prefix of the keys of the queue. The files of a job type are stored
+	// under `<prefix>/<job type>/` in the same layout as the requests
+	// directories, e.g. `<prefix>/execution/requests/`.
+	Prefix string
+	// Local directory in which the requests are downloaded and the
+	// responses are written before being uploaded.
+	StagingDir string `mapstructure:"staging_dir"`
+}
+
 type Execution struct {
 	WithRequestDir `mapstructure:",squash"`
 
@@ -22,11 +22,23 @@ func setDefaultValues() {
 	viper.SetDefault("controller.retry_delays", []int{0, 1, 2, 3, 5, 8, 13, 21, 44, 85})
 	viper.SetDefault("controller.defer_to_other_large_codes", DefaultDeferToOtherLargeCodes)
 	viper.SetDefault("controller.retry_locally_with_large_codes", DefaultRetryLocallyWithLargeCodes)
+	viper.SetDefault("controller.job_source", string(JobSourceFilesystem))
+	viper.SetDefault("controller.watch_events", true)
+	viper.SetDefault("controller.api.host", "localhost")
+	viper.SetDefault("controller.request_settle_delay", 0)
+	viper.SetDefault("controller.blocked_warn_delay", 1800)
+	viper.SetDefault("controller.slots", 1)
+	viper.SetDefault("controller.heartbeat_period", 30)
+	viper.SetDefault("controller.stale_lock_ttl", 0)
+	viper.SetDefault("controller.kill_grace_period", 30)
+	viper.SetDefault("controller.precheck_timeout", 600)
 
 	// Set default for cmdTmpl and cmdLargeTmpl
 	// TODO @gbotrel binary to run prover is hardcoded here.
-	viper.SetDefault("controller.worker_cmd_tmpl", "prover prove --config {{.ConfFile}} --in {{.InFile}} --out {{.OutFile}}")
-	viper.SetDefault("controller.worker_cmd_large_tmpl", "prover prove --config {{.ConfFile}} --in {{.InFile}} --out {{.OutFile}} --large")
+	// The job type and the traces profile are passed explicitly so that the
+	// prover does not derive them from the name of the request file.
+	viper.SetDefault("controller.worker_cmd_tmpl", "prover prove --config {{.ConfFile}} --in {{.InFile}} --out {{.OutFile}} --job-type {{.JobType}} --traces-profile normal")
+	viper.SetDefault("controller.worker_cmd_large_tmpl", "prover prove --config {{.ConfFile}} --in {{.InFile}} --out {{.OutFile}} --job-type {{.JobType}} --traces-profile large")
 
 }
 
@@ -45,6 +57,7 @@ func setDefaultTracesLimit() {
 	viper.SetDefault("traces_limits.BLAKE_MODEXP_DATA", 16384)
 	viper.SetDefault("traces_limits.BLOCK_DATA", 1024)
 	viper.SetDefault("traces_limits.BLOCK_HASH", 512)
+	viper.SetDefault("traces_limits.BLS_DATA", 65536)
 	viper.SetDefault("traces_limits.EC_DATA", 262144)
 	viper.SetDefault("traces_limits.EUC", 65536)
 	viper.SetDefault("traces_limits.EXP", 8192)
@@ -83,6 +96,21 @@ func setDefaultTracesLimit() {
 	viper.SetDefault("traces_limits.PRECOMPILE_ECPAIRING_G2_MEMBERSHIP_CALLS", 64)
 	viper.SetDefault("traces_limits.PRECOMPILE_BLAKE_EFFECTIVE_CALLS", 600)
 	viper.SetDefault("traces_limits.PRECOMPILE_BLAKE_ROUNDS", 600)
+	viper.SetDefault("traces_limits.PRECOMPILE_P256_VERIFY_EFFECTIVE_CALLS", 128)
+	viper.SetDefault("traces_limits.PRECOMPILE_POINT_EVALUATION_EFFECTIVE_CALLS", 16)
+	viper.SetDefault("traces_limits.PRECOMPILE_POINT_EVALUATION_FAILURE_EFFECTIVE_CALLS", 8)
+	viper.SetDefault("traces_limits.PRECOMPILE_BLS_G1_ADD_EFFECTIVE_CALLS", 256)
+	viper.SetDefault("traces_limits.PRECOMPILE_BLS_G2_ADD_EFFECTIVE_CALLS", 128)
+	viper.SetDefault("traces_limits.PRECOMPILE_BLS_G1_MSM_SCALAR_MULTIPLICATIONS", 32)
+	viper.SetDefault("traces_limits.PRECOMPILE_BLS_G2_MSM_SCALAR_MULTIPLICATIONS", 16)
+	viper.SetDefault("traces_limits.PRECOMPILE_BLS_PAIRING_CHECK_MILLER_LOOPS", 64)
+	viper.SetDefault("traces_limits.PRECOMPILE_BLS_PAIRING_CHECK_FINAL_EXPONENTIATIONS", 16)
+	viper.SetDefault("traces_limits.PRECOMPILE_BLS_MAP_FP_TO_G1_EFFECTIVE_CALLS", 64)
+	viper.SetDefault("traces_limits.PRECOMPILE_BLS_MAP_FP2_TO_G2_EFFECTIVE_CALLS", 64)
+	viper.SetDefault("traces_limits.PRECOMPILE_BLS_C1_MEMBERSHIP_CALLS", 16)
+	viper.SetDefault("traces_limits.PRECOMPILE_BLS_G1_MEMBERSHIP_CALLS", 16)
+	viper.SetDefault("traces_limits.PRECOMPILE_BLS_C2_MEMBERSHIP_CALLS", 16)
+	viper.SetDefault("traces_limits.PRECOMPILE_BLS_G2_MEMBERSHIP_CALLS", 16)
 
 	// Block limits
 	viper.SetDefault("traces_limits.BLOCK_KECCAK", 8192)
@@ -106,6 +134,7 @@ func setDefaultTracesLimit() {
 	viper.SetDefault("traces_limits_large.BLAKE_MODEXP_DATA", 32768)
 	viper.SetDefault("traces_limits_large.BLOCK_DATA", 2048)
 	viper.SetDefault("traces_limits_large.BLOCK_HASH", 1024)
+	viper.SetDefault("traces_limits_large.BLS_DATA", 131072)
 	viper.SetDefault("traces_limits_large.EC_DATA", 524288)
 	viper.SetDefault("traces_limits_large.EUC", 131072)
 	viper.SetDefault("traces_limits_large.EXP", 16384)
@@ -144,6 +173,21 @@ func setDefaultTracesLimit() {
 	viper.SetDefault("traces_limits_large.PRECOMPILE_ECPAIRING_G2_MEMBERSHIP_CALLS", 128)
 	viper.SetDefault("traces_limits_large.PRECOMPILE_BLAKE_EFFECTIVE_CALLS", 600)
 	viper.SetDefault("traces_limits_large.PRECOMPILE_BLAKE_ROUNDS", 600)
+	viper.SetDefault("traces_limits_large.PRECOMPILE_P256_VERIFY_EFFECTIVE_CALLS", 256)
+	viper.SetDefault("traces_limits_large.PRECOMPILE_POINT_EVALUATION_EFFECTIVE_CALLS", 32)
+	viper.SetDefault("traces_limits_large.PRECOMPILE_POINT_EVALUATION_FAILURE_EFFECTIVE_CALLS", 16)
+	viper.SetDefault("traces_limits_large.PRECOMPILE_BLS_G1_ADD_EFFECTIVE_CALLS", 512)
+	viper.SetDefault("traces_limits_large.PRECOMPILE_BLS_G2_ADD_EFFECTIVE_CALLS", 256)
+	viper.SetDefault("traces_limits_large.PRECOMPILE_BLS_G1_MSM_SCALAR_MULTIPLICATIONS", 64)
+	viper.SetDefault("traces_limits_large.PRECOMPILE_BLS_G2_MSM_SCALAR_MULTIPLICATIONS", 32)
+	viper.SetDefault("traces_limits_large.PRECOMPILE_BLS_PAIRING_CHECK_MILLER_LOOPS", 128)
+	viper.SetDefault("traces_limits_large.PRECOMPILE_BLS_PAIRING_CHECK_FINAL_EXPONENTIATIONS", 32)
+	viper.SetDefault("traces_limits_large.PRECOMPILE_BLS_MAP_FP_TO_G1_EFFECTIVE_CALLS", 128)
+	viper.SetDefault("traces_limits_large.PRECOMPILE_BLS_MAP_FP2_TO_G2_EFFECTIVE_CALLS", 128)
+	viper.SetDefault("traces_limits_large.PRECOMPILE_BLS_C1_MEMBERSHIP_CALLS", 32)
+	viper.SetDefault("traces_limits_large.PRECOMPILE_BLS_G1_MEMBERSHIP_CALLS", 32)
+	viper.SetDefault("traces_limits_large.PRECOMPILE_BLS_C2_MEMBERSHIP_CALLS", 32)
+	viper.SetDefault("traces_limits_large.PRECOMPILE_BLS_G2_MEMBERSHIP_CALLS", 32)
 
 	// Block limits
 	viper.SetDefault("traces_limits_large.BLOCK_KECCAK", 8192)
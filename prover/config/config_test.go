@@ -2,6 +2,7 @@ package config
 
 import (
 	"os"
+	"reflect"
 	"regexp"
 	"strings"
 	"testing"
@@ -58,3 +59,48 @@ func TestEnvironment(t *testing.T) {
 
 	assert.NotEqual(0, count, "no config file found")
 }
+
+// TestDefaultTracesLimits ensures that every module of the arithmetization
+// has a default limit in both the normal and the large profiles. A missing
+// limit would size the columns of the module to zero.
+func TestDefaultTracesLimits(t *testing.T) {
+
+	setDefaultValues()
+
+	for _, key := range []string{"traces_limits", "traces_limits_large"} {
+
+		var limits TracesLimits
+		require.NoError(t, viper.UnmarshalKey(key, &limits))
+
+		var (
+			limitVal  = reflect.ValueOf(limits)
+			limitType = limitVal.Type()
+		)
+
+		for i := 0; i < limitType.NumField(); i++ {
+			if len(limitType.Field(i).Tag.Get("corset")) == 0 {
+				continue
+			}
+			limit := limitVal.Field(i).Int()
+			require.Truef(t, limit > 0 && limit&(limit-1) == 0, "%v.%v is not a power of two: %v", key, limitType.Field(i).Name, limit)
+		}
+	}
+}
+
+// TestStaleLockTTLRequiresHeartbeat ensures that a config reclaiming the
+// stale locks without sending the heartbeat is rejected: the long jobs of
+// the controller would otherwise be put back in the queue while running.
+func TestStaleLockTTLRequiresHeartbeat(t *testing.T) {
+
+	t.Cleanup(viper.Reset)
+
+	viper.Set("assets_dir", "../prover-assets")
+	viper.Set("controller.stale_lock_ttl", 600)
+	viper.Set("controller.heartbeat_period", 0)
+	_, err := NewConfigFromFile("config-integration-full.toml")
+	require.ErrorContains(t, err, "HeartbeatPeriod")
+
+	viper.Set("controller.heartbeat_period", 30)
+	_, err = NewConfigFromFile("config-integration-full.toml")
+	require.NoError(t, err)
+}
@@ -10,6 +10,8 @@ const (
 	RequestsFromSubDir = "requests"
 	RequestsToSubDir   = "responses"
 	RequestsDoneSubDir = "requests-done"
+	// Directory of the metadata of the done requests, see the controller
+	RequestsDoneMetadataSubDir = "requests-done-metadata"
 
 	InProgressSufix = "inprogress"
 	FailSuffix      = "code"
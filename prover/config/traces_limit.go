@@ -18,6 +18,7 @@ type TracesLimits struct {
 	Blake2Fmodexpdata int `mapstructure:"BLAKE_MODEXP_DATA" validate:"power_of_2" corset:"blake2fmodexpdata"`
 	Blockdata         int `mapstructure:"BLOCK_DATA" corset:"blockdata"`
 	Blockhash         int `mapstructure:"BLOCK_HASH" validate:"power_of_2" corset:"blockhash"`
+	Blsdata           int `mapstructure:"BLS_DATA" validate:"power_of_2" corset:"blsdata"`
 	Ecdata            int `mapstructure:"EC_DATA" validate:"power_of_2" corset:"ecdata"`
 	Euc               int `mapstructure:"EUC" validate:"power_of_2" corset:"euc"`
 	Exp               int `mapstructure:"EXP" validate:"power_of_2" corset:"exp"`
@@ -48,17 +49,32 @@ type TracesLimits struct {
 	Shfreftable int `mapstructure:"SHF_REFERENCE_TABLE" validate:"power_of_2" corset:"shfreftable"`
 	Instdecoder int `mapstructure:"INSTRUCTION_DECODER" validate:"power_of_2" corset:"instdecoder"`
 
-	PrecompileEcrecoverEffectiveCalls    int `mapstructure:"PRECOMPILE_ECRECOVER_EFFECTIVE_CALLS"`
-	PrecompileSha2Blocks                 int `mapstructure:"PRECOMPILE_SHA2_BLOCKS"`
-	PrecompileRipemdBlocks               int `mapstructure:"PRECOMPILE_RIPEMD_BLOCKS"`
-	PrecompileModexpEffectiveCalls       int `mapstructure:"PRECOMPILE_MODEXP_EFFECTIVE_CALLS"`
-	PrecompileEcaddEffectiveCalls        int `mapstructure:"PRECOMPILE_ECADD_EFFECTIVE_CALLS"`
-	PrecompileEcmulEffectiveCalls        int `mapstructure:"PRECOMPILE_ECMUL_EFFECTIVE_CALLS"`
-	PrecompileEcpairingEffectiveCalls    int `mapstructure:"PRECOMPILE_ECPAIRING_FINAL_EXPONENTIATIONS"`
-	PrecompileEcpairingMillerLoops       int `mapstructure:"PRECOMPILE_ECPAIRING_MILLER_LOOPS"`
-	PrecompileEcpairingG2MembershipCalls int `mapstructure:"PRECOMPILE_ECPAIRING_G2_MEMBERSHIP_CALLS"`
-	PrecompileBlakeEffectiveCalls        int `mapstructure:"PRECOMPILE_BLAKE_EFFECTIVE_CALLS"`
-	PrecompileBlakeRounds                int `mapstructure:"PRECOMPILE_BLAKE_ROUNDS"`
+	PrecompileEcrecoverEffectiveCalls        int `mapstructure:"PRECOMPILE_ECRECOVER_EFFECTIVE_CALLS"`
+	PrecompileSha2Blocks                     int `mapstructure:"PRECOMPILE_SHA2_BLOCKS"`
+	PrecompileRipemdBlocks                   int `mapstructure:"PRECOMPILE_RIPEMD_BLOCKS"`
+	PrecompileModexpEffectiveCalls           int `mapstructure:"PRECOMPILE_MODEXP_EFFECTIVE_CALLS"`
+	PrecompileEcaddEffectiveCalls            int `mapstructure:"PRECOMPILE_ECADD_EFFECTIVE_CALLS"`
+	PrecompileEcmulEffectiveCalls            int `mapstructure:"PRECOMPILE_ECMUL_EFFECTIVE_CALLS"`
+	PrecompileEcpairingEffectiveCalls        int `mapstructure:"PRECOMPILE_ECPAIRING_FINAL_EXPONENTIATIONS"`
+	PrecompileEcpairingMillerLoops           int `mapstructure:"PRECOMPILE_ECPAIRING_MILLER_LOOPS"`
+	PrecompileEcpairingG2MembershipCalls     int `mapstructure:"PRECOMPILE_ECPAIRING_G2_MEMBERSHIP_CALLS"`
+	PrecompileBlakeEffectiveCalls            int `mapstructure:"PRECOMPILE_BLAKE_EFFECTIVE_CALLS"`
+	PrecompileBlakeRounds                    int `mapstructure:"PRECOMPILE_BLAKE_ROUNDS"`
+	PrecompileP256VerifyEffectiveCalls       int `mapstructure:"PRECOMPILE_P256_VERIFY_EFFECTIVE_CALLS"`
+	PrecompilePointEvaluationEffectiveCalls  int `mapstructure:"PRECOMPILE_POINT_EVALUATION_EFFECTIVE_CALLS"`
+	PrecompilePointEvalFailureEffectiveCalls int `mapstructure:"PRECOMPILE_POINT_EVALUATION_FAILURE_EFFECTIVE_CALLS"`
+	PrecompileBlsG1AddEffectiveCalls         int `mapstructure:"PRECOMPILE_BLS_G1_ADD_EFFECTIVE_CALLS"`
+	PrecompileBlsG2AddEffectiveCalls         int `mapstructure:"PRECOMPILE_BLS_G2_ADD_EFFECTIVE_CALLS"`
+	PrecompileBlsG1MsmScalarMuls             int `mapstructure:"PRECOMPILE_BLS_G1_MSM_SCALAR_MULTIPLICATIONS"`
+	PrecompileBlsG2MsmScalarMuls             int `mapstructure:"PRECOMPILE_BLS_G2_MSM_SCALAR_MULTIPLICATIONS"`
+	PrecompileBlsPairingMillerLoops          int `mapstructure:"PRECOMPILE_BLS_PAIRING_CHECK_MILLER_LOOPS"`
+	PrecompileBlsPairingFinalExponentiations int `mapstructure:"PRECOMPILE_BLS_PAIRING_CHECK_FINAL_EXPONENTIATIONS"`
+	PrecompileBlsMapFpToG1EffectiveCalls     int `mapstructure:"PRECOMPILE_BLS_MAP_FP_TO_G1_EFFECTIVE_CALLS"`
+	PrecompileBlsMapFp2ToG2EffectiveCalls    int `mapstructure:"PRECOMPILE_BLS_MAP_FP2_TO_G2_EFFECTIVE_CALLS"`
+	PrecompileBlsC1MembershipCalls           int `mapstructure:"PRECOMPILE_BLS_C1_MEMBERSHIP_CALLS"`
+	PrecompileBlsG1MembershipCalls           int `mapstructure:"PRECOMPILE_BLS_G1_MEMBERSHIP_CALLS"`
+	PrecompileBlsC2MembershipCalls           int `mapstructure:"PRECOMPILE_BLS_C2_MEMBERSHIP_CALLS"`
+	PrecompileBlsG2MembershipCalls           int `mapstructure:"PRECOMPILE_BLS_G2_MEMBERSHIP_CALLS"`
 
 	BlockKeccak       int `mapstructure:"BLOCK_KECCAK"`
 	BlockL1Size       int `mapstructure:"BLOCK_L1_SIZE"`
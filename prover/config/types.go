@@ -28,3 +28,13 @@ const (
 	// ProverModeCheckOnly is used to test the constraints of the whole system
 	ProverModeCheckOnly ProverMode = "check-only"
 )
+
+// JobSource is the kind of queue the controller fetches its jobs from
+type JobSource string
+
+const (
+	// JobSourceFilesystem watches the requests directories
+	JobSourceFilesystem JobSource = "filesystem"
+	// JobSourceObjectStore watches a bucket of an S3-compatible object store
+	JobSourceObjectStore JobSource = "object-store"
+)
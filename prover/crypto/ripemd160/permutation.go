@@ -0,0 +1,87 @@
+package ripemd160
+
+import "math/bits"
+
+// message word selection for the left line
+var _rLeft = [80]uint{
+	0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15,
+	7, 4, 13, 1, 10, 6, 15, 3, 12, 0, 9, 5, 2, 14, 11, 8,
+	3, 10, 14, 4, 9, 15, 8, 1, 2, 7, 0, 6, 13, 11, 5, 12,
+	1, 9, 11, 10, 0, 8, 12, 4, 13, 3, 7, 15, 14, 5, 6, 2,
+	4, 0, 5, 9, 7, 12, 2, 10, 14, 1, 3, 8, 11, 6, 15, 13,
+}
+
+// message word selection for the right line
+var _rRight = [80]uint{
+	5, 14, 7, 0, 9, 2, 11, 4, 13, 6, 15, 8, 1, 10, 3, 12,
+	6, 11, 3, 7, 0, 13, 5, 10, 14, 15, 8, 12, 4, 9, 1, 2,
+	15, 5, 1, 3, 7, 14, 6, 9, 11, 8, 12, 2, 10, 0, 4, 13,
+	8, 6, 4, 1, 3, 11, 15, 0, 5, 12, 2, 13, 9, 7, 10, 14,
+	12, 15, 10, 4, 1, 5, 8, 7, 6, 2, 13, 14, 0, 3, 9, 11,
+}
+
+// rotation amounts for the left line
+var _sLeft = [80]int{
+	11, 14, 15, 12, 5, 8, 7, 9, 11, 13, 14, 15, 6, 7, 9, 8,
+	7, 6, 8, 13, 11, 9, 7, 15, 7, 12, 15, 9, 11, 7, 13, 12,
+	11, 13, 6, 7, 14, 9, 13, 15, 14, 8, 13, 6, 5, 12, 7, 5,
+	11, 12, 14, 15, 14, 15, 9, 8, 9, 14, 5, 6, 8, 6, 5, 12,
+	9, 15, 5, 11, 6, 8, 13, 12, 5, 12, 13, 14, 11, 8, 5, 6,
+}
+
+// rotation amounts for the right line
+var _sRight = [80]int{
+	8, 9, 9, 11, 13, 15, 15, 5, 7, 7, 8, 11, 14, 14, 12, 6,
+	9, 13, 15, 7, 12, 8, 9, 11, 7, 7, 12, 7, 6, 15, 13, 11,
+	9, 7, 15, 11, 8, 6, 6, 14, 12, 13, 5, 14, 13, 13, 7, 5,
+	15, 5, 8, 11, 14, 14, 6, 14, 6, 9, 12, 9, 12, 5, 15, 8,
+	8, 5, 12, 9, 12, 5, 14, 6, 8, 13, 6, 5, 15, 13, 11, 11,
+}
+
+// round constants of the left and the right lines
+var (
+	_kLeft  = [5]uint32{0x00000000, 0x5a827999, 0x6ed9eba1, 0x8f1bbcdc, 0xa953fd4e}
+	_kRight = [5]uint32{0x50a28be6, 0x5c4dd124, 0x6d703ef3, 0x7a6d76e9, 0x00000000}
+)
+
+// boolean function of RIPEMD-160 used at step j
+func f(j int, x, y, z uint32) uint32 {
+	switch j / 16 {
+	case 0:
+		return x ^ y ^ z
+	case 1:
+		return (x & y) | (^x & z)
+	case 2:
+		return (x | ^y) ^ z
+	case 3:
+		return (x & z) | (y & ^z)
+	default:
+		return x ^ (y | ^z)
+	}
+}
+
+// permutation of ripemd160
+func permRipemd160(dig *digestUint32, p blockUint32) {
+
+	var (
+		a, b, c, d, e      = dig[0], dig[1], dig[2], dig[3], dig[4]
+		aa, bb, cc, dd, ee = a, b, c, d, e
+	)
+
+	for j := 0; j < 80; j++ {
+
+		t := bits.RotateLeft32(a+f(j, b, c, d)+p[_rLeft[j]]+_kLeft[j/16], _sLeft[j]) + e
+		a, b, c, d, e = e, t, b, bits.RotateLeft32(c, 10), d
+
+		// the right line uses the boolean functions in reverse order
+		t = bits.RotateLeft32(aa+f(79-j, bb, cc, dd)+p[_rRight[j]]+_kRight[j/16], _sRight[j]) + ee
+		aa, bb, cc, dd, ee = ee, t, bb, bits.RotateLeft32(cc, 10), dd
+	}
+
+	t := dig[1] + c + dd
+	dig[1] = dig[2] + d + ee
+	dig[2] = dig[3] + e + aa
+	dig[3] = dig[4] + a + bb
+	dig[4] = dig[0] + b + cc
+	dig[0] = t
+}
@@ -0,0 +1,54 @@
+package ripemd160
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	//lint:ignore SA1019 the reference implementation is only used for testing
+	"golang.org/x/crypto/ripemd160" //nolint:staticcheck
+)
+
+type testCase struct {
+	ExpectedHash Digest
+	Stream       []byte
+}
+
+func TestHash(t *testing.T) {
+
+	var (
+		maxSizeByte = 1000
+		// #nosec G404 -- we don't need a cryptographic PRNG for testing purposes
+		rng = rand.New(rand.NewSource(212678))
+	)
+
+	for sizeByte := 0; sizeByte < maxSizeByte; sizeByte++ {
+
+		var (
+			testCase                = genTestCase(rng, sizeByte)
+			recoveredHashWoTraces   = Hash(testCase.Stream, nil)
+			recoveredHashWithTraces = Hash(testCase.Stream, &HashTraces{})
+		)
+
+		assert.Equalf(t, testCase.ExpectedHash, recoveredHashWoTraces, "(without trace) for input of size: %v", sizeByte)
+		assert.Equalf(t, testCase.ExpectedHash, recoveredHashWithTraces, "(with trace) for input of size: %v", sizeByte)
+	}
+}
+
+func genTestCase(rng *rand.Rand, sizeByte int) testCase {
+
+	var (
+		stream = make([]byte, sizeByte)
+		h      = ripemd160.New()
+		res    Digest
+	)
+
+	rng.Read(stream)
+	h.Write(stream)
+	copy(res[:], h.Sum(nil))
+
+	return testCase{
+		Stream:       stream,
+		ExpectedHash: res,
+	}
+}
@@ -17,8 +17,15 @@ func GetVersion(blob []byte) uint16 {
 		return 0
 	}
 
-	if blob[0] == 0x3f && blob[1] == 0xff && blob[2]&0xc0 == 0xc0 {
-		return 1
+	// The versions count down from 0xffff. Once packed, the version spans the
+	// first 18 bits of the blob, the first two of which are zero.
+	if blob[0] == 0x3f && blob[1] == 0xff {
+		switch blob[2] & 0xc0 {
+		case 0xc0:
+			return 1
+		case 0x80:
+			return 2
+		}
 	}
 	return 0
 }
@@ -45,6 +52,9 @@ func DecompressBlob(blob []byte, dictStore dictionary.Store) ([]byte, error) {
 	case 1:
 		_, _, blocks, err = v1.DecompressBlob(blob, dictStore)
 		blockDecoder = v1.DecodeBlockFromUncompressed
+	case 2:
+		_, _, blocks, err = v1.DecompressBlob(blob, dictStore)
+		blockDecoder = v1.VersionV2.DecodeBlockFromUncompressed
 	default:
 		return nil, errors.New("unrecognized blob version")
 	}
@@ -7,9 +7,13 @@ import (
 	"github.com/consensys/linea-monorepo/prover/lib/compressor/blob/dictionary"
 	"github.com/consensys/linea-monorepo/prover/lib/compressor/blob/encode"
 	v0 "github.com/consensys/linea-monorepo/prover/lib/compressor/blob/v0"
+	v1 "github.com/consensys/linea-monorepo/prover/lib/compressor/blob/v1"
 	blobv1testing "github.com/consensys/linea-monorepo/prover/lib/compressor/blob/v1/test_utils"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+	"github.com/holiman/uint256"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"os"
@@ -22,6 +26,52 @@ func TestGetVersion(t *testing.T) {
 	assert.Equal(t, uint32(0x10000), uint32(0xffff)+uint32(blob.GetVersion(_blob)), "version should match the current one")
 }
 
+func TestGetVersionV2(t *testing.T) {
+	privKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+
+	chainID := uint256.NewInt(59144)
+	auth, err := types.SignSetCode(privKey, types.SetCodeAuthorization{ChainID: *chainID, Nonce: 2})
+	require.NoError(t, err)
+
+	tx, err := types.SignNewTx(privKey, types.NewPragueSigner(chainID.ToBig()), &types.SetCodeTx{
+		ChainID:   chainID,
+		Nonce:     1,
+		GasTipCap: uint256.NewInt(1),
+		GasFeeCap: uint256.NewInt(2),
+		Gas:       21000,
+		Value:     uint256.NewInt(0),
+		AuthList:  []types.SetCodeAuthorization{auth},
+	})
+	require.NoError(t, err)
+
+	block := types.NewBlock(&types.Header{Time: 1}, &types.Body{Transactions: []*types.Transaction{tx}}, nil, trie.NewStackTrie(nil))
+	blockRlp, err := rlp.EncodeToBytes(block)
+	require.NoError(t, err)
+
+	bm, err := v1.NewBlobMakerVersion(64*1024, dictPath, v1.VersionV2)
+	require.NoError(t, err)
+	ok, err := bm.Write(blockRlp, false)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	_blob := bm.Bytes()
+	assert.Equal(t, uint32(0x10000), uint32(0xfffe)+uint32(blob.GetVersion(_blob)), "version should match the v2 one")
+
+	dictStore := dictionary.NewStore()
+	require.NoError(t, dictStore.Load(dictPath))
+	blocksSerialized, err := blob.DecompressBlob(_blob, dictStore)
+	require.NoError(t, err)
+
+	var blocksRlp [][]byte
+	require.NoError(t, rlp.DecodeBytes(blocksSerialized, &blocksRlp))
+	require.Len(t, blocksRlp, 1)
+	var blockBack types.Block
+	require.NoError(t, rlp.DecodeBytes(blocksRlp[0], &blockBack))
+	require.Len(t, blockBack.Transactions(), 1)
+	assert.Equal(t, tx.SetCodeAuthorizations(), blockBack.Transactions()[0].SetCodeAuthorizations())
+}
+
 const dictPath = "../compressor_dict.bin"
 
 func TestAddToBlob(t *testing.T) {
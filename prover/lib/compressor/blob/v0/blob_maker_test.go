@@ -569,9 +569,10 @@ func init() {
 			panic(err)
 		}
 
-		for _, block := range proverInput.Blocks() {
+		blocks := proverInput.Blocks()
+		for i := range blocks {
 			var bb bytes.Buffer
-			if err = block.EncodeRLP(&bb); err != nil {
+			if err = blocks[i].EncodeRLP(&bb); err != nil {
 				panic(err)
 			}
 			testBlocks = append(testBlocks, bb.Bytes())
@@ -50,11 +50,21 @@ type BlobMaker struct {
 	packBuffer bytes.Buffer
 }
 
-// NewBlobMaker returns a new bm.
+// NewBlobMaker returns a new bm producing [VersionV1] blobs.
 func NewBlobMaker(dataLimit int, dictPath string) (*BlobMaker, error) {
+	return NewBlobMakerVersion(dataLimit, dictPath, VersionV1)
+}
+
+// NewBlobMakerVersion returns a new bm producing blobs of the given version.
+func NewBlobMakerVersion(dataLimit int, dictPath string, version Version) (*BlobMaker, error) {
+	if version != VersionV1 && version != VersionV2 {
+		return nil, fmt.Errorf("unsupported blob version %d", version)
+	}
+
 	blobMaker := BlobMaker{
 		Limit: dataLimit,
 	}
+	blobMaker.header.Version = version
 	blobMaker.buf.Grow(1 << 17)
 
 	// initialize compressor
@@ -149,7 +159,7 @@ func (bm *BlobMaker) Write(rlpBlock []byte, forceReset bool) (ok bool, err error
 
 	// re-encode it for compression
 	bm.buf.Reset()
-	if err = EncodeBlockForCompression(&block, &bm.buf); err != nil {
+	if err = bm.header.version().EncodeBlockForCompression(&block, &bm.buf); err != nil {
 		return false, fmt.Errorf("when re-encoding block for compression: %w", err)
 	}
 	blockLen := bm.buf.Len()
@@ -334,7 +344,7 @@ func DecompressBlob(b []byte, dictStore dictionary.Store) (blobHeader *Header, r
 
 		batchOffset := offset
 		for offset < batchOffset+batchLen {
-			if blockLen, err := ScanBlockByteLen(rawPayload[offset:]); err != nil {
+			if blockLen, err := blobHeader.version().ScanBlockByteLen(rawPayload[offset:]); err != nil {
 				return nil, nil, nil, err
 			} else {
 				blocks = append(blocks, rawPayload[offset:offset+blockLen])
@@ -368,7 +378,7 @@ func (bm *BlobMaker) WorstCompressedBlockSize(rlpBlock []byte) (bool, int, error
 
 	// encode the block in Linea format.
 	var buf bytes.Buffer
-	if err := EncodeBlockForCompression(&block, &buf); err != nil {
+	if err := bm.header.version().EncodeBlockForCompression(&block, &buf); err != nil {
 		return false, -1, fmt.Errorf("failed to encode block: %w", err)
 	}
 
@@ -410,7 +420,7 @@ func (bm *BlobMaker) WorstCompressedTxSize(rlpTx []byte) (int, error) {
 
 	// encode the transaction in Linea format.
 	var buf bytes.Buffer
-	if err := EncodeTxForCompression(&tx, &buf); err != nil {
+	if err := bm.header.version().EncodeTxForCompression(&tx, &buf); err != nil {
 		return -1, fmt.Errorf("failed to encode transaction: %w", err)
 	}
 
@@ -14,8 +14,63 @@ import (
 	"github.com/ethereum/go-ethereum/core/types"
 )
 
-// EncodeBlockForCompression encodes a block for compression.
+// ErrSetCodeTxUnsupported is returned when encoding, scanning or decoding an
+// EIP-7702 set-code transaction with [VersionV1]. The decompression circuit of
+// the v1 format does not parse them: they are supported by [VersionV2].
+var ErrSetCodeTxUnsupported = errors.New("set-code transactions are not supported by the v1 blob format")
+
+// Version is the version of the blob format, as written in the first two bytes
+// of the header. The versions share the same header and block encoding, they
+// only differ by the transaction types they accept.
+type Version uint16
+
+const (
+	// VersionV1 is the format proven by the v1 decompression circuit.
+	VersionV1 Version = 0xffff
+	// VersionV2 extends [VersionV1] with the EIP-7702 set-code transactions.
+	VersionV2 Version = 0xfffe
+)
+
+// supportsSetCode returns true if the version can encode set-code transactions.
+func (v Version) supportsSetCode() bool {
+	return v == VersionV2
+}
+
+// lastTxType returns the highest transaction type prefix of the version.
+func (v Version) lastTxType() int {
+	if v.supportsSetCode() {
+		return types.SetCodeTxType
+	}
+	return types.DynamicFeeTxType
+}
+
+// EncodeBlockForCompression encodes a block for compression with [VersionV1].
 func EncodeBlockForCompression(block *types.Block, w io.Writer) error {
+	return VersionV1.EncodeBlockForCompression(block, w)
+}
+
+// EncodeTxForCompression encodes a single transaction with [VersionV1].
+func EncodeTxForCompression(tx *types.Transaction, w io.Writer) error {
+	return VersionV1.EncodeTxForCompression(tx, w)
+}
+
+// ScanBlockByteLen scans a block encoded with [VersionV1].
+func ScanBlockByteLen(b []byte) (int, error) {
+	return VersionV1.ScanBlockByteLen(b)
+}
+
+// DecodeBlockFromUncompressed decodes a block encoded with [VersionV1].
+func DecodeBlockFromUncompressed(r *bytes.Reader) (encode.DecodedBlockData, error) {
+	return VersionV1.DecodeBlockFromUncompressed(r)
+}
+
+// DecodeTxFromUncompressed decodes a transaction encoded with [VersionV1].
+func DecodeTxFromUncompressed(r *bytes.Reader, from *common.Address) (types.TxData, error) {
+	return VersionV1.DecodeTxFromUncompressed(r, from)
+}
+
+// EncodeBlockForCompression encodes a block for compression.
+func (v Version) EncodeBlockForCompression(block *types.Block, w io.Writer) error {
 
 	if block == nil {
 		return fmt.Errorf("block is nil")
@@ -43,7 +98,7 @@ func EncodeBlockForCompression(block *types.Block, w io.Writer) error {
 	w.Write(blockHash[:])
 
 	for i, tx := range transactions {
-		if err := EncodeTxForCompression(tx, w); err != nil {
+		if err := v.EncodeTxForCompression(tx, w); err != nil {
 			return fmt.Errorf("could not encode transaction #%v: %w", i, err)
 		}
 	}
@@ -52,11 +107,15 @@ func EncodeBlockForCompression(block *types.Block, w io.Writer) error {
 }
 
 // encodeTransaction encodes a single transaction
-func EncodeTxForCompression(tx *types.Transaction, w io.Writer) error {
+func (v Version) EncodeTxForCompression(tx *types.Transaction, w io.Writer) error {
 	if tx == nil {
 		return fmt.Errorf("transactions is nil")
 	}
 
+	if tx.Type() == types.SetCodeTxType && !v.supportsSetCode() {
+		return ErrSetCodeTxUnsupported
+	}
+
 	var (
 		from    = ethereum.GetFrom(tx)
 		txRlp   = ethereum.EncodeTxForSigning(tx)
@@ -70,7 +129,7 @@ func EncodeTxForCompression(tx *types.Transaction, w io.Writer) error {
 // ScanBlockByteLen scans the stream of bytes `b`, expecting to find an encoded
 // block starting from position 0 and returns the length of the block. It returns
 // an error if the scanner goes out of bound.
-func ScanBlockByteLen(b []byte) (int, error) {
+func (v Version) ScanBlockByteLen(b []byte) (int, error) {
 
 	const (
 		// preTxBufSize corresponds to the size of the buffer area used for
@@ -108,18 +167,24 @@ func ScanBlockByteLen(b []byte) (int, error) {
 		// Pass the tx from address
 		r.Seek(int64(len(common.Address{})), io.SeekCurrent)
 
-		// The transaction of type dynamicFee and accessList have a prefix ahead
-		// the RLP encoding. When that is the case, we need to skip it before
-		// scanning the RLP prefix of the transaction.
+		// The transaction of type accessList, dynamicFee and setCode have a
+		// prefix ahead the RLP encoding. When that is the case, we need to
+		// skip it before scanning the RLP prefix of the transaction.
 		prefix, err := r.ReadByte()
 		if err != nil {
 			return 0, fmt.Errorf("could not read the prefix byte of the tx #%v", i)
 		}
 
+		// The v1 format predates EIP-7702 and the blocks it encodes cannot
+		// hold set-code transactions.
+		if prefix == types.SetCodeTxType && !v.supportsSetCode() {
+			return 0, fmt.Errorf("tx #%v: %w", i, ErrSetCodeTxUnsupported)
+		}
+
 		// When the prefix does not match a transaction type, this indicates a
 		// legacy transaction and the prefix was in fact the RLP prefix.
 		// We unread it, so that [passRlpTx] can access it.
-		if int(prefix) > types.DynamicFeeTxType {
+		if int(prefix) > v.lastTxType() {
 			r.UnreadByte()
 		}
 
@@ -164,7 +229,7 @@ func PassRlpList(r *bytes.Reader) error {
 
 // DecodeBlockFromUncompressed inverts [EncodeBlockForCompression]. It is primarily meant for
 // testing and ensuring the encoding is bijective.
-func DecodeBlockFromUncompressed(r *bytes.Reader) (encode.DecodedBlockData, error) {
+func (v Version) DecodeBlockFromUncompressed(r *bytes.Reader) (encode.DecodedBlockData, error) {
 
 	var (
 		decNumTxs    uint16
@@ -194,7 +259,7 @@ func DecodeBlockFromUncompressed(r *bytes.Reader) (encode.DecodedBlockData, erro
 
 	var err error
 	for i := 0; i < int(decNumTxs); i++ {
-		if decodedBlk.Txs[i], err = DecodeTxFromUncompressed(r, &decodedBlk.Froms[i]); err != nil {
+		if decodedBlk.Txs[i], err = v.DecodeTxFromUncompressed(r, &decodedBlk.Froms[i]); err != nil {
 			return encode.DecodedBlockData{}, fmt.Errorf("could not decode transaction #%v: %w", i, err)
 		}
 	}
@@ -202,10 +267,14 @@ func DecodeBlockFromUncompressed(r *bytes.Reader) (encode.DecodedBlockData, erro
 	return decodedBlk, nil
 }
 
-func DecodeTxFromUncompressed(r *bytes.Reader, from *common.Address) (types.TxData, error) {
+func (v Version) DecodeTxFromUncompressed(r *bytes.Reader, from *common.Address) (types.TxData, error) {
 	if _, err := r.Read(from[:]); err != nil {
 		return nil, fmt.Errorf("could not read from address: %w", err)
 	}
 
-	return ethereum.DecodeTxFromBytes(r)
+	tx, err := ethereum.DecodeTxFromBytes(r)
+	if _, ok := tx.(*types.SetCodeTx); ok && !v.supportsSetCode() {
+		return nil, ErrSetCodeTxUnsupported
+	}
+	return tx, err
 }
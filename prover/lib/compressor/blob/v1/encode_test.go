@@ -15,12 +15,15 @@ import (
 
 	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
 	"github.com/consensys/linea-monorepo/prover/backend/blobdecompression"
+	"github.com/consensys/linea-monorepo/prover/backend/ethereum"
 	"github.com/consensys/linea-monorepo/prover/lib/compressor/blob/dictionary"
 	v1 "github.com/consensys/linea-monorepo/prover/lib/compressor/blob/v1"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/crypto/secp256k1"
+	"github.com/holiman/uint256"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -159,6 +162,98 @@ func TestEncodeDecodeTx(t *testing.T) {
 
 }
 
+func TestSetCodeTxUnsupported(t *testing.T) {
+
+	privKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+
+	chainID := uint256.NewInt(59144)
+	auth, err := types.SignSetCode(privKey, types.SetCodeAuthorization{ChainID: *chainID, Nonce: 2})
+	require.NoError(t, err)
+
+	tx, err := types.SignNewTx(privKey, types.NewPragueSigner(chainID.ToBig()), &types.SetCodeTx{
+		ChainID:   chainID,
+		Nonce:     1,
+		GasTipCap: uint256.NewInt(1),
+		GasFeeCap: uint256.NewInt(2),
+		Gas:       21000,
+		Value:     uint256.NewInt(0),
+		AuthList:  []types.SetCodeAuthorization{auth},
+	})
+	require.NoError(t, err)
+
+	var (
+		from  common.Address
+		block bytes.Buffer
+	)
+
+	require.ErrorIs(t, v1.EncodeTxForCompression(tx, &block), v1.ErrSetCodeTxUnsupported)
+
+	// a block with a single transaction: the number of transactions, the
+	// timestamp and the block hash followed by the transaction
+	block.Write([]byte{0, 1})
+	block.Write(make([]byte, 4+32))
+	block.Write(from[:])
+	block.Write(ethereum.EncodeTxForSigning(tx))
+
+	_, err = v1.ScanBlockByteLen(block.Bytes())
+	require.ErrorIs(t, err, v1.ErrSetCodeTxUnsupported)
+
+	_, err = v1.DecodeBlockFromUncompressed(bytes.NewReader(block.Bytes()))
+	require.ErrorIs(t, err, v1.ErrSetCodeTxUnsupported)
+}
+
+func TestEncodeDecodeSetCodeTx(t *testing.T) {
+
+	privKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+
+	chainID := uint256.NewInt(59144)
+	auth, err := types.SignSetCode(privKey, types.SetCodeAuthorization{ChainID: *chainID, Address: common.Address{12, 24}, Nonce: 2})
+	require.NoError(t, err)
+
+	tx, err := types.SignNewTx(privKey, types.NewPragueSigner(chainID.ToBig()), &types.SetCodeTx{
+		ChainID:   chainID,
+		Nonce:     1,
+		GasTipCap: uint256.NewInt(1),
+		GasFeeCap: uint256.NewInt(2),
+		Gas:       21000,
+		To:        common.Address{12, 24},
+		Value:     uint256.NewInt(0),
+		AuthList:  []types.SetCodeAuthorization{auth},
+	})
+	require.NoError(t, err)
+
+	var encoded bytes.Buffer
+	require.NoError(t, v1.VersionV2.EncodeTxForCompression(tx, &encoded))
+
+	var from common.Address
+	txData, err := v1.VersionV2.DecodeTxFromUncompressed(bytes.NewReader(encoded.Bytes()), &from)
+	require.NoError(t, err)
+
+	tx2 := types.NewTx(txData)
+	assert.Equal(t, uint8(types.SetCodeTxType), tx2.Type())
+	assert.Equal(t, tx.To(), tx2.To(), "field `to` mismatches")
+	assert.Equal(t, tx.SetCodeAuthorizations(), tx2.SetCodeAuthorizations(), "field `authorizationList` mismatches")
+
+	// a block with a single transaction: the number of transactions, the
+	// timestamp and the block hash followed by the transaction
+	var block bytes.Buffer
+	block.Write([]byte{0, 1})
+	block.Write(make([]byte, 4+32))
+	block.Write(from[:])
+	block.Write(ethereum.EncodeTxForSigning(tx))
+
+	n, err := v1.VersionV2.ScanBlockByteLen(block.Bytes())
+	require.NoError(t, err)
+	assert.Equal(t, block.Len(), n)
+
+	blockData, err := v1.VersionV2.DecodeBlockFromUncompressed(bytes.NewReader(block.Bytes()))
+	require.NoError(t, err)
+	require.Len(t, blockData.Txs, 1)
+	assert.Equal(t, tx.SetCodeAuthorizations(), types.NewTx(blockData.Txs[0]).SetCodeAuthorizations())
+}
+
 func TestEncodeDecodeFromResponse(t *testing.T) {
 
 	var (
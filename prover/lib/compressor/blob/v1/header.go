@@ -11,13 +11,12 @@ import (
 	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
 )
 
-const version uint16 = 0xffff
-
 // A Header is a list of batches of blocks of len(blocks)
 // len(BatchSizes) == nb of batches in the blob
 type Header struct {
-	BatchSizes         []int // BatchSizes[i] == byte size of the i-th batch
-	CurrBatchBlocksLen []int // CurrBatchBlocksLen[i] == byte size of the i-th block in the current batch
+	Version            Version // Version of the format, the zero value stands for [VersionV1]
+	BatchSizes         []int   // BatchSizes[i] == byte size of the i-th batch
+	CurrBatchBlocksLen []int   // CurrBatchBlocksLen[i] == byte size of the i-th block in the current batch
 	DictChecksum       [fr.Bytes]byte
 }
 
@@ -25,7 +24,7 @@ func (s *Header) Equals(other *Header) bool {
 	if other == nil {
 		return false
 	}
-	if s.DictChecksum != other.DictChecksum {
+	if s.version() != other.version() || s.DictChecksum != other.DictChecksum {
 		return false
 	}
 
@@ -51,6 +50,14 @@ func (s *Header) Equals(other *Header) bool {
 		large.BatchSizes[len(small.BatchSizes)] == smallSum
 }
 
+// version returns the version of the header, defaulting to [VersionV1].
+func (s *Header) version() Version {
+	if s.Version == 0 {
+		return VersionV1
+	}
+	return s.Version
+}
+
 func (s *Header) NbBatches() int {
 	return len(s.BatchSizes)
 }
@@ -87,7 +94,7 @@ func (s *Header) resetTable() {
 func (s *Header) WriteTo(w io.Writer) (int64, error) {
 	var written int64
 
-	if err := binary.Write(w, binary.BigEndian, version); err != nil {
+	if err := binary.Write(w, binary.BigEndian, s.version()); err != nil {
 		return written, err
 	}
 	written += 2
@@ -140,14 +147,15 @@ func (s *Header) WriteTo(w io.Writer) (int64, error) {
 func (s *Header) ReadFrom(r io.Reader) (int64, error) {
 	var read int64
 
-	var givenVersion uint16
+	var givenVersion Version
 	if err := binary.Read(r, binary.BigEndian, &givenVersion); err != nil {
 		return read, err
 	}
 	read += 2
-	if givenVersion != version {
+	if givenVersion != VersionV1 && givenVersion != VersionV2 {
 		return read, fmt.Errorf("unsupported blob version %d", givenVersion)
 	}
+	s.Version = givenVersion
 
 	// read dictChecksum (32 bytes)
 	if _, err := io.ReadFull(r, s.DictChecksum[:]); err != nil {
@@ -27,13 +27,23 @@ var (
 //export Init
 func Init(dataLimit int, dictPath *C.char) bool {
 	fPath := C.GoString(dictPath)
-	return initGo(dataLimit, fPath)
+	return initGo(dataLimit, fPath, blob_v1.VersionV1)
 }
 
-func initGo(dataLimit int, dictPath string) bool {
+// InitWithVersion behaves as Init, except that the compressor produces blobs
+// of the given version: 0xffff (v1) or 0xfffe (v2, with the EIP-7702 set-code
+// transactions).
+//
+//export InitWithVersion
+func InitWithVersion(dataLimit int, dictPath *C.char, version uint16) bool {
+	fPath := C.GoString(dictPath)
+	return initGo(dataLimit, fPath, blob_v1.Version(version))
+}
+
+func initGo(dataLimit int, dictPath string, version blob_v1.Version) bool {
 	lock.Lock()
 	defer lock.Unlock()
-	compressor, lastError = blob_v1.NewBlobMaker(dataLimit, dictPath)
+	compressor, lastError = blob_v1.NewBlobMakerVersion(dataLimit, dictPath, version)
 
 	return lastError == nil
 }
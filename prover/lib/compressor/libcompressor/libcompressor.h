@@ -82,6 +82,12 @@ extern "C" {
 //
 extern GoUint8 Init(GoInt dataLimit, char* dictPath);
 
+// InitWithVersion behaves as Init, except that the compressor produces blobs
+// of the given version: 0xffff (v1) or 0xfffe (v2, with the EIP-7702 set-code
+// transactions).
+//
+extern GoUint8 InitWithVersion(GoInt dataLimit, char* dictPath, GoUint16 version);
+
 // Reset resets the compressor. Must be called between each Blob.
 //
 extern void Reset();
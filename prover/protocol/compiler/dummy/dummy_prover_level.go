@@ -1,15 +1,20 @@
 package dummy
 
 import (
+	"errors"
 	"fmt"
 	"sync"
 
 	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
-	"github.com/consensys/linea-monorepo/prover/utils"
 	"github.com/consensys/linea-monorepo/prover/utils/parallel"
 	"github.com/sirupsen/logrus"
 )
 
+// ErrFailedQueries is the error [CompileAtProverLvl] panics with when the
+// assignment of the prover does not satisfy the queries. The panic value wraps
+// it, along with the failed queries.
+var ErrFailedQueries = errors.New("dummy.Compile brought errors")
+
 // CompileAtProverLvl instantiate the oracle as the prover. Meaning that the
 // prover is responsible for checking all the queries and the verifier does not
 // see any compiled IOP.
@@ -88,7 +93,7 @@ func CompileAtProverLvl(comp *wizard.CompiledIOP) {
 		})
 
 		if finalErr != nil {
-			utils.Panic("dummy.Compile brought errors: %v", finalErr.Error())
+			panic(fmt.Errorf("%w: %v", ErrFailedQueries, finalErr.Error()))
 		}
 	}
 
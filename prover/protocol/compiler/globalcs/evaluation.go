@@ -4,7 +4,6 @@ import (
 	"fmt"
 	"math/big"
 	"reflect"
-	"sync"
 
 	"github.com/consensys/gnark/frontend"
 	sv "github.com/consensys/linea-monorepo/prover/maths/common/smartvectors"
@@ -124,22 +123,29 @@ func (pa evaluationProver) Run(run *wizard.ProverRuntime) {
 	*/
 
 	var (
-		maxRatio          = utils.Max(pa.Ratios...)
-		mulGenInv         = fft.NewDomain(maxRatio * pa.DomainSize).FrMultiplicativeGenInv
-		rootInv           = fft.GetOmega(maxRatio * pa.DomainSize)
-		quotientEvalPoint field.Element
-		wg                = &sync.WaitGroup{}
+		maxRatio           = utils.Max(pa.Ratios...)
+		mulGenInv          = fft.NewDomain(maxRatio * pa.DomainSize).FrMultiplicativeGenInv
+		rootInv            = fft.GetOmega(maxRatio * pa.DomainSize)
+		quotientEvalPoints = make([]field.Element, len(pa.QuotientEvals))
 	)
 
 	rootInv.Inverse(&rootInv)
-	quotientEvalPoint.Mul(&mulGenInv, &r)
+	for i := range quotientEvalPoints {
+		if i == 0 {
+			quotientEvalPoints[i].Mul(&mulGenInv, &r)
+			continue
+		}
+		quotientEvalPoints[i].Mul(&quotientEvalPoints[i-1], &rootInv)
+	}
 
-	for i := range pa.QuotientEvals {
-		wg.Add(1)
-		go func(i int, evalPoint field.Element) {
+	// One goroutine per quotient share. A panic is raised again in the
+	// calling goroutine.
+	parallel.Execute(len(pa.QuotientEvals), func(start, stop int) {
+		for i := start; i < stop; i++ {
 			var (
-				q  = pa.QuotientEvals[i]
-				ys = make([]field.Element, len(q.Pols))
+				q         = pa.QuotientEvals[i]
+				evalPoint = quotientEvalPoints[i]
+				ys        = make([]field.Element, len(q.Pols))
 			)
 
 			parallel.Execute(len(q.Pols), func(start, stop int) {
@@ -150,12 +156,8 @@ func (pa evaluationProver) Run(run *wizard.ProverRuntime) {
 			})
 
 			run.AssignUnivariate(q.Name(), evalPoint, ys...)
-			wg.Done()
-		}(i, quotientEvalPoint)
-		quotientEvalPoint.Mul(&quotientEvalPoint, &rootInv)
-	}
-
-	wg.Wait()
+		}
+	}, len(pa.QuotientEvals))
 
 	/*
 		as we shifted the evaluation point. No need to do do coset evaluation
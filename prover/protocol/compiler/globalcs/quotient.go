@@ -23,6 +23,7 @@ import (
 	"github.com/consensys/linea-monorepo/prover/symbolic"
 	"github.com/consensys/linea-monorepo/prover/utils"
 	"github.com/consensys/linea-monorepo/prover/utils/collection"
+	"github.com/consensys/linea-monorepo/prover/utils/parallel"
 	ppool "github.com/consensys/linea-monorepo/prover/utils/parallel/pool"
 	"github.com/consensys/linea-monorepo/prover/utils/profiling"
 )
@@ -215,10 +216,7 @@ func (ctx *quotientCtx) Run(run *wizard.ProverRuntime) {
 		logrus.Infof("global constraints : spent %v ms in gc, total time %v ms", time.Since(tGc), totalTimeGc)
 	}
 
-	var wg sync.WaitGroup
-	wg.Add(2)
-
-	go func() {
+	parallel.ExecuteConcurrently(func() {
 		// Compute once the FFT of the natural columns
 
 		ppool.ExecutePoolChunky(len(ctx.AllInvolvedRoots), func(k int) {
@@ -239,11 +237,7 @@ func (ctx *quotientCtx) Run(run *wizard.ProverRuntime) {
 
 			coeffs.Store(name, witness)
 		})
-
-		wg.Done()
-	}()
-
-	go func() {
+	}, func() {
 		ppool.ExecutePoolChunky(len(ctx.AllInvolvedColumns), func(k int) {
 			pol := ctx.AllInvolvedColumns[k]
 
@@ -264,10 +258,8 @@ func (ctx *quotientCtx) Run(run *wizard.ProverRuntime) {
 
 			coeffs.Store(name, witness)
 		})
-		wg.Done()
-	}()
+	})
 
-	wg.Wait()
 	stopTimer()
 
 	// Take the max quotient degree
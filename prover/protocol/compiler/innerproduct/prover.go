@@ -1,12 +1,11 @@
 package innerproduct
 
 import (
-	"sync"
-
 	"github.com/consensys/linea-monorepo/prover/maths/common/smartvectors"
 	"github.com/consensys/linea-monorepo/prover/maths/field"
 	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
 	"github.com/consensys/linea-monorepo/prover/protocol/wizardutils"
+	"github.com/consensys/linea-monorepo/prover/utils/parallel"
 )
 
 // proverTask implements the [wizard.ProverAction] interface and as such
@@ -17,20 +16,13 @@ type proverTask []*contextForSize
 // Run implements the [wizard.ProverAction] interface.
 func (p proverTask) Run(run *wizard.ProverRuntime) {
 
-	wg := &sync.WaitGroup{}
-	wg.Add(len(p))
-
-	for i := range p {
-		// Passing the loop index ensures each go routine is storing the value
-		// of i in a different variable so that there is no race condition over
-		// i.
-		go func(i int) {
+	// One goroutine per task. A panic in a task is raised again in the
+	// calling goroutine.
+	parallel.Execute(len(p), func(start, stop int) {
+		for i := start; i < stop; i++ {
 			p[i].run(run)
-			wg.Done()
-		}(i)
-	}
-
-	wg.Wait()
+		}
+	}, len(p))
 }
 
 // run partially implements the prover runtime associated with the current
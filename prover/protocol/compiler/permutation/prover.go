@@ -1,13 +1,12 @@
 package permutation
 
 import (
-	"sync"
-
 	"github.com/consensys/linea-monorepo/prover/maths/common/smartvectors"
 	"github.com/consensys/linea-monorepo/prover/maths/common/vector"
 	"github.com/consensys/linea-monorepo/prover/maths/field"
 	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
 	"github.com/consensys/linea-monorepo/prover/protocol/wizardutils"
+	"github.com/consensys/linea-monorepo/prover/utils/parallel"
 )
 
 // proverTaskAtRound implements the [wizard.ProverAction] interface and is
@@ -21,20 +20,13 @@ type proverTaskAtRound []*ZCtx
 // (e.g. less than 1000s).
 func (p proverTaskAtRound) Run(run *wizard.ProverRuntime) {
 
-	wg := &sync.WaitGroup{}
-	wg.Add(len(p))
-
-	for i := range p {
-		// the passing of the index `i` is there to ensure that the go-routine
-		// is running over a local copy of `i` which is not incremented every
-		// time the loop goes to the next iteration.
-		go func(i int) {
+	// One goroutine per task. A panic in a task is raised again in the
+	// calling goroutine.
+	parallel.Execute(len(p), func(start, stop int) {
+		for i := start; i < stop; i++ {
 			p[i].run(run)
-			wg.Done()
-		}(i)
-	}
-
-	wg.Wait()
+		}
+	}, len(p))
 }
 
 // run assigns all the Zs in parallel and set the parameters for their
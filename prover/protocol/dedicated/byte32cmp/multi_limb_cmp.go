@@ -2,7 +2,6 @@ package byte32cmp
 
 import (
 	"fmt"
-	"sync"
 
 	"github.com/consensys/linea-monorepo/prover/maths/common/smartvectors"
 	"github.com/consensys/linea-monorepo/prover/maths/field"
@@ -184,12 +183,15 @@ func (mCmp *multiLimbCmp) Run(run *wizard.ProverRuntime) {
 
 	// This will assign the IsEqual column. It can be done in parallel of the
 	// the rest. But it requires the per-limb context to be run prior to this.
-	wg := &sync.WaitGroup{}
-	wg.Add(1)
-	go func() {
-		mCmp.isEqualCtx.Run(run)
-		wg.Done()
-	}()
+	parallel.ExecuteConcurrently(
+		func() { mCmp.isEqualCtx.Run(run) },
+		func() { mCmp.assignComparison(run) },
+	)
+}
+
+// assignComparison assigns the isGreater, isLower and nonNegativeSyndrom
+// columns from the syndrom.
+func (mCmp *multiLimbCmp) assignComparison(run *wizard.ProverRuntime) {
 
 	var (
 		syndrom   = wizardutils.EvalExprColumn(run, mCmp.syndromBoard)
@@ -217,6 +219,4 @@ func (mCmp *multiLimbCmp) Run(run *wizard.ProverRuntime) {
 	run.AssignColumn(mCmp.isGreater.GetColID(), smartvectors.NewRegular(isGreater))
 	run.AssignColumn(mCmp.isLower.GetColID(), smartvectors.NewRegular(isLower))
 	run.AssignColumn(mCmp.nonNegativeSyndrom.GetColID(), smartvectors.NewRegular(nnSyndrom))
-
-	wg.Wait()
 }
@@ -1,7 +1,9 @@
 package plonk
 
 import (
+	"fmt"
 	"math/big"
+	"runtime/debug"
 	"sync"
 
 	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
@@ -25,6 +27,8 @@ type solverSync struct {
 	randChan chan field.Element
 	// The final solution
 	solChan chan *cs.SparseR1CSSolution
+	// The failure of the solver, if it fails
+	errChan chan error
 }
 
 type (
@@ -71,6 +75,7 @@ func (pa initialBBSProverAction) Run(run *wizard.ProverRuntime, wa WitnessAssign
 				comChan:  make(chan []field.Element, 1),
 				randChan: make(chan field.Element, 1),
 				solChan:  make(chan *cs.SparseR1CSSolution, 1),
+				errChan:  make(chan error, 1),
 			}
 
 			// Store the channels in the runtime so that we can
@@ -103,8 +108,15 @@ func (pa initialBBSProverAction) Run(run *wizard.ProverRuntime, wa WitnessAssign
 			// the randomness.
 			go ctx.runGnarkPlonkProver(witness, &solSync)
 
-			// Get the commitment from the chan once ready
-			com := <-solSync.comChan
+			// Get the commitment from the chan once ready. A failure of the
+			// solver is raised here, in a goroutine of [parallel.Execute],
+			// so that it reaches the caller of the prover.
+			var com []field.Element
+			select {
+			case com = <-solSync.comChan:
+			case err := <-solSync.errChan:
+				panic(err)
+			}
 
 			// And assign it in the runtime
 			run.AssignColumn(ctx.Columns.Cp[i].GetColID(), smartvectors.NewRegular(com))
@@ -143,7 +155,12 @@ func (pa lroCommitProverAction) Run(run *wizard.ProverRuntime) {
 
 			// And we block until the solver has completely finished
 			// and returns a solution
-			solution := <-solsync.solChan
+			var solution *cs.SparseR1CSSolution
+			select {
+			case solution = <-solsync.solChan:
+			case err := <-solsync.errChan:
+				panic(err)
+			}
 
 			// And finally, we assign L, R, O from it
 			run.AssignColumn(ctx.Columns.L[i].GetColID(), smartvectors.NewRegular(solution.L))
@@ -158,12 +175,20 @@ func (pa lroCommitProverAction) Run(run *wizard.ProverRuntime) {
 	}
 }
 
-// Run the gnark solver and put the result in solSync.solChan
+// Run the gnark solver and put the result in solSync.solChan. As it runs in
+// its own goroutine, its failures are sent in solSync.errChan instead of
+// panicking, which would crash the process.
 func (ctx compilationCtx) runGnarkPlonkProver(
 	witness witness.Witness,
 	solSync *solverSync,
 ) {
 
+	defer func() {
+		if r := recover(); r != nil {
+			solSync.errChan <- fmt.Errorf("the solver panicked: circ=%v: %v\n%s", ctx.name, r, debug.Stack())
+		}
+	}()
+
 	// This is the hint used to derive the BBS22 randomness
 	commitHintID := solver.GetHintID(fcs.Bsb22CommitmentComputePlaceholder)
 
@@ -179,7 +204,8 @@ func (ctx compilationCtx) runGnarkPlonkProver(
 	)
 
 	if err != nil {
-		utils.Panic("Error in the solver: circ=%v err=%v", ctx.name, err)
+		solSync.errChan <- fmt.Errorf("error in the solver: circ=%v err=%w", ctx.name, err)
+		return
 	}
 
 	// Once the solver has finished, return the solution
@@ -1,7 +1,9 @@
 package wizard
 
 import (
+	"context"
 	"sync"
+	"time"
 
 	"github.com/consensys/linea-monorepo/prover/crypto/fiatshamir"
 	"github.com/consensys/linea-monorepo/prover/maths/common/smartvectors"
@@ -124,6 +126,11 @@ type ProverRuntime struct {
 	// round. The first entry is the initial state, the final entry is the final
 	// state.
 	FiatShamirHistory [][2][]field.Element
+
+	// settings holds the options passed to [ProveWithOptions] and startedAt
+	// is the time at which the prover started.
+	settings  proveSettings
+	startedAt time.Time
 }
 
 // Prove is the top-level function that runs the Prover on the user's side. It
@@ -147,19 +154,46 @@ type ProverRuntime struct {
 // when the specified protocol is complicated and involves multiple multi-rounds
 // sub-protocols that runs independently.
 func Prove(c *CompiledIOP, highLevelprover ProverStep) Proof {
-	runtime := c.createProver()
+	// Without a context, the prover cannot be interrupted so no error can
+	// be returned.
+	proof, _ := ProveWithOptions(c, highLevelprover)
+	return proof
+}
+
+// ProveWithOptions is as [Prove] but it accepts options: [WithContext] to
+// interrupt the prover and [WithProgress] to follow its progress. The only
+// error it returns is the one of the context when it is done.
+func ProveWithOptions(c *CompiledIOP, highLevelprover ProverStep, opts ...ProveOption) (proof Proof, err error) {
+
+	settings := proveSettings{ctx: context.Background()}
+	for _, opt := range opts {
+		opt(&settings)
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			interrupted, ok := r.(proverInterrupted)
+			if !ok {
+				panic(r)
+			}
+			proof, err = Proof{}, interrupted.err
+		}
+	}()
+
+	runtime := c.createProver(settings)
 	/*
 		Run the user provided assignment function. We can't expect it
 		to run all the rounds, because the compilation could have added
 		extra-rounds.
 	*/
-	highLevelprover(&runtime)
+	runtime.runStep(highLevelprover)
 
 	/*
 		Then, run the compiled prover steps
 	*/
 	runtime.runProverSteps()
 	for runtime.currRound+1 < runtime.NumRounds() {
+		runtime.checkInterrupted()
 		runtime.goNextRound()
 		runtime.runProverSteps()
 	}
@@ -183,7 +217,7 @@ func Prove(c *CompiledIOP, highLevelprover ProverStep) Proof {
 	return Proof{
 		Messages:      messages,
 		QueriesParams: runtime.QueriesParams,
-	}
+	}, nil
 }
 
 // NumRounds returns the total number of rounds in the corresponding WizardIOP.
@@ -200,7 +234,7 @@ func (run *ProverRuntime) NumRounds() int {
 
 // createProver is the internal function that is used by the [Prove]
 // function to instantiate and fresh and new [ProverRuntime].
-func (c *CompiledIOP) createProver() ProverRuntime {
+func (c *CompiledIOP) createProver(settings proveSettings) ProverRuntime {
 
 	// Create a new fresh FS state and bootstrap it
 	fs := fiatshamir.NewMiMCFiatShamir()
@@ -217,6 +251,8 @@ func (c *CompiledIOP) createProver() ProverRuntime {
 		currRound:         0,
 		lock:              &sync.Mutex{},
 		FiatShamirHistory: make([][2][]field.Element, c.NumRounds()),
+		settings:          settings,
+		startedAt:         time.Now(),
 	}
 
 	runtime.FiatShamirHistory[0] = [2][]field.Element{
@@ -556,7 +592,7 @@ func (run *ProverRuntime) runProverSteps() {
 	// Run all the assigners
 	subProverSteps := run.Spec.SubProvers.MustGet(run.currRound)
 	for _, step := range subProverSteps {
-		step(run)
+		run.runStep(step)
 	}
 }
 
@@ -0,0 +1,102 @@
+package wizard
+
+import (
+	"context"
+	"reflect"
+	"runtime"
+	"time"
+)
+
+// ProveOption is an option of [ProveWithOptions]
+type ProveOption func(*proveSettings)
+
+// proveSettings collects the options passed to [ProveWithOptions]
+type proveSettings struct {
+	ctx      context.Context
+	progress func(ProverProgress)
+}
+
+// ProverProgress is reported to the callback passed with [WithProgress] every
+// time the prover completes a [ProverStep].
+type ProverProgress struct {
+	// Round is the round of the protocol in which the step ran
+	Round int
+	// NumRounds is the total number of rounds of the protocol
+	NumRounds int
+	// Action is the name of the function implementing the step. For the
+	// steps registered with [CompiledIOP.RegisterProverAction], it is the
+	// name of the Run method of the action.
+	Action string
+	// Duration is the time spent running the step
+	Duration time.Duration
+	// Elapsed is the time elapsed since the prover started
+	Elapsed time.Duration
+}
+
+// WithContext makes the prover stop as soon as ctx is done, in which case
+// [ProveWithOptions] returns ctx.Err(). The context is checked before every
+// [ProverStep] and before moving to the next round: a step that has started
+// always runs to completion.
+func WithContext(ctx context.Context) ProveOption {
+	return func(s *proveSettings) {
+		s.ctx = ctx
+	}
+}
+
+// WithProgress registers a callback that is called every time the prover
+// completes a [ProverStep]. The callback is called synchronously by the
+// prover and should return quickly.
+func WithProgress(progress func(ProverProgress)) ProveOption {
+	return func(s *proveSettings) {
+		s.progress = progress
+	}
+}
+
+// proverInterrupted is the panic value used to unwind the prover when its
+// context is done. It is recovered by [ProveWithOptions] which returns the
+// error. A panic is used because the steps can also be run from within the
+// high-level prover, when it moves to the next round by sampling a coin.
+type proverInterrupted struct {
+	err error
+}
+
+// checkInterrupted panics with a [proverInterrupted] if the context of the
+// prover is done.
+func (run *ProverRuntime) checkInterrupted() {
+	if err := run.settings.ctx.Err(); err != nil {
+		panic(proverInterrupted{err: err})
+	}
+}
+
+// runStep runs a [ProverStep] after checking the context of the prover and
+// reports it to the progress callback, if any.
+func (run *ProverRuntime) runStep(step ProverStep) {
+
+	run.checkInterrupted()
+
+	var (
+		round = run.currRound
+		start = time.Now()
+	)
+
+	step(run)
+
+	if run.settings.progress != nil {
+		run.settings.progress(ProverProgress{
+			Round:     round,
+			NumRounds: run.NumRounds(),
+			Action:    stepName(step),
+			Duration:  time.Since(start),
+			Elapsed:   time.Since(run.startedAt),
+		})
+	}
+}
+
+// stepName returns the name of the function implementing a [ProverStep]
+func stepName(step ProverStep) string {
+	f := runtime.FuncForPC(reflect.ValueOf(step).Pointer())
+	if f == nil {
+		return "unknown"
+	}
+	return f.Name()
+}
@@ -1,6 +1,7 @@
 package wizard_test
 
 import (
+	"context"
 	"testing"
 
 	"github.com/consensys/linea-monorepo/prover/maths/common/smartvectors"
@@ -57,3 +58,78 @@ func TestCompiler(t *testing.T) {
 	err := wizard.Verify(compiled, proof)
 	require.NoError(t, err)
 }
+
+func TestProveWithOptions(t *testing.T) {
+
+	var (
+		P    ifaces.ColID   = "P"
+		U    ifaces.QueryID = "U"
+		COIN coin.Name      = "R"
+	)
+
+	define := func(build *wizard.Builder) {
+		P := build.RegisterCommit(P, SIZE)
+		build.RegisterRandomCoin(COIN, coin.Field)
+		build.UnivariateEval(U, P)
+	}
+
+	var (
+		compiled    = wizard.Compile(define, dummy.Compile)
+		p           = smartvectors.ForTest(1, 2, 3, 3)
+		ctx, cancel = context.WithCancel(context.Background())
+		interrupt   = false
+	)
+
+	defer cancel()
+
+	// The high-level prover only assigns the first round, the second one
+	// is left to a compiled prover step so that the protocol has a round to
+	// go to after the context is cancelled.
+	prover := func(run *wizard.ProverRuntime) {
+		run.AssignColumn(P, p)
+	}
+
+	compiled.SubProvers.AppendToInner(0, func(run *wizard.ProverRuntime) {
+		if interrupt {
+			cancel()
+		}
+	})
+
+	compiled.SubProvers.AppendToInner(1, func(run *wizard.ProverRuntime) {
+		u := run.GetRandomCoinField(COIN)
+		run.AssignUnivariate(U, u, smartvectors.Interpolate(p, u))
+	})
+
+	var progress []wizard.ProverProgress
+	proof, err := wizard.ProveWithOptions(compiled, prover,
+		wizard.WithContext(ctx),
+		wizard.WithProgress(func(p wizard.ProverProgress) {
+			progress = append(progress, p)
+		}),
+	)
+	require.NoError(t, err)
+	require.NoError(t, wizard.Verify(compiled, proof))
+
+	// The high-level prover and the two compiled steps. The dummy compiler
+	// does not add any step.
+	require.Len(t, progress, 3)
+	for i, round := range []int{0, 0, 1} {
+		require.Equal(t, round, progress[i].Round)
+		require.Equal(t, 2, progress[i].NumRounds)
+		require.Contains(t, progress[i].Action, "TestProveWithOptions")
+		require.LessOrEqual(t, progress[i].Duration, progress[i].Elapsed)
+	}
+
+	// The context is cancelled by the step of the first round, so the
+	// prover stops before the second round.
+	interrupt = true
+	progress = nil
+	_, err = wizard.ProveWithOptions(compiled, prover,
+		wizard.WithContext(ctx),
+		wizard.WithProgress(func(p wizard.ProverProgress) {
+			progress = append(progress, p)
+		}),
+	)
+	require.ErrorIs(t, err, context.Canceled)
+	require.Len(t, progress, 2)
+}
@@ -24,15 +24,22 @@ func ExecuteChunky(nbIterations int, work func(start, stop int), numcpus ...int)
 	}
 
 	// The wait group ensures that all the children goroutine have terminated
-	// before we close the
-	wg := sync.WaitGroup{}
-	wg.Add(nbIterations)
+	// before we return. It counts the goroutines rather than the tasks so
+	// that a panicking task does not leave it waiting forever.
+	var (
+		wg      = sync.WaitGroup{}
+		catcher panicCatcher
+	)
+	wg.Add(numcpu)
 
 	taskCounter := NewAtomicCounter(nbIterations)
 
 	// Each goroutine consumes the jobChan to
 	for p := 0; p < numcpu; p++ {
 		go func() {
+			defer wg.Done()
+			defer catcher.catch()
+
 			for {
 				taskID, ok := taskCounter.Next()
 				if !ok {
@@ -40,10 +47,10 @@ func ExecuteChunky(nbIterations int, work func(start, stop int), numcpus ...int)
 				}
 
 				work(taskID, taskID+1)
-				wg.Done()
 			}
 		}()
 	}
 
 	wg.Wait()
+	catcher.repanic()
 }
@@ -25,12 +25,24 @@ func ExecuteFromChan(nbIterations int, work func(wg *sync.WaitGroup, taskCounter
 	wg := &sync.WaitGroup{}
 	wg.Add(nbIterations)
 
+	// The tasks of a panicking goroutine are never marked as done, so the
+	// goroutines are awaited separately and wg only once none panicked.
+	var (
+		workers = sync.WaitGroup{}
+		catcher panicCatcher
+	)
+	workers.Add(numcpu)
+
 	// Each goroutine consumes the jobChan to
 	for p := 0; p < numcpu; p++ {
 		go func() {
+			defer workers.Done()
+			defer catcher.catch()
 			work(wg, tasksCounter)
 		}()
 	}
 
+	workers.Wait()
+	catcher.repanic()
 	wg.Wait()
 }
@@ -0,0 +1,67 @@
+package parallel
+
+import (
+	"fmt"
+	"runtime/debug"
+	"sync"
+)
+
+// panicCatcher records the first panic of the goroutines spawned by a
+// parallel execution so that it can be raised again in the calling
+// goroutine. Otherwise, a panic in a spawned goroutine crashes the process
+// without giving the caller a chance to recover it.
+type panicCatcher struct {
+	once  sync.Once
+	msg   any
+	trace []byte
+}
+
+// catch must be deferred by the spawned goroutines
+func (p *panicCatcher) catch() {
+	if r := recover(); r != nil {
+		p.once.Do(func() {
+			p.msg = r
+			p.trace = debug.Stack()
+		})
+	}
+}
+
+// repanic raises the recorded panic, if any, in the calling goroutine. It
+// must be called once all the spawned goroutines have returned. If the
+// recorded panic is an error, the new panic wraps it so that the caller can
+// still inspect it with [errors.Is].
+func (p *panicCatcher) repanic() {
+
+	if len(p.trace) == 0 {
+		return
+	}
+
+	if err, ok := p.msg.(error); ok {
+		panic(fmt.Errorf("Had a panic: %w\nStack: %v\n", err, string(p.trace)))
+	}
+
+	panic(fmt.Sprintf("Had a panic: %v\nStack: %v\n", p.msg, string(p.trace)))
+}
+
+// ExecuteConcurrently runs each of the functions in its own goroutine and
+// waits for all of them to return. As for [Execute], a panic in any of the
+// functions is raised again in the calling goroutine.
+func ExecuteConcurrently(fns ...func()) {
+
+	var (
+		wg      sync.WaitGroup
+		catcher panicCatcher
+	)
+
+	wg.Add(len(fns))
+	for _, fn := range fns {
+		go func() {
+			defer wg.Done()
+			defer catcher.catch()
+			fn()
+		}()
+	}
+
+	wg.Wait()
+	catcher.repanic()
+}
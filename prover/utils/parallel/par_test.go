@@ -1,6 +1,7 @@
 package parallel_test
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -46,3 +47,31 @@ func TestAtomicCounter(t *testing.T) {
 	_, ok := counter.Next()
 	assert.False(t, ok, "expected ok to be false after reaching the maximum value")
 }
+
+// TestRepanic checks that a panic in a spawned goroutine is raised again in
+// the calling goroutine and that an error panic can still be inspected.
+func TestRepanic(t *testing.T) {
+
+	errWork := errors.New("work failed")
+
+	runs := map[string]func(){
+		"execute":      func() { parallel.Execute(16, func(_, _ int) { panic(errWork) }) },
+		"chunky":       func() { parallel.ExecuteChunky(16, func(_, _ int) { panic(errWork) }) },
+		"concurrently": func() { parallel.ExecuteConcurrently(func() {}, func() { panic(errWork) }) },
+		"thread-aware": func() {
+			parallel.ExecuteThreadAware(16, func(int) {}, func(_, _ int) { panic(errWork) })
+		},
+	}
+
+	for name, run := range runs {
+		t.Run(name, func(t *testing.T) {
+			defer func() {
+				r := recover()
+				err, ok := r.(error)
+				assert.True(t, ok, "expected an error panic, got %v", r)
+				assert.ErrorIs(t, err, errWork)
+			}()
+			run()
+		})
+	}
+}
@@ -2,10 +2,7 @@ package parallel
 
 import (
 	"runtime"
-	"runtime/debug"
 	"sync"
-
-	"github.com/consensys/linea-monorepo/prover/utils"
 )
 
 // Execute process in parallel the work function
@@ -31,11 +28,7 @@ func Execute(nbIterations int, work func(int, int), maxCpus ...int) {
 	extraTasks := nbIterations - (nbTasks * nbIterationsPerCpus)
 	extraTasksOffset := 0
 
-	var (
-		panicTrace []byte
-		panicMsg   any
-		panicOnce  = &sync.Once{}
-	)
+	var catcher panicCatcher
 
 	for i := 0; i < nbTasks; i++ {
 		wg.Add(1)
@@ -51,24 +44,13 @@ func Execute(nbIterations int, work func(int, int), maxCpus ...int) {
 			// In case the subtask panics, we recover so that we can repanic in
 			// the main goroutine. Simplifying the process of tracing back the
 			// error and allowing to test the panics.
-			defer func() {
-				if r := recover(); r != nil {
-					panicOnce.Do(func() {
-						panicMsg = r
-						panicTrace = debug.Stack()
-					})
-				}
-
-				wg.Done()
-			}()
+			defer wg.Done()
+			defer catcher.catch()
 
 			work(_start, _end)
 		}()
 	}
 
 	wg.Wait()
-
-	if len(panicTrace) > 0 {
-		utils.Panic("Had a panic: %v\nStack: %v\n", panicMsg, string(panicTrace))
-	}
+	catcher.repanic()
 }
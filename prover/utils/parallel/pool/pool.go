@@ -1,7 +1,9 @@
 package pool
 
 import (
+	"fmt"
 	"runtime"
+	"runtime/debug"
 	"sync"
 )
 
@@ -15,16 +17,41 @@ func ExecutePoolChunky(nbIterations int, work func(k int)) {
 	wg := sync.WaitGroup{}
 	wg.Add(nbIterations)
 
+	// The tasks run in goroutines of the scheduler, their first panic is
+	// raised again in the calling goroutine as in [parallel.Execute].
+	var (
+		panicMsg   any
+		panicTrace []byte
+		panicOnce  = &sync.Once{}
+	)
+
 	for i := 0; i < nbIterations; i++ {
 		k := i
 		queue <- func() {
+			defer func() {
+				if r := recover(); r != nil {
+					panicOnce.Do(func() {
+						panicMsg = r
+						panicTrace = debug.Stack()
+					})
+				}
+
+				wg.Done()
+				available <- struct{}{}
+			}()
+
 			work(k)
-			wg.Done()
-			available <- struct{}{}
 		}
 	}
 
 	wg.Wait()
+
+	if len(panicTrace) > 0 {
+		if err, ok := panicMsg.(error); ok {
+			panic(fmt.Errorf("Had a panic: %w\nStack: %v\n", err, string(panicTrace)))
+		}
+		panic(fmt.Sprintf("Had a panic: %v\nStack: %v\n", panicMsg, string(panicTrace)))
+	}
 }
 
 func initialize() {
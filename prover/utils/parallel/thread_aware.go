@@ -16,9 +16,13 @@ func ExecuteThreadAware(nbIterations int, init ThreadInit, worker Worker, numcpu
 	}
 
 	// The wait group ensures that all the children goroutine have terminated
-	// before we close the
-	wg := sync.WaitGroup{}
-	wg.Add(nbIterations)
+	// before we return. It counts the goroutines rather than the tasks so
+	// that a panicking task does not leave it waiting forever.
+	var (
+		wg      = sync.WaitGroup{}
+		catcher panicCatcher
+	)
+	wg.Add(numcpu)
 
 	taskCounter := NewAtomicCounter(nbIterations)
 
@@ -26,6 +30,9 @@ func ExecuteThreadAware(nbIterations int, init ThreadInit, worker Worker, numcpu
 	for p := 0; p < numcpu; p++ {
 		threadID := p
 		go func() {
+			defer wg.Done()
+			defer catcher.catch()
+
 			init(threadID)
 			for {
 				taskID, ok := taskCounter.Next()
@@ -34,10 +41,10 @@ func ExecuteThreadAware(nbIterations int, init ThreadInit, worker Worker, numcpu
 				}
 
 				worker(taskID, threadID)
-				wg.Done()
 			}
 		}()
 	}
 
 	wg.Wait()
+	catcher.repanic()
 }
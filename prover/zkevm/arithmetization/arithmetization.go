@@ -1,14 +1,20 @@
 package arithmetization
 
 import (
+	"errors"
 	"fmt"
+	"os"
 
 	"github.com/consensys/go-corset/pkg/air"
-	"github.com/consensys/linea-monorepo/prover/backend/files"
+	"github.com/consensys/go-corset/pkg/trace"
 	"github.com/consensys/linea-monorepo/prover/config"
 	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
 )
 
+// ErrInvalidTraces is returned by [Arithmetization.ReadTraces] when the trace
+// file cannot be opened or parsed.
+var ErrInvalidTraces = errors.New("invalid trace file")
+
 // Settings specifies the parameters for the arithmetization part of the zkEVM.
 type Settings struct {
 	Limits *config.TracesLimits
@@ -40,16 +46,31 @@ func NewArithmetization(builder *wizard.Builder, settings Settings) *Arithmetiza
 	}
 }
 
-// Assign the arithmetization related columns. Namely, it will open the file
-// specified in the witness object, call corset and assign the prover runtime
-// columns.
-func (a *Arithmetization) Assign(run *wizard.ProverRuntime, traceFile string) {
+// ReadTraces opens the `.lt` trace file, expands it and checks it against the
+// limits of the arithmetization. The returned error wraps a
+// [TraceOverflowError] for every module that overflows its limit, or
+// [ErrInvalidTraces] if the file cannot be read.
+func (a *Arithmetization) ReadTraces(traceFile string) (trace.Trace, error) {
+
+	traceF, err := os.Open(traceFile)
+	if err != nil {
+		return nil, fmt.Errorf("%w: could not open the trace fpath=%q: %w", ErrInvalidTraces, traceFile, err)
+	}
 
-	traceF := files.MustRead(traceFile)
-	trace, errT := ReadLtTraces(traceF, a.Schema)
+	expTraces, errT := ReadLtTraces(traceF, a.Schema)
 	if errT != nil {
-		fmt.Printf("error loading the trace fpath=%q err=%v", traceFile, errT.Error())
+		return nil, fmt.Errorf("%w: error loading the trace fpath=%q: %w", ErrInvalidTraces, traceFile, errT)
 	}
 
-	AssignFromLtTraces(run, a.Schema, trace, a.Settings.Limits)
+	if err := CheckTraceLimits(expTraces, a.Settings.Limits); err != nil {
+		return nil, err
+	}
+
+	return expTraces, nil
+}
+
+// Assign the arithmetization related columns from the expanded traces
+// returned by [Arithmetization.ReadTraces].
+func (a *Arithmetization) Assign(run *wizard.ProverRuntime, expTraces trace.Trace) {
+	AssignFromLtTraces(run, a.Schema, expTraces)
 }
@@ -2,8 +2,6 @@ package arithmetization
 
 import (
 	"errors"
-	"fmt"
-	"os"
 
 	"github.com/consensys/go-corset/pkg/air"
 	"github.com/consensys/go-corset/pkg/trace"
@@ -12,20 +10,19 @@ import (
 	"github.com/consensys/linea-monorepo/prover/maths/field"
 	"github.com/consensys/linea-monorepo/prover/protocol/ifaces"
 	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
+	"github.com/consensys/linea-monorepo/prover/utils"
 	"github.com/sirupsen/logrus"
 )
 
-// ReadExpandedTraces parses the provided trace file, expands it and returns the
-// corset object holding the expanded traces.
-func AssignFromLtTraces(run *wizard.ProverRuntime, schema *air.Schema, expTraces trace.Trace, limits *config.TracesLimits) {
+// CheckTraceLimits logs the utilization of every module of the expanded
+// traces and returns a [TraceOverflowError] for every module whose height
+// exceeds its limit. The errors are joined using [errors.Join].
+func CheckTraceLimits(expTraces trace.Trace, limits *config.TracesLimits) error {
 
-	// This loops checks the module assignment to see if we have created a 77
-	// error.
 	var (
 		modules      = expTraces.Modules().Collect()
 		moduleLimits = mapModuleLimits(limits)
-		err77        error
-		numCols      = expTraces.Width()
+		errOverflow  error
 	)
 
 	for _, module := range modules {
@@ -40,36 +37,87 @@ func AssignFromLtTraces(run *wizard.ProverRuntime, schema *air.Schema, expTraces
 
 		if uint(limit) < height {
 			level = logrus.ErrorLevel
-			err77 = errors.Join(err77, fmt.Errorf("limit overflow: module '%s' overflows its limit height=%v limit=%v ratio=%v", name, height, limit, ratio))
+			errOverflow = errors.Join(errOverflow, &TraceOverflowError{
+				Module: name,
+				Height: height,
+				Limit:  uint(limit),
+				Ratio:  ratio,
+			})
 		}
 
 		logrus.StandardLogger().Logf(level, "module utilization module=%v height=%v limit=%v ratio=%v", name, height, limit, ratio)
 	}
 
-	if err77 != nil {
-		logrus.Errorf("Error code 77 %v", err77)
-		os.Exit(TraceOverflowExitCode)
-	}
+	return errOverflow
+}
+
+// AssignFromLtTraces assigns the columns of the arithmetization from the
+// expanded traces. The caller is expected to have checked the traces against
+// the limits using [CheckTraceLimits] beforehand.
+func AssignFromLtTraces(run *wizard.ProverRuntime, schema *air.Schema, expTraces trace.Trace) {
+
+	numCols := expTraces.Width()
 
 	for id := uint(0); id < numCols; id++ {
 
 		var (
-			col     = expTraces.Column(id)
-			name    = ifaces.ColID(wizardName(getModuleName(schema, col), col.Name()))
-			wCol    = run.Spec.Columns.GetHandle(name)
-			padding = col.Padding()
-			data    = col.Data()
-			plain   = make([]field.Element, data.Len())
+			col  = expTraces.Column(id)
+			name = ifaces.ColID(wizardName(getModuleName(schema, col), col.Name()))
 		)
 
 		if !run.Spec.Columns.Exists(name) {
 			continue
 		}
 
-		for i := range plain {
-			plain[i] = data.Get(uint(i))
+		wCol := run.Spec.Columns.GetHandle(name)
+		run.AssignColumn(name, columnAssignment(col, wCol.Size()))
+	}
+}
+
+// columnAssignment decodes a corset column into a smart-vector of the given
+// size, which is the size of the assigned column. The column is left-padded
+// with its padding value. Columns that only contain the padding value are
+// returned as a constant smart-vector and do not allocate at all.
+//
+// The vectors are not allocated from a memory pool: the runtime holds them
+// until the end of the proof and never releases them, so a pool would not
+// reuse anything.
+func columnAssignment(col trace.Column, size int) smartvectors.SmartVector {
+
+	var (
+		padding = col.Padding()
+		data    = col.Data()
+		length  = int(data.Len())
+		start   = 0
+	)
+
+	for start < length {
+		if x := data.Get(uint(start)); x != padding {
+			break
 		}
+		start++
+	}
+
+	if start == length {
+		return smartvectors.NewConstant(padding, size)
+	}
 
-		run.AssignColumn(ifaces.ColID(name), smartvectors.LeftPadded(plain, padding, wCol.Size()))
+	if length > size {
+		utils.Panic("column %v has %v rows but the assigned column has size %v", col.Name(), length, size)
 	}
+
+	var (
+		res    = make([]field.Element, size)
+		offset = size - length
+	)
+
+	for i := 0; i < offset+start; i++ {
+		res[i] = padding
+	}
+
+	for i := start; i < length; i++ {
+		res[offset+i] = data.Get(uint(i))
+	}
+
+	return smartvectors.NewRegular(res)
 }
@@ -0,0 +1,244 @@
+package arithmetization
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/consensys/go-corset/pkg/air"
+	"github.com/consensys/go-corset/pkg/schema"
+	"github.com/consensys/go-corset/pkg/trace"
+	"github.com/consensys/go-corset/pkg/trace/lt"
+	"github.com/consensys/go-corset/pkg/util"
+	"github.com/consensys/linea-monorepo/prover/config"
+	"github.com/consensys/linea-monorepo/prover/maths/common/smartvectors"
+	"github.com/consensys/linea-monorepo/prover/maths/field"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testFrArray returns a corset array holding the provided values. The width
+// is that of a full field element, as this is the width that the lt encoder
+// assumes for the non-compact arrays.
+func testFrArray(values ...uint64) util.FrArray {
+	arr := util.NewFrArray(uint(len(values)), 256)
+	for i, v := range values {
+		arr.Set(uint(i), field.NewElement(v))
+	}
+	return arr
+}
+
+func TestColumnAssignment(t *testing.T) {
+
+	testCases := []struct {
+		Name     string
+		Values   []uint64
+		Padding  uint64
+		Expected []uint64
+		Constant bool
+	}{
+		{
+			Name:     "only-padding",
+			Values:   []uint64{3, 3, 3},
+			Padding:  3,
+			Expected: []uint64{3, 3, 3, 3, 3, 3, 3, 3},
+			Constant: true,
+		},
+		{
+			Name:     "empty",
+			Padding:  0,
+			Expected: []uint64{0, 0, 0, 0, 0, 0, 0, 0},
+			Constant: true,
+		},
+		{
+			Name:     "leading-padding",
+			Values:   []uint64{0, 0, 1, 0, 2},
+			Padding:  0,
+			Expected: []uint64{0, 0, 0, 0, 0, 1, 0, 2},
+		},
+		{
+			Name:     "no-leading-padding",
+			Values:   []uint64{1, 2, 3},
+			Padding:  7,
+			Expected: []uint64{7, 7, 7, 7, 7, 1, 2, 3},
+		},
+		{
+			Name:     "full",
+			Values:   []uint64{0, 1, 2, 3, 4, 5, 6, 7},
+			Padding:  0,
+			Expected: []uint64{0, 1, 2, 3, 4, 5, 6, 7},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+
+			col := trace.NewArrayColumn(
+				trace.NewContext(0, 1),
+				"A",
+				testFrArray(tc.Values...),
+				field.NewElement(tc.Padding),
+			)
+
+			res := columnAssignment(&col, len(tc.Expected))
+			require.Equal(t, len(tc.Expected), res.Len())
+
+			for i, e := range tc.Expected {
+				assert.Equalf(t, field.NewElement(e), res.Get(i), "position %v", i)
+			}
+
+			_, isConstant := res.(*smartvectors.Constant)
+			assert.Equal(t, tc.Constant, isConstant)
+		})
+	}
+}
+
+func TestReadRawLtTraces(t *testing.T) {
+
+	columns := []trace.RawColumn{
+		{Module: "", Name: "A", Data: testFrArray(0, 1, 2, 3)},
+		{Module: "mod", Name: "B", Data: testFrArray(1<<40, 5)},
+		{Module: "mod", Name: "C", Data: testFrArray()},
+	}
+
+	encoded, err := lt.ToBytes(columns)
+	require.NoError(t, err)
+
+	fname := filepath.Join(t.TempDir(), "trace.lt")
+	require.NoError(t, os.WriteFile(fname, encoded, 0600))
+
+	f, err := os.Open(fname)
+	require.NoError(t, err)
+	defer f.Close()
+
+	// The file is memory-mapped and the reader goes through io.ReadAll: both
+	// must agree.
+	for _, r := range []io.Reader{f, bytes.NewReader(encoded)} {
+
+		data, release, err := readLtFile(r)
+		require.NoError(t, err)
+		defer release()
+
+		decoded, err := readRawLtTraces(data)
+		require.NoError(t, err)
+
+		require.Len(t, decoded, len(columns))
+		for i := range columns {
+			assert.Equal(t, columns[i].Module, decoded[i].Module)
+			assert.Equal(t, columns[i].Name, decoded[i].Name)
+			require.Equal(t, columns[i].Data.Len(), decoded[i].Data.Len())
+			for k := uint(0); k < columns[i].Data.Len(); k++ {
+				assert.Equal(t, columns[i].Data.Get(k), decoded[i].Data.Get(k))
+			}
+
+			// The expansion decodes the columns by padding them
+			padded := decoded[i].Data.PadFront(2, field.NewElement(9))
+			require.Equal(t, columns[i].Data.Len()+2, padded.Len())
+			assert.Equal(t, field.NewElement(9), padded.Get(1))
+			for k := uint(0); k < columns[i].Data.Len(); k++ {
+				assert.Equal(t, columns[i].Data.Get(k), padded.Get(k+2))
+			}
+		}
+	}
+}
+
+func TestReadRawLtTracesErrors(t *testing.T) {
+
+	encoded, err := lt.ToBytes([]trace.RawColumn{
+		{Module: "mod", Name: "A", Data: testFrArray(1, 2, 3)},
+	})
+	require.NoError(t, err)
+
+	_, err = readRawLtTraces(encoded[:len(encoded)-1])
+	assert.Error(t, err, "truncated data")
+
+	_, err = readRawLtTraces(encoded[:6])
+	assert.Error(t, err, "truncated header")
+
+	_, err = readRawLtTraces(nil)
+	assert.Error(t, err, "empty file")
+}
+
+func TestReadLtTraces(t *testing.T) {
+
+	sch := air.EmptySchema[air.Expr]()
+	mod := sch.AddModule("mod")
+	sch.AddColumn(trace.NewContext(mod, 1), "A", schema.NewUintType(8))
+	sch.AddColumn(trace.NewContext(mod, 1), "B", schema.NewUintType(64))
+
+	encoded, err := lt.ToBytes([]trace.RawColumn{
+		{Module: "mod", Name: "A", Data: testFrArray(1, 2, 3)},
+		{Module: "mod", Name: "B", Data: testFrArray(1<<40, 5, 6)},
+	})
+	require.NoError(t, err)
+
+	fname := filepath.Join(t.TempDir(), "trace.lt")
+	require.NoError(t, os.WriteFile(fname, encoded, 0600))
+
+	f, err := os.Open(fname)
+	require.NoError(t, err)
+
+	// The file is unmapped when ReadLtTraces returns: the expanded traces
+	// must not refer to it.
+	expTraces, err := ReadLtTraces(f, sch)
+	require.NoError(t, err)
+
+	expected := map[string][]uint64{
+		"A": {0, 1, 2, 3},
+		"B": {0, 1 << 40, 5, 6},
+	}
+
+	require.Equal(t, uint(2), expTraces.Width())
+	for id := uint(0); id < expTraces.Width(); id++ {
+		col := expTraces.Column(id)
+		require.Equal(t, uint(len(expected[col.Name()])), col.Data().Len())
+		for k, v := range expected[col.Name()] {
+			assert.Equalf(t, field.NewElement(v), col.Get(k), "column %v, row %v", col.Name(), k)
+		}
+	}
+}
+
+func TestReadLtTracesExpansionError(t *testing.T) {
+
+	sch := air.EmptySchema[air.Expr]()
+	mod := sch.AddModule("mod")
+	sch.AddColumn(trace.NewContext(mod, 1), "A", schema.NewUintType(8))
+	sch.AddColumn(trace.NewContext(mod, 1), "B", schema.NewUintType(8))
+
+	// The column B is missing while the module has other columns, which the
+	// expansion cannot recover from.
+	encoded, err := lt.ToBytes([]trace.RawColumn{
+		{Module: "mod", Name: "A", Data: testFrArray(1, 2, 3)},
+	})
+	require.NoError(t, err)
+
+	expTraces, err := ReadLtTraces(io.NopCloser(bytes.NewReader(encoded)), sch)
+	assert.Nil(t, expTraces)
+	assert.ErrorContains(t, err, "missing input column 'mod.B'")
+}
+
+func TestReadTracesInvalidFile(t *testing.T) {
+
+	sch := air.EmptySchema[air.Expr]()
+	sch.AddColumn(trace.NewContext(sch.AddModule("mod"), 1), "A", schema.NewUintType(8))
+
+	var (
+		a       = &Arithmetization{Schema: sch, Settings: &Settings{Limits: &config.TracesLimits{}}}
+		invalid = filepath.Join(t.TempDir(), "invalid.lt")
+	)
+
+	// A truncated trace file
+	encoded, err := lt.ToBytes([]trace.RawColumn{
+		{Module: "mod", Name: "A", Data: testFrArray(1, 2, 3)},
+	})
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(invalid, encoded[:len(encoded)/2], 0600))
+
+	for _, file := range []string{filepath.Join(t.TempDir(), "missing.lt"), invalid} {
+		expTraces, err := a.ReadTraces(file)
+		assert.Nil(t, expTraces)
+		assert.ErrorIs(t, err, ErrInvalidTraces)
+	}
+}
@@ -255,6 +255,27 @@ func wizardName(moduleName, objectName string) string {
 	return moduleName + "." + objectName
 }
 
+// ColumnsByID returns the columns of the expanded traces indexed by the name
+// of the corresponding column on the wizard side.
+func ColumnsByID(schema *air.Schema, expTraces trace.Trace) map[ifaces.ColID]trace.Column {
+
+	res := make(map[ifaces.ColID]trace.Column, expTraces.Width())
+
+	for id := uint(0); id < expTraces.Width(); id++ {
+		col := expTraces.Column(id)
+		res[ifaces.ColID(wizardName(getModuleName(schema, col), col.Name()))] = col
+	}
+
+	return res
+}
+
+// ModuleLimit returns the limit on the height of a module of the
+// arithmetization. ok is false if the limits do not define the module.
+func ModuleLimit(limits *config.TracesLimits, module string) (limit int, ok bool) {
+	limit, ok = mapModuleLimits(limits)[module]
+	return limit, ok
+}
+
 // compColumnByCorsetID returns an [ifaces.Column] that has already been
 // registered inside of the [wizard.CompiledIOP] from its index in the corset
 // [air.Schema].
@@ -1,19 +1,26 @@
 package arithmetization
 
 import (
+	"bytes"
 	_ "embed"
+	"encoding/binary"
 	"errors"
 	"fmt"
 	"io"
+	"os"
+	"strings"
 
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
 	"github.com/consensys/go-corset/pkg/air"
 	"github.com/consensys/go-corset/pkg/binfile"
 	"github.com/consensys/go-corset/pkg/schema"
 	"github.com/consensys/go-corset/pkg/trace"
-	"github.com/consensys/go-corset/pkg/trace/lt"
+	"github.com/consensys/go-corset/pkg/util"
 	"github.com/sirupsen/logrus"
 )
 
+// TraceOverflowExitCode is the exit code of the prover when the traces
+// overflow the limits of the arithmetization. See [TraceOverflowError].
 const TraceOverflowExitCode = 77
 
 // Embed the whole constraint system at compile time, so no
@@ -36,16 +43,23 @@ func ReadZkevmBin() (*air.Schema, error) {
 	return hirSchema.LowerToMir().LowerToAir(), nil
 }
 
+// ReadLtTraces parses the raw traces of the `.lt` file f and expands them
+// following the schema. f is closed at the end of the function call.
+//
+// The raw columns are not decoded up front: they refer to the bytes of the
+// file and are decoded one by one when the expansion pads them, so that only
+// the expanded columns are ever held in memory.
 func ReadLtTraces(f io.ReadCloser, sch *air.Schema) (trace.Trace, error) {
 
 	defer f.Close()
 
-	readBytes, err := io.ReadAll(f)
+	data, release, err := readLtFile(f)
 	if err != nil {
-		return nil, fmt.Errorf("failed reading the file: %w", err)
+		return nil, fmt.Errorf("failed reading the raw trace '.lt' file: %w", err)
 	}
+	defer release()
 
-	rawTraces, err := lt.FromBytes(readBytes)
+	rawTraces, err := readRawLtTraces(data)
 	if err != nil {
 		return nil, fmt.Errorf("failed parsing the bytes of the raw trace '.lt' file: %w", err)
 	}
@@ -55,5 +69,212 @@ func ReadLtTraces(f io.ReadCloser, sch *air.Schema) (trace.Trace, error) {
 		logrus.Warnf("corset expansion gave the following errors: %v", errors.Join(errs...).Error())
 	}
 
+	if expTraces == nil {
+		return nil, fmt.Errorf("the corset expansion of the traces failed: %w", errors.Join(errs...))
+	}
+
+	// The expansion pads every input column, which decodes it into a new
+	// array. This is what allows releasing the file: check it still holds.
+	for id := uint(0); id < expTraces.Width(); id++ {
+		if _, ok := expTraces.Column(id).Data().(*ltColumnData); ok {
+			return nil, fmt.Errorf("column %v was not decoded by the expansion", expTraces.Column(id).Name())
+		}
+	}
+
 	return expTraces, nil
 }
+
+// readLtFile returns the content of f. When f is a regular file, it is
+// memory-mapped instead of being read in full: the raw bytes of the traces
+// are then never copied on the heap, where they would live alongside the
+// decoded columns. The returned function must be called once the content is
+// no longer used.
+func readLtFile(f io.Reader) (data []byte, release func(), err error) {
+
+	if osFile, ok := f.(*os.File); ok {
+		data, unmap, err := mmapFile(osFile)
+		if err == nil {
+			return data, func() {
+				if err := unmap(); err != nil {
+					logrus.Warnf("could not unmap the trace file: %v", err)
+				}
+			}, nil
+		}
+		logrus.Debugf("could not memory-map the trace file, falling back to reading it: %v", err)
+	}
+
+	data, err = io.ReadAll(f)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed reading the file: %w", err)
+	}
+
+	return data, func() {}, nil
+}
+
+// readRawLtTraces parses the header of a `.lt` file and returns its columns.
+// The data of the columns is an [ltColumnData] referring to data, which must
+// therefore outlive them.
+//
+// The format is that of [lt.FromBytes]: the number of columns as a uint32,
+// then for each column its qualified name prefixed by its uint16 length, the
+// number of bytes per element as a uint8 and the number of elements as a
+// uint32. The elements of the columns follow in the same order, in big-endian.
+func readRawLtTraces(data []byte) ([]trace.RawColumn, error) {
+
+	buf := bytes.NewReader(data)
+
+	var ncols uint32
+	if err := binary.Read(buf, binary.BigEndian, &ncols); err != nil {
+		return nil, fmt.Errorf("could not read the number of columns: %w", err)
+	}
+
+	type columnHeader struct {
+		name          string
+		width, length uint32
+	}
+
+	headers := make([]columnHeader, ncols)
+
+	for i := range headers {
+
+		var (
+			nameLen uint16
+			width   uint8
+			length  uint32
+		)
+
+		if err := binary.Read(buf, binary.BigEndian, &nameLen); err != nil {
+			return nil, fmt.Errorf("could not read the header of column %v: %w", i, err)
+		}
+
+		name := make([]byte, nameLen)
+		if _, err := io.ReadFull(buf, name); err != nil {
+			return nil, fmt.Errorf("could not read the name of column %v: %w", i, err)
+		}
+
+		if err := binary.Read(buf, binary.BigEndian, &width); err != nil {
+			return nil, fmt.Errorf("could not read the header of column %q: %w", name, err)
+		}
+
+		if err := binary.Read(buf, binary.BigEndian, &length); err != nil {
+			return nil, fmt.Errorf("could not read the header of column %q: %w", name, err)
+		}
+
+		if width == 0 || width > fr.Bytes {
+			return nil, fmt.Errorf("column %q has an invalid width of %v bytes", name, width)
+		}
+
+		headers[i] = columnHeader{name: string(name), width: uint32(width), length: length}
+	}
+
+	var (
+		columns = make([]trace.RawColumn, ncols)
+		offset  = uint64(len(data) - buf.Len())
+	)
+
+	for i, header := range headers {
+
+		nbBytes := uint64(header.width) * uint64(header.length)
+		if offset+nbBytes > uint64(len(data)) {
+			return nil, fmt.Errorf("column %q is truncated", header.name)
+		}
+
+		// Columns without a module belong to the prelude
+		module, name := "", header.name
+		if k := strings.Index(header.name, "."); k >= 0 {
+			module, name = header.name[:k], header.name[k+1:]
+		}
+
+		columns[i] = trace.RawColumn{
+			Module: module,
+			Name:   name,
+			Data: &ltColumnData{
+				bytes: data[offset : offset+nbBytes],
+				width: uint(header.width),
+			},
+		}
+
+		offset += nbBytes
+	}
+
+	return columns, nil
+}
+
+// ltColumnData is the data of a column of a `.lt` file. It implements
+// [util.FrArray] by decoding the elements from the bytes of the file when
+// they are accessed and is read-only. [ltColumnData.PadFront], which the
+// expansion calls on every input column, returns a decoded copy: the same
+// array as [lt.FromBytes] would have returned, so the expanded traces do not
+// refer to the file.
+type ltColumnData struct {
+	// bytes stores the big-endian encoding of the elements
+	bytes []byte
+	// width is the number of bytes per element
+	width uint
+}
+
+// Len returns the number of elements of the column
+func (c *ltColumnData) Len() uint {
+	return uint(len(c.bytes)) / c.width
+}
+
+// BitWidth returns the number of bits used to encode an element
+func (c *ltColumnData) BitWidth() uint {
+	return c.width * 8
+}
+
+// Get decodes the element at position i
+func (c *ltColumnData) Get(i uint) fr.Element {
+
+	b := c.bytes[i*c.width : (i+1)*c.width]
+
+	switch c.width {
+	case 1:
+		return fr.NewElement(uint64(b[0]))
+	case 2:
+		return fr.NewElement(uint64(binary.BigEndian.Uint16(b)))
+	case 4:
+		return fr.NewElement(uint64(binary.BigEndian.Uint32(b)))
+	case 8:
+		return fr.NewElement(binary.BigEndian.Uint64(b))
+	}
+
+	var res fr.Element
+	res.SetBytes(b)
+	return res
+}
+
+// Set panics as the column is read-only
+func (c *ltColumnData) Set(uint, fr.Element) {
+	panic("the columns of a .lt file are read-only")
+}
+
+// Clone returns a decoded copy of the column
+func (c *ltColumnData) Clone() util.Array[fr.Element] {
+	return c.PadFront(0, fr.Element{})
+}
+
+// PadFront returns a decoded copy of the column, preceded by n copies of
+// padding.
+func (c *ltColumnData) PadFront(n uint, padding fr.Element) util.Array[fr.Element] {
+
+	var (
+		length = c.Len()
+		res    = util.NewFrArray(n+length, c.BitWidth())
+	)
+
+	for i := uint(0); i < n; i++ {
+		res.Set(i, padding)
+	}
+
+	for i := uint(0); i < length; i++ {
+		res.Set(n+i, c.Get(i))
+	}
+
+	return res
+}
+
+// Write writes the decoded column to w
+func (c *ltColumnData) Write(w io.Writer) error {
+	return c.Clone().Write(w)
+}
@@ -0,0 +1,14 @@
+//go:build !unix
+
+package arithmetization
+
+import (
+	"errors"
+	"os"
+)
+
+// mmapFile is not supported on this platform and the callers fall back to
+// reading the file.
+func mmapFile(f *os.File) (data []byte, unmap func() error, err error) {
+	return nil, nil, errors.New("memory-mapping is not supported on this platform")
+}
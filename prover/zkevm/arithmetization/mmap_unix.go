@@ -0,0 +1,36 @@
+//go:build unix
+
+package arithmetization
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+)
+
+// mmapFile maps the content of f in memory in read-only mode. The returned
+// function unmaps it and must be called once the data is no longer used.
+func mmapFile(f *os.File) (data []byte, unmap func() error, err error) {
+
+	stat, err := f.Stat()
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not stat the file: %w", err)
+	}
+
+	if !stat.Mode().IsRegular() {
+		return nil, nil, fmt.Errorf("not a regular file: %v", stat.Mode())
+	}
+
+	size := stat.Size()
+	if size == 0 {
+		// mmap does not accept empty mappings
+		return []byte{}, func() error { return nil }, nil
+	}
+
+	data, err = syscall.Mmap(int(f.Fd()), 0, int(size), syscall.PROT_READ, syscall.MAP_SHARED)
+	if err != nil {
+		return nil, nil, fmt.Errorf("mmap failed: %w", err)
+	}
+
+	return data, func() error { return syscall.Munmap(data) }, nil
+}
@@ -0,0 +1,65 @@
+package arithmetization
+
+import (
+	"errors"
+	"fmt"
+)
+
+// TraceOverflowError is returned when a module of the arithmetization has more
+// rows than the limit configured for it. When several modules overflow, the
+// errors are joined using [errors.Join]; [TraceOverflows] recovers all of
+// them.
+type TraceOverflowError struct {
+	Module string  `json:"module"`
+	Height uint    `json:"height"`
+	Limit  uint    `json:"limit"`
+	Ratio  float64 `json:"ratio"`
+}
+
+func (e *TraceOverflowError) Error() string {
+	return fmt.Sprintf("limit overflow: module '%s' overflows its limit height=%v limit=%v ratio=%v", e.Module, e.Height, e.Limit, e.Ratio)
+}
+
+// TraceOverflows returns all the [TraceOverflowError] wrapped in err. The
+// returned list is empty if err does not originate from a limit overflow.
+func TraceOverflows(err error) []*TraceOverflowError {
+
+	if err == nil {
+		return nil
+	}
+
+	if e, ok := err.(*TraceOverflowError); ok {
+		return []*TraceOverflowError{e}
+	}
+
+	switch wrapped := err.(type) {
+	case interface{ Unwrap() []error }:
+		var res []*TraceOverflowError
+		for _, e := range wrapped.Unwrap() {
+			res = append(res, TraceOverflows(e)...)
+		}
+		return res
+	case interface{ Unwrap() error }:
+		return TraceOverflows(wrapped.Unwrap())
+	}
+
+	return nil
+}
+
+// IsTraceOverflow returns true if err originates from a limit overflow.
+func IsTraceOverflow(err error) bool {
+	var e *TraceOverflowError
+	return errors.As(err, &e)
+}
+
+// PanicOverflow panics with a [TraceOverflowError] for a module of the zkEVM
+// whose count exceeds its limit. It is meant for the modules that can only
+// detect the overflow while assigning their columns. The panic is recovered
+// by the prover of the zkEVM and returned as an error.
+func PanicOverflow(module string, count, limit int) {
+	e := &TraceOverflowError{Module: module, Height: uint(count), Limit: uint(limit)}
+	if limit > 0 {
+		e.Ratio = float64(count) / float64(limit)
+	}
+	panic(e)
+}
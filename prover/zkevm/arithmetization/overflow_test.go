@@ -0,0 +1,38 @@
+package arithmetization
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTraceOverflows(t *testing.T) {
+
+	var (
+		hub = &TraceOverflowError{Module: "hub", Height: 20, Limit: 16, Ratio: 1.25}
+		mmu = &TraceOverflowError{Module: "mmu", Height: 9, Limit: 8, Ratio: 1.125}
+		err = fmt.Errorf("could not prove: %w", errors.Join(hub, mmu))
+	)
+
+	assert.True(t, IsTraceOverflow(err))
+	assert.Equal(t, []*TraceOverflowError{hub, mmu}, TraceOverflows(err))
+
+	other := errors.New("unrelated")
+	assert.False(t, IsTraceOverflow(other))
+	assert.Empty(t, TraceOverflows(other))
+	assert.Empty(t, TraceOverflows(nil))
+}
+
+func TestPanicOverflow(t *testing.T) {
+
+	defer func() {
+		r := recover()
+		err, ok := r.(*TraceOverflowError)
+		assert.True(t, ok)
+		assert.Equal(t, &TraceOverflowError{Module: "blake2f", Height: 6, Limit: 4, Ratio: 1.5}, err)
+	}()
+
+	PanicOverflow("blake2f", 6, 4)
+}
@@ -14,12 +14,17 @@ import (
 	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
 	"github.com/consensys/linea-monorepo/prover/utils"
 	"github.com/consensys/linea-monorepo/prover/zkevm/arithmetization"
+	"github.com/consensys/linea-monorepo/prover/zkevm/prover/blake2f"
+	"github.com/consensys/linea-monorepo/prover/zkevm/prover/bls12381"
 	"github.com/consensys/linea-monorepo/prover/zkevm/prover/ecarith"
 	"github.com/consensys/linea-monorepo/prover/zkevm/prover/ecdsa"
 	"github.com/consensys/linea-monorepo/prover/zkevm/prover/ecpair"
 	"github.com/consensys/linea-monorepo/prover/zkevm/prover/hash/keccak"
+	"github.com/consensys/linea-monorepo/prover/zkevm/prover/hash/ripemd"
 	"github.com/consensys/linea-monorepo/prover/zkevm/prover/hash/sha2"
 	"github.com/consensys/linea-monorepo/prover/zkevm/prover/modexp"
+	"github.com/consensys/linea-monorepo/prover/zkevm/prover/p256verify"
+	"github.com/consensys/linea-monorepo/prover/zkevm/prover/pointeval"
 	"github.com/consensys/linea-monorepo/prover/zkevm/prover/statemanager"
 	"github.com/consensys/linea-monorepo/prover/zkevm/prover/statemanager/accumulator"
 )
@@ -122,6 +127,14 @@ func FullZkEVMCheckOnly(tl *config.TracesLimits) *ZkEvm {
 
 func fullZKEVMWithSuite(tl *config.TracesLimits, suite compilationSuite) *ZkEvm {
 
+	// Initialize the Full zkEVM arithmetization
+	fullZkEvm = NewZkEVM(fullSettings(tl, suite))
+	return fullZkEvm
+}
+
+// fullSettings returns the settings of the full zkEVM for the given limits
+func fullSettings(tl *config.TracesLimits, suite compilationSuite) Settings {
+
 	// @Alex: only set mandatory parameters here. aka, the one that are not
 	// actually feature-gated.
 	settings := Settings{
@@ -175,9 +188,80 @@ func fullZKEVMWithSuite(tl *config.TracesLimits, suite compilationSuite) *ZkEvm
 		Sha2: sha2.Settings{
 			MaxNumSha2F: tl.PrecompileSha2Blocks,
 		},
+		Ripemd: ripemd.Settings{
+			MaxNumRipemdF: tl.PrecompileRipemdBlocks,
+		},
+		Blake2f: blake2f.Settings{
+			MaxNbInstances: tl.PrecompileBlakeEffectiveCalls,
+			MaxNbRounds:    tl.PrecompileBlakeRounds,
+		},
+		P256Verify: p256verify.Settings{
+			MaxNbP256Verify: tl.PrecompileP256VerifyEffectiveCalls,
+			// a single verification costs a bit less than 2^20 constraints
+			// so we batch them by 2.
+			NbInputInstance:    2,
+			NbCircuitInstances: utils.DivCeil(tl.PrecompileP256VerifyEffectiveCalls, 2),
+		},
+		PointEval: pointeval.Limits{
+			// a single point evaluation already takes more than 2^22
+			// constraints.
+			NbInputInstances:          1,
+			NbCircuitInstances:        tl.PrecompilePointEvaluationEffectiveCalls,
+			NbFailureCircuitInstances: tl.PrecompilePointEvalFailureEffectiveCalls,
+		},
+		BlsG1Add: bls12381.Limits{
+			NbInputInstances:   16,
+			NbCircuitInstances: utils.DivCeil(tl.PrecompileBlsG1AddEffectiveCalls, 16),
+		},
+		BlsG2Add: bls12381.Limits{
+			NbInputInstances:   8,
+			NbCircuitInstances: utils.DivCeil(tl.PrecompileBlsG2AddEffectiveCalls, 8),
+		},
+		// the MSMs are limited by the number of scalar multiplications, each
+		// of them including a subgroup check.
+		BlsG1Msm: bls12381.Limits{
+			NbInputInstances:   2,
+			NbCircuitInstances: utils.DivCeil(tl.PrecompileBlsG1MsmScalarMuls, 2),
+		},
+		BlsG2Msm: bls12381.Limits{
+			NbInputInstances:   1,
+			NbCircuitInstances: tl.PrecompileBlsG2MsmScalarMuls,
+		},
+		BlsPairing: bls12381.PairingLimits{
+			NbMillerLoopInputInstances: 1,
+			NbMillerLoopCircuits:       tl.PrecompileBlsPairingMillerLoops,
+			NbFinalExpInputInstances:   1,
+			NbFinalExpCircuits:         tl.PrecompileBlsPairingFinalExponentiations,
+		},
+		BlsMapFpToG1: bls12381.Limits{
+			NbInputInstances:   4,
+			NbCircuitInstances: utils.DivCeil(tl.PrecompileBlsMapFpToG1EffectiveCalls, 4),
+		},
+		BlsMapFp2ToG2: bls12381.Limits{
+			NbInputInstances:   2,
+			NbCircuitInstances: utils.DivCeil(tl.PrecompileBlsMapFp2ToG2EffectiveCalls, 2),
+		},
+		// the subgroup checks take about 2^19 constraints and the curve
+		// checks about 2^14.
+		BlsNonMembership: bls12381.NonMembershipLimits{
+			C1: bls12381.Limits{
+				NbInputInstances:   16,
+				NbCircuitInstances: utils.DivCeil(tl.PrecompileBlsC1MembershipCalls, 16),
+			},
+			G1: bls12381.Limits{
+				NbInputInstances:   4,
+				NbCircuitInstances: utils.DivCeil(tl.PrecompileBlsG1MembershipCalls, 4),
+			},
+			C2: bls12381.Limits{
+				NbInputInstances:   16,
+				NbCircuitInstances: utils.DivCeil(tl.PrecompileBlsC2MembershipCalls, 16),
+			},
+			G2: bls12381.Limits{
+				NbInputInstances:   4,
+				NbCircuitInstances: utils.DivCeil(tl.PrecompileBlsG2MembershipCalls, 4),
+			},
+		},
 	}
 
-	// Initialize the Full zkEVM arithmetization
-	fullZkEvm = NewZkEVM(settings)
-	return fullZkEvm
+	return settings
 }
@@ -0,0 +1,217 @@
+package blake2f
+
+import (
+	"fmt"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/bitslice"
+	"github.com/consensys/gnark/std/math/uints"
+)
+
+const (
+	// limbSizeBits is the size (in bits) of a limb as in the public inputs of
+	// the circuit. This is a parameter linked to how the arithmetization
+	// encodes the operands.
+	limbSizeBits = 128
+)
+
+// iv is the initialization vector of BLAKE2b
+var iv = [8]uint64{
+	0x6a09e667f3bcc908, 0xbb67ae8584caa73b, 0x3c6ef372fe94f82b, 0xa54ff53a5f1d36f1,
+	0x510e527fade682d1, 0x9b05688c2b3e6c1f, 0x1f83d9abfb41bd6b, 0x5be0cd19137e2179,
+}
+
+// sigma is the message schedule of BLAKE2b
+var sigma = [10][16]int{
+	{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15},
+	{14, 10, 4, 8, 9, 15, 13, 6, 1, 12, 0, 2, 11, 7, 5, 3},
+	{11, 8, 12, 0, 5, 2, 15, 13, 10, 14, 3, 6, 7, 1, 9, 4},
+	{7, 9, 3, 1, 13, 12, 11, 14, 2, 6, 5, 10, 4, 0, 15, 8},
+	{9, 0, 5, 7, 2, 4, 10, 15, 14, 1, 11, 12, 6, 8, 3, 13},
+	{2, 12, 6, 10, 0, 11, 8, 3, 4, 13, 7, 5, 15, 14, 1, 9},
+	{12, 5, 1, 15, 14, 13, 4, 10, 0, 7, 6, 3, 9, 2, 8, 11},
+	{13, 11, 7, 14, 12, 1, 3, 9, 5, 0, 15, 4, 8, 6, 2, 10},
+	{6, 15, 14, 9, 11, 3, 0, 8, 12, 2, 13, 7, 1, 4, 10, 5},
+	{10, 2, 8, 4, 7, 6, 1, 5, 15, 11, 9, 14, 3, 12, 13, 0},
+}
+
+// blake2fCircuit implements the [frontend.Circuit] interface and is
+// responsible for ensuring all the BLAKE2f claims brought to the module.
+type blake2fCircuit struct {
+	Instances []blake2fCircuitInstance `gnark:",public"`
+}
+
+// blake2fCircuitInstance is responsible for ensuring the correctness of a
+// chunk of the evaluation of the BLAKE2f precompile. The fields are laid out
+// in the same order as in the module.
+type blake2fCircuitInstance struct {
+	// Data stores the h, m and t operands in limbs of 16 bytes
+	Data [nbRowsData]frontend.Variable `gnark:",public"`
+	// RoundsRemaining is the number of rounds of the call remaining at the
+	// start of the chunk.
+	RoundsRemaining frontend.Variable `gnark:",public"`
+	// F is the final block indicator flag
+	F frontend.Variable `gnark:",public"`
+	// Result is the claimed result of the precompile in limbs of 16 bytes
+	Result [nbRowsResult]frontend.Variable `gnark:",public"`
+	// RoundsInChunk is the number of rounds applied by the chunk
+	RoundsInChunk frontend.Variable `gnark:",public"`
+	// StateIn is the state at the start of the chunk in limbs of 16 bytes. It
+	// is ignored for the first chunk of a call.
+	StateIn [nbRowsState]frontend.Variable `gnark:",public"`
+	// StateOut is the state at the end of the chunk in limbs of 16 bytes
+	StateOut [nbRowsState]frontend.Variable `gnark:",public"`
+	// IsFirst and IsLast indicate whether the chunk starts and ends the call.
+	// They are constrained to be binary by the wizard.
+	IsFirst, IsLast frontend.Variable `gnark:",public"`
+}
+
+// allocateCircuit allocates [blake2fCircuit] for n instances
+func allocateCircuit(n int) *blake2fCircuit {
+	return &blake2fCircuit{
+		Instances: make([]blake2fCircuitInstance, n),
+	}
+}
+
+// Define implements the [frontend.Circuit] interface
+func (c *blake2fCircuit) Define(api frontend.API) error {
+
+	uapi, err := uints.New[uints.U64](api)
+	if err != nil {
+		return fmt.Errorf("unexpected error when instantiating `uapi`: %w", err)
+	}
+
+	for i := range c.Instances {
+		c.Instances[i].checkBlake2f(api, uapi)
+	}
+
+	return nil
+}
+
+// checkBlake2f adds the constraints ensuring the correctness of the chunk.
+// The result of the call is only checked on the last chunk of the call. The
+// padding instances, which are all zeroes, are valid chunks applying no round
+// to a zero state.
+func (inst *blake2fCircuitInstance) checkBlake2f(api frontend.API, uapi *uints.BinaryField[uints.U64]) {
+
+	var (
+		data     = limbsToWords(api, uapi, inst.Data[:])
+		result   = limbsToWords(api, uapi, inst.Result[:])
+		stateIn  = limbsToWords(api, uapi, inst.StateIn[:])
+		stateOut = limbsToWords(api, uapi, inst.StateOut[:])
+		h        = [8]uints.U64(data[0:8])
+		m        = [16]uints.U64(data[8:24])
+		t        = [2]uints.U64(data[24:26])
+		v        = [16]uints.U64{}
+		isActive = frontend.Variable(1)
+	)
+
+	api.AssertIsBoolean(inst.F)
+
+	copy(v[:8], h[:])
+	for i := range iv {
+		v[8+i] = uints.NewU64(iv[i])
+	}
+
+	v[12] = uapi.Xor(v[12], t[0])
+	v[13] = uapi.Xor(v[13], t[1])
+	v[14] = selectU64(api, inst.F, uapi.Not(v[14]), v[14])
+
+	// The chunks following the first one start from the state left by the
+	// previous chunk.
+	for i := range v {
+		v[i] = selectU64(api, inst.IsFirst, v[i], stateIn[i])
+	}
+
+	// The rounds are applied as long as the round counter is lower than the
+	// requested number of rounds. Past this point, the state is left
+	// unchanged. Every chunk starts at a multiple of nbRoundsPerChunk in the
+	// rounds of the call, so the message schedule starts over.
+	for r := 0; r < nbRoundsPerChunk; r++ {
+
+		isActive = api.Mul(isActive, api.Sub(1, api.IsZero(api.Sub(inst.RoundsInChunk, r))))
+
+		var (
+			s    = sigma[r]
+			next = v
+		)
+
+		g(uapi, &next, 0, 4, 8, 12, m[s[0]], m[s[1]])
+		g(uapi, &next, 1, 5, 9, 13, m[s[2]], m[s[3]])
+		g(uapi, &next, 2, 6, 10, 14, m[s[4]], m[s[5]])
+		g(uapi, &next, 3, 7, 11, 15, m[s[6]], m[s[7]])
+		g(uapi, &next, 0, 5, 10, 15, m[s[8]], m[s[9]])
+		g(uapi, &next, 1, 6, 11, 12, m[s[10]], m[s[11]])
+		g(uapi, &next, 2, 7, 8, 13, m[s[12]], m[s[13]])
+		g(uapi, &next, 3, 4, 9, 14, m[s[14]], m[s[15]])
+
+		for i := range v {
+			v[i] = selectU64(api, isActive, next[i], v[i])
+		}
+	}
+
+	// If isActive is still 1 at this point, it means that the number of rounds
+	// is at least what the circuit supports. The only allowed value in that
+	// case is exactly nbRoundsPerChunk.
+	api.AssertIsEqual(api.Mul(isActive, api.Sub(inst.RoundsInChunk, nbRoundsPerChunk)), 0)
+
+	for i := range v {
+		api.AssertIsEqual(uapi.ToValue(v[i]), uapi.ToValue(stateOut[i]))
+	}
+
+	for i := range h {
+		recomputed := uapi.Xor(h[i], v[i], v[i+8])
+		api.AssertIsEqual(
+			api.Mul(
+				inst.IsLast,
+				api.Sub(uapi.ToValue(recomputed), uapi.ToValue(result[i])),
+			),
+			0,
+		)
+	}
+}
+
+// g is the mixing function of BLAKE2b
+func g(uapi *uints.BinaryField[uints.U64], v *[16]uints.U64, a, b, c, d int, x, y uints.U64) {
+	v[a] = uapi.Add(v[a], v[b], x)
+	v[d] = uapi.Lrot(uapi.Xor(v[d], v[a]), -32)
+	v[c] = uapi.Add(v[c], v[d])
+	v[b] = uapi.Lrot(uapi.Xor(v[b], v[c]), -24)
+	v[a] = uapi.Add(v[a], v[b], y)
+	v[d] = uapi.Lrot(uapi.Xor(v[d], v[a]), -16)
+	v[c] = uapi.Add(v[c], v[d])
+	v[b] = uapi.Lrot(uapi.Xor(v[b], v[c]), -63)
+}
+
+// selectU64 returns a if cond is 1 and b if cond is 0. cond must be
+// constrained to be boolean by the caller.
+func selectU64(api frontend.API, cond frontend.Variable, a, b uints.U64) uints.U64 {
+	var res uints.U64
+	for i := range res {
+		res[i] = uints.U8{Val: api.Select(cond, a[i].Val, b[i].Val)}
+	}
+	return res
+}
+
+// limbsToWords converts a sequence of 16 bytes limbs (in big-endian order) into
+// the sequence of 8-bytes words they represent when the stream of bytes is
+// read in little-endian order, as done by BLAKE2f.
+func limbsToWords(api frontend.API, uapi *uints.BinaryField[uints.U64], limbs []frontend.Variable) []uints.U64 {
+
+	var (
+		bytes = make([]uints.U8, 0, 16*len(limbs))
+		words = make([]uints.U64, 0, 2*len(limbs))
+	)
+
+	for i := range limbs {
+		lo, hi := bitslice.Partition(api, limbs[i], 64, bitslice.WithNbDigits(limbSizeBits))
+		bytes = append(bytes, uapi.UnpackMSB(uapi.ValueOf(hi))...)
+		bytes = append(bytes, uapi.UnpackMSB(uapi.ValueOf(lo))...)
+	}
+
+	for i := 0; i < len(bytes); i += 8 {
+		words = append(words, uapi.PackLSB(bytes[i:i+8]...))
+	}
+
+	return words
+}
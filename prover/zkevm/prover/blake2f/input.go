@@ -0,0 +1,86 @@
+package blake2f
+
+import (
+	"github.com/consensys/linea-monorepo/prover/maths/common/smartvectors"
+	"github.com/consensys/linea-monorepo/prover/protocol/ifaces"
+	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
+	sym "github.com/consensys/linea-monorepo/prover/symbolic"
+	"github.com/consensys/linea-monorepo/prover/utils"
+)
+
+// Input collects references to the columns of the arithmetization containing
+// the BLAKE2f statements. These columns are constrained via a projection query
+// to describe the same statement as what is being stated in the blake2f
+// module. They are also used as a data source to assign the columns of the
+// module.
+//
+// The columns provided here are columns from the BLK_MDXP module.
+type Input struct {
+	Settings Settings
+	// Binary column indicating if we have limbs of the (h, m, t) operands
+	IsBlakeData ifaces.Column
+	// Binary column indicating if we have the rounds and the f flag
+	IsBlakeParams ifaces.Column
+	// Binary column indicating if we have result limbs
+	IsBlakeResult ifaces.Column
+	// isBlake is a constructed column constrained to be equal to the sum of
+	// the 3 above columns.
+	isBlake ifaces.Column
+	// Multiplexed column containing limbs for the operands and the result
+	Limbs ifaces.Column
+}
+
+// Settings specifies the limits of the BLAKE2f module.
+type Settings struct {
+	// MaxNbInstances is the maximal number of calls to the precompile
+	MaxNbInstances int
+	// MaxNbRounds is the maximal total number of rounds over all the calls
+	MaxNbRounds int
+}
+
+// maxNbChunks returns the number of chunks needed to hold any set of calls
+// complying with the limits. A call with r rounds takes at most
+// 1 + r/nbRoundsPerChunk chunks.
+func (s Settings) maxNbChunks() int {
+	return s.MaxNbInstances + utils.DivCeil(s.MaxNbRounds, nbRoundsPerChunk)
+}
+
+func newZkEVMInput(comp *wizard.CompiledIOP, settings Settings) Input {
+	return Input{
+		Settings:      settings,
+		IsBlakeData:   comp.Columns.GetHandle("blake2fmodexpdata.IS_BLAKE_DATA"),
+		IsBlakeParams: comp.Columns.GetHandle("blake2fmodexpdata.IS_BLAKE_PARAMS"),
+		IsBlakeResult: comp.Columns.GetHandle("blake2fmodexpdata.IS_BLAKE_RESULT"),
+		Limbs:         comp.Columns.GetHandle("blake2fmodexpdata.LIMB"),
+	}
+}
+
+// setIsBlake constructs, constraints and set the [isBlake] column
+func (i *Input) setIsBlake(comp *wizard.CompiledIOP) {
+
+	i.isBlake = comp.InsertCommit(0, "BLAKE2F_INPUT_IS_BLAKE", i.IsBlakeData.Size())
+
+	comp.InsertGlobal(
+		0,
+		"BLAKE2F_IS_BLAKE_WELL_CONSTRUCTED",
+		sym.Sub(
+			i.isBlake,
+			i.IsBlakeData,
+			i.IsBlakeParams,
+			i.IsBlakeResult,
+		),
+	)
+}
+
+// assignIsBlake evaluates and assigns the isBlake column
+func (i *Input) assignIsBlake(run *wizard.ProverRuntime) {
+
+	var (
+		isData   = i.IsBlakeData.GetColAssignment(run)
+		isParams = i.IsBlakeParams.GetColAssignment(run)
+		isResult = i.IsBlakeResult.GetColAssignment(run)
+		isBlake  = smartvectors.Add(isData, isParams, isResult)
+	)
+
+	run.AssignColumn(i.isBlake.GetColID(), isBlake)
+}
@@ -0,0 +1,347 @@
+// The blake2f package implements the wizard module responsible for verifying
+// the calls to the BLAKE2f precompile (0x09). The claims are extracted from
+// the BLK_MDXP module of the arithmetization and checked with a gnark circuit
+// through the Plonk-in-Wizard alignment.
+//
+// The number of rounds of a call is only bounded by a 4-bytes integer. To
+// avoid having a per-call cap, every call is split into chunks of at most
+// [nbRoundsPerChunk] rounds, each of them verified by a circuit instance. The
+// intermediate state of the compression is passed from a chunk to the next
+// one through the columns of the module.
+package blake2f
+
+import (
+	"github.com/consensys/linea-monorepo/prover/maths/common/smartvectors"
+	"github.com/consensys/linea-monorepo/prover/maths/field"
+	"github.com/consensys/linea-monorepo/prover/protocol/column"
+	"github.com/consensys/linea-monorepo/prover/protocol/dedicated/plonk"
+	"github.com/consensys/linea-monorepo/prover/protocol/dedicated/projection"
+	"github.com/consensys/linea-monorepo/prover/protocol/ifaces"
+	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
+	sym "github.com/consensys/linea-monorepo/prover/symbolic"
+	"github.com/consensys/linea-monorepo/prover/utils"
+	commonconstraints "github.com/consensys/linea-monorepo/prover/zkevm/prover/common/common_constraints"
+)
+
+const (
+	// nbRowsData corresponds to the number of limbs used by the
+	// arithmetization to represent the h (64 bytes), m (128 bytes) and t
+	// (16 bytes) operands of BLAKE2f.
+	nbRowsData = 13
+	// nbRowsParams corresponds to the number of limbs used to represent the
+	// number of rounds and the final block indicator flag f, in that order.
+	nbRowsParams = 2
+	// nbRowsResult corresponds to the number of limbs used to represent the
+	// 64 bytes of the result.
+	nbRowsResult = 4
+	// blakeNumRowsPerInstance corresponds to the number of rows taken by a
+	// single instance of BLAKE2f in the BLK_MDXP module.
+	blakeNumRowsPerInstance = nbRowsData + nbRowsParams + nbRowsResult
+	// nbRowsState corresponds to the number of limbs used to represent the
+	// 16 words of the internal state of the compression.
+	nbRowsState = 8
+
+	// The positions of the fields of a chunk in the rows of the module. The
+	// first [blakeNumRowsPerInstance] rows follow the layout of the BLK_MDXP
+	// module, the row holding the number of rounds being the number of rounds
+	// remaining at the start of the chunk.
+	posRoundsRemaining = nbRowsData
+	posRoundsInChunk   = blakeNumRowsPerInstance
+	posStateIn         = posRoundsInChunk + 1
+	posStateOut        = posStateIn + nbRowsState
+	posIsFirst         = posStateOut + nbRowsState
+	posIsLast          = posIsFirst + 1
+	// nbRowsPerChunk is the number of rows taken by a chunk in the module.
+	nbRowsPerChunk = posIsLast + 1
+
+	// nbInstancePerCircuit states how many chunks are taken care of by a
+	// single gnark circuit.
+	nbInstancePerCircuit = 4
+
+	// nbRoundsPerChunk is the maximal number of rounds applied by a circuit
+	// instance. It is set to the period of the message schedule so that
+	// every chunk starts at the same position in the schedule.
+	nbRoundsPerChunk = 10
+)
+
+// Module implements the wizard part responsible for checking the BLAKE2f
+// claims coming from the BLKMDXP module of the arithmetization.
+type Module struct {
+	// MaxNbInstances corresponds to the maximum number of calls that we want
+	// to support.
+	MaxNbInstances int
+	// MaxNbRounds corresponds to the maximum total number of rounds over all
+	// the calls that we want to support.
+	MaxNbRounds int
+	// MaxNbChunks is the number of chunks the module can hold. It is derived
+	// from the two above limits so that any set of calls complying with them
+	// fits.
+	MaxNbChunks int
+	// Input stores the columns used as a source for the module.
+	Input Input
+	// IsActive is a binary indicator column marking with a 1, the rows of the
+	// module corresponding "active" rows: e.g. NOT padding rows. It is
+	// constant over the rows of a chunk.
+	IsActive ifaces.Column
+	// Limbs contains the chunks laid out as described by the pos* constants.
+	// It is constrained to zero when IsActive = 0.
+	Limbs ifaces.Column
+	// IsCallLimb marks with a 1 the first [blakeNumRowsPerInstance] rows of
+	// the first chunk of every call. It is used as the filter of the
+	// projection from the BLK_MDXP module.
+	IsCallLimb ifaces.Column
+	// IsChunkStart is a precomputed column marking with a 1 the first row of
+	// every chunk fitting in the module.
+	IsChunkStart ifaces.Column
+	// HasNextChunk is a precomputed column marking with a 1 the first row of
+	// every chunk that is followed by another chunk in the module.
+	HasNextChunk ifaces.Column
+	// IsCallPosition is a precomputed column marking with a 1 the first
+	// [blakeNumRowsPerInstance] rows of every chunk.
+	IsCallPosition ifaces.Column
+	// IsInChunk is a precomputed column marking with a 1 the rows belonging
+	// to a chunk. The last rows of the module are not if the size of the
+	// module is not a multiple of [nbRowsPerChunk].
+	IsInChunk ifaces.Column
+	// GnarkCircuitConnector is the connection logic of the BLAKE2f circuit.
+	GnarkCircuitConnector *plonk.Alignment
+	// hasCircuit indicates whether the circuit has been set in the module. In
+	// production, it will be always set to true. But for convenience we omit
+	// the circuit in some of the test as this is CPU intensive.
+	hasCircuit bool
+}
+
+// NewModuleZkEvm constructs an instance of the BLAKE2f module. It should be
+// called only once.
+func NewModuleZkEvm(comp *wizard.CompiledIOP, settings Settings) *Module {
+	return newModule(comp, newZkEVMInput(comp, settings)).
+		WithCircuit(comp, plonk.WithRangecheck(16, 6, false))
+}
+
+func newModule(comp *wizard.CompiledIOP, input Input) *Module {
+
+	var (
+		settings    = input.Settings
+		maxNbChunks = settings.maxNbChunks()
+		size        = utils.NextPowerOfTwo(maxNbChunks * nbRowsPerChunk)
+		mod         = &Module{
+			Input:          input,
+			MaxNbInstances: settings.MaxNbInstances,
+			MaxNbRounds:    settings.MaxNbRounds,
+			MaxNbChunks:    maxNbChunks,
+			IsActive:       comp.InsertCommit(0, "BLAKE2F_IS_ACTIVE", size),
+			Limbs:          comp.InsertCommit(0, "BLAKE2F_LIMBS", size),
+			IsCallLimb:     comp.InsertCommit(0, "BLAKE2F_IS_CALL_LIMB", size),
+			IsChunkStart:   comp.InsertPrecomputed("BLAKE2F_IS_CHUNK_START", chunkPositionValue(size, func(pos, _, _ int) bool { return pos == 0 })),
+			HasNextChunk:   comp.InsertPrecomputed("BLAKE2F_HAS_NEXT_CHUNK", chunkPositionValue(size, func(pos, chunk, nbChunks int) bool { return pos == 0 && chunk+1 < nbChunks })),
+			IsCallPosition: comp.InsertPrecomputed("BLAKE2F_IS_CALL_POSITION", chunkPositionValue(size, func(pos, _, _ int) bool { return pos < blakeNumRowsPerInstance })),
+			IsInChunk:      comp.InsertPrecomputed("BLAKE2F_IS_IN_CHUNK", chunkPositionValue(size, func(_, _, _ int) bool { return true })),
+		}
+	)
+
+	mod.Input.setIsBlake(comp)
+
+	commonconstraints.MustBeActivationColumns(comp, mod.IsActive)
+	commonconstraints.MustZeroWhenInactive(comp, mod.IsActive, mod.Limbs)
+
+	// at returns the limb at the given position of the chunk starting on the
+	// current row, shifted by the given number of chunks.
+	at := func(pos, chunkShift int) ifaces.Column {
+		return column.Shift(mod.Limbs, pos+chunkShift*nbRowsPerChunk)
+	}
+
+	var (
+		isFirst     = at(posIsFirst, 0)
+		isLast      = at(posIsLast, 0)
+		notLast     = sym.Sub(1, isLast)
+		nextActive  = column.Shift(mod.IsActive, nbRowsPerChunk)
+		continuesOn = sym.Mul(mod.HasNextChunk, notLast)
+	)
+
+	// IsActive is constant over a chunk and the rows past the last chunk are
+	// inactive. Along with the activation form, this ensures that the active
+	// rows are made of whole chunks.
+	comp.InsertGlobal(
+		0,
+		"BLAKE2F_IS_ACTIVE_CONSTANT_IN_CHUNK",
+		sym.Mul(
+			sym.Sub(mod.IsInChunk, mod.IsChunkStart),
+			sym.Sub(mod.IsActive, column.Shift(mod.IsActive, -1)),
+		),
+	)
+
+	comp.InsertGlobal(
+		0,
+		"BLAKE2F_IS_ACTIVE_ONLY_IN_CHUNKS",
+		sym.Mul(mod.IsActive, sym.Sub(1, mod.IsInChunk)),
+	)
+
+	for _, flag := range []struct {
+		name string
+		col  ifaces.Column
+	}{{"IS_FIRST", isFirst}, {"IS_LAST", isLast}} {
+		comp.InsertGlobal(
+			0,
+			ifaces.QueryIDf("BLAKE2F_%v_IS_BINARY", flag.name),
+			sym.Mul(mod.IsChunkStart, flag.col, sym.Sub(flag.col, 1)),
+		)
+	}
+
+	// The first chunk of the module starts a call and a chunk starts a call
+	// if and only if the previous one ends one.
+	comp.InsertLocal(
+		0,
+		"BLAKE2F_FIRST_CHUNK_IS_FIRST",
+		sym.Mul(mod.IsActive, sym.Sub(1, isFirst)),
+	)
+
+	comp.InsertGlobal(
+		0,
+		"BLAKE2F_IS_FIRST_AFTER_IS_LAST",
+		sym.Mul(
+			mod.HasNextChunk,
+			nextActive,
+			sym.Sub(at(posIsFirst, 1), isLast),
+		),
+	)
+
+	// An active chunk that does not end its call must be followed by another
+	// active chunk. In particular, the last chunk of the module ends its call.
+	comp.InsertGlobal(
+		0,
+		"BLAKE2F_NOT_LAST_HAS_NEXT",
+		sym.Mul(
+			mod.IsChunkStart,
+			mod.IsActive,
+			notLast,
+			sym.Sub(1, sym.Mul(mod.HasNextChunk, nextActive)),
+		),
+	)
+
+	// The chunks of a call share the operands and the result of the call and
+	// the state at the end of a chunk is the state at the start of the next.
+	for pos := 0; pos < blakeNumRowsPerInstance; pos++ {
+
+		if pos == posRoundsRemaining {
+			continue
+		}
+
+		comp.InsertGlobal(
+			0,
+			ifaces.QueryIDf("BLAKE2F_CALL_LIMB_CONTINUITY_%v", pos),
+			sym.Mul(continuesOn, sym.Sub(at(pos, 1), at(pos, 0))),
+		)
+	}
+
+	for i := 0; i < nbRowsState; i++ {
+		comp.InsertGlobal(
+			0,
+			ifaces.QueryIDf("BLAKE2F_STATE_CONTINUITY_%v", i),
+			sym.Mul(continuesOn, sym.Sub(at(posStateIn+i, 1), at(posStateOut+i, 0))),
+		)
+	}
+
+	comp.InsertGlobal(
+		0,
+		"BLAKE2F_ROUNDS_REMAINING_CONTINUITY",
+		sym.Mul(
+			continuesOn,
+			sym.Sub(
+				sym.Add(at(posRoundsRemaining, 1), at(posRoundsInChunk, 0)),
+				at(posRoundsRemaining, 0),
+			),
+		),
+	)
+
+	// The last chunk of a call applies all the remaining rounds and the other
+	// chunks apply [nbRoundsPerChunk] rounds. The circuit ensures that a chunk
+	// does not apply more than [nbRoundsPerChunk] rounds.
+	comp.InsertGlobal(
+		0,
+		"BLAKE2F_ROUNDS_IN_CHUNK",
+		sym.Mul(
+			mod.IsChunkStart,
+			mod.IsActive,
+			sym.Sub(
+				at(posRoundsInChunk, 0),
+				sym.Mul(isLast, at(posRoundsRemaining, 0)),
+				sym.Mul(notLast, nbRoundsPerChunk),
+			),
+		),
+	)
+
+	// IsCallLimb is a copy of IsFirst over the first blakeNumRowsPerInstance
+	// rows of the chunk and zero elsewhere.
+	comp.InsertGlobal(
+		0,
+		"BLAKE2F_IS_CALL_LIMB_ONLY_AT_CALL_POSITIONS",
+		sym.Mul(mod.IsCallLimb, sym.Sub(1, mod.IsCallPosition)),
+	)
+
+	comp.InsertGlobal(
+		0,
+		"BLAKE2F_IS_CALL_LIMB_CONSTANT_IN_CALL_POSITIONS",
+		sym.Mul(
+			sym.Sub(mod.IsCallPosition, mod.IsChunkStart),
+			sym.Sub(mod.IsCallLimb, column.Shift(mod.IsCallLimb, -1)),
+		),
+	)
+
+	comp.InsertGlobal(
+		0,
+		"BLAKE2F_IS_CALL_LIMB_IS_FIRST",
+		sym.Mul(mod.IsChunkStart, sym.Sub(mod.IsCallLimb, isFirst)),
+	)
+
+	projection.InsertProjection(
+		comp,
+		"BLAKE2F_BLKMDXP_PROJECTION",
+		[]ifaces.Column{mod.Input.Limbs},
+		[]ifaces.Column{mod.Limbs},
+		mod.Input.isBlake,
+		mod.IsCallLimb,
+	)
+
+	return mod
+}
+
+// WithCircuit adds the Plonk-in-Wizard circuit verification to complete the
+// module.
+func (mod *Module) WithCircuit(comp *wizard.CompiledIOP, options ...plonk.Option) *Module {
+
+	mod.hasCircuit = true
+
+	mod.GnarkCircuitConnector = plonk.DefineAlignment(
+		comp,
+		&plonk.CircuitAlignmentInput{
+			Name:               "BLAKE2F_COMPRESSION",
+			DataToCircuit:      mod.Limbs,
+			DataToCircuitMask:  mod.IsActive,
+			Circuit:            allocateCircuit(nbInstancePerCircuit),
+			NbCircuitInstances: utils.DivCeil(mod.MaxNbChunks, nbInstancePerCircuit),
+			PlonkOptions:       options,
+		},
+	)
+
+	return mod
+}
+
+// chunkPositionValue returns the smartvector of the given size having a 1 on
+// the rows of the chunks fitting in the module for which isSet returns true.
+// isSet is given the position of the row in its chunk, the index of the chunk
+// and the number of chunks fitting in the module.
+func chunkPositionValue(size int, isSet func(pos, chunk, nbChunks int) bool) smartvectors.SmartVector {
+
+	var (
+		resSlice = make([]field.Element, size)
+		nbChunks = size / nbRowsPerChunk
+	)
+
+	for i := 0; i < nbChunks*nbRowsPerChunk; i++ {
+		if isSet(i%nbRowsPerChunk, i/nbRowsPerChunk, nbChunks) {
+			resSlice[i].SetOne()
+		}
+	}
+
+	return smartvectors.NewRegular(resSlice)
+}
@@ -0,0 +1,203 @@
+package blake2f
+
+import (
+	"encoding/binary"
+	"math/bits"
+
+	"github.com/consensys/linea-monorepo/prover/maths/field"
+	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
+	"github.com/consensys/linea-monorepo/prover/utils"
+	"github.com/consensys/linea-monorepo/prover/zkevm/arithmetization"
+	"github.com/consensys/linea-monorepo/prover/zkevm/prover/common"
+	"github.com/sirupsen/logrus"
+)
+
+// Assign assigns the BLAKE2f module
+func (mod *Module) Assign(run *wizard.ProverRuntime) {
+
+	mod.Input.assignIsBlake(run)
+
+	var (
+		calls       = [][]field.Element{}
+		roundsCount = 0
+		isBlake     = mod.Input.isBlake.GetColAssignment(run).IntoRegVecSaveAlloc()
+		limbs       = mod.Input.Limbs.GetColAssignment(run).IntoRegVecSaveAlloc()
+		isActive    = common.NewVectorBuilder(mod.IsActive)
+		isCallLimb  = common.NewVectorBuilder(mod.IsCallLimb)
+		modLimbs    = common.NewVectorBuilder(mod.Limbs)
+	)
+
+	for currPosition := 0; currPosition < len(limbs); {
+
+		if isBlake[currPosition].IsZero() {
+			currPosition++
+			continue
+		}
+
+		// This sanity-check is purely defensive and will indicate that we
+		// missed the start of a BLAKE2f instance
+		if len(limbs)-currPosition < blakeNumRowsPerInstance {
+			utils.Panic("A new blake2f is starting but there is not enough rows (currPosition=%v len(blkmdxp.Limb)=%v)", currPosition, len(limbs))
+		}
+
+		call := limbs[currPosition : currPosition+blakeNumRowsPerInstance]
+		calls = append(calls, call)
+		// The number of rounds is given by a 4-bytes integer in the input of
+		// the precompile so it always fits.
+		roundsCount += int(call[posRoundsRemaining].Uint64())
+		currPosition += blakeNumRowsPerInstance
+	}
+
+	// The limits are checked before running the compressions as the number
+	// of rounds of an overflowing call can be arbitrarily large.
+	if len(calls) > mod.MaxNbInstances {
+		logrus.Errorf("limit overflow: the blake2f count is %v and the limit is %v\n", len(calls), mod.MaxNbInstances)
+		arithmetization.PanicOverflow("blake2f", len(calls), mod.MaxNbInstances)
+	}
+
+	if roundsCount > mod.MaxNbRounds {
+		logrus.Errorf("limit overflow: the blake2f rounds count is %v and the limit is %v\n", roundsCount, mod.MaxNbRounds)
+		arithmetization.PanicOverflow("blake2f_rounds", roundsCount, mod.MaxNbRounds)
+	}
+
+	for _, call := range calls {
+		for _, chunk := range splitIntoChunks(call) {
+			for k := range chunk {
+				isActive.PushOne()
+				modLimbs.PushField(chunk[k])
+				isCallLimb.PushBoolean(k < blakeNumRowsPerInstance && !chunk[posIsFirst].IsZero())
+			}
+		}
+	}
+
+	isActive.PadAndAssign(run, field.Zero())
+	isCallLimb.PadAndAssign(run, field.Zero())
+	modLimbs.PadAndAssign(run, field.Zero())
+
+	// It is possible to not declare the circuit (for testing purpose) in that
+	// case we skip the corresponding assignment part.
+	if mod.hasCircuit {
+		mod.GnarkCircuitConnector.Assign(run)
+	}
+}
+
+// splitIntoChunks runs the compression of a call, given by its limbs in the
+// BLK_MDXP module, and returns the limbs of its chunks.
+func splitIntoChunks(call []field.Element) [][nbRowsPerChunk]field.Element {
+
+	var (
+		data            = nativeLimbsToWords(call[:nbRowsData])
+		roundsRemaining = call[posRoundsRemaining].Uint64()
+		f               = !call[nbRowsData+1].IsZero()
+		h               = data[0:8]
+		m               = data[8:24]
+		v               = [16]uint64{}
+		chunks          = [][nbRowsPerChunk]field.Element{}
+	)
+
+	copy(v[:8], h)
+	copy(v[8:], iv[:])
+	v[12] ^= data[24]
+	v[13] ^= data[25]
+	if f {
+		v[14] = ^v[14]
+	}
+
+	for isFirst := true; ; isFirst = false {
+
+		var (
+			chunk         [nbRowsPerChunk]field.Element
+			isLast        = roundsRemaining <= nbRoundsPerChunk
+			roundsInChunk = min(roundsRemaining, nbRoundsPerChunk)
+		)
+
+		copy(chunk[:], call)
+		chunk[posRoundsRemaining].SetUint64(roundsRemaining)
+		chunk[posRoundsInChunk].SetUint64(roundsInChunk)
+
+		// The state in is left to zero for the first chunk as the circuit
+		// does not use it.
+		if !isFirst {
+			copy(chunk[posStateIn:], nativeWordsToLimbs(v[:]))
+		}
+
+		for r := 0; r < int(roundsInChunk); r++ {
+			round(&v, m, sigma[r])
+		}
+
+		copy(chunk[posStateOut:], nativeWordsToLimbs(v[:]))
+
+		if isFirst {
+			chunk[posIsFirst].SetOne()
+		}
+
+		if isLast {
+			chunk[posIsLast].SetOne()
+		}
+
+		chunks = append(chunks, chunk)
+		roundsRemaining -= roundsInChunk
+
+		if isLast {
+			break
+		}
+	}
+
+	return chunks
+}
+
+// round applies a round of the BLAKE2b compression function to v with the
+// given message schedule.
+func round(v *[16]uint64, m []uint64, s [16]int) {
+	mix(v, 0, 4, 8, 12, m[s[0]], m[s[1]])
+	mix(v, 1, 5, 9, 13, m[s[2]], m[s[3]])
+	mix(v, 2, 6, 10, 14, m[s[4]], m[s[5]])
+	mix(v, 3, 7, 11, 15, m[s[6]], m[s[7]])
+	mix(v, 0, 5, 10, 15, m[s[8]], m[s[9]])
+	mix(v, 1, 6, 11, 12, m[s[10]], m[s[11]])
+	mix(v, 2, 7, 8, 13, m[s[12]], m[s[13]])
+	mix(v, 3, 4, 9, 14, m[s[14]], m[s[15]])
+}
+
+// mix is the native counterpart of [g]
+func mix(v *[16]uint64, a, b, c, d int, x, y uint64) {
+	v[a] = v[a] + v[b] + x
+	v[d] = bits.RotateLeft64(v[d]^v[a], -32)
+	v[c] = v[c] + v[d]
+	v[b] = bits.RotateLeft64(v[b]^v[c], -24)
+	v[a] = v[a] + v[b] + y
+	v[d] = bits.RotateLeft64(v[d]^v[a], -16)
+	v[c] = v[c] + v[d]
+	v[b] = bits.RotateLeft64(v[b]^v[c], -63)
+}
+
+// nativeLimbsToWords is the native counterpart of [limbsToWords]
+func nativeLimbsToWords(limbs []field.Element) []uint64 {
+
+	words := make([]uint64, 0, 2*len(limbs))
+
+	for i := range limbs {
+		b := limbs[i].Bytes()
+		words = append(words,
+			binary.LittleEndian.Uint64(b[16:24]),
+			binary.LittleEndian.Uint64(b[24:32]),
+		)
+	}
+
+	return words
+}
+
+// nativeWordsToLimbs is the inverse of [nativeLimbsToWords]
+func nativeWordsToLimbs(words []uint64) []field.Element {
+
+	limbs := make([]field.Element, len(words)/2)
+
+	for i := range limbs {
+		var b [16]byte
+		binary.LittleEndian.PutUint64(b[0:8], words[2*i])
+		binary.LittleEndian.PutUint64(b[8:16], words[2*i+1])
+		limbs[i].SetBytes(b[:])
+	}
+
+	return limbs
+}
@@ -0,0 +1,71 @@
+//go:build !fuzzlight
+
+package blake2f
+
+import (
+	"testing"
+
+	"github.com/consensys/linea-monorepo/prover/protocol/compiler/dummy"
+	"github.com/consensys/linea-monorepo/prover/protocol/dedicated/plonk"
+	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
+	"github.com/consensys/linea-monorepo/prover/utils/csvtraces"
+)
+
+func TestBlake2fWithCircuit(t *testing.T) {
+
+	testCases := []struct {
+		InputFName string
+	}{
+		{
+			InputFName: "testdata/single_12_rounds_input.csv",
+		},
+		{
+			InputFName: "testdata/single_25_rounds_input.csv",
+		},
+		{
+			InputFName: "testdata/multiple_with_filler_input.csv",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.InputFName, func(t *testing.T) {
+
+			var (
+				inp   Input
+				mod   *Module
+				inpCt = csvtraces.MustOpenCsvFile(tc.InputFName)
+			)
+
+			cmp := wizard.Compile(func(build *wizard.Builder) {
+				inp = Input{
+					IsBlakeData:   inpCt.GetCommit(build, "IS_BLAKE_DATA"),
+					IsBlakeParams: inpCt.GetCommit(build, "IS_BLAKE_PARAMS"),
+					IsBlakeResult: inpCt.GetCommit(build, "IS_BLAKE_RESULT"),
+					Limbs:         inpCt.GetCommit(build, "LIMBS"),
+					Settings:      Settings{MaxNbInstances: 4, MaxNbRounds: 40},
+				}
+
+				mod = newModule(build.CompiledIOP, inp).
+					WithCircuit(build.CompiledIOP, plonk.WithRangecheck(16, 6, false))
+			}, dummy.Compile)
+
+			proof := wizard.Prove(cmp, func(run *wizard.ProverRuntime) {
+
+				inpCt.Assign(run,
+					"LIMBS",
+					"IS_BLAKE_DATA",
+					"IS_BLAKE_PARAMS",
+					"IS_BLAKE_RESULT",
+				)
+
+				mod.Assign(run)
+			})
+
+			if err := wizard.Verify(cmp, proof); err != nil {
+				t.Fatal("proof failed", err)
+			}
+
+			t.Log("proof succeeded")
+		})
+	}
+}
@@ -0,0 +1,359 @@
+package main
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math/big"
+	"math/bits"
+	"math/rand"
+
+	"github.com/consensys/linea-monorepo/prover/backend/files"
+	"github.com/ethereum/go-ethereum/crypto/blake2b"
+)
+
+func main() {
+
+	for _, tcase := range testCases {
+
+		f := files.MustOverwrite("./" + tcase.name + "_input.csv")
+		dumpInputAsCsv(f, tcase.tab)
+		f.Close()
+
+		f = files.MustOverwrite("./" + tcase.name + "_module.csv")
+		dumpModuleAsCsv(f, tcase.tab)
+		f.Close()
+	}
+}
+
+var testCases = []struct {
+	name string
+	tab  [][]*big.Int
+}{
+	{
+		name: "single_12_rounds",
+		tab: func() [][]*big.Int {
+
+			var (
+				tab = make([][]*big.Int, 4)
+				rng = rand.New(rand.NewSource(98764357))
+			)
+
+			pushBlake2fToInput(createRandomBlake2f(rng, 12, true), tab)
+			return tab
+		}(),
+	},
+	{
+		name: "single_25_rounds",
+		tab: func() [][]*big.Int {
+
+			var (
+				tab = make([][]*big.Int, 4)
+				rng = rand.New(rand.NewSource(7623518))
+			)
+
+			pushBlake2fToInput(createRandomBlake2f(rng, 25, true), tab)
+			return tab
+		}(),
+	},
+	{
+		name: "multiple_with_filler",
+		tab: func() [][]*big.Int {
+
+			var (
+				tab = make([][]*big.Int, 4)
+				rng = rand.New(rand.NewSource(436547097))
+			)
+
+			pushFillerToInput(tab, rng, 5)
+			pushBlake2fToInput(createRandomBlake2f(rng, 0, false), tab)
+			pushFillerToInput(tab, rng, 3)
+			pushBlake2fToInput(createRandomBlake2f(rng, 5, true), tab)
+			pushBlake2fToInput(createRandomBlake2f(rng, 12, false), tab)
+			pushFillerToInput(tab, rng, 7)
+			pushBlake2fToInput(createRandomBlake2f(rng, 20, true), tab)
+			pushFillerToInput(tab, rng, 2)
+			return tab
+		}(),
+	},
+}
+
+// blake2fInstance stores the serialized operands and result of a BLAKE2f
+// call. The data field contains the concatenation of h, m and t as they are
+// passed to the precompile.
+type blake2fInstance struct {
+	data   [208]byte
+	rounds uint32
+	f      bool
+	result [64]byte
+}
+
+func createRandomBlake2f(rng *rand.Rand, rounds uint32, f bool) blake2fInstance {
+
+	var (
+		res = blake2fInstance{rounds: rounds, f: f}
+		h   [8]uint64
+		m   [16]uint64
+		t   [2]uint64
+	)
+
+	rng.Read(res.data[:])
+
+	for i := range h {
+		h[i] = binary.LittleEndian.Uint64(res.data[8*i:])
+	}
+
+	for i := range m {
+		m[i] = binary.LittleEndian.Uint64(res.data[64+8*i:])
+	}
+
+	for i := range t {
+		t[i] = binary.LittleEndian.Uint64(res.data[192+8*i:])
+	}
+
+	blake2b.F(&h, m, t, f, rounds)
+
+	for i := range h {
+		binary.LittleEndian.PutUint64(res.result[8*i:], h[i])
+	}
+
+	return res
+}
+
+func dumpInputAsCsv(w io.Writer, tab [][]*big.Int) {
+
+	fmt.Fprintf(w, "LIMBS,IS_BLAKE_DATA,IS_BLAKE_PARAMS,IS_BLAKE_RESULT\n")
+
+	for i := range tab[0] {
+		fmt.Fprintf(w, "0x%v,%v,%v,%v\n", tab[0][i].Text(16), tab[1][i].String(), tab[2][i].String(), tab[3][i].String())
+	}
+}
+
+// dumpModuleAsCsv writes the expected columns of the module. The calls are
+// split into chunks of 10 rounds and every chunk is written as its operands,
+// the remaining rounds, f, the result, the rounds of the chunk, the state in,
+// the state out and the first and last flags.
+func dumpModuleAsCsv(w io.Writer, tab [][]*big.Int) {
+
+	fmt.Fprintf(w, "BLAKE2F_LIMBS,BLAKE2F_IS_ACTIVE,BLAKE2F_IS_CALL_LIMB\n")
+
+	call := []*big.Int{}
+
+	for i := range tab[0] {
+
+		if tab[1][i].Sign() == 0 && tab[2][i].Sign() == 0 && tab[3][i].Sign() == 0 {
+			continue
+		}
+
+		call = append(call, tab[0][i])
+		if len(call) < 19 {
+			continue
+		}
+
+		for _, chunk := range chunksOf(call) {
+			for k := range chunk {
+				isCallLimb := 0
+				if k < 19 && chunk[36].Sign() != 0 {
+					isCallLimb = 1
+				}
+				fmt.Fprintf(w, "0x%v,1,%v\n", chunk[k].Text(16), isCallLimb)
+			}
+		}
+
+		call = call[:0]
+	}
+}
+
+// chunksOf returns the chunks of a call given by its 19 limbs
+func chunksOf(call []*big.Int) [][]*big.Int {
+
+	var (
+		data   = make([]byte, 0, 208)
+		rounds = call[13].Uint64()
+		f      = call[14].Sign() != 0
+		h, m   [16]uint64
+		v      [16]uint64
+		res    = [][]*big.Int{}
+	)
+
+	for i := 0; i < 13; i++ {
+		data = append(data, call[i].FillBytes(make([]byte, 16))...)
+	}
+
+	for i := 0; i < 8; i++ {
+		h[i] = binary.LittleEndian.Uint64(data[8*i:])
+	}
+
+	for i := range m {
+		m[i] = binary.LittleEndian.Uint64(data[64+8*i:])
+	}
+
+	copy(v[:8], h[:8])
+	copy(v[8:], iv[:])
+	v[12] ^= binary.LittleEndian.Uint64(data[192:])
+	v[13] ^= binary.LittleEndian.Uint64(data[200:])
+	if f {
+		v[14] = ^v[14]
+	}
+
+	for c := 0; ; c++ {
+
+		var (
+			isLast   = rounds <= 10
+			inChunk  = min(rounds, 10)
+			chunk    = append([]*big.Int{}, call[:13]...)
+			stateIn  = stateToLimbs(v)
+			isFirst  = big.NewInt(0)
+			isLastBi = big.NewInt(0)
+		)
+
+		if c == 0 {
+			isFirst.SetInt64(1)
+			stateIn = stateToLimbs([16]uint64{})
+		}
+
+		if isLast {
+			isLastBi.SetInt64(1)
+		}
+
+		for r := 0; r < int(inChunk); r++ {
+			s := sigma[r]
+			g(&v, 0, 4, 8, 12, m[s[0]], m[s[1]])
+			g(&v, 1, 5, 9, 13, m[s[2]], m[s[3]])
+			g(&v, 2, 6, 10, 14, m[s[4]], m[s[5]])
+			g(&v, 3, 7, 11, 15, m[s[6]], m[s[7]])
+			g(&v, 0, 5, 10, 15, m[s[8]], m[s[9]])
+			g(&v, 1, 6, 11, 12, m[s[10]], m[s[11]])
+			g(&v, 2, 7, 8, 13, m[s[12]], m[s[13]])
+			g(&v, 3, 4, 9, 14, m[s[14]], m[s[15]])
+		}
+
+		chunk = append(chunk, new(big.Int).SetUint64(rounds), call[14])
+		chunk = append(chunk, call[15:19]...)
+		chunk = append(chunk, new(big.Int).SetUint64(inChunk))
+		chunk = append(chunk, stateIn...)
+		chunk = append(chunk, stateToLimbs(v)...)
+		chunk = append(chunk, isFirst, isLastBi)
+		res = append(res, chunk)
+
+		rounds -= inChunk
+		if isLast {
+			break
+		}
+	}
+
+	// Sanity-check the last state against the expected result
+	for i := 0; i < 8; i++ {
+		h[i] ^= v[i] ^ v[i+8]
+	}
+
+	if got := stateToLimbs(h)[:4]; !slicesEqual(got, call[15:19]) {
+		panic("the chunks do not match the result of the call")
+	}
+
+	return res
+}
+
+// stateToLimbs returns the limbs of 16 bytes of the state, written in little
+// endian.
+func stateToLimbs(v [16]uint64) []*big.Int {
+
+	var (
+		b   [128]byte
+		res = make([]*big.Int, 8)
+	)
+
+	for i := range v {
+		binary.LittleEndian.PutUint64(b[8*i:], v[i])
+	}
+
+	for i := range res {
+		res[i] = new(big.Int).SetBytes(b[16*i : 16*i+16])
+	}
+
+	return res
+}
+
+func slicesEqual(a, b []*big.Int) bool {
+	for i := range a {
+		if a[i].Cmp(b[i]) != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func g(v *[16]uint64, a, b, c, d int, x, y uint64) {
+	v[a] = v[a] + v[b] + x
+	v[d] = bits.RotateLeft64(v[d]^v[a], -32)
+	v[c] = v[c] + v[d]
+	v[b] = bits.RotateLeft64(v[b]^v[c], -24)
+	v[a] = v[a] + v[b] + y
+	v[d] = bits.RotateLeft64(v[d]^v[a], -16)
+	v[c] = v[c] + v[d]
+	v[b] = bits.RotateLeft64(v[b]^v[c], -63)
+}
+
+var iv = [8]uint64{
+	0x6a09e667f3bcc908, 0xbb67ae8584caa73b, 0x3c6ef372fe94f82b, 0xa54ff53a5f1d36f1,
+	0x510e527fade682d1, 0x9b05688c2b3e6c1f, 0x1f83d9abfb41bd6b, 0x5be0cd19137e2179,
+}
+
+var sigma = [10][16]int{
+	{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15},
+	{14, 10, 4, 8, 9, 15, 13, 6, 1, 12, 0, 2, 11, 7, 5, 3},
+	{11, 8, 12, 0, 5, 2, 15, 13, 10, 14, 3, 6, 7, 1, 9, 4},
+	{7, 9, 3, 1, 13, 12, 11, 14, 2, 6, 5, 10, 4, 0, 15, 8},
+	{9, 0, 5, 7, 2, 4, 10, 15, 14, 1, 11, 12, 6, 8, 3, 13},
+	{2, 12, 6, 10, 0, 11, 8, 3, 4, 13, 7, 5, 15, 14, 1, 9},
+	{12, 5, 1, 15, 14, 13, 4, 10, 0, 7, 6, 3, 9, 2, 8, 11},
+	{13, 11, 7, 14, 12, 1, 3, 9, 5, 0, 15, 4, 8, 6, 2, 10},
+	{6, 15, 14, 9, 11, 3, 0, 8, 12, 2, 13, 7, 1, 4, 10, 5},
+	{10, 2, 8, 4, 7, 6, 1, 5, 15, 11, 9, 14, 3, 12, 13, 0},
+}
+
+func pushFillerToInput(tab [][]*big.Int, rng *rand.Rand, numRow int) {
+
+	maxValue := new(big.Int).Lsh(big.NewInt(1), 128)
+
+	for i := 0; i < numRow; i++ {
+		tab[0] = append(tab[0], new(big.Int).Rand(rng, maxValue))
+		tab[1] = append(tab[1], &big.Int{})
+		tab[2] = append(tab[2], &big.Int{})
+		tab[3] = append(tab[3], &big.Int{})
+	}
+}
+
+func pushBlake2fToInput(inst blake2fInstance, tab [][]*big.Int) {
+
+	var (
+		zero = &big.Int{}
+		one  = big.NewInt(1)
+		f    = &big.Int{}
+	)
+
+	if inst.f {
+		f = one
+	}
+
+	for i := 0; i < len(inst.data); i += 16 {
+		tab[0] = append(tab[0], new(big.Int).SetBytes(inst.data[i:i+16]))
+		tab[1] = append(tab[1], one)
+		tab[2] = append(tab[2], zero)
+		tab[3] = append(tab[3], zero)
+	}
+
+	for _, param := range []*big.Int{big.NewInt(int64(inst.rounds)), f} {
+		tab[0] = append(tab[0], param)
+		tab[1] = append(tab[1], zero)
+		tab[2] = append(tab[2], one)
+		tab[3] = append(tab[3], zero)
+	}
+
+	for i := 0; i < len(inst.result); i += 16 {
+		tab[0] = append(tab[0], new(big.Int).SetBytes(inst.result[i:i+16]))
+		tab[1] = append(tab[1], zero)
+		tab[2] = append(tab[2], zero)
+		tab[3] = append(tab[3], one)
+	}
+}
@@ -0,0 +1,526 @@
+package bls12381
+
+import (
+	"github.com/consensys/linea-monorepo/prover/maths/field"
+	"github.com/consensys/linea-monorepo/prover/protocol/column"
+	"github.com/consensys/linea-monorepo/prover/protocol/column/verifiercol"
+	"github.com/consensys/linea-monorepo/prover/protocol/dedicated"
+	"github.com/consensys/linea-monorepo/prover/protocol/dedicated/projection"
+	"github.com/consensys/linea-monorepo/prover/protocol/ifaces"
+	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
+	sym "github.com/consensys/linea-monorepo/prover/symbolic"
+	"github.com/consensys/linea-monorepo/prover/utils"
+	"github.com/consensys/linea-monorepo/prover/zkevm/prover/common"
+	common_constraints "github.com/consensys/linea-monorepo/prover/zkevm/prover/common/common_constraints"
+)
+
+func createColFn(comp *wizard.CompiledIOP, rootName string, size int) func(name string) ifaces.Column {
+	return func(name string) ifaces.Column {
+		return comp.InsertCommit(ROUND_NR, ifaces.ColIDf("%s_%s", rootName, name), size)
+	}
+}
+
+// accumulationSettings describes how the calls of an operation with a
+// variable number of inputs are split into steps. A call with n inputs is laid
+// out as n blocks:
+//
+//	[ACC_PREV, INPUT_1, ACC_CURR] ... [ACC_PREV, INPUT_n-1, ACC_CURR] [ACC_PREV, INPUT_n, RESULT]
+//
+// where the ACC_PREV of a block is the ACC_CURR of the previous one and the
+// ACC_PREV of the first block is the initial accumulator. The first kind of
+// blocks is sent to the main circuit and the last one to the final circuit,
+// which may be the same circuit.
+type accumulationSettings struct {
+	// name is used as a prefix for the columns and the queries
+	name string
+	// size of the columns
+	size int
+	// number of limbs of the accumulator, of an input and of the result
+	nbAccLimbs, nbInputLimbs, nbResultLimbs int
+	// initialAcc are the limbs of the accumulator at the start of a call
+	initialAcc []field.Element
+	// accumulate returns the limbs of the accumulator after processing input
+	accumulate func(acc, input []field.Element) []field.Element
+}
+
+func (s *accumulationSettings) nbRowsPerMainBlock() int {
+	return 2*s.nbAccLimbs + s.nbInputLimbs
+}
+
+func (s *accumulationSettings) nbRowsPerFinalBlock() int {
+	return s.nbAccLimbs + s.nbInputLimbs + s.nbResultLimbs
+}
+
+// UnalignedAccumulationData represents the unaligned columns of the
+// operations with a variable number of inputs, the MSMs and the pairing
+// check.
+//
+// As the calls are checked one input at a time, this module is responsible
+// for computing the intermediate accumulators and their consistency. It also
+// filters the limbs to be passed to the circuits checking the intermediate
+// and the last steps.
+type UnalignedAccumulationData struct {
+	IsActive          ifaces.Column
+	IsPulling         ifaces.Column
+	IsComputed        ifaces.Column
+	IsAccumulatorInit ifaces.Column
+	IsAccumulatorCurr ifaces.Column
+	IsAccumulatorPrev ifaces.Column
+
+	InstanceID  ifaces.Column
+	InputID     ifaces.Column
+	TotalInputs ifaces.Column
+	Limb        ifaces.Column
+	Index       ifaces.Column
+
+	ToMainCircuitMask  ifaces.Column
+	ToFinalCircuitMask ifaces.Column
+
+	IsFirstLineOfInstance        ifaces.Column
+	IsFirstLineOfPrevAccumulator ifaces.Column
+	IsFirstLineOfCurrAccumulator ifaces.Column
+
+	CptPrevEqualCurrID wizard.ProverAction
+
+	src      *MultiInputSource
+	settings accumulationSettings
+}
+
+// newUnalignedAccumulationData creates the columns and the constraints of the
+// unaligned data.
+func newUnalignedAccumulationData(comp *wizard.CompiledIOP, src *MultiInputSource, settings accumulationSettings) *UnalignedAccumulationData {
+	createCol := createColFn(comp, settings.name, settings.size)
+
+	res := &UnalignedAccumulationData{
+		IsActive:                     createCol("IS_ACTIVE"),
+		IsPulling:                    createCol("IS_PULLING"),
+		IsComputed:                   createCol("IS_COMPUTED"),
+		Limb:                         createCol("LIMB"),
+		InstanceID:                   createCol("INSTANCE_ID"),
+		InputID:                      createCol("INPUT_ID"),
+		TotalInputs:                  createCol("TOTAL_INPUTS"),
+		ToMainCircuitMask:            createCol("TO_MAIN_CIRCUIT"),
+		ToFinalCircuitMask:           createCol("TO_FINAL_CIRCUIT"),
+		IsFirstLineOfInstance:        createCol("IS_FIRST_LINE_OF_INSTANCE"),
+		IsFirstLineOfPrevAccumulator: createCol("IS_FIRST_LINE_OF_PREV_ACC"),
+		IsFirstLineOfCurrAccumulator: createCol("IS_FIRST_LINE_OF_CURR_ACC"),
+		IsAccumulatorPrev:            createCol("IS_ACCUMULATOR_PREV"),
+		IsAccumulatorCurr:            createCol("IS_ACCUMULATOR_CURR"),
+		IsAccumulatorInit:            createCol("IS_ACCUMULATOR_INIT"),
+		Index:                        createCol("INDEX"),
+		src:                          src,
+		settings:                     settings,
+	}
+
+	// IsActive activation - can only go from 1 to {0, 1} and from 0 to 0.
+	common_constraints.MustBeActivationColumns(comp, res.IsActive)
+	// masks and flags are binary
+	res.csBinaryConstraints(comp)
+	// IsActive is only active when we are either pulling or computing
+	res.csFlagConsistency(comp)
+	// when not active, then all values are zero.
+	res.csOffWhenInactive(comp)
+	// projection from the arithmetization
+	res.csProjection(comp)
+
+	res.csConstantWhenIsComputing(comp)
+	res.csInstanceIDChangeWhenNewInstance(comp)
+	res.csAccumulatorInit(comp)
+	res.csAccumulatorConsistency(comp)
+	res.csLastInputToFinalCircuit(comp)
+	res.csIndexConsistency(comp)
+	res.csAccumulatorMask(comp)
+	// only to the main or to the final circuit
+	common_constraints.MustBeMutuallyExclusiveBinaryFlags(comp, res.IsActive, []ifaces.Column{
+		res.ToMainCircuitMask,
+		res.ToFinalCircuitMask,
+	})
+
+	return res
+}
+
+func (d *UnalignedAccumulationData) csBinaryConstraints(comp *wizard.CompiledIOP) {
+	common_constraints.MustBeBinary(comp, d.IsFirstLineOfInstance)
+	common_constraints.MustBeBinary(comp, d.IsFirstLineOfPrevAccumulator)
+	common_constraints.MustBeBinary(comp, d.IsFirstLineOfCurrAccumulator)
+}
+
+func (d *UnalignedAccumulationData) csFlagConsistency(comp *wizard.CompiledIOP) {
+	// flag consistency. That the assigned data is pulled from input or computed.
+	common_constraints.MustBeMutuallyExclusiveBinaryFlags(comp, d.IsActive, []ifaces.Column{
+		d.IsPulling,
+		d.IsComputed,
+	})
+}
+
+func (d *UnalignedAccumulationData) csOffWhenInactive(comp *wizard.CompiledIOP) {
+	// nothing is set when inactive
+	common_constraints.MustZeroWhenInactive(comp, d.IsActive,
+		d.InstanceID,
+		d.InputID,
+		d.TotalInputs,
+		d.Limb,
+		d.Index,
+		d.IsFirstLineOfInstance,
+		d.IsFirstLineOfPrevAccumulator,
+		d.IsFirstLineOfCurrAccumulator,
+	)
+}
+
+func (d *UnalignedAccumulationData) csProjection(comp *wizard.CompiledIOP) {
+	// we project data from the arithmetization correctly to the unaligned part of the circuit
+	projection.InsertProjection(
+		comp, ifaces.QueryIDf("%v_PROJECTION", d.settings.name),
+		[]ifaces.Column{d.src.Limb, d.src.AccInputs, d.src.TotalInputs, d.src.ID},
+		[]ifaces.Column{d.Limb, d.InputID, d.TotalInputs, d.InstanceID},
+		d.src.CsSelector,
+		d.IsPulling,
+	)
+}
+
+func (d *UnalignedAccumulationData) csConstantWhenIsComputing(comp *wizard.CompiledIOP) {
+	// IF IS_COMPUTING AND IS_ACTIVE AND NOT FIRST_LINE => INPUT_ID_{i} = INPUT_ID_{i-1} AND TOTAL_INPUTS_{i} = TOTAL_INPUTS_{i-1}
+	comp.InsertGlobal(
+		ROUND_NR,
+		ifaces.QueryIDf("%v_COUNTERS_CONSISTENCY", d.settings.name),
+		sym.Mul(
+			d.IsActive,
+			d.IsComputed,
+			sym.Sub(1, d.IsFirstLineOfInstance),
+			sym.Sub(d.InputID, column.Shift(d.InputID, -1)),
+			sym.Sub(d.TotalInputs, column.Shift(d.TotalInputs, -1)),
+		),
+	)
+}
+
+func (d *UnalignedAccumulationData) csInstanceIDChangeWhenNewInstance(comp *wizard.CompiledIOP) {
+	// when we are at the first line of the new instance then the instance ID
+	// should change
+	prevEqualCurrID, cptPrevEqualCurrID := dedicated.IsZero(
+		comp,
+		sym.Sub(d.InstanceID, column.Shift(d.InstanceID, -1)),
+	)
+
+	d.CptPrevEqualCurrID = cptPrevEqualCurrID
+
+	// IF IS_ACTIVE AND FIRST_LINE AND INSTANCE_ID != 0 => INSTANCE_ID_{i} != INSTANCE_ID_{i-1}
+	// And the constraint does not apply on the first row.
+	comp.InsertGlobal(
+		ROUND_NR,
+		ifaces.QueryIDf("%v_INSTANCE_ID_CHANGE", d.settings.name),
+		sym.Mul(
+			column.Shift(verifiercol.NewConstantCol(field.One(), d.IsActive.Size()), -1), // cancels the constraint on the first row
+			d.IsActive,
+			d.IsFirstLineOfInstance,
+			d.InstanceID,
+			prevEqualCurrID,
+		),
+	)
+}
+
+func (d *UnalignedAccumulationData) csAccumulatorInit(comp *wizard.CompiledIOP) {
+	// The accumulator is set to the initial value at the start of each
+	// instance. We constrain every limb separately as the initial accumulator
+	// may have several non-zero limbs.
+	for i := 0; i < d.settings.nbAccLimbs; i++ {
+		comp.InsertGlobal(
+			ROUND_NR,
+			ifaces.QueryIDf("%v_ACCUMULATOR_INIT_%v", d.settings.name, i),
+			sym.Mul(
+				d.IsActive,
+				d.IsFirstLineOfInstance,
+				sym.Sub(column.Shift(d.Limb, i), d.settings.initialAcc[i]),
+			),
+		)
+	}
+}
+
+func (d *UnalignedAccumulationData) csAccumulatorConsistency(comp *wizard.CompiledIOP) {
+	// that the accumulator between inputs is consistent
+	projection.InsertProjection(
+		comp,
+		ifaces.QueryIDf("%v_ACCUMULATOR_CONSISTENCY", d.settings.name),
+		[]ifaces.Column{d.Limb}, []ifaces.Column{d.Limb},
+		d.IsAccumulatorCurr, d.IsAccumulatorPrev,
+	)
+}
+
+func (d *UnalignedAccumulationData) csLastInputToFinalCircuit(comp *wizard.CompiledIOP) {
+	// IF IS_ACTIVE AND TO_FINAL_CIRCUIT_MASK => INPUT_ID == TOTAL_INPUTS OR INPUT_ID == 0
+	comp.InsertGlobal(
+		ROUND_NR,
+		ifaces.QueryIDf("%v_LAST_INPUT_TO_FINAL_CIRCUIT", d.settings.name),
+		sym.Mul(
+			d.IsActive,
+			sym.Sub(d.InputID, d.TotalInputs),
+			d.InputID,
+			d.ToFinalCircuitMask,
+		),
+	)
+}
+
+func (d *UnalignedAccumulationData) csIndexConsistency(comp *wizard.CompiledIOP) {
+	// index switches to zero when the first line of new instance. Otherwise increases
+	comp.InsertGlobal(
+		ROUND_NR,
+		ifaces.QueryIDf("%v_INDEX_START", d.settings.name),
+		sym.Mul(
+			d.IsActive,
+			d.IsFirstLineOfInstance,
+			d.Index,
+		),
+	)
+	comp.InsertGlobal(
+		ROUND_NR,
+		ifaces.QueryIDf("%v_INDEX_INCREMENT", d.settings.name),
+		sym.Mul(
+			d.IsActive,
+			sym.Sub(1, d.IsFirstLineOfInstance),
+			sym.Sub(d.Index, column.Shift(d.Index, -1), 1),
+		),
+	)
+}
+
+func (d *UnalignedAccumulationData) csAccumulatorMask(comp *wizard.CompiledIOP) {
+	var (
+		nbAccLimbs   = d.settings.nbAccLimbs
+		nbBlockLimbs = d.settings.nbRowsPerMainBlock()
+	)
+
+	// accumulator sum is IS_COMPUTED
+	common_constraints.MustBeMutuallyExclusiveBinaryFlags(comp, d.IsComputed, []ifaces.Column{
+		d.IsAccumulatorCurr,
+		d.IsAccumulatorPrev,
+		d.IsAccumulatorInit,
+	})
+
+	// first prev accumulator starts at index (inputID-1) * nbBlockLimbs
+	comp.InsertGlobal(
+		ROUND_NR,
+		ifaces.QueryIDf("%v_FIRST_ACC_PREV", d.settings.name),
+		sym.Mul(
+			d.IsActive,
+			d.IsFirstLineOfPrevAccumulator,
+			sym.Sub(
+				sym.Mul(nbBlockLimbs, sym.Sub(d.InputID, 1)),
+				d.Index,
+			),
+		),
+	)
+
+	// first curr accumulator starts at index inputID * nbBlockLimbs - nbAccLimbs
+	comp.InsertGlobal(
+		ROUND_NR,
+		ifaces.QueryIDf("%v_FIRST_ACC_CURR", d.settings.name),
+		sym.Mul(
+			d.IsActive,
+			d.IsFirstLineOfCurrAccumulator,
+			sym.Sub(
+				sym.Mul(nbBlockLimbs, d.InputID),
+				d.Index,
+				nbAccLimbs,
+			),
+		),
+	)
+
+	sumMask := func(col ifaces.Column) *sym.Expression {
+		r := sym.NewConstant(0)
+		for i := 0; i < nbAccLimbs; i++ {
+			r = sym.Add(r, column.Shift(col, i))
+		}
+		return r
+	}
+	// init accumulator mask is 1 when at the start of the instance
+	comp.InsertGlobal(
+		ROUND_NR,
+		ifaces.QueryIDf("%v_INIT_ACC_MASK", d.settings.name),
+		sym.Mul(
+			d.IsActive,
+			d.IsFirstLineOfInstance,
+			sym.Sub(nbAccLimbs, sumMask(d.IsAccumulatorInit)),
+		),
+	)
+
+	// curr accumulator mask is 1 when at the end of the input
+	comp.InsertGlobal(
+		ROUND_NR,
+		ifaces.QueryIDf("%v_CURR_ACC_MASK", d.settings.name),
+		sym.Mul(
+			d.IsActive,
+			d.IsFirstLineOfCurrAccumulator,
+			sym.Sub(nbAccLimbs, sumMask(d.IsAccumulatorCurr)),
+		),
+	)
+
+	// prev accumulator mask is 1 when at the start of the input
+	comp.InsertGlobal(
+		ROUND_NR,
+		ifaces.QueryIDf("%v_PREV_ACC_MASK", d.settings.name),
+		sym.Mul(
+			d.IsActive,
+			d.IsFirstLineOfPrevAccumulator,
+			sym.Sub(nbAccLimbs, sumMask(d.IsAccumulatorPrev)),
+		),
+	)
+}
+
+// assign assigns the unaligned data from the source and returns the number of
+// blocks sent to the main and to the final circuit. The caller is responsible
+// for checking them against the limits.
+func (d *UnalignedAccumulationData) assign(run *wizard.ProverRuntime) (nbMainBlocks, nbFinalBlocks int) {
+	var (
+		srcSelector = d.src.CsSelector.GetColAssignment(run).IntoRegVecSaveAlloc()
+		srcLimbs    = d.src.Limb.GetColAssignment(run).IntoRegVecSaveAlloc()
+		srcIsRes    = d.src.IsRes.GetColAssignment(run).IntoRegVecSaveAlloc()
+		srcID       = d.src.ID.GetColAssignment(run).IntoRegVecSaveAlloc()
+
+		nbAccLimbs    = d.settings.nbAccLimbs
+		nbInputLimbs  = d.settings.nbInputLimbs
+		nbResultLimbs = d.settings.nbResultLimbs
+	)
+	if len(srcSelector) != len(srcLimbs) || len(srcSelector) != len(srcIsRes) || len(srcSelector) != len(srcID) {
+		utils.Panic("%v: input length mismatch", d.settings.name)
+	}
+
+	var (
+		dstIsActive    = common.NewVectorBuilder(d.IsActive)
+		dstLimb        = common.NewVectorBuilder(d.Limb)
+		dstToMain      = common.NewVectorBuilder(d.ToMainCircuitMask)
+		dstToFinal     = common.NewVectorBuilder(d.ToFinalCircuitMask)
+		dstIsPulling   = common.NewVectorBuilder(d.IsPulling)
+		dstIsComputed  = common.NewVectorBuilder(d.IsComputed)
+		dstInputID     = common.NewVectorBuilder(d.InputID)
+		dstTotalInputs = common.NewVectorBuilder(d.TotalInputs)
+		dstIsFirstLine = common.NewVectorBuilder(d.IsFirstLineOfInstance)
+		dstInstanceID  = common.NewVectorBuilder(d.InstanceID)
+		dstIsAccPrev   = common.NewVectorBuilder(d.IsAccumulatorPrev)
+		dstIsAccCurr   = common.NewVectorBuilder(d.IsAccumulatorCurr)
+		dstIsAccInit   = common.NewVectorBuilder(d.IsAccumulatorInit)
+		dstIndex       = common.NewVectorBuilder(d.Index)
+		dstIsFirstPrev = common.NewVectorBuilder(d.IsFirstLineOfPrevAccumulator)
+		dstIsFirstCurr = common.NewVectorBuilder(d.IsFirstLineOfCurrAccumulator)
+	)
+
+	// pushRow pushes a row which is not specific to the accumulator
+	pushRow := func(limb field.Element, isPulling bool, toFinal bool, inputID, totalInputs int) {
+		dstLimb.PushField(limb)
+		dstIsActive.PushOne()
+		dstIsPulling.PushBoolean(isPulling)
+		dstIsComputed.PushBoolean(!isPulling)
+		dstToMain.PushBoolean(!toFinal)
+		dstToFinal.PushBoolean(toFinal)
+		dstInputID.PushInt(inputID)
+		dstTotalInputs.PushInt(totalInputs)
+	}
+
+	for currPos := 0; currPos < len(srcLimbs); {
+		// we need to check if the current position is the start of a call. If
+		// not, then skip it.
+		if srcSelector[currPos].IsZero() {
+			currPos++
+			continue
+		}
+		// we iterate over the data to get the number of inputs. There is
+		// always at least one input.
+		nbInputs := 1
+		for !srcIsRes[currPos+nbInputs*nbInputLimbs].IsOne() {
+			nbInputs++
+		}
+
+		var (
+			instanceID = srcID[currPos]
+			acc        = d.settings.initialAcc
+			nbRows     = 0
+		)
+
+		for i := 0; i < nbInputs; i++ {
+			var (
+				input   = srcLimbs[currPos+i*nbInputLimbs : currPos+(i+1)*nbInputLimbs]
+				isFinal = i == nbInputs-1
+			)
+
+			// previous accumulator
+			for j := 0; j < nbAccLimbs; j++ {
+				pushRow(acc[j], false, isFinal, i+1, nbInputs)
+				dstIsAccInit.PushBoolean(i == 0)
+				dstIsAccPrev.PushBoolean(i > 0)
+				dstIsFirstPrev.PushBoolean(i > 0 && j == 0)
+				dstIsAccCurr.PushZero()
+				dstIsFirstCurr.PushZero()
+			}
+
+			// input
+			for j := 0; j < nbInputLimbs; j++ {
+				pushRow(input[j], true, isFinal, i+1, nbInputs)
+				dstIsAccInit.PushZero()
+				dstIsAccPrev.PushZero()
+				dstIsFirstPrev.PushZero()
+				dstIsAccCurr.PushZero()
+				dstIsFirstCurr.PushZero()
+			}
+
+			if isFinal {
+				// result pulled from the source
+				resPos := currPos + nbInputs*nbInputLimbs
+				for j := 0; j < nbResultLimbs; j++ {
+					pushRow(srcLimbs[resPos+j], true, true, 0, nbInputs)
+					dstIsAccInit.PushZero()
+					dstIsAccPrev.PushZero()
+					dstIsFirstPrev.PushZero()
+					dstIsAccCurr.PushZero()
+					dstIsFirstCurr.PushZero()
+				}
+				nbRows += d.settings.nbRowsPerFinalBlock()
+				nbFinalBlocks++
+				continue
+			}
+
+			// current accumulator
+			acc = d.settings.accumulate(acc, input)
+			for j := 0; j < nbAccLimbs; j++ {
+				pushRow(acc[j], false, false, i+1, nbInputs)
+				dstIsAccInit.PushZero()
+				dstIsAccPrev.PushZero()
+				dstIsFirstPrev.PushZero()
+				dstIsAccCurr.PushOne()
+				dstIsFirstCurr.PushBoolean(j == 0)
+			}
+			nbRows += d.settings.nbRowsPerMainBlock()
+			nbMainBlocks++
+		}
+
+		for i := 0; i < nbRows; i++ {
+			dstIsFirstLine.PushBoolean(i == 0)
+			dstInstanceID.PushField(instanceID)
+			dstIndex.PushInt(i)
+		}
+
+		currPos += nbInputs*nbInputLimbs + nbResultLimbs
+	}
+
+	if dstIsActive.Height() > d.settings.size {
+		// the caller reports the limit overflow with more details
+		return nbMainBlocks, nbFinalBlocks
+	}
+
+	dstIsActive.PadAndAssign(run, field.Zero())
+	dstLimb.PadAndAssign(run, field.Zero())
+	dstInputID.PadAndAssign(run, field.Zero())
+	dstTotalInputs.PadAndAssign(run, field.Zero())
+	dstToMain.PadAndAssign(run, field.Zero())
+	dstToFinal.PadAndAssign(run, field.Zero())
+	dstIsPulling.PadAndAssign(run, field.Zero())
+	dstIsComputed.PadAndAssign(run, field.Zero())
+	dstIsFirstLine.PadAndAssign(run, field.Zero())
+	dstInstanceID.PadAndAssign(run, field.Zero())
+	dstIsAccInit.PadAndAssign(run, field.Zero())
+	dstIsAccPrev.PadAndAssign(run, field.Zero())
+	dstIsAccCurr.PadAndAssign(run, field.Zero())
+	dstIndex.PadAndAssign(run, field.Zero())
+	dstIsFirstPrev.PadAndAssign(run, field.Zero())
+	dstIsFirstCurr.PadAndAssign(run, field.Zero())
+
+	d.CptPrevEqualCurrID.Run(run)
+
+	return nbMainBlocks, nbFinalBlocks
+}
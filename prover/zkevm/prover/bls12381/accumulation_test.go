@@ -0,0 +1,80 @@
+package bls12381
+
+import (
+	"testing"
+
+	"github.com/consensys/linea-monorepo/prover/protocol/compiler/dummy"
+	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
+	"github.com/consensys/linea-monorepo/prover/utils/csvtraces"
+)
+
+type accumulationTestCase struct {
+	InputFName, Selector        string
+	Settings                    accumulationSettings
+	NbMainBlocks, NbFinalBlocks int
+}
+
+var accumulationTestCases = []accumulationTestCase{
+	{
+		// a single input and three inputs
+		InputFName:    "testdata/g1msm_test.csv",
+		Selector:      "CS_G1_MSM",
+		Settings:      g1MsmSettings(&Limits{NbInputInstances: 4, NbCircuitInstances: 1}),
+		NbMainBlocks:  2,
+		NbFinalBlocks: 2,
+	},
+	{
+		// two checks of two pairs and a check of a single pair
+		InputFName: "testdata/pairing_test.csv",
+		Selector:   "CS_PAIRING_CHECK",
+		Settings: pairingSettings(&PairingLimits{
+			NbMillerLoopInputInstances: 2,
+			NbMillerLoopCircuits:       1,
+			NbFinalExpInputInstances:   3,
+			NbFinalExpCircuits:         1,
+		}),
+		NbMainBlocks:  2,
+		NbFinalBlocks: 3,
+	},
+}
+
+func TestAccumulation(t *testing.T) {
+	for _, tc := range accumulationTestCases {
+		t.Run(tc.InputFName, func(t *testing.T) {
+
+			var (
+				ct                          = csvtraces.MustOpenCsvFile(tc.InputFName)
+				unaligned                   *UnalignedAccumulationData
+				nbMainBlocks, nbFinalBlocks int
+			)
+
+			cmp := wizard.Compile(func(b *wizard.Builder) {
+				src := &MultiInputSource{
+					ID:          ct.GetCommit(b, "ID"),
+					CsSelector:  ct.GetCommit(b, tc.Selector),
+					Limb:        ct.GetCommit(b, "LIMB"),
+					Index:       ct.GetCommit(b, "INDEX"),
+					AccInputs:   ct.GetCommit(b, "ACC_INPUTS"),
+					TotalInputs: ct.GetCommit(b, "TOTAL_INPUTS"),
+					IsData:      ct.GetCommit(b, "IS_DATA"),
+					IsRes:       ct.GetCommit(b, "IS_RES"),
+				}
+				unaligned = newUnalignedAccumulationData(b.CompiledIOP, src, tc.Settings)
+			}, dummy.Compile)
+
+			proof := wizard.Prove(cmp, func(run *wizard.ProverRuntime) {
+				ct.Assign(run, "ID", tc.Selector, "LIMB", "INDEX", "ACC_INPUTS", "TOTAL_INPUTS", "IS_DATA", "IS_RES")
+				nbMainBlocks, nbFinalBlocks = unaligned.assign(run)
+			})
+
+			if err := wizard.Verify(cmp, proof); err != nil {
+				t.Fatal("proof failed", err)
+			}
+
+			if nbMainBlocks != tc.NbMainBlocks || nbFinalBlocks != tc.NbFinalBlocks {
+				t.Fatalf("unexpected number of blocks: got (%v, %v), expected (%v, %v)",
+					nbMainBlocks, nbFinalBlocks, tc.NbMainBlocks, tc.NbFinalBlocks)
+			}
+		})
+	}
+}
@@ -0,0 +1,335 @@
+package bls12381
+
+import (
+	"math/big"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/emulated/fields_bls12381"
+	"github.com/consensys/gnark/std/algebra/emulated/sw_bls12381"
+	"github.com/consensys/gnark/std/math/emulated"
+)
+
+// thirdRootOne is the primitive cube root of unity of Fp defining the
+// endomorphisms ϕ(x, y) = (thirdRootOne * x, y) of E(Fp) and ψ² of E'(Fp2).
+const thirdRootOne = "4002409555221667392624310435006688643935503118305586438271171395842971157480381377015405980053539358417135540939436"
+
+// seed is the absolute value of the seed x = -0xd201000000010000 of
+// BLS12-381 and seedSquare is its square.
+var (
+	seed       = new(big.Int).SetUint64(0xd201000000010000)
+	seedSquare = new(big.Int).Mul(seed, seed)
+)
+
+// g2Affine is a point of the twist E'(Fp2) in affine coordinates. The point at
+// infinity is represented as (0, 0). We use our own type as gnark does not
+// expose the arithmetic over the twist.
+type g2Affine struct {
+	X, Y fields_bls12381.E2
+}
+
+// toGnark converts the point into its gnark representation for using it with
+// the pairing API.
+func (p *g2Affine) toGnark() *sw_bls12381.G2Affine {
+	var res sw_bls12381.G2Affine
+	res.P.X = p.X
+	res.P.Y = p.Y
+	return &res
+}
+
+// g1Arith implements complete arithmetic over E(Fp). The formulas of gnark
+// are not used for the addition as they do not handle the case where the
+// y-coordinates of the operands are opposite for distinct x-coordinates.
+type g1Arith struct {
+	api frontend.API
+	fp  *emulated.Field[baseField]
+}
+
+func newG1Arith(api frontend.API, fp *emulated.Field[baseField]) *g1Arith {
+	return &g1Arith{api: api, fp: fp}
+}
+
+// isInfinity returns 1 if p is the point at infinity and 0 otherwise.
+func (g *g1Arith) isInfinity(p *sw_bls12381.G1Affine) frontend.Variable {
+	return g.api.And(g.fp.IsZero(&p.X), g.fp.IsZero(&p.Y))
+}
+
+// selectPoint returns p if sel is 1 and q otherwise.
+func (g *g1Arith) selectPoint(sel frontend.Variable, p, q *sw_bls12381.G1Affine) *sw_bls12381.G1Affine {
+	return &sw_bls12381.G1Affine{
+		X: *g.fp.Select(sel, &p.X, &q.X),
+		Y: *g.fp.Select(sel, &p.Y, &q.Y),
+	}
+}
+
+// add returns p + q. The function supports every combination of points of
+// the curve including the point at infinity.
+func (g *g1Arith) add(p, q *sw_bls12381.G1Affine) *sw_bls12381.G1Affine {
+
+	var (
+		fp    = g.fp
+		pInf  = g.isInfinity(p)
+		qInf  = g.isInfinity(q)
+		xEq   = fp.IsZero(fp.Sub(&q.X, &p.X))
+		yEq   = fp.IsZero(fp.Sub(&q.Y, &p.Y))
+		isDbl = g.api.And(xEq, yEq)
+		// p = -q. The curve has no point of order 2, so this can only
+		// happen when the y-coordinates differ.
+		isOpp = g.api.Sub(xEq, isDbl)
+	)
+
+	// λ = 3x²/2y when doubling and (q.y - p.y)/(q.x - p.x) otherwise. The
+	// denominator is replaced by 1 in the cases where the result does not
+	// depend on it.
+	num := fp.Select(isDbl, fp.MulConst(fp.Mul(&p.X, &p.X), big.NewInt(3)), fp.Sub(&q.Y, &p.Y))
+	den := fp.Select(isDbl, fp.MulConst(&p.Y, big.NewInt(2)), fp.Sub(&q.X, &p.X))
+	den = fp.Select(fp.IsZero(den), fp.One(), den)
+	lambda := fp.Div(num, den)
+
+	x := fp.Sub(fp.Mul(lambda, lambda), fp.Add(&p.X, &q.X))
+	y := fp.Sub(fp.Mul(lambda, fp.Sub(&p.X, x)), &p.Y)
+
+	res := &sw_bls12381.G1Affine{X: *fp.Reduce(x), Y: *fp.Reduce(y)}
+	res = g.selectPoint(isOpp, &sw_bls12381.G1Affine{X: *fp.Zero(), Y: *fp.Zero()}, res)
+	res = g.selectPoint(pInf, q, res)
+	res = g.selectPoint(qInf, p, res)
+	return res
+}
+
+// double returns 2p. p must be a point of the curve, possibly the point at
+// infinity.
+func (g *g1Arith) double(p *sw_bls12381.G1Affine) *sw_bls12381.G1Affine {
+
+	var (
+		fp   = g.fp
+		pInf = g.isInfinity(p)
+		den  = fp.Select(pInf, fp.One(), fp.MulConst(&p.Y, big.NewInt(2)))
+	)
+
+	lambda := fp.Div(fp.MulConst(fp.Mul(&p.X, &p.X), big.NewInt(3)), den)
+	x := fp.Sub(fp.Mul(lambda, lambda), fp.MulConst(&p.X, big.NewInt(2)))
+	y := fp.Sub(fp.Mul(lambda, fp.Sub(&p.X, x)), &p.Y)
+
+	res := &sw_bls12381.G1Affine{X: *fp.Reduce(x), Y: *fp.Reduce(y)}
+	return g.selectPoint(pInf, &sw_bls12381.G1Affine{X: *fp.Zero(), Y: *fp.Zero()}, res)
+}
+
+// isOnCurve returns 1 if p is on the curve y² = x³ + 4 or is the point at
+// infinity and 0 otherwise.
+func (g *g1Arith) isOnCurve(p *sw_bls12381.G1Affine) frontend.Variable {
+	var (
+		fp = g.fp
+		b  = fp.Select(g.isInfinity(p), fp.Zero(), fp.NewElement(4))
+	)
+	return fp.IsZero(fp.Sub(fp.Mul(&p.Y, &p.Y), fp.Add(fp.Mul(fp.Mul(&p.X, &p.X), &p.X), b)))
+}
+
+// isInSubgroup returns 1 if p, which must be on the curve, is in G1 and 0
+// otherwise. As in [sw_bls12381.Pairing.AssertIsOnG1], this is the case iff
+// p = -[x²]ϕ(p). The complete arithmetic is used so that the check is
+// defined for all the points of the curve.
+func (g *g1Arith) isInSubgroup(p *sw_bls12381.G1Affine) frontend.Variable {
+	var (
+		fp   = g.fp
+		w    = emulated.ValueOf[baseField](thirdRootOne)
+		phiP = &sw_bls12381.G1Affine{X: *fp.Mul(&p.X, &w), Y: p.Y}
+		q    = g.scalarMulConst(phiP, seedSquare)
+	)
+	return g.api.And(fp.IsZero(fp.Sub(&q.X, &p.X)), fp.IsZero(fp.Add(&q.Y, &p.Y)))
+}
+
+// scalarMulConst returns [s]p for a constant s > 0 using double-and-add.
+func (g *g1Arith) scalarMulConst(p *sw_bls12381.G1Affine, s *big.Int) *sw_bls12381.G1Affine {
+	res := p
+	for i := s.BitLen() - 2; i >= 0; i-- {
+		res = g.double(res)
+		if s.Bit(i) == 1 {
+			res = g.add(res, p)
+		}
+	}
+	return res
+}
+
+// g2Arith implements complete arithmetic over the twist E'(Fp2).
+type g2Arith struct {
+	api  frontend.API
+	ext2 *fields_bls12381.Ext2
+	// psiX and psiY are the constants of the untwist-Frobenius-twist
+	// endomorphism ψ(x, y) = (psiX * conj(x), psiY * conj(y)).
+	psiX, psiY *fields_bls12381.E2
+	// thirdRootOne is the constant such that ψ²(x, y) = (thirdRootOne * x, -y).
+	thirdRootOne *baseEl
+}
+
+func newG2Arith(api frontend.API) *g2Arith {
+	var (
+		psiX = fields_bls12381.E2{
+			A0: emulated.ValueOf[baseField](0),
+			A1: emulated.ValueOf[baseField]("4002409555221667392624310435006688643935503118305586438271171395842971157480381377015405980053539358417135540939437"),
+		}
+		psiY = fields_bls12381.E2{
+			A0: emulated.ValueOf[baseField]("2973677408986561043442465346520108879172042883009249989176415018091420807192182638567116318576472649347015917690530"),
+			A1: emulated.ValueOf[baseField]("1028732146235106349975324479215795277384839936929757896155643118032610843298655225875571310552543014690878354869257"),
+		}
+		thirdRootOneEl = emulated.ValueOf[baseField](thirdRootOne)
+	)
+	return &g2Arith{
+		api:          api,
+		ext2:         fields_bls12381.NewExt2(api),
+		psiX:         &psiX,
+		psiY:         &psiY,
+		thirdRootOne: &thirdRootOneEl,
+	}
+}
+
+// infinity returns the point at infinity. The coordinates are built with
+// explicit limbs as the constant zero of the emulated field has none and
+// cannot be tested with IsZero.
+func (g *g2Arith) infinity() *g2Affine {
+	return &g2Affine{X: *fp2Const([2]int64{0, 0}), Y: *fp2Const([2]int64{0, 0})}
+}
+
+// isInfinity returns 1 if p is the point at infinity and 0 otherwise.
+func (g *g2Arith) isInfinity(p *g2Affine) frontend.Variable {
+	return g.api.And(g.ext2.IsZero(&p.X), g.ext2.IsZero(&p.Y))
+}
+
+// selectPoint returns p if sel is 1 and q otherwise.
+func (g *g2Arith) selectPoint(sel frontend.Variable, p, q *g2Affine) *g2Affine {
+	return &g2Affine{
+		X: *g.ext2.Select(sel, &p.X, &q.X),
+		Y: *g.ext2.Select(sel, &p.Y, &q.Y),
+	}
+}
+
+// assertIsEqual asserts that p and q are equal.
+func (g *g2Arith) assertIsEqual(p, q *g2Affine) {
+	g.ext2.AssertIsEqual(&p.X, &q.X)
+	g.ext2.AssertIsEqual(&p.Y, &q.Y)
+}
+
+// assertIsOnTwist asserts that p is on the twist y² = x³ + 4(1+u) or is the
+// point at infinity.
+func (g *g2Arith) assertIsOnTwist(p *g2Affine) {
+	var (
+		e      = g.ext2
+		bTwist = fp2Const([2]int64{4, 4})
+		b      = e.Select(g.isInfinity(p), e.Zero(), bTwist)
+	)
+	e.AssertIsEqual(e.Square(&p.Y), e.Add(e.Mul(e.Square(&p.X), &p.X), b))
+}
+
+// isOnTwist returns 1 if p is on the twist y² = x³ + 4(1+u) or is the point
+// at infinity and 0 otherwise.
+func (g *g2Arith) isOnTwist(p *g2Affine) frontend.Variable {
+	var (
+		e      = g.ext2
+		bTwist = fp2Const([2]int64{4, 4})
+		b      = e.Select(g.isInfinity(p), e.Zero(), bTwist)
+	)
+	return e.IsZero(e.Sub(e.Square(&p.Y), e.Add(e.Mul(e.Square(&p.X), &p.X), b)))
+}
+
+// isInSubgroup returns 1 if p, which must be on the twist, is in G2 and 0
+// otherwise. As in [sw_bls12381.Pairing.AssertIsOnG2], this is the case iff
+// ψ(p) = [x]p. As x is negative, we check that [|x|]p = -ψ(p).
+func (g *g2Arith) isInSubgroup(p *g2Affine) frontend.Variable {
+	var (
+		e    = g.ext2
+		xp   = g.scalarMulConst(p, seed)
+		psiP = g.psi(p)
+	)
+	return g.api.And(e.IsZero(e.Sub(&xp.X, &psiP.X)), e.IsZero(e.Add(&xp.Y, &psiP.Y)))
+}
+
+// neg returns -p.
+func (g *g2Arith) neg(p *g2Affine) *g2Affine {
+	return &g2Affine{X: p.X, Y: *g.ext2.Neg(&p.Y)}
+}
+
+// add returns p + q. The function supports every combination of points of
+// the curve including the point at infinity.
+func (g *g2Arith) add(p, q *g2Affine) *g2Affine {
+
+	var (
+		e     = g.ext2
+		pInf  = g.isInfinity(p)
+		qInf  = g.isInfinity(q)
+		xEq   = e.IsZero(e.Sub(&q.X, &p.X))
+		yEq   = e.IsZero(e.Sub(&q.Y, &p.Y))
+		isDbl = g.api.And(xEq, yEq)
+		// p = -q. The twist has no point of order 2, so this can only
+		// happen when the y-coordinates differ.
+		isOpp = g.api.Sub(xEq, isDbl)
+	)
+
+	num := e.Select(isDbl, e.MulByConstElement(e.Square(&p.X), big.NewInt(3)), e.Sub(&q.Y, &p.Y))
+	den := e.Select(isDbl, e.Double(&p.Y), e.Sub(&q.X, &p.X))
+	den = e.Select(e.IsZero(den), e.One(), den)
+	lambda := e.DivUnchecked(num, den)
+
+	x := e.Sub(e.Square(lambda), e.Add(&p.X, &q.X))
+	y := e.Sub(e.Mul(lambda, e.Sub(&p.X, x)), &p.Y)
+
+	res := &g2Affine{X: *x, Y: *y}
+	res = g.selectPoint(isOpp, g.infinity(), res)
+	res = g.selectPoint(pInf, q, res)
+	res = g.selectPoint(qInf, p, res)
+	return res
+}
+
+// double returns 2p. p must be a point of the twist, possibly the point at
+// infinity.
+func (g *g2Arith) double(p *g2Affine) *g2Affine {
+
+	var (
+		e    = g.ext2
+		pInf = g.isInfinity(p)
+		den  = e.Select(pInf, e.One(), e.Double(&p.Y))
+	)
+
+	lambda := e.DivUnchecked(e.MulByConstElement(e.Square(&p.X), big.NewInt(3)), den)
+	x := e.Sub(e.Square(lambda), e.Double(&p.X))
+	y := e.Sub(e.Mul(lambda, e.Sub(&p.X, x)), &p.Y)
+
+	return g.selectPoint(pInf, g.infinity(), &g2Affine{X: *x, Y: *y})
+}
+
+// scalarMul returns [s]p where s is given by its bits in little-endian order.
+func (g *g2Arith) scalarMul(p *g2Affine, bits []frontend.Variable) *g2Affine {
+	res := g.infinity()
+	for i := len(bits) - 1; i >= 0; i-- {
+		if i < len(bits)-1 {
+			res = g.double(res)
+		}
+		res = g.selectPoint(bits[i], g.add(res, p), res)
+	}
+	return res
+}
+
+// scalarMulConst returns [s]p for a constant s > 0 using double-and-add.
+func (g *g2Arith) scalarMulConst(p *g2Affine, s *big.Int) *g2Affine {
+	res := p
+	for i := s.BitLen() - 2; i >= 0; i-- {
+		res = g.double(res)
+		if s.Bit(i) == 1 {
+			res = g.add(res, p)
+		}
+	}
+	return res
+}
+
+// psi returns ψ(p), the untwist-Frobenius-twist endomorphism.
+func (g *g2Arith) psi(p *g2Affine) *g2Affine {
+	return &g2Affine{
+		X: *g.ext2.Mul(g.ext2.Conjugate(&p.X), g.psiX),
+		Y: *g.ext2.Mul(g.ext2.Conjugate(&p.Y), g.psiY),
+	}
+}
+
+// psi2 returns ψ²(p).
+func (g *g2Arith) psi2(p *g2Affine) *g2Affine {
+	return &g2Affine{
+		X: *g.ext2.MulByElement(&p.X, g.thirdRootOne),
+		Y: *g.ext2.Neg(&p.Y),
+	}
+}
@@ -0,0 +1,312 @@
+//go:build !fuzzlight
+
+package bls12381
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381"
+	blsfp "github.com/consensys/gnark-crypto/ecc/bls12-381/fp"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/test"
+	"github.com/consensys/linea-monorepo/prover/maths/field"
+)
+
+// oneInstance are the limits used by the circuit tests: the circuits are
+// solved for each test vector separately.
+var oneInstance = &Limits{NbInputInstances: 1, NbCircuitInstances: 1}
+
+// checkSolved asserts that the witness solves the circuit if shouldPass is
+// true and that it does not otherwise.
+func checkSolved(t *testing.T, name string, circuit, witness frontend.Circuit, shouldPass bool) {
+	err := test.IsSolved(circuit, witness, ecc.BLS12_377.ScalarField())
+	if shouldPass && err != nil {
+		t.Errorf("%v: expected the circuit to be solved: %v", name, err)
+	}
+	if !shouldPass && err == nil {
+		t.Errorf("%v: expected the circuit not to be solved", name)
+	}
+}
+
+func TestG1AddCircuit(t *testing.T) {
+	for _, v := range loadVectors(t, "g1_add") {
+		var (
+			input    = hexToLimbs(t, v.Input)
+			expected = hexToLimbs(t, v.Expected)
+		)
+		for _, shouldPass := range []bool{true, false} {
+			witness := NewG1AddCircuit(oneInstance)
+			assignLimbs(witness.Instances[0].P[:], input[:nbG1Limbs])
+			assignLimbs(witness.Instances[0].Q[:], input[nbG1Limbs:])
+			if shouldPass {
+				assignLimbs(witness.Instances[0].R[:], expected)
+			} else {
+				assignLimbs(witness.Instances[0].R[:], perturbed(expected))
+			}
+			checkSolved(t, v.Name, NewG1AddCircuit(oneInstance), witness, shouldPass)
+		}
+	}
+}
+
+func TestG2AddCircuit(t *testing.T) {
+	for _, v := range loadVectors(t, "g2_add") {
+		var (
+			input    = hexToLimbs(t, v.Input)
+			expected = hexToLimbs(t, v.Expected)
+		)
+		for _, shouldPass := range []bool{true, false} {
+			witness := NewG2AddCircuit(oneInstance)
+			assignLimbs(witness.Instances[0].P[:], input[:nbG2Limbs])
+			assignLimbs(witness.Instances[0].Q[:], input[nbG2Limbs:])
+			if shouldPass {
+				assignLimbs(witness.Instances[0].R[:], expected)
+			} else {
+				assignLimbs(witness.Instances[0].R[:], perturbed(expected))
+			}
+			checkSolved(t, v.Name, NewG2AddCircuit(oneInstance), witness, shouldPass)
+		}
+	}
+}
+
+func TestMapFpToG1Circuit(t *testing.T) {
+	for _, v := range loadVectors(t, "map_fp_to_g1") {
+		var (
+			input    = hexToLimbs(t, v.Input)
+			expected = hexToLimbs(t, v.Expected)
+		)
+		for _, shouldPass := range []bool{true, false} {
+			witness := NewMapFpToG1Circuit(oneInstance)
+			assignLimbs(witness.Instances[0].U[:], input)
+			if shouldPass {
+				assignLimbs(witness.Instances[0].R[:], expected)
+			} else {
+				assignLimbs(witness.Instances[0].R[:], perturbed(expected))
+			}
+			checkSolved(t, v.Name, NewMapFpToG1Circuit(oneInstance), witness, shouldPass)
+		}
+	}
+
+	// the input filler and a few random inputs checked against gnark-crypto
+	inputs := []blsfp.Element{{}}
+	for i := 0; i < 2; i++ {
+		var u blsfp.Element
+		u.SetRandom()
+		inputs = append(inputs, u)
+	}
+	for _, u := range inputs {
+		witness := NewMapFpToG1Circuit(oneInstance)
+		limbs := fpToLimbs(u)
+		assignLimbs(witness.Instances[0].U[:], limbs[:])
+		assignLimbs(witness.Instances[0].R[:], g1ToLimbs(bls12381.MapToG1(u)))
+		checkSolved(t, u.String(), NewMapFpToG1Circuit(oneInstance), witness, true)
+	}
+}
+
+func TestMapFp2ToG2Circuit(t *testing.T) {
+	for _, v := range loadVectors(t, "map_fp2_to_g2") {
+		var (
+			input    = hexToLimbs(t, v.Input)
+			expected = hexToLimbs(t, v.Expected)
+		)
+		for _, shouldPass := range []bool{true, false} {
+			witness := NewMapFp2ToG2Circuit(oneInstance)
+			assignLimbs(witness.Instances[0].U[:], input)
+			if shouldPass {
+				assignLimbs(witness.Instances[0].R[:], expected)
+			} else {
+				assignLimbs(witness.Instances[0].R[:], perturbed(expected))
+			}
+			checkSolved(t, v.Name, NewMapFp2ToG2Circuit(oneInstance), witness, shouldPass)
+		}
+	}
+
+	// the input filler
+	witness := NewMapFp2ToG2Circuit(oneInstance)
+	assignLimbs(witness.Instances[0].U[:], mapFp2ToG2Filler[:nbFp2Limbs])
+	assignLimbs(witness.Instances[0].R[:], mapFp2ToG2Filler[nbFp2Limbs:])
+	checkSolved(t, "filler", NewMapFp2ToG2Circuit(oneInstance), witness, true)
+}
+
+func TestG1MsmCircuit(t *testing.T) {
+	const nbInputLimbs = nbG1Limbs + nbScalarLimbs
+
+	for _, v := range loadVectors(t, "g1_msm") {
+		var (
+			input = hexToLimbs(t, v.Input)
+			acc   = make([]field.Element, nbG1Limbs)
+		)
+		// checks every step of the MSM
+		for i := 0; i < len(input); i += nbInputLimbs {
+			var (
+				curr    = g1MsmAccumulate(acc, input[i:i+nbInputLimbs])
+				witness = NewG1MsmCircuit(oneInstance)
+			)
+			assignLimbs(witness.Instances[0].AccPrev[:], acc)
+			assignLimbs(witness.Instances[0].P[:], input[i:i+nbG1Limbs])
+			assignLimbs(witness.Instances[0].S[:], input[i+nbG1Limbs:i+nbInputLimbs])
+			assignLimbs(witness.Instances[0].AccCurr[:], curr)
+			checkSolved(t, v.Name, NewG1MsmCircuit(oneInstance), witness, true)
+			acc = curr
+		}
+
+		witness := NewG1MsmCircuit(oneInstance)
+		assignLimbs(witness.Instances[0].AccPrev[:], make([]field.Element, nbG1Limbs))
+		assignLimbs(witness.Instances[0].P[:], input[:nbG1Limbs])
+		assignLimbs(witness.Instances[0].S[:], input[nbG1Limbs:nbInputLimbs])
+		assignLimbs(witness.Instances[0].AccCurr[:], perturbed(g1MsmAccumulate(make([]field.Element, nbG1Limbs), input[:nbInputLimbs])))
+		checkSolved(t, v.Name, NewG1MsmCircuit(oneInstance), witness, false)
+	}
+}
+
+func TestG2MsmCircuit(t *testing.T) {
+	const nbInputLimbs = nbG2Limbs + nbScalarLimbs
+
+	v := loadVectors(t, "g2_msm")[0]
+	input := hexToLimbs(t, v.Input)
+	expected := hexToLimbs(t, v.Expected)
+
+	for _, shouldPass := range []bool{true, false} {
+		witness := NewG2MsmCircuit(oneInstance)
+		assignLimbs(witness.Instances[0].AccPrev[:], make([]field.Element, nbG2Limbs))
+		assignLimbs(witness.Instances[0].Q[:], input[:nbG2Limbs])
+		assignLimbs(witness.Instances[0].S[:], input[nbG2Limbs:nbInputLimbs])
+		if shouldPass {
+			assignLimbs(witness.Instances[0].AccCurr[:], expected)
+		} else {
+			assignLimbs(witness.Instances[0].AccCurr[:], perturbed(expected))
+		}
+		checkSolved(t, v.Name, NewG2MsmCircuit(oneInstance), witness, shouldPass)
+	}
+}
+
+func TestPairingCircuits(t *testing.T) {
+	const nbPairLimbs = nbG1Limbs + nbG2Limbs
+
+	limits := &PairingLimits{
+		NbMillerLoopInputInstances: 1,
+		NbMillerLoopCircuits:       1,
+		NbFinalExpInputInstances:   1,
+		NbFinalExpCircuits:         1,
+	}
+
+	for _, v := range loadVectors(t, "pairing_check") {
+		var (
+			input    = hexToLimbs(t, v.Input)
+			expected = hexToLimbs(t, v.Expected)
+			acc      = gtOneLimbs
+			nbPairs  = len(input) / nbPairLimbs
+		)
+
+		for i := 0; i < nbPairs-1; i++ {
+			var (
+				pair    = input[i*nbPairLimbs : (i+1)*nbPairLimbs]
+				curr    = pairingAccumulate(acc, pair)
+				witness = NewMillerLoopCircuit(limits)
+			)
+			assignLimbs(witness.Instances[0].AccPrev[:], acc)
+			assignLimbs(witness.Instances[0].P[:], pair[:nbG1Limbs])
+			assignLimbs(witness.Instances[0].Q[:], pair[nbG1Limbs:])
+			assignLimbs(witness.Instances[0].AccCurr[:], curr)
+			checkSolved(t, v.Name, NewMillerLoopCircuit(limits), witness, true)
+			acc = curr
+		}
+
+		last := input[(nbPairs-1)*nbPairLimbs:]
+		for _, shouldPass := range []bool{true, false} {
+			witness := NewFinalExpCircuit(limits)
+			assignLimbs(witness.Instances[0].AccPrev[:], acc)
+			assignLimbs(witness.Instances[0].P[:], last[:nbG1Limbs])
+			assignLimbs(witness.Instances[0].Q[:], last[nbG1Limbs:])
+			result := expected[len(expected)-nbPairingResultLimbs:]
+			if !shouldPass {
+				// flips the boolean result
+				result = []field.Element{field.Zero(), field.One()}
+				if expected[len(expected)-1].IsOne() {
+					result[1] = field.Zero()
+				}
+			}
+			assignLimbs(witness.Instances[0].Result[:], result)
+			checkSolved(t, v.Name, NewFinalExpCircuit(limits), witness, shouldPass)
+		}
+	}
+
+	// the input fillers
+	var (
+		mlFiller = make([]field.Element, nbRowsPerMillerLoop)
+		feFiller = make([]field.Element, nbRowsPerFinalExp)
+	)
+	for i := range mlFiller {
+		mlFiller[i] = inputFillerMillerLoop(0, i)
+	}
+	for i := range feFiller {
+		feFiller[i] = inputFillerFinalExp(0, i)
+	}
+
+	mlWitness := NewMillerLoopCircuit(limits)
+	assignLimbs(mlWitness.Instances[0].AccPrev[:], mlFiller[:nbGtLimbs])
+	assignLimbs(mlWitness.Instances[0].P[:], mlFiller[nbGtLimbs:nbGtLimbs+nbG1Limbs])
+	assignLimbs(mlWitness.Instances[0].Q[:], mlFiller[nbGtLimbs+nbG1Limbs:nbGtLimbs+nbPairLimbs])
+	assignLimbs(mlWitness.Instances[0].AccCurr[:], mlFiller[nbGtLimbs+nbPairLimbs:])
+	checkSolved(t, "filler", NewMillerLoopCircuit(limits), mlWitness, true)
+
+	feWitness := NewFinalExpCircuit(limits)
+	assignLimbs(feWitness.Instances[0].AccPrev[:], feFiller[:nbGtLimbs])
+	assignLimbs(feWitness.Instances[0].P[:], feFiller[nbGtLimbs:nbGtLimbs+nbG1Limbs])
+	assignLimbs(feWitness.Instances[0].Q[:], feFiller[nbGtLimbs+nbG1Limbs:nbGtLimbs+nbPairLimbs])
+	assignLimbs(feWitness.Instances[0].Result[:], feFiller[nbGtLimbs+nbPairLimbs:])
+	checkSolved(t, "filler", NewFinalExpCircuit(limits), feWitness, true)
+}
+
+func TestNonMembershipCircuits(t *testing.T) {
+
+	var (
+		_, _, g1Gen, g2Gen = bls12381.Generators()
+		g1Inf              = make([]field.Element, nbG1Limbs)
+		g2Inf              = make([]field.Element, nbG2Limbs)
+	)
+
+	// the points and whether they are proven not on the curve and not in the
+	// subgroup.
+	g1Cases := []struct {
+		name                   string
+		p                      []field.Element
+		notOnCurve, notInGroup bool
+	}{
+		{"generator", g1ToLimbs(g1Gen), false, false},
+		{"infinity", g1Inf, false, false},
+		{"not-on-curve", c1NonMemberFiller, true, false},
+		{"not-in-subgroup", g1NonMemberFiller, false, true},
+	}
+
+	for _, tc := range g1Cases {
+		c1 := NewC1NonMembershipCircuit(oneInstance)
+		assignLimbs(c1.Instances[0].P[:], tc.p)
+		checkSolved(t, "c1/"+tc.name, NewC1NonMembershipCircuit(oneInstance), c1, tc.notOnCurve)
+
+		g1 := NewG1NonMembershipCircuit(oneInstance)
+		assignLimbs(g1.Instances[0].P[:], tc.p)
+		checkSolved(t, "g1/"+tc.name, NewG1NonMembershipCircuit(oneInstance), g1, tc.notInGroup)
+	}
+
+	g2Cases := []struct {
+		name                   string
+		q                      []field.Element
+		notOnCurve, notInGroup bool
+	}{
+		{"generator", g2ToLimbs(g2Gen), false, false},
+		{"infinity", g2Inf, false, false},
+		{"not-on-twist", c2NonMemberFiller, true, false},
+		{"not-in-subgroup", g2NonMemberFiller, false, true},
+	}
+
+	for _, tc := range g2Cases {
+		c2 := NewC2NonMembershipCircuit(oneInstance)
+		assignLimbs(c2.Instances[0].Q[:], tc.q)
+		checkSolved(t, "c2/"+tc.name, NewC2NonMembershipCircuit(oneInstance), c2, tc.notOnCurve)
+
+		g2 := NewG2NonMembershipCircuit(oneInstance)
+		assignLimbs(g2.Instances[0].Q[:], tc.q)
+		checkSolved(t, "g2/"+tc.name, NewG2NonMembershipCircuit(oneInstance), g2, tc.notInGroup)
+	}
+}
@@ -0,0 +1,30 @@
+// Package bls12381 provides the integrations of the BLS12-381 precompile
+// calls of EIP-2537: BLS12_G1ADD, BLS12_G1MSM, BLS12_G2ADD, BLS12_G2MSM,
+// BLS12_PAIRING_CHECK, BLS12_MAP_FP_TO_G1 and BLS12_MAP_FP2_TO_G2. The calls
+// are fetched from the BLS_DATA module of the arithmetization and verified in
+// gnark circuits aligned via PLONK-in-Wizard.
+//
+// The operations with a fixed number of inputs are verified one call per
+// circuit instance as in the ecarith package. The MSMs and the pairing check
+// take a variable number of inputs and are split in individual steps
+// accumulating the result as for ECPAIR.
+//
+// The successful calls are forwarded to the circuits of their operation:
+// the NonMembership module constrains the circuit selector of each operation
+// to match SUCCESS_BIT on the inputs, so that a call cannot escape its
+// circuit by being flagged as failing. A call fails on one of:
+//
+//   - a malformed encoding: an input of the wrong size, a non-zero padding
+//     byte or a coordinate which is not reduced. These are checked by the
+//     arithmetization with its own comparisons.
+//   - a point which is not on the curve E(Fp) or on the twist E'(Fp2). This
+//     is proven by the C1 and C2 non-membership circuits.
+//   - a point on the curve which is not in the subgroup, for the MSMs and the
+//     pairing check. This is proven by the G1 and G2 non-membership circuits.
+//
+// The membership selectors of BLS_DATA flag the limbs of the point proven to
+// be outside of the curve or of the subgroup, and are only allowed on the
+// inputs of the failing calls. Choosing which point of a failing call is
+// checked, and that the additions are only failed by the curve checks, is
+// left to the arithmetization.
+package bls12381
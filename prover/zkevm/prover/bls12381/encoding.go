@@ -0,0 +1,194 @@
+package bls12381
+
+import (
+	"github.com/consensys/gnark-crypto/ecc/bls12-381"
+	blsfp "github.com/consensys/gnark-crypto/ecc/bls12-381/fp"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/emulated/fields_bls12381"
+	"github.com/consensys/gnark/std/algebra/emulated/sw_bls12381"
+	"github.com/consensys/gnark/std/math/bitslice"
+	"github.com/consensys/gnark/std/math/emulated"
+	"github.com/consensys/linea-monorepo/prover/maths/field"
+)
+
+// The arithmetization stores the operands and the results of the precompiles
+// in limbs of 128 bits following the encoding of EIP-2537: a base field
+// element takes 64 bytes in big-endian order where the first 16 bytes are
+// zeroes, a point is the concatenation of its coordinates and an element of
+// Fp2 is the encoding of c0 followed by the encoding of c1.
+const (
+	nbFpLimbs     = 4
+	nbFp2Limbs    = 2 * nbFpLimbs
+	nbG1Limbs     = 2 * nbFpLimbs
+	nbG2Limbs     = 2 * nbFp2Limbs
+	nbScalarLimbs = 2
+	// nbGtLimbs is the number of limbs of an element of GT. They are only
+	// used for the intermediate accumulators of the pairing check and we
+	// encode the 12 coordinates in the tower representation of gnark-crypto
+	// with the same per-coordinate encoding as the base field elements.
+	nbGtLimbs = 12 * nbFpLimbs
+	// nbPairingResultLimbs is the number of limbs of the result of the pairing
+	// check which is a 32 bytes boolean.
+	nbPairingResultLimbs = 2
+)
+
+type (
+	baseField   = emulated.BLS12381Fp
+	scalarField = emulated.BLS12381Fr
+	baseEl      = emulated.Element[baseField]
+)
+
+// fpFromLimbs returns the emulated base field element encoded by limbs. The
+// function asserts that the padding limb is zero but not that the element is
+// canonical.
+func fpFromLimbs(api frontend.API, fp *emulated.Field[baseField], limbs []frontend.Variable) *baseEl {
+
+	api.AssertIsEqual(limbs[0], 0)
+
+	// the emulated elements are represented by 6 limbs of 64 bits in
+	// little-endian order.
+	res := make([]frontend.Variable, 6)
+	for i := 1; i < nbFpLimbs; i++ {
+		lo, hi := bitslice.Partition(api, limbs[i], 64, bitslice.WithNbDigits(128))
+		res[2*(nbFpLimbs-1-i)] = lo
+		res[2*(nbFpLimbs-1-i)+1] = hi
+	}
+
+	return fp.NewElement(res)
+}
+
+// canonicalFpFromLimbs is as [fpFromLimbs] but additionally asserts that the
+// element is reduced. The EIP-2537 precompiles fail on non-canonical
+// encodings so this holds for all the successful calls.
+func canonicalFpFromLimbs(api frontend.API, fp *emulated.Field[baseField], limbs []frontend.Variable) *baseEl {
+	res := fpFromLimbs(api, fp, limbs)
+	fp.AssertIsInRange(res)
+	return res
+}
+
+// fp2FromLimbs returns the element of Fp2 encoded by limbs.
+func fp2FromLimbs(api frontend.API, fp *emulated.Field[baseField], limbs []frontend.Variable) *fields_bls12381.E2 {
+	return &fields_bls12381.E2{
+		A0: *canonicalFpFromLimbs(api, fp, limbs[:nbFpLimbs]),
+		A1: *canonicalFpFromLimbs(api, fp, limbs[nbFpLimbs:nbFp2Limbs]),
+	}
+}
+
+// g1FromLimbs returns the point of E(Fp) encoded by limbs. The point at
+// infinity is encoded as (0, 0).
+func g1FromLimbs(api frontend.API, fp *emulated.Field[baseField], limbs []frontend.Variable) *sw_bls12381.G1Affine {
+	return &sw_bls12381.G1Affine{
+		X: *canonicalFpFromLimbs(api, fp, limbs[:nbFpLimbs]),
+		Y: *canonicalFpFromLimbs(api, fp, limbs[nbFpLimbs:nbG1Limbs]),
+	}
+}
+
+// g2FromLimbs returns the point of E'(Fp2) encoded by limbs. The point at
+// infinity is encoded as (0, 0).
+func g2FromLimbs(api frontend.API, fp *emulated.Field[baseField], limbs []frontend.Variable) *g2Affine {
+	return &g2Affine{
+		X: *fp2FromLimbs(api, fp, limbs[:nbFp2Limbs]),
+		Y: *fp2FromLimbs(api, fp, limbs[nbFp2Limbs:nbG2Limbs]),
+	}
+}
+
+// gtFromLimbs returns the element of GT encoded by limbs.
+func gtFromLimbs(api frontend.API, ext12 *fields_bls12381.Ext12, fp *emulated.Field[baseField], limbs []frontend.Variable) *sw_bls12381.GTEl {
+	var tower [12]*baseEl
+	for i := range tower {
+		tower[i] = fpFromLimbs(api, fp, limbs[i*nbFpLimbs:(i+1)*nbFpLimbs])
+	}
+	return ext12.FromTower(tower)
+}
+
+// fpToLimbs returns the limbs encoding x as in the arithmetization.
+func fpToLimbs(x blsfp.Element) [nbFpLimbs]field.Element {
+	var (
+		res   [nbFpLimbs]field.Element
+		bytes = x.Bytes()
+	)
+	for i := 1; i < nbFpLimbs; i++ {
+		res[i].SetBytes(bytes[16*(i-1) : 16*i])
+	}
+	return res
+}
+
+// fpFromLimbsNative is the reciprocal of [fpToLimbs].
+func fpFromLimbsNative(limbs []field.Element) blsfp.Element {
+	var (
+		bytes [blsfp.Bytes]byte
+		res   blsfp.Element
+	)
+	for i := 1; i < nbFpLimbs; i++ {
+		b := limbs[i].Bytes()
+		copy(bytes[16*(i-1):16*i], b[16:])
+	}
+	res.SetBytes(bytes[:])
+	return res
+}
+
+// g1ToLimbs returns the limbs encoding p as in the arithmetization.
+func g1ToLimbs(p bls12381.G1Affine) []field.Element {
+	x, y := fpToLimbs(p.X), fpToLimbs(p.Y)
+	return append(x[:], y[:]...)
+}
+
+// g1FromLimbsNative is the reciprocal of [g1ToLimbs].
+func g1FromLimbsNative(limbs []field.Element) bls12381.G1Affine {
+	return bls12381.G1Affine{
+		X: fpFromLimbsNative(limbs[:nbFpLimbs]),
+		Y: fpFromLimbsNative(limbs[nbFpLimbs:nbG1Limbs]),
+	}
+}
+
+// g2ToLimbs returns the limbs encoding p as in the arithmetization.
+func g2ToLimbs(p bls12381.G2Affine) []field.Element {
+	res := make([]field.Element, 0, nbG2Limbs)
+	for _, c := range []blsfp.Element{p.X.A0, p.X.A1, p.Y.A0, p.Y.A1} {
+		l := fpToLimbs(c)
+		res = append(res, l[:]...)
+	}
+	return res
+}
+
+// g2FromLimbsNative is the reciprocal of [g2ToLimbs].
+func g2FromLimbsNative(limbs []field.Element) bls12381.G2Affine {
+	var p bls12381.G2Affine
+	p.X.A0 = fpFromLimbsNative(limbs[0*nbFpLimbs:])
+	p.X.A1 = fpFromLimbsNative(limbs[1*nbFpLimbs:])
+	p.Y.A0 = fpFromLimbsNative(limbs[2*nbFpLimbs:])
+	p.Y.A1 = fpFromLimbsNative(limbs[3*nbFpLimbs:])
+	return p
+}
+
+// gtToLimbs returns the limbs encoding the coordinates of x in the tower
+// representation.
+func gtToLimbs(x bls12381.GT) []field.Element {
+	var (
+		res    = make([]field.Element, 0, nbGtLimbs)
+		coords = []blsfp.Element{
+			x.C0.B0.A0, x.C0.B0.A1, x.C0.B1.A0, x.C0.B1.A1, x.C0.B2.A0, x.C0.B2.A1,
+			x.C1.B0.A0, x.C1.B0.A1, x.C1.B1.A0, x.C1.B1.A1, x.C1.B2.A0, x.C1.B2.A1,
+		}
+	)
+	for _, c := range coords {
+		l := fpToLimbs(c)
+		res = append(res, l[:]...)
+	}
+	return res
+}
+
+// gtFromLimbsNative is the reciprocal of [gtToLimbs].
+func gtFromLimbsNative(limbs []field.Element) bls12381.GT {
+	var (
+		res    bls12381.GT
+		coords = []*blsfp.Element{
+			&res.C0.B0.A0, &res.C0.B0.A1, &res.C0.B1.A0, &res.C0.B1.A1, &res.C0.B2.A0, &res.C0.B2.A1,
+			&res.C1.B0.A0, &res.C1.B0.A1, &res.C1.B1.A0, &res.C1.B1.A1, &res.C1.B2.A0, &res.C1.B2.A1,
+		}
+	)
+	for i := range coords {
+		*coords[i] = fpFromLimbsNative(limbs[i*nbFpLimbs:])
+	}
+	return res
+}
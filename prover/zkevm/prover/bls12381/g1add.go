@@ -0,0 +1,123 @@
+package bls12381
+
+import (
+	"fmt"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/emulated/sw_emulated"
+	"github.com/consensys/gnark/std/math/emulated"
+	"github.com/consensys/linea-monorepo/prover/protocol/dedicated/plonk"
+	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
+)
+
+const (
+	NAME_G1_ADD = "BLS_G1_ADD_INTEGRATION"
+)
+
+const (
+	// two points as inputs and one point as result
+	nbRowsPerG1Add = 3 * nbG1Limbs
+)
+
+// G1Add integrates the verification of the BLS12_G1ADD precompile calls
+// inside a gnark circuit.
+type G1Add struct {
+	*DataSource
+	AlignedGnarkData *plonk.Alignment
+
+	size int
+	*Limits
+}
+
+func NewG1AddZkEvm(comp *wizard.CompiledIOP, limits *Limits) *G1Add {
+	if !isSupported(comp) {
+		return nil
+	}
+	return newG1Add(
+		comp,
+		limits,
+		newDataSourceZkEvm(comp, "G1_ADD"),
+		[]plonk.Option{plonk.WithRangecheck(16, 6, true)},
+	)
+}
+
+// newG1Add creates a new G1ADD integration.
+func newG1Add(comp *wizard.CompiledIOP, limits *Limits, src *DataSource, plonkOptions []plonk.Option) *G1Add {
+	size := limits.sizeIntegration(nbRowsPerG1Add)
+
+	toAlign := &plonk.CircuitAlignmentInput{
+		Name:               NAME_G1_ADD + "_ALIGNMENT",
+		Round:              ROUND_NR,
+		DataToCircuitMask:  src.CsSelector,
+		DataToCircuit:      src.Limb,
+		Circuit:            NewG1AddCircuit(limits),
+		NbCircuitInstances: limits.NbCircuitInstances,
+		PlonkOptions:       plonkOptions,
+		InputFiller:        nil, // not necessary: O + O = O
+	}
+	res := &G1Add{
+		DataSource:       src,
+		AlignedGnarkData: plonk.DefineAlignment(comp, toAlign),
+		size:             size,
+		Limits:           limits,
+	}
+
+	return res
+}
+
+// Assign assigns the data from the trace to the gnark inputs.
+func (ga *G1Add) Assign(run *wizard.ProverRuntime) {
+	ga.AlignedGnarkData.Assign(run)
+}
+
+// MultiG1AddCircuit is a circuit that can handle multiple G1ADD instances.
+// The length of the slice Instances should correspond to the one defined in
+// the Limits struct.
+type MultiG1AddCircuit struct {
+	Instances []G1AddInstance
+}
+
+// G1AddInstance stores the operands and the result of a G1ADD call in limbs
+// of 128 bits as laid out in the arithmetization.
+type G1AddInstance struct {
+	P [nbG1Limbs]frontend.Variable `gnark:",public"`
+	Q [nbG1Limbs]frontend.Variable `gnark:",public"`
+	// The result of the addition. Is provided non-deterministically by the
+	// caller, we have to ensure that the result is correct.
+	R [nbG1Limbs]frontend.Variable `gnark:",public"`
+}
+
+// NewG1AddCircuit creates a new circuit for verifying the G1ADD precompile
+// based on the defined number of inputs.
+func NewG1AddCircuit(limits *Limits) *MultiG1AddCircuit {
+	return &MultiG1AddCircuit{
+		Instances: make([]G1AddInstance, limits.NbInputInstances),
+	}
+}
+
+func (c *MultiG1AddCircuit) Define(api frontend.API) error {
+
+	fp, err := emulated.NewField[baseField](api)
+	if err != nil {
+		return fmt.Errorf("field emulation: %w", err)
+	}
+	curve, err := sw_emulated.New[baseField, scalarField](api, sw_emulated.GetBLS12381Params())
+	if err != nil {
+		return fmt.Errorf("new curve: %w", err)
+	}
+	arith := newG1Arith(api, fp)
+
+	for i := range c.Instances {
+		var (
+			p = g1FromLimbs(api, fp, c.Instances[i].P[:])
+			q = g1FromLimbs(api, fp, c.Instances[i].Q[:])
+			r = g1FromLimbs(api, fp, c.Instances[i].R[:])
+		)
+		// G1ADD does not require the inputs to be in the subgroup but only
+		// on the curve. The point at infinity (0, 0) passes the check.
+		curve.AssertIsOnCurve(p)
+		curve.AssertIsOnCurve(q)
+		curve.AssertIsEqual(arith.add(p, q), r)
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+//go:build !fuzzlight
+
+package bls12381
+
+import (
+	"testing"
+
+	"github.com/consensys/linea-monorepo/prover/protocol/compiler/dummy"
+	"github.com/consensys/linea-monorepo/prover/protocol/dedicated/plonk"
+	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
+	"github.com/consensys/linea-monorepo/prover/utils/csvtraces"
+)
+
+func TestG1AddIntegration(t *testing.T) {
+	limits := &Limits{
+		NbInputInstances:   2,
+		NbCircuitInstances: 1,
+	}
+	ct := csvtraces.MustOpenCsvFile("testdata/g1add_test.csv")
+	var g1Add *G1Add
+	var g1AddSource *DataSource
+	cmp := wizard.Compile(
+		func(b *wizard.Builder) {
+			g1AddSource = &DataSource{
+				CsSelector: ct.GetCommit(b, "CS_G1_ADD"),
+				Limb:       ct.GetCommit(b, "LIMB"),
+				Index:      ct.GetCommit(b, "INDEX"),
+				IsData:     ct.GetCommit(b, "IS_DATA"),
+				IsRes:      ct.GetCommit(b, "IS_RES"),
+			}
+			g1Add = newG1Add(b.CompiledIOP, limits, g1AddSource, []plonk.Option{plonk.WithRangecheck(16, 6, true)})
+		},
+		dummy.Compile,
+	)
+
+	proof := wizard.Prove(cmp,
+		func(run *wizard.ProverRuntime) {
+			ct.Assign(run, "CS_G1_ADD", "LIMB", "INDEX", "IS_DATA", "IS_RES")
+			g1Add.Assign(run)
+		})
+
+	if err := wizard.Verify(cmp, proof); err != nil {
+		t.Fatal("proof failed", err)
+	}
+
+	t.Log("proof succeeded")
+}
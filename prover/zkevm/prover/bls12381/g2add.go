@@ -0,0 +1,118 @@
+package bls12381
+
+import (
+	"fmt"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/emulated"
+	"github.com/consensys/linea-monorepo/prover/protocol/dedicated/plonk"
+	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
+)
+
+const (
+	NAME_G2_ADD = "BLS_G2_ADD_INTEGRATION"
+)
+
+const (
+	// two points as inputs and one point as result
+	nbRowsPerG2Add = 3 * nbG2Limbs
+)
+
+// G2Add integrates the verification of the BLS12_G2ADD precompile calls
+// inside a gnark circuit.
+type G2Add struct {
+	*DataSource
+	AlignedGnarkData *plonk.Alignment
+
+	size int
+	*Limits
+}
+
+func NewG2AddZkEvm(comp *wizard.CompiledIOP, limits *Limits) *G2Add {
+	if !isSupported(comp) {
+		return nil
+	}
+	return newG2Add(
+		comp,
+		limits,
+		newDataSourceZkEvm(comp, "G2_ADD"),
+		[]plonk.Option{plonk.WithRangecheck(16, 6, true)},
+	)
+}
+
+// newG2Add creates a new G2ADD integration.
+func newG2Add(comp *wizard.CompiledIOP, limits *Limits, src *DataSource, plonkOptions []plonk.Option) *G2Add {
+	size := limits.sizeIntegration(nbRowsPerG2Add)
+
+	toAlign := &plonk.CircuitAlignmentInput{
+		Name:               NAME_G2_ADD + "_ALIGNMENT",
+		Round:              ROUND_NR,
+		DataToCircuitMask:  src.CsSelector,
+		DataToCircuit:      src.Limb,
+		Circuit:            NewG2AddCircuit(limits),
+		NbCircuitInstances: limits.NbCircuitInstances,
+		PlonkOptions:       plonkOptions,
+		InputFiller:        nil, // not necessary: O + O = O
+	}
+	res := &G2Add{
+		DataSource:       src,
+		AlignedGnarkData: plonk.DefineAlignment(comp, toAlign),
+		size:             size,
+		Limits:           limits,
+	}
+
+	return res
+}
+
+// Assign assigns the data from the trace to the gnark inputs.
+func (ga *G2Add) Assign(run *wizard.ProverRuntime) {
+	ga.AlignedGnarkData.Assign(run)
+}
+
+// MultiG2AddCircuit is a circuit that can handle multiple G2ADD instances.
+// The length of the slice Instances should correspond to the one defined in
+// the Limits struct.
+type MultiG2AddCircuit struct {
+	Instances []G2AddInstance
+}
+
+// G2AddInstance stores the operands and the result of a G2ADD call in limbs
+// of 128 bits as laid out in the arithmetization.
+type G2AddInstance struct {
+	P [nbG2Limbs]frontend.Variable `gnark:",public"`
+	Q [nbG2Limbs]frontend.Variable `gnark:",public"`
+	// The result of the addition. Is provided non-deterministically by the
+	// caller, we have to ensure that the result is correct.
+	R [nbG2Limbs]frontend.Variable `gnark:",public"`
+}
+
+// NewG2AddCircuit creates a new circuit for verifying the G2ADD precompile
+// based on the defined number of inputs.
+func NewG2AddCircuit(limits *Limits) *MultiG2AddCircuit {
+	return &MultiG2AddCircuit{
+		Instances: make([]G2AddInstance, limits.NbInputInstances),
+	}
+}
+
+func (c *MultiG2AddCircuit) Define(api frontend.API) error {
+
+	fp, err := emulated.NewField[baseField](api)
+	if err != nil {
+		return fmt.Errorf("field emulation: %w", err)
+	}
+	arith := newG2Arith(api)
+
+	for i := range c.Instances {
+		var (
+			p = g2FromLimbs(api, fp, c.Instances[i].P[:])
+			q = g2FromLimbs(api, fp, c.Instances[i].Q[:])
+			r = g2FromLimbs(api, fp, c.Instances[i].R[:])
+		)
+		// G2ADD does not require the inputs to be in the subgroup but only
+		// on the twist. The point at infinity (0, 0) passes the check.
+		arith.assertIsOnTwist(p)
+		arith.assertIsOnTwist(q)
+		arith.assertIsEqual(arith.add(p, q), r)
+	}
+	return nil
+}
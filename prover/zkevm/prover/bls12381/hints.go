@@ -0,0 +1,67 @@
+package bls12381
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark/constraint/solver"
+	"github.com/consensys/gnark/std/math/emulated"
+)
+
+func init() {
+	solver.RegisterHint(fpIsSquareHint, fp2IsSquareHint, fp2SqrtHint)
+}
+
+// fpIsSquareHint returns 1 if the input is a square in Fp and 0 otherwise.
+// The result is not trusted: the caller has to check it by computing a square
+// root.
+func fpIsSquareHint(mod *big.Int, inputs, outputs []*big.Int) error {
+	return emulated.UnwrapHintWithNativeOutput(inputs, outputs, func(field *big.Int, inputs, outputs []*big.Int) error {
+		if len(inputs) != 1 || len(outputs) != 1 {
+			return errors.New("expecting one input and one output")
+		}
+		outputs[0].SetUint64(0)
+		if big.Jacobi(inputs[0], field) >= 0 {
+			outputs[0].SetUint64(1)
+		}
+		return nil
+	})
+}
+
+// fp2IsSquareHint returns 1 if the input (a0, a1) is a square in Fp2 and 0
+// otherwise.
+func fp2IsSquareHint(mod *big.Int, inputs, outputs []*big.Int) error {
+	return emulated.UnwrapHintWithNativeOutput(inputs, outputs, func(field *big.Int, inputs, outputs []*big.Int) error {
+		if len(inputs) != 2 || len(outputs) != 1 {
+			return errors.New("expecting two inputs and one output")
+		}
+		var x bls12381.E2
+		x.A0.SetBigInt(inputs[0])
+		x.A1.SetBigInt(inputs[1])
+		outputs[0].SetUint64(0)
+		if x.Legendre() >= 0 {
+			outputs[0].SetUint64(1)
+		}
+		return nil
+	})
+}
+
+// fp2SqrtHint returns a square root (b0, b1) of the input (a0, a1) in Fp2.
+func fp2SqrtHint(mod *big.Int, inputs, outputs []*big.Int) error {
+	return emulated.UnwrapHint(inputs, outputs, func(field *big.Int, inputs, outputs []*big.Int) error {
+		if len(inputs) != 2 || len(outputs) != 2 {
+			return errors.New("expecting two inputs and two outputs")
+		}
+		var x bls12381.E2
+		x.A0.SetBigInt(inputs[0])
+		x.A1.SetBigInt(inputs[1])
+		if x.Legendre() < 0 {
+			return errors.New("no square root")
+		}
+		x.Sqrt(&x)
+		x.A0.BigInt(outputs[0])
+		x.A1.BigInt(outputs[1])
+		return nil
+	})
+}
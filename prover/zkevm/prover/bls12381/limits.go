@@ -0,0 +1,55 @@
+package bls12381
+
+import "github.com/consensys/linea-monorepo/prover/utils"
+
+// Limits defines the upper limits on the size of the circuit and the number of
+// gnark circuits for a single operation. The total number of allowed operations
+// is the product of the fields. For the MSMs, an operation is a single
+// scalar multiplication of the MSM.
+type Limits struct {
+	// how many operations can we do in a single circuit
+	NbInputInstances int
+	// how many circuit instances can we have
+	NbCircuitInstances int
+}
+
+func (l *Limits) nbOperations() int {
+	return l.NbInputInstances * l.NbCircuitInstances
+}
+
+// sizeIntegration returns the size of the columns of a module where each
+// operation takes nbRows rows.
+func (l *Limits) sizeIntegration(nbRows int) int {
+	return utils.NextPowerOfTwo(l.NbInputInstances*nbRows) * utils.NextPowerOfTwo(l.NbCircuitInstances)
+}
+
+// PairingLimits defines the upper limits for the pairing check precompile. As
+// for ECPAIR, the Miller loops and the final exponentiations are checked by
+// separate circuits.
+type PairingLimits struct {
+	// Number of inputs per Miller loop circuits. Counted without the last
+	// Miller loop of each call which is done in the final exponentiation
+	// part.
+	NbMillerLoopInputInstances int
+	// Number of Miller loop circuits
+	NbMillerLoopCircuits int
+
+	// Number of inputs per final exponentiation circuits
+	NbFinalExpInputInstances int
+	// Number of final exponentiation circuits
+	NbFinalExpCircuits int
+}
+
+func (l *PairingLimits) nbMillerLoops() int {
+	return l.NbMillerLoopInputInstances * l.NbMillerLoopCircuits
+}
+
+func (l *PairingLimits) nbFinalExps() int {
+	return l.NbFinalExpInputInstances * l.NbFinalExpCircuits
+}
+
+func (l *PairingLimits) sizePairing() int {
+	return utils.NextPowerOfTwo(
+		l.nbMillerLoops()*nbRowsPerMillerLoop + l.nbFinalExps()*nbRowsPerFinalExp,
+	)
+}
@@ -0,0 +1,257 @@
+package bls12381
+
+import (
+	"math/big"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/emulated/fields_bls12381"
+	"github.com/consensys/gnark/std/algebra/emulated/sw_bls12381"
+	"github.com/consensys/gnark/std/math/emulated"
+	"github.com/consensys/linea-monorepo/prover/utils"
+)
+
+// The maps to the curves of EIP-2537 are the simplified SWU maps to curves
+// isogenous to E and E', followed by the isogenies and the clearing of the
+// cofactors as specified in RFC 9380 for the BLS12381G1_XMD:SHA-256_SSWU_RO_
+// and BLS12381G2_XMD:SHA-256_SSWU_RO_ suites.
+const (
+	// coefficients of the curve E1': y² = x³ + A'x + B' isogenous to E
+	sswuG1A = "0x144698a3b8e9433d693a02c96d4982b0ea985383ee66a8d8e8981aefd881ac98936f8da0e0f97f5cf428082d584c1d"
+	sswuG1B = "0x12e2908d11688030018b12e8753eee3b2016c1f0f24f4070a0b9c14fcef35ef55a23215a316ceaa5d1cc48e98e172be0"
+	sswuG1Z = 11
+	// effective cofactor of G1, the cofactor is cleared by multiplying by it
+	g1EffectiveCofactor = "0xd201000000010001"
+	// xGen is the opposite of the seed of the curve
+	xGen = "15132376222941642752"
+)
+
+var (
+	// coefficients of the curve E2': y² = x³ + A'x + B' isogenous to E'
+	sswuG2A = [2]int64{0, 240}
+	sswuG2B = [2]int64{1012, 1012}
+	sswuG2Z = [2]int64{-2, -1}
+)
+
+// mapFpToG1 returns the image of u by the map to G1 of EIP-2537.
+func mapFpToG1(api frontend.API, fp *emulated.Field[baseField], u *baseEl) *sw_bls12381.G1Affine {
+
+	var (
+		a     = emulated.ValueOf[baseField](sswuG1A)
+		b     = emulated.ValueOf[baseField](sswuG1B)
+		z     = emulated.ValueOf[baseField](sswuG1Z)
+		curve = func(x *baseEl) *baseEl {
+			// x³ + A'x + B'
+			return fp.Add(fp.Mul(fp.Add(fp.Mul(x, x), &a), x), &b)
+		}
+	)
+
+	// 1. simplified SWU map to E1'. The notations follow RFC 9380, section
+	// 6.6.2. The choice of the square root is not constrained here as it is
+	// fixed by the sign condition.
+	var (
+		tv1     = fp.MulConst(fp.Mul(u, u), big.NewInt(sswuG1Z))
+		tv2     = fp.Add(fp.Mul(tv1, tv1), tv1)
+		tv2Zero = fp.IsZero(tv2)
+		x1Den   = fp.Mul(&a, fp.Select(tv2Zero, &z, fp.Neg(tv2)))
+		x1      = fp.Div(fp.Mul(&b, fp.Add(tv2, fp.One())), x1Den)
+		gx1     = curve(x1)
+		x2      = fp.Mul(tv1, x1)
+		gx2     = curve(x2)
+	)
+
+	// As Z is not a square, gx2 = Z³u⁶ * gx1 is a square if and only if gx1
+	// is not. Thus, the prover cannot lie on isSquare unless gx1 = 0 or in
+	// the exceptional case tv2 = 0 where gx1 is always a square.
+	isSquare := hintIsSquare(fp, gx1)
+	api.AssertIsBoolean(isSquare)
+	api.AssertIsEqual(api.Mul(api.Sub(1, isSquare), api.Add(tv2Zero, fp.IsZero(gx1))), 0)
+
+	var (
+		x = fp.Select(isSquare, x1, x2)
+		y = fp.Sqrt(fp.Select(isSquare, gx1, gx2))
+	)
+
+	// sgn0(y) must equal sgn0(u)
+	flipY := api.Xor(fp.ToBitsCanonical(u)[0], fp.ToBitsCanonical(y)[0])
+	y = fp.Select(flipY, fp.Neg(y), y)
+
+	// 2. 11-isogeny to E. The exceptional points are sent to the point at
+	// infinity.
+	var (
+		xNum     = evalPolynomialFp(fp, g1IsogenyXNumerator, false, x)
+		xDen     = evalPolynomialFp(fp, g1IsogenyXDenominator, true, x)
+		yNum     = fp.Mul(evalPolynomialFp(fp, g1IsogenyYNumerator, false, x), y)
+		yDen     = evalPolynomialFp(fp, g1IsogenyYDenominator, true, x)
+		isExcept = api.Or(fp.IsZero(xDen), fp.IsZero(yDen))
+		arith    = newG1Arith(api, fp)
+	)
+
+	xDen = fp.Select(isExcept, fp.One(), xDen)
+	yDen = fp.Select(isExcept, fp.One(), yDen)
+	p := &sw_bls12381.G1Affine{X: *fp.Div(xNum, xDen), Y: *fp.Div(yNum, yDen)}
+	p = arith.selectPoint(isExcept, &sw_bls12381.G1Affine{X: *fp.Zero(), Y: *fp.Zero()}, p)
+
+	// 3. clearing of the cofactor
+	return arith.scalarMulConst(p, mustParseBig(g1EffectiveCofactor))
+}
+
+// mapFp2ToG2 returns the image of u by the map to G2 of EIP-2537.
+func mapFp2ToG2(api frontend.API, fp *emulated.Field[baseField], u *fields_bls12381.E2) *g2Affine {
+
+	var (
+		ext2  = fields_bls12381.NewExt2(api)
+		a     = fp2Const(sswuG2A)
+		b     = fp2Const(sswuG2B)
+		z     = fp2Const(sswuG2Z)
+		curve = func(x *fields_bls12381.E2) *fields_bls12381.E2 {
+			// x³ + A'x + B'
+			return ext2.Add(ext2.Mul(ext2.Add(ext2.Square(x), a), x), b)
+		}
+	)
+
+	// 1. simplified SWU map to E2'
+	var (
+		tv1     = ext2.Mul(ext2.Square(u), z)
+		tv2     = ext2.Add(ext2.Square(tv1), tv1)
+		tv2Zero = ext2.IsZero(tv2)
+		x1Den   = ext2.Mul(a, ext2.Select(tv2Zero, z, ext2.Neg(tv2)))
+		x1      = ext2.DivUnchecked(ext2.Mul(b, ext2.Add(tv2, ext2.One())), x1Den)
+		gx1     = curve(x1)
+		x2      = ext2.Mul(tv1, x1)
+		gx2     = curve(x2)
+	)
+
+	// same argument as for G1, Z is not a square in Fp2
+	isSquare := hintIsSquareFp2(fp, gx1)
+	api.AssertIsBoolean(isSquare)
+	api.AssertIsEqual(api.Mul(api.Sub(1, isSquare), api.Add(tv2Zero, ext2.IsZero(gx1))), 0)
+
+	var (
+		x  = ext2.Select(isSquare, x1, x2)
+		gx = ext2.Select(isSquare, gx1, gx2)
+		y  = hintSqrtFp2(fp, gx)
+	)
+	ext2.AssertIsEqual(ext2.Square(y), gx)
+
+	flipY := api.Xor(sgn0Fp2(api, fp, u), sgn0Fp2(api, fp, y))
+	y = ext2.Select(flipY, ext2.Neg(y), y)
+
+	// 2. 3-isogeny to E'
+	var (
+		xNum     = evalPolynomialFp2(ext2, g2IsogenyXNumerator, false, x)
+		xDen     = evalPolynomialFp2(ext2, g2IsogenyXDenominator, true, x)
+		yNum     = ext2.Mul(evalPolynomialFp2(ext2, g2IsogenyYNumerator, false, x), y)
+		yDen     = evalPolynomialFp2(ext2, g2IsogenyYDenominator, true, x)
+		isExcept = api.Or(ext2.IsZero(xDen), ext2.IsZero(yDen))
+		arith    = newG2Arith(api)
+	)
+
+	xDen = ext2.Select(isExcept, ext2.One(), xDen)
+	yDen = ext2.Select(isExcept, ext2.One(), yDen)
+	p := &g2Affine{X: *ext2.DivUnchecked(xNum, xDen), Y: *ext2.DivUnchecked(yNum, yDen)}
+	p = arith.selectPoint(isExcept, arith.infinity(), p)
+
+	// 3. clearing of the cofactor following Budroni-Pintore, with x the
+	// seed of the curve:
+	//
+	// 	[x²-x-1]P + ψ([x-1]P) + ψ²(2P)
+	var (
+		x0   = mustParseBig(xGen)
+		xP   = arith.neg(arith.scalarMulConst(p, x0))
+		xxP  = arith.scalarMulConst(arith.neg(xP), x0)
+		negP = arith.neg(p)
+		res  = arith.add(arith.add(xxP, arith.neg(xP)), negP)
+	)
+	res = arith.add(res, arith.psi(arith.add(xP, negP)))
+	res = arith.add(res, arith.psi2(arith.double(p)))
+	return res
+}
+
+// evalPolynomialFp returns the evaluation at x of the polynomial whose
+// coefficients are given from the lowest to the highest degree. If monic is
+// set, the polynomial has an additional leading coefficient equal to 1.
+func evalPolynomialFp(fp *emulated.Field[baseField], coeffs []string, monic bool, x *baseEl) *baseEl {
+	c := emulated.ValueOf[baseField](coeffs[len(coeffs)-1])
+	res := &c
+	if monic {
+		res = fp.Add(res, x)
+	}
+	for i := len(coeffs) - 2; i >= 0; i-- {
+		c := emulated.ValueOf[baseField](coeffs[i])
+		res = fp.Add(fp.Mul(res, x), &c)
+	}
+	return res
+}
+
+// evalPolynomialFp2 is as [evalPolynomialFp] for polynomials over Fp2.
+func evalPolynomialFp2(ext2 *fields_bls12381.Ext2, coeffs [][2]string, monic bool, x *fields_bls12381.E2) *fields_bls12381.E2 {
+	toE2 := func(c [2]string) *fields_bls12381.E2 {
+		return &fields_bls12381.E2{
+			A0: emulated.ValueOf[baseField](c[0]),
+			A1: emulated.ValueOf[baseField](c[1]),
+		}
+	}
+	res := toE2(coeffs[len(coeffs)-1])
+	if monic {
+		res = ext2.Add(res, x)
+	}
+	for i := len(coeffs) - 2; i >= 0; i-- {
+		res = ext2.Add(ext2.Mul(res, x), toE2(coeffs[i]))
+	}
+	return res
+}
+
+// sgn0Fp2 returns the sign of x as defined in RFC 9380, section 4.1.
+func sgn0Fp2(api frontend.API, fp *emulated.Field[baseField], x *fields_bls12381.E2) frontend.Variable {
+	var (
+		sign0 = fp.ToBitsCanonical(&x.A0)[0]
+		zero0 = fp.IsZero(&x.A0)
+		sign1 = fp.ToBitsCanonical(&x.A1)[0]
+	)
+	return api.Or(sign0, api.And(zero0, sign1))
+}
+
+// hintIsSquare returns a non-constrained boolean indicating whether x is a
+// square in Fp.
+func hintIsSquare(fp *emulated.Field[baseField], x *baseEl) frontend.Variable {
+	res, err := fp.NewHintWithNativeOutput(fpIsSquareHint, 1, x)
+	if err != nil {
+		utils.Panic("could not call the hint: %v", err)
+	}
+	return res[0]
+}
+
+// hintIsSquareFp2 returns a non-constrained boolean indicating whether x is a
+// square in Fp2.
+func hintIsSquareFp2(fp *emulated.Field[baseField], x *fields_bls12381.E2) frontend.Variable {
+	res, err := fp.NewHintWithNativeOutput(fp2IsSquareHint, 1, &x.A0, &x.A1)
+	if err != nil {
+		utils.Panic("could not call the hint: %v", err)
+	}
+	return res[0]
+}
+
+// hintSqrtFp2 returns a non-constrained square root of x in Fp2.
+func hintSqrtFp2(fp *emulated.Field[baseField], x *fields_bls12381.E2) *fields_bls12381.E2 {
+	res, err := fp.NewHint(fp2SqrtHint, 2, &x.A0, &x.A1)
+	if err != nil {
+		utils.Panic("could not call the hint: %v", err)
+	}
+	return &fields_bls12381.E2{A0: *res[0], A1: *res[1]}
+}
+
+// fp2Const returns the constant c0 + c1*u of Fp2.
+func fp2Const(c [2]int64) *fields_bls12381.E2 {
+	return &fields_bls12381.E2{
+		A0: emulated.ValueOf[baseField](c[0]),
+		A1: emulated.ValueOf[baseField](c[1]),
+	}
+}
+
+func mustParseBig(s string) *big.Int {
+	res, ok := new(big.Int).SetString(s, 0)
+	if !ok {
+		utils.Panic("could not parse %v", s)
+	}
+	return res
+}
@@ -0,0 +1,129 @@
+package bls12381
+
+import (
+	"fmt"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381"
+	blsfp "github.com/consensys/gnark-crypto/ecc/bls12-381/fp"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/emulated/sw_emulated"
+	"github.com/consensys/gnark/std/math/emulated"
+	"github.com/consensys/linea-monorepo/prover/maths/field"
+	"github.com/consensys/linea-monorepo/prover/protocol/dedicated/plonk"
+	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
+)
+
+const (
+	NAME_MAP_FP_TO_G1 = "BLS_MAP_FP_TO_G1_INTEGRATION"
+)
+
+const (
+	// one base field element as input and one point as result
+	nbRowsPerMapFpToG1 = nbFpLimbs + nbG1Limbs
+)
+
+// MapFpToG1 integrates the verification of the BLS12_MAP_FP_TO_G1 precompile
+// calls inside a gnark circuit.
+type MapFpToG1 struct {
+	*DataSource
+	AlignedGnarkData *plonk.Alignment
+
+	size int
+	*Limits
+}
+
+func NewMapFpToG1ZkEvm(comp *wizard.CompiledIOP, limits *Limits) *MapFpToG1 {
+	if !isSupported(comp) {
+		return nil
+	}
+	return newMapFpToG1(
+		comp,
+		limits,
+		newDataSourceZkEvm(comp, "MAP_FP_TO_G1"),
+		[]plonk.Option{plonk.WithRangecheck(16, 6, true)},
+	)
+}
+
+// newMapFpToG1 creates a new MAP_FP_TO_G1 integration.
+func newMapFpToG1(comp *wizard.CompiledIOP, limits *Limits, src *DataSource, plonkOptions []plonk.Option) *MapFpToG1 {
+	size := limits.sizeIntegration(nbRowsPerMapFpToG1)
+
+	toAlign := &plonk.CircuitAlignmentInput{
+		Name:               NAME_MAP_FP_TO_G1 + "_ALIGNMENT",
+		Round:              ROUND_NR,
+		DataToCircuitMask:  src.CsSelector,
+		DataToCircuit:      src.Limb,
+		Circuit:            NewMapFpToG1Circuit(limits),
+		NbCircuitInstances: limits.NbCircuitInstances,
+		PlonkOptions:       plonkOptions,
+		InputFiller:        mapFpToG1InputFiller,
+	}
+	res := &MapFpToG1{
+		DataSource:       src,
+		AlignedGnarkData: plonk.DefineAlignment(comp, toAlign),
+		size:             size,
+		Limits:           limits,
+	}
+
+	return res
+}
+
+// Assign assigns the data from the trace to the gnark inputs.
+func (m *MapFpToG1) Assign(run *wizard.ProverRuntime) {
+	m.AlignedGnarkData.Assign(run)
+}
+
+// mapFpToG1Filler is the input and the result of the map of 0, used for the
+// unused instances of the circuit.
+var mapFpToG1Filler = func() []field.Element {
+	var u blsfp.Element
+	res := make([]field.Element, nbFpLimbs, nbRowsPerMapFpToG1)
+	return append(res, g1ToLimbs(bls12381.MapToG1(u))...)
+}()
+
+func mapFpToG1InputFiller(_, inputIndex int) field.Element {
+	return mapFpToG1Filler[inputIndex%nbRowsPerMapFpToG1]
+}
+
+// MultiMapFpToG1Circuit is a circuit that can handle multiple MAP_FP_TO_G1
+// instances. The length of the slice Instances should correspond to the one
+// defined in the Limits struct.
+type MultiMapFpToG1Circuit struct {
+	Instances []MapFpToG1Instance
+}
+
+// MapFpToG1Instance stores the input and the result of a MAP_FP_TO_G1 call in
+// limbs of 128 bits as laid out in the arithmetization.
+type MapFpToG1Instance struct {
+	U [nbFpLimbs]frontend.Variable `gnark:",public"`
+	R [nbG1Limbs]frontend.Variable `gnark:",public"`
+}
+
+// NewMapFpToG1Circuit creates a new circuit for verifying the MAP_FP_TO_G1
+// precompile based on the defined number of inputs.
+func NewMapFpToG1Circuit(limits *Limits) *MultiMapFpToG1Circuit {
+	return &MultiMapFpToG1Circuit{
+		Instances: make([]MapFpToG1Instance, limits.NbInputInstances),
+	}
+}
+
+func (c *MultiMapFpToG1Circuit) Define(api frontend.API) error {
+
+	fp, err := emulated.NewField[baseField](api)
+	if err != nil {
+		return fmt.Errorf("field emulation: %w", err)
+	}
+	curve, err := sw_emulated.New[baseField, scalarField](api, sw_emulated.GetBLS12381Params())
+	if err != nil {
+		return fmt.Errorf("new curve: %w", err)
+	}
+
+	for i := range c.Instances {
+		var (
+			u = canonicalFpFromLimbs(api, fp, c.Instances[i].U[:])
+			r = g1FromLimbs(api, fp, c.Instances[i].R[:])
+		)
+		curve.AssertIsEqual(mapFpToG1(api, fp, u), r)
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+package bls12381
+
+import (
+	"fmt"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/emulated"
+	"github.com/consensys/linea-monorepo/prover/maths/field"
+	"github.com/consensys/linea-monorepo/prover/protocol/dedicated/plonk"
+	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
+)
+
+const (
+	NAME_MAP_FP2_TO_G2 = "BLS_MAP_FP2_TO_G2_INTEGRATION"
+)
+
+const (
+	// one element of Fp2 as input and one point as result
+	nbRowsPerMapFp2ToG2 = nbFp2Limbs + nbG2Limbs
+)
+
+// MapFp2ToG2 integrates the verification of the BLS12_MAP_FP2_TO_G2
+// precompile calls inside a gnark circuit.
+type MapFp2ToG2 struct {
+	*DataSource
+	AlignedGnarkData *plonk.Alignment
+
+	size int
+	*Limits
+}
+
+func NewMapFp2ToG2ZkEvm(comp *wizard.CompiledIOP, limits *Limits) *MapFp2ToG2 {
+	if !isSupported(comp) {
+		return nil
+	}
+	return newMapFp2ToG2(
+		comp,
+		limits,
+		newDataSourceZkEvm(comp, "MAP_FP2_TO_G2"),
+		[]plonk.Option{plonk.WithRangecheck(16, 6, true)},
+	)
+}
+
+// newMapFp2ToG2 creates a new MAP_FP2_TO_G2 integration.
+func newMapFp2ToG2(comp *wizard.CompiledIOP, limits *Limits, src *DataSource, plonkOptions []plonk.Option) *MapFp2ToG2 {
+	size := limits.sizeIntegration(nbRowsPerMapFp2ToG2)
+
+	toAlign := &plonk.CircuitAlignmentInput{
+		Name:               NAME_MAP_FP2_TO_G2 + "_ALIGNMENT",
+		Round:              ROUND_NR,
+		DataToCircuitMask:  src.CsSelector,
+		DataToCircuit:      src.Limb,
+		Circuit:            NewMapFp2ToG2Circuit(limits),
+		NbCircuitInstances: limits.NbCircuitInstances,
+		PlonkOptions:       plonkOptions,
+		InputFiller:        mapFp2ToG2InputFiller,
+	}
+	res := &MapFp2ToG2{
+		DataSource:       src,
+		AlignedGnarkData: plonk.DefineAlignment(comp, toAlign),
+		size:             size,
+		Limits:           limits,
+	}
+
+	return res
+}
+
+// Assign assigns the data from the trace to the gnark inputs.
+func (m *MapFp2ToG2) Assign(run *wizard.ProverRuntime) {
+	m.AlignedGnarkData.Assign(run)
+}
+
+// mapFp2ToG2Filler is the input and the result of the map of 0, used for the
+// unused instances of the circuit.
+var mapFp2ToG2Filler = func() []field.Element {
+	var u bls12381.E2
+	res := make([]field.Element, nbFp2Limbs, nbRowsPerMapFp2ToG2)
+	return append(res, g2ToLimbs(bls12381.MapToG2(u))...)
+}()
+
+func mapFp2ToG2InputFiller(_, inputIndex int) field.Element {
+	return mapFp2ToG2Filler[inputIndex%nbRowsPerMapFp2ToG2]
+}
+
+// MultiMapFp2ToG2Circuit is a circuit that can handle multiple MAP_FP2_TO_G2
+// instances. The length of the slice Instances should correspond to the one
+// defined in the Limits struct.
+type MultiMapFp2ToG2Circuit struct {
+	Instances []MapFp2ToG2Instance
+}
+
+// MapFp2ToG2Instance stores the input and the result of a MAP_FP2_TO_G2 call
+// in limbs of 128 bits as laid out in the arithmetization.
+type MapFp2ToG2Instance struct {
+	U [nbFp2Limbs]frontend.Variable `gnark:",public"`
+	R [nbG2Limbs]frontend.Variable  `gnark:",public"`
+}
+
+// NewMapFp2ToG2Circuit creates a new circuit for verifying the MAP_FP2_TO_G2
+// precompile based on the defined number of inputs.
+func NewMapFp2ToG2Circuit(limits *Limits) *MultiMapFp2ToG2Circuit {
+	return &MultiMapFp2ToG2Circuit{
+		Instances: make([]MapFp2ToG2Instance, limits.NbInputInstances),
+	}
+}
+
+func (c *MultiMapFp2ToG2Circuit) Define(api frontend.API) error {
+
+	fp, err := emulated.NewField[baseField](api)
+	if err != nil {
+		return fmt.Errorf("field emulation: %w", err)
+	}
+	arith := newG2Arith(api)
+
+	for i := range c.Instances {
+		var (
+			u = fp2FromLimbs(api, fp, c.Instances[i].U[:])
+			r = g2FromLimbs(api, fp, c.Instances[i].R[:])
+		)
+		arith.assertIsEqual(mapFp2ToG2(api, fp, u), r)
+	}
+	return nil
+}
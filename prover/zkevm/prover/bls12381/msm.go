@@ -0,0 +1,346 @@
+package bls12381
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/algopts"
+	"github.com/consensys/gnark/std/algebra/emulated/fields_bls12381"
+	"github.com/consensys/gnark/std/algebra/emulated/sw_bls12381"
+	"github.com/consensys/gnark/std/algebra/emulated/sw_emulated"
+	"github.com/consensys/gnark/std/math/bitslice"
+	"github.com/consensys/gnark/std/math/emulated"
+	"github.com/consensys/linea-monorepo/prover/maths/field"
+	"github.com/consensys/linea-monorepo/prover/protocol/dedicated/plonk"
+	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
+	"github.com/consensys/linea-monorepo/prover/zkevm/arithmetization"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	NAME_G1_MSM = "BLS_G1_MSM_INTEGRATION"
+	NAME_G2_MSM = "BLS_G2_MSM_INTEGRATION"
+)
+
+const (
+	// the previous and the current accumulator, the point and the scalar.
+	// The result of the MSM takes the place of the last accumulator.
+	nbRowsPerG1Msm = 2*nbG1Limbs + nbG1Limbs + nbScalarLimbs
+	nbRowsPerG2Msm = 2*nbG2Limbs + nbG2Limbs + nbScalarLimbs
+)
+
+// G1Msm integrates the verification of the BLS12_G1MSM precompile calls. The
+// MSMs are split into single scalar multiplications accumulated in a point of
+// G1, each step being verified in a gnark circuit.
+type G1Msm struct {
+	*Limits
+	Source           *MultiInputSource
+	Unaligned        *UnalignedAccumulationData
+	AlignedGnarkData *plonk.Alignment
+}
+
+func NewG1MsmZkEvm(comp *wizard.CompiledIOP, limits *Limits) *G1Msm {
+	if !isSupported(comp) {
+		return nil
+	}
+	return newG1Msm(
+		comp,
+		limits,
+		newMultiInputSourceZkEvm(comp, "G1_MSM"),
+		[]plonk.Option{plonk.WithRangecheck(16, 6, true)},
+	)
+}
+
+// newG1Msm creates a new G1MSM integration.
+func newG1Msm(comp *wizard.CompiledIOP, limits *Limits, src *MultiInputSource, plonkOptions []plonk.Option) *G1Msm {
+	unaligned := newUnalignedAccumulationData(comp, src, g1MsmSettings(limits))
+
+	toAlign := &plonk.CircuitAlignmentInput{
+		Name:               NAME_G1_MSM + "_ALIGNMENT",
+		Round:              ROUND_NR,
+		DataToCircuitMask:  unaligned.IsActive,
+		DataToCircuit:      unaligned.Limb,
+		Circuit:            NewG1MsmCircuit(limits),
+		NbCircuitInstances: limits.NbCircuitInstances,
+		PlonkOptions:       plonkOptions,
+		InputFiller:        nil, // not necessary: O + [0]O = O
+	}
+
+	return &G1Msm{
+		Limits:           limits,
+		Source:           src,
+		Unaligned:        unaligned,
+		AlignedGnarkData: plonk.DefineAlignment(comp, toAlign),
+	}
+}
+
+// Assign assigns the unaligned data and the gnark inputs.
+func (m *G1Msm) Assign(run *wizard.ProverRuntime) {
+	nbMain, nbFinal := m.Unaligned.assign(run)
+	if nbMain+nbFinal > m.nbOperations() {
+		logrus.Errorf("limit overflow: the bls G1 MSM scalar multiplication count is %v and the limit is %v\n", nbMain+nbFinal, m.nbOperations())
+		arithmetization.PanicOverflow("bls_g1_msm_scalar_muls", nbMain+nbFinal, m.nbOperations())
+	}
+	m.AlignedGnarkData.Assign(run)
+}
+
+// g1MsmSettings returns the settings of the accumulation of the G1MSM calls.
+func g1MsmSettings(limits *Limits) accumulationSettings {
+	return accumulationSettings{
+		name:          NAME_G1_MSM + "_UNALIGNED",
+		size:          limits.sizeIntegration(nbRowsPerG1Msm),
+		nbAccLimbs:    nbG1Limbs,
+		nbInputLimbs:  nbG1Limbs + nbScalarLimbs,
+		nbResultLimbs: nbG1Limbs,
+		initialAcc:    make([]field.Element, nbG1Limbs), // the point at infinity
+		accumulate:    g1MsmAccumulate,
+	}
+}
+
+// g1MsmAccumulate returns acc + [s]P where input is the encoding of P
+// followed by the scalar s.
+func g1MsmAccumulate(acc, input []field.Element) []field.Element {
+	var (
+		a = g1FromLimbsNative(acc)
+		p = g1FromLimbsNative(input[:nbG1Limbs])
+		s = scalarFromLimbsNative(input[nbG1Limbs:])
+	)
+	p.ScalarMultiplication(&p, s)
+	a.Add(&a, &p)
+	return g1ToLimbs(a)
+}
+
+// G2Msm integrates the verification of the BLS12_G2MSM precompile calls in
+// the same way as [G1Msm].
+type G2Msm struct {
+	*Limits
+	Source           *MultiInputSource
+	Unaligned        *UnalignedAccumulationData
+	AlignedGnarkData *plonk.Alignment
+}
+
+func NewG2MsmZkEvm(comp *wizard.CompiledIOP, limits *Limits) *G2Msm {
+	if !isSupported(comp) {
+		return nil
+	}
+	return newG2Msm(
+		comp,
+		limits,
+		newMultiInputSourceZkEvm(comp, "G2_MSM"),
+		[]plonk.Option{plonk.WithRangecheck(16, 6, true)},
+	)
+}
+
+// newG2Msm creates a new G2MSM integration.
+func newG2Msm(comp *wizard.CompiledIOP, limits *Limits, src *MultiInputSource, plonkOptions []plonk.Option) *G2Msm {
+	unaligned := newUnalignedAccumulationData(comp, src, g2MsmSettings(limits))
+
+	toAlign := &plonk.CircuitAlignmentInput{
+		Name:               NAME_G2_MSM + "_ALIGNMENT",
+		Round:              ROUND_NR,
+		DataToCircuitMask:  unaligned.IsActive,
+		DataToCircuit:      unaligned.Limb,
+		Circuit:            NewG2MsmCircuit(limits),
+		NbCircuitInstances: limits.NbCircuitInstances,
+		PlonkOptions:       plonkOptions,
+		InputFiller:        nil, // not necessary: O + [0]O = O
+	}
+
+	return &G2Msm{
+		Limits:           limits,
+		Source:           src,
+		Unaligned:        unaligned,
+		AlignedGnarkData: plonk.DefineAlignment(comp, toAlign),
+	}
+}
+
+// Assign assigns the unaligned data and the gnark inputs.
+func (m *G2Msm) Assign(run *wizard.ProverRuntime) {
+	nbMain, nbFinal := m.Unaligned.assign(run)
+	if nbMain+nbFinal > m.nbOperations() {
+		logrus.Errorf("limit overflow: the bls G2 MSM scalar multiplication count is %v and the limit is %v\n", nbMain+nbFinal, m.nbOperations())
+		arithmetization.PanicOverflow("bls_g2_msm_scalar_muls", nbMain+nbFinal, m.nbOperations())
+	}
+	m.AlignedGnarkData.Assign(run)
+}
+
+// g2MsmSettings returns the settings of the accumulation of the G2MSM calls.
+func g2MsmSettings(limits *Limits) accumulationSettings {
+	return accumulationSettings{
+		name:          NAME_G2_MSM + "_UNALIGNED",
+		size:          limits.sizeIntegration(nbRowsPerG2Msm),
+		nbAccLimbs:    nbG2Limbs,
+		nbInputLimbs:  nbG2Limbs + nbScalarLimbs,
+		nbResultLimbs: nbG2Limbs,
+		initialAcc:    make([]field.Element, nbG2Limbs), // the point at infinity
+		accumulate:    g2MsmAccumulate,
+	}
+}
+
+// g2MsmAccumulate returns acc + [s]Q where input is the encoding of Q
+// followed by the scalar s.
+func g2MsmAccumulate(acc, input []field.Element) []field.Element {
+	var (
+		a = g2FromLimbsNative(acc)
+		q = g2FromLimbsNative(input[:nbG2Limbs])
+		s = scalarFromLimbsNative(input[nbG2Limbs:])
+	)
+	q.ScalarMultiplication(&q, s)
+	a.Add(&a, &q)
+	return g2ToLimbs(a)
+}
+
+// scalarFromLimbsNative returns the 256 bits scalar encoded by two limbs of
+// 128 bits in big-endian order.
+func scalarFromLimbsNative(limbs []field.Element) *big.Int {
+	var hi, lo big.Int
+	limbs[0].BigInt(&hi)
+	limbs[1].BigInt(&lo)
+	return hi.Lsh(&hi, 128).Add(&hi, &lo)
+}
+
+// MultiG1MsmCircuit is a circuit that can handle multiple steps of G1MSM
+// calls. The length of the slice Instances should correspond to the one
+// defined in the Limits struct.
+type MultiG1MsmCircuit struct {
+	Instances []G1MsmInstance
+}
+
+// G1MsmInstance is a step of a G1MSM call: AccCurr = AccPrev + [S]P.
+type G1MsmInstance struct {
+	AccPrev [nbG1Limbs]frontend.Variable     `gnark:",public"`
+	P       [nbG1Limbs]frontend.Variable     `gnark:",public"`
+	S       [nbScalarLimbs]frontend.Variable `gnark:",public"`
+	AccCurr [nbG1Limbs]frontend.Variable     `gnark:",public"`
+}
+
+// NewG1MsmCircuit creates a new circuit for verifying the steps of the G1MSM
+// precompile based on the defined number of inputs.
+func NewG1MsmCircuit(limits *Limits) *MultiG1MsmCircuit {
+	return &MultiG1MsmCircuit{
+		Instances: make([]G1MsmInstance, limits.NbInputInstances),
+	}
+}
+
+func (c *MultiG1MsmCircuit) Define(api frontend.API) error {
+
+	fp, err := emulated.NewField[baseField](api)
+	if err != nil {
+		return fmt.Errorf("field emulation: %w", err)
+	}
+	fr, err := emulated.NewField[scalarField](api)
+	if err != nil {
+		return fmt.Errorf("field emulation: %w", err)
+	}
+	curve, err := sw_emulated.New[baseField, scalarField](api, sw_emulated.GetBLS12381Params())
+	if err != nil {
+		return fmt.Errorf("new curve: %w", err)
+	}
+	pairing, err := sw_bls12381.NewPairing(api)
+	if err != nil {
+		return fmt.Errorf("new pairing: %w", err)
+	}
+
+	var (
+		arith          = newG1Arith(api, fp)
+		_, _, g1Gen, _ = bls12381.Generators()
+		generator      = sw_bls12381.NewG1Affine(g1Gen)
+	)
+
+	for i := range c.Instances {
+		var (
+			prev  = g1FromLimbs(api, fp, c.Instances[i].AccPrev[:])
+			p     = g1FromLimbs(api, fp, c.Instances[i].P[:])
+			s     = scalarFromLimbs(api, fr, c.Instances[i].S)
+			curr  = g1FromLimbs(api, fp, c.Instances[i].AccCurr[:])
+			isInf = arith.isInfinity(p)
+		)
+		// The points of G1MSM must be in the subgroup. The subgroup check
+		// does not support the point at infinity which is replaced by the
+		// generator.
+		pairing.AssertIsOnG1(arith.selectPoint(isInf, &generator, p))
+		sp := curve.ScalarMul(p, s, algopts.WithCompleteArithmetic())
+		curve.AssertIsEqual(arith.add(prev, sp), curr)
+	}
+	return nil
+}
+
+// MultiG2MsmCircuit is a circuit that can handle multiple steps of G2MSM
+// calls. The length of the slice Instances should correspond to the one
+// defined in the Limits struct.
+type MultiG2MsmCircuit struct {
+	Instances []G2MsmInstance
+}
+
+// G2MsmInstance is a step of a G2MSM call: AccCurr = AccPrev + [S]Q.
+type G2MsmInstance struct {
+	AccPrev [nbG2Limbs]frontend.Variable     `gnark:",public"`
+	Q       [nbG2Limbs]frontend.Variable     `gnark:",public"`
+	S       [nbScalarLimbs]frontend.Variable `gnark:",public"`
+	AccCurr [nbG2Limbs]frontend.Variable     `gnark:",public"`
+}
+
+// NewG2MsmCircuit creates a new circuit for verifying the steps of the G2MSM
+// precompile based on the defined number of inputs.
+func NewG2MsmCircuit(limits *Limits) *MultiG2MsmCircuit {
+	return &MultiG2MsmCircuit{
+		Instances: make([]G2MsmInstance, limits.NbInputInstances),
+	}
+}
+
+func (c *MultiG2MsmCircuit) Define(api frontend.API) error {
+
+	fp, err := emulated.NewField[baseField](api)
+	if err != nil {
+		return fmt.Errorf("field emulation: %w", err)
+	}
+	pairing, err := sw_bls12381.NewPairing(api)
+	if err != nil {
+		return fmt.Errorf("new pairing: %w", err)
+	}
+
+	var (
+		arith          = newG2Arith(api)
+		_, _, _, g2Gen = bls12381.Generators()
+		generator      = g2AffineConst(g2Gen)
+	)
+
+	for i := range c.Instances {
+		var (
+			prev  = g2FromLimbs(api, fp, c.Instances[i].AccPrev[:])
+			q     = g2FromLimbs(api, fp, c.Instances[i].Q[:])
+			curr  = g2FromLimbs(api, fp, c.Instances[i].AccCurr[:])
+			isInf = arith.isInfinity(q)
+			// the scalar is not reduced, we use all its 256 bits
+			bits = append(
+				api.ToBinary(c.Instances[i].S[1], 128),
+				api.ToBinary(c.Instances[i].S[0], 128)...,
+			)
+		)
+		// The points of G2MSM must be in the subgroup. The subgroup check
+		// does not support the point at infinity which is replaced by the
+		// generator.
+		pairing.AssertIsOnG2(arith.selectPoint(isInf, generator, q).toGnark())
+		arith.assertIsEqual(arith.add(prev, arith.scalarMul(q, bits)), curr)
+	}
+	return nil
+}
+
+// scalarFromLimbs returns the scalar encoded by two limbs of 128 bits in
+// big-endian order. The scalar is not reduced.
+func scalarFromLimbs(api frontend.API, fr *emulated.Field[scalarField], limbs [nbScalarLimbs]frontend.Variable) *emulated.Element[scalarField] {
+	res := make([]frontend.Variable, 4)
+	res[0], res[1] = bitslice.Partition(api, limbs[1], 64, bitslice.WithNbDigits(128))
+	res[2], res[3] = bitslice.Partition(api, limbs[0], 64, bitslice.WithNbDigits(128))
+	return fr.NewElement(res)
+}
+
+// g2AffineConst returns the constant point q.
+func g2AffineConst(q bls12381.G2Affine) *g2Affine {
+	return &g2Affine{
+		X: fields_bls12381.FromE2(&q.X),
+		Y: fields_bls12381.FromE2(&q.Y),
+	}
+}
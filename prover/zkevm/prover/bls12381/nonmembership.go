@@ -0,0 +1,320 @@
+package bls12381
+
+import (
+	"fmt"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381"
+	blsfp "github.com/consensys/gnark-crypto/ecc/bls12-381/fp"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/emulated"
+	"github.com/consensys/linea-monorepo/prover/maths/field"
+	"github.com/consensys/linea-monorepo/prover/protocol/dedicated/plonk"
+	"github.com/consensys/linea-monorepo/prover/protocol/ifaces"
+	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
+	sym "github.com/consensys/linea-monorepo/prover/symbolic"
+)
+
+const (
+	NAME_NON_MEMBERSHIP = "BLS_NON_MEMBERSHIP"
+)
+
+// The operations of BLS_DATA
+var blsOperations = []string{
+	"G1_ADD", "G2_ADD", "G1_MSM", "G2_MSM", "PAIRING_CHECK", "MAP_FP_TO_G1", "MAP_FP2_TO_G2",
+}
+
+// NonMembershipLimits defines the upper limits on the number of points of the
+// failing calls proven to be outside of the curves or of the subgroups.
+type NonMembershipLimits struct {
+	// points of E(Fp) which are not on the curve
+	C1 Limits
+	// points on E(Fp) which are not in the subgroup G1
+	G1 Limits
+	// points of E'(Fp2) which are not on the twist
+	C2 Limits
+	// points on E'(Fp2) which are not in the subgroup G2
+	G2 Limits
+}
+
+// NonMembershipSource holds the columns of the BLS_DATA module used to prove
+// the failing calls. The membership selectors flag the limbs of a point of a
+// failing call which is not on the curve (C1 and C2) or not in the subgroup
+// (G1 and G2). IsData and CsSelector are the IS_<op>_DATA and the circuit
+// selectors of all the operations.
+type NonMembershipSource struct {
+	Limb           ifaces.Column
+	SuccessBit     ifaces.Column
+	CsC1Membership ifaces.Column
+	CsG1Membership ifaces.Column
+	CsC2Membership ifaces.Column
+	CsG2Membership ifaces.Column
+	IsData         []ifaces.Column
+	CsSelector     []ifaces.Column
+}
+
+// NonMembership proves that the calls of BLS_DATA which are not forwarded to
+// the circuits of the operations have a point outside of the curve or of
+// the subgroup.
+type NonMembership struct {
+	*NonMembershipSource
+	AlignedC1 *plonk.Alignment
+	AlignedG1 *plonk.Alignment
+	AlignedC2 *plonk.Alignment
+	AlignedG2 *plonk.Alignment
+
+	*NonMembershipLimits
+}
+
+func NewNonMembershipZkEvm(comp *wizard.CompiledIOP, limits *NonMembershipLimits) *NonMembership {
+	if !isSupported(comp) {
+		return nil
+	}
+	src := &NonMembershipSource{
+		Limb:           comp.Columns.GetHandle("blsdata.LIMB"),
+		SuccessBit:     comp.Columns.GetHandle("blsdata.SUCCESS_BIT"),
+		CsC1Membership: comp.Columns.GetHandle("blsdata.CIRCUIT_SELECTOR_C1_MEMBERSHIP"),
+		CsG1Membership: comp.Columns.GetHandle("blsdata.CIRCUIT_SELECTOR_G1_MEMBERSHIP"),
+		CsC2Membership: comp.Columns.GetHandle("blsdata.CIRCUIT_SELECTOR_C2_MEMBERSHIP"),
+		CsG2Membership: comp.Columns.GetHandle("blsdata.CIRCUIT_SELECTOR_G2_MEMBERSHIP"),
+	}
+	for _, op := range blsOperations {
+		src.IsData = append(src.IsData, comp.Columns.GetHandle(ifaces.ColID("blsdata.IS_"+op+"_DATA")))
+		src.CsSelector = append(src.CsSelector, comp.Columns.GetHandle(ifaces.ColID("blsdata.CIRCUIT_SELECTOR_"+op)))
+	}
+	return newNonMembership(comp, limits, src, []plonk.Option{plonk.WithRangecheck(16, 6, true)})
+}
+
+// newNonMembership creates a new non-membership integration.
+func newNonMembership(comp *wizard.CompiledIOP, limits *NonMembershipLimits, src *NonMembershipSource, plonkOptions []plonk.Option) *NonMembership {
+
+	align := func(name string, mask ifaces.Column, circuit frontend.Circuit, l *Limits, filler []field.Element) *plonk.Alignment {
+		return plonk.DefineAlignment(comp, &plonk.CircuitAlignmentInput{
+			Name:               NAME_NON_MEMBERSHIP + "_" + name + "_ALIGNMENT",
+			Round:              ROUND_NR,
+			DataToCircuitMask:  mask,
+			DataToCircuit:      src.Limb,
+			Circuit:            circuit,
+			NbCircuitInstances: l.NbCircuitInstances,
+			PlonkOptions:       plonkOptions,
+			// the point at infinity is a member of every group so the
+			// unused instances are filled with non-members.
+			InputFiller: func(_, inputIndex int) field.Element {
+				return filler[inputIndex%len(filler)]
+			},
+		})
+	}
+
+	res := &NonMembership{
+		NonMembershipSource: src,
+		AlignedC1:           align("C1", src.CsC1Membership, NewC1NonMembershipCircuit(&limits.C1), &limits.C1, c1NonMemberFiller),
+		AlignedG1:           align("G1", src.CsG1Membership, NewG1NonMembershipCircuit(&limits.G1), &limits.G1, g1NonMemberFiller),
+		AlignedC2:           align("C2", src.CsC2Membership, NewC2NonMembershipCircuit(&limits.C2), &limits.C2, c2NonMemberFiller),
+		AlignedG2:           align("G2", src.CsG2Membership, NewG2NonMembershipCircuit(&limits.G2), &limits.G2, g2NonMemberFiller),
+		NonMembershipLimits: limits,
+	}
+
+	res.csCallsAreProven(comp)
+	res.csMembershipSelectors(comp)
+	return res
+}
+
+// csCallsAreProven ensures that the inputs of the successful calls are sent to
+// the circuits of their operation and that the inputs of the failing calls are
+// not. Otherwise, the arithmetization could skip the verification of any call
+// by claiming it fails.
+func (nm *NonMembership) csCallsAreProven(comp *wizard.CompiledIOP) {
+	for i := range nm.IsData {
+		comp.InsertGlobal(
+			ROUND_NR,
+			ifaces.QueryIDf("%v_SUCCESS_BIT_MATCHES_%v", NAME_NON_MEMBERSHIP, nm.CsSelector[i].GetColID()),
+			sym.Mul(nm.IsData[i], sym.Sub(nm.SuccessBit, nm.CsSelector[i])),
+		)
+	}
+}
+
+// csMembershipSelectors ensures that the membership selectors are only set on
+// the inputs of the failing calls.
+func (nm *NonMembership) csMembershipSelectors(comp *wizard.CompiledIOP) {
+
+	isData := make([]any, len(nm.IsData))
+	for i := range nm.IsData {
+		isData[i] = nm.IsData[i]
+	}
+
+	selectors := []struct {
+		name string
+		cs   ifaces.Column
+	}{
+		{"C1", nm.CsC1Membership},
+		{"G1", nm.CsG1Membership},
+		{"C2", nm.CsC2Membership},
+		{"G2", nm.CsG2Membership},
+	}
+
+	for _, s := range selectors {
+		comp.InsertGlobal(
+			ROUND_NR,
+			ifaces.QueryIDf("%v_%v_ONLY_ON_FAILING_INPUTS", NAME_NON_MEMBERSHIP, s.name),
+			sym.Add(
+				sym.Mul(s.cs, nm.SuccessBit),
+				sym.Mul(s.cs, sym.Sub(1, sym.Add(isData...))),
+			),
+		)
+	}
+}
+
+// Assign assigns the data from the trace to the gnark inputs.
+func (nm *NonMembership) Assign(run *wizard.ProverRuntime) {
+	nm.AlignedC1.Assign(run)
+	nm.AlignedG1.Assign(run)
+	nm.AlignedC2.Assign(run)
+	nm.AlignedG2.Assign(run)
+}
+
+// The fillers of the unused instances: (0, 1) is on neither of the curves and
+// the fillers of the subgroup checks are the first points of the curves found
+// by incrementing x which are not in the subgroups.
+var (
+	c1NonMemberFiller = g1ToLimbs(bls12381.G1Affine{Y: blsfp.One()})
+	c2NonMemberFiller = g2ToLimbs(bls12381.G2Affine{Y: bls12381.E2{A0: blsfp.One()}})
+
+	g1NonMemberFiller = func() []field.Element {
+		var p bls12381.G1Affine
+		for x := uint64(0); ; x++ {
+			var rhs blsfp.Element
+			p.X.SetUint64(x)
+			rhs.Square(&p.X).Mul(&rhs, &p.X).Add(&rhs, new(blsfp.Element).SetUint64(4))
+			if p.Y.Sqrt(&rhs) != nil && !p.IsInSubGroup() {
+				return g1ToLimbs(p)
+			}
+		}
+	}()
+
+	g2NonMemberFiller = func() []field.Element {
+		var (
+			p      bls12381.G2Affine
+			bTwist = bls12381.E2{A0: blsfp.NewElement(4), A1: blsfp.NewElement(4)}
+		)
+		for x := uint64(0); ; x++ {
+			var rhs bls12381.E2
+			p.X.A0.SetUint64(x)
+			rhs.Square(&p.X).Mul(&rhs, &p.X).Add(&rhs, &bTwist)
+			if rhs.Legendre() < 0 {
+				continue
+			}
+			p.Y.Sqrt(&rhs)
+			if !p.IsInSubGroup() {
+				return g2ToLimbs(p)
+			}
+		}
+	}()
+)
+
+// MultiC1NonMembershipCircuit is a circuit proving that points are not on the
+// curve E(Fp). The length of the slice Instances should correspond to the one
+// defined in the Limits struct.
+type MultiC1NonMembershipCircuit struct {
+	Instances []G1NonMembershipInstance
+}
+
+// MultiG1NonMembershipCircuit is a circuit proving that points of the curve
+// E(Fp) are not in the subgroup G1.
+type MultiG1NonMembershipCircuit struct {
+	Instances []G1NonMembershipInstance
+}
+
+// G1NonMembershipInstance stores a point of E(Fp) in limbs of 128 bits as
+// laid out in the arithmetization.
+type G1NonMembershipInstance struct {
+	P [nbG1Limbs]frontend.Variable `gnark:",public"`
+}
+
+// MultiC2NonMembershipCircuit is a circuit proving that points are not on the
+// twist E'(Fp2).
+type MultiC2NonMembershipCircuit struct {
+	Instances []G2NonMembershipInstance
+}
+
+// MultiG2NonMembershipCircuit is a circuit proving that points of the twist
+// E'(Fp2) are not in the subgroup G2.
+type MultiG2NonMembershipCircuit struct {
+	Instances []G2NonMembershipInstance
+}
+
+// G2NonMembershipInstance stores a point of E'(Fp2) in limbs of 128 bits as
+// laid out in the arithmetization.
+type G2NonMembershipInstance struct {
+	Q [nbG2Limbs]frontend.Variable `gnark:",public"`
+}
+
+func NewC1NonMembershipCircuit(limits *Limits) *MultiC1NonMembershipCircuit {
+	return &MultiC1NonMembershipCircuit{Instances: make([]G1NonMembershipInstance, limits.NbInputInstances)}
+}
+
+func NewG1NonMembershipCircuit(limits *Limits) *MultiG1NonMembershipCircuit {
+	return &MultiG1NonMembershipCircuit{Instances: make([]G1NonMembershipInstance, limits.NbInputInstances)}
+}
+
+func NewC2NonMembershipCircuit(limits *Limits) *MultiC2NonMembershipCircuit {
+	return &MultiC2NonMembershipCircuit{Instances: make([]G2NonMembershipInstance, limits.NbInputInstances)}
+}
+
+func NewG2NonMembershipCircuit(limits *Limits) *MultiG2NonMembershipCircuit {
+	return &MultiG2NonMembershipCircuit{Instances: make([]G2NonMembershipInstance, limits.NbInputInstances)}
+}
+
+func (c *MultiC1NonMembershipCircuit) Define(api frontend.API) error {
+	fp, err := emulated.NewField[baseField](api)
+	if err != nil {
+		return fmt.Errorf("field emulation: %w", err)
+	}
+	arith := newG1Arith(api, fp)
+	for i := range c.Instances {
+		// The precompiles check the encoding before the curve so the
+		// coordinates of the point are canonical.
+		p := g1FromLimbs(api, fp, c.Instances[i].P[:])
+		api.AssertIsEqual(arith.isOnCurve(p), 0)
+	}
+	return nil
+}
+
+func (c *MultiG1NonMembershipCircuit) Define(api frontend.API) error {
+	fp, err := emulated.NewField[baseField](api)
+	if err != nil {
+		return fmt.Errorf("field emulation: %w", err)
+	}
+	arith := newG1Arith(api, fp)
+	for i := range c.Instances {
+		p := g1FromLimbs(api, fp, c.Instances[i].P[:])
+		api.AssertIsEqual(arith.isOnCurve(p), 1)
+		api.AssertIsEqual(arith.isInSubgroup(p), 0)
+	}
+	return nil
+}
+
+func (c *MultiC2NonMembershipCircuit) Define(api frontend.API) error {
+	fp, err := emulated.NewField[baseField](api)
+	if err != nil {
+		return fmt.Errorf("field emulation: %w", err)
+	}
+	arith := newG2Arith(api)
+	for i := range c.Instances {
+		q := g2FromLimbs(api, fp, c.Instances[i].Q[:])
+		api.AssertIsEqual(arith.isOnTwist(q), 0)
+	}
+	return nil
+}
+
+func (c *MultiG2NonMembershipCircuit) Define(api frontend.API) error {
+	fp, err := emulated.NewField[baseField](api)
+	if err != nil {
+		return fmt.Errorf("field emulation: %w", err)
+	}
+	arith := newG2Arith(api)
+	for i := range c.Instances {
+		q := g2FromLimbs(api, fp, c.Instances[i].Q[:])
+		api.AssertIsEqual(arith.isOnTwist(q), 1)
+		api.AssertIsEqual(arith.isInSubgroup(q), 0)
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+//go:build !fuzzlight
+
+package bls12381
+
+import (
+	"testing"
+
+	"github.com/consensys/linea-monorepo/prover/protocol/compiler/dummy"
+	"github.com/consensys/linea-monorepo/prover/protocol/dedicated/plonk"
+	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
+	"github.com/consensys/linea-monorepo/prover/utils/csvtraces"
+)
+
+func TestNonMembershipIntegration(t *testing.T) {
+	limits := &NonMembershipLimits{
+		C1: Limits{NbInputInstances: 2, NbCircuitInstances: 1},
+		G1: Limits{NbInputInstances: 1, NbCircuitInstances: 1},
+		C2: Limits{NbInputInstances: 2, NbCircuitInstances: 1},
+		G2: Limits{NbInputInstances: 1, NbCircuitInstances: 1},
+	}
+	ct := csvtraces.MustOpenCsvFile("testdata/non_membership_test.csv")
+	var nm *NonMembership
+	cmp := wizard.Compile(
+		func(b *wizard.Builder) {
+			src := &NonMembershipSource{
+				Limb:           ct.GetCommit(b, "LIMB"),
+				SuccessBit:     ct.GetCommit(b, "SUCCESS_BIT"),
+				CsC1Membership: ct.GetCommit(b, "CS_C1_MEMBERSHIP"),
+				CsG1Membership: ct.GetCommit(b, "CS_G1_MEMBERSHIP"),
+				CsC2Membership: ct.GetCommit(b, "CS_C2_MEMBERSHIP"),
+				CsG2Membership: ct.GetCommit(b, "CS_G2_MEMBERSHIP"),
+			}
+			for _, op := range []string{"G1_ADD", "G2_ADD", "G1_MSM", "G2_MSM"} {
+				src.IsData = append(src.IsData, ct.GetCommit(b, "IS_"+op+"_DATA"))
+				src.CsSelector = append(src.CsSelector, ct.GetCommit(b, "CS_"+op))
+			}
+			nm = newNonMembership(b.CompiledIOP, limits, src, []plonk.Option{plonk.WithRangecheck(16, 6, true)})
+		},
+		dummy.Compile,
+	)
+
+	proof := wizard.Prove(cmp,
+		func(run *wizard.ProverRuntime) {
+			ct.Assign(run,
+				"LIMB", "SUCCESS_BIT", "CS_C1_MEMBERSHIP", "CS_G1_MEMBERSHIP", "CS_C2_MEMBERSHIP", "CS_G2_MEMBERSHIP",
+				"IS_G1_ADD_DATA", "CS_G1_ADD", "IS_G2_ADD_DATA", "CS_G2_ADD",
+				"IS_G1_MSM_DATA", "CS_G1_MSM", "IS_G2_MSM_DATA", "CS_G2_MSM",
+			)
+			nm.Assign(run)
+		})
+
+	if err := wizard.Verify(cmp, proof); err != nil {
+		t.Fatal("proof failed", err)
+	}
+
+	t.Log("proof succeeded")
+}
@@ -0,0 +1,333 @@
+package bls12381
+
+import (
+	"fmt"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/emulated/fields_bls12381"
+	"github.com/consensys/gnark/std/algebra/emulated/sw_bls12381"
+	"github.com/consensys/gnark/std/math/emulated"
+	"github.com/consensys/linea-monorepo/prover/maths/field"
+	"github.com/consensys/linea-monorepo/prover/protocol/dedicated/plonk"
+	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
+	"github.com/consensys/linea-monorepo/prover/utils"
+	"github.com/consensys/linea-monorepo/prover/zkevm/arithmetization"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	NAME_PAIRING = "BLS_PAIRING_INTEGRATION"
+)
+
+const (
+	// the previous and the current accumulators and the pair of points
+	nbRowsPerMillerLoop = 2*nbGtLimbs + nbG1Limbs + nbG2Limbs
+	// the previous accumulator, the last pair of points and the result
+	nbRowsPerFinalExp = nbGtLimbs + nbG1Limbs + nbG2Limbs + nbPairingResultLimbs
+)
+
+// Pairing integrates the verification of the BLS12_PAIRING_CHECK precompile
+// calls. As for ECPAIR, the Miller loops of the pairs are accumulated in GT
+// and checked by one circuit, while the last Miller loop and the final
+// exponentiation are checked by another circuit.
+type Pairing struct {
+	*PairingLimits
+	Source    *MultiInputSource
+	Unaligned *UnalignedAccumulationData
+
+	AlignedMillerLoopCircuit *plonk.Alignment
+	AlignedFinalExpCircuit   *plonk.Alignment
+}
+
+func NewPairingZkEvm(comp *wizard.CompiledIOP, limits *PairingLimits) *Pairing {
+	if !isSupported(comp) {
+		return nil
+	}
+	return newPairing(
+		comp,
+		limits,
+		newMultiInputSourceZkEvm(comp, "PAIRING_CHECK"),
+		[]plonk.Option{plonk.WithRangecheck(16, 6, true)},
+	)
+}
+
+// newPairing creates a new pairing check integration.
+func newPairing(comp *wizard.CompiledIOP, limits *PairingLimits, src *MultiInputSource, plonkOptions []plonk.Option) *Pairing {
+	unaligned := newUnalignedAccumulationData(comp, src, pairingSettings(limits))
+
+	alignInputMillerLoop := &plonk.CircuitAlignmentInput{
+		Name:               NAME_PAIRING + "_ALIGNMENT_ML",
+		Round:              ROUND_NR,
+		DataToCircuit:      unaligned.Limb,
+		DataToCircuitMask:  unaligned.ToMainCircuitMask,
+		Circuit:            NewMillerLoopCircuit(limits),
+		InputFiller:        inputFillerMillerLoop,
+		PlonkOptions:       plonkOptions,
+		NbCircuitInstances: limits.NbMillerLoopCircuits,
+	}
+
+	alignInputFinalExp := &plonk.CircuitAlignmentInput{
+		Name:               NAME_PAIRING + "_ALIGNMENT_FINALEXP",
+		Round:              ROUND_NR,
+		DataToCircuit:      unaligned.Limb,
+		DataToCircuitMask:  unaligned.ToFinalCircuitMask,
+		Circuit:            NewFinalExpCircuit(limits),
+		InputFiller:        inputFillerFinalExp,
+		PlonkOptions:       plonkOptions,
+		NbCircuitInstances: limits.NbFinalExpCircuits,
+	}
+
+	return &Pairing{
+		PairingLimits:            limits,
+		Source:                   src,
+		Unaligned:                unaligned,
+		AlignedMillerLoopCircuit: plonk.DefineAlignment(comp, alignInputMillerLoop),
+		AlignedFinalExpCircuit:   plonk.DefineAlignment(comp, alignInputFinalExp),
+	}
+}
+
+// Assign assigns the unaligned data and the gnark inputs.
+func (p *Pairing) Assign(run *wizard.ProverRuntime) {
+	nbMillerLoops, nbFinalExps := p.Unaligned.assign(run)
+
+	if nbMillerLoops > p.nbMillerLoops() {
+		logrus.Errorf("limit overflow: the bls pairing Miller loop count is %v and the limit is %v\n", nbMillerLoops, p.nbMillerLoops())
+		arithmetization.PanicOverflow("bls_pairing_miller_loops", nbMillerLoops, p.nbMillerLoops())
+	}
+
+	if nbFinalExps > p.nbFinalExps() {
+		logrus.Errorf("limit overflow: the bls pairing final exponentiation count is %v and the limit is %v\n", nbFinalExps, p.nbFinalExps())
+		arithmetization.PanicOverflow("bls_pairing_final_exponentiations", nbFinalExps, p.nbFinalExps())
+	}
+
+	p.AlignedMillerLoopCircuit.Assign(run)
+	p.AlignedFinalExpCircuit.Assign(run)
+}
+
+// pairingSettings returns the settings of the accumulation of the pairing
+// checks.
+func pairingSettings(limits *PairingLimits) accumulationSettings {
+	return accumulationSettings{
+		name:          NAME_PAIRING + "_UNALIGNED",
+		size:          limits.sizePairing(),
+		nbAccLimbs:    nbGtLimbs,
+		nbInputLimbs:  nbG1Limbs + nbG2Limbs,
+		nbResultLimbs: nbPairingResultLimbs,
+		initialAcc:    gtOneLimbs,
+		accumulate:    pairingAccumulate,
+	}
+}
+
+// gtOneLimbs are the limbs of the neutral element of GT.
+var gtOneLimbs = func() []field.Element {
+	var one bls12381.GT
+	one.SetOne()
+	return gtToLimbs(one)
+}()
+
+// pairingAccumulate returns acc * MillerLoop(P, Q) where input is the encoding
+// of P followed by the encoding of Q. If any of the points is at infinity, the
+// accumulator is unchanged.
+func pairingAccumulate(acc, input []field.Element) []field.Element {
+	var (
+		a = gtFromLimbsNative(acc)
+		p = g1FromLimbsNative(input[:nbG1Limbs])
+		q = g2FromLimbsNative(input[nbG1Limbs:])
+	)
+
+	if p.IsInfinity() || q.IsInfinity() {
+		return gtToLimbs(a)
+	}
+
+	// Miller loop with and without line precomputations give different
+	// results. As in-circuit we're using the variant with precomputation, we
+	// use it here as well.
+	lines := bls12381.PrecomputeLines(q)
+	ml, err := bls12381.MillerLoopFixedQ(
+		[]bls12381.G1Affine{p},
+		[][2][len(bls12381.LoopCounter) - 1]bls12381.LineEvaluationAff{lines},
+	)
+	if err != nil {
+		utils.Panic("bls pairing: failed to compute miller loop: %v", err)
+	}
+
+	a.Mul(&a, &ml)
+	return gtToLimbs(a)
+}
+
+func inputFillerMillerLoop(_, inputIndex int) field.Element {
+	// the accumulators are 1 and the points are at infinity
+	if inputIndex%nbRowsPerMillerLoop < nbGtLimbs {
+		return gtOneLimbs[inputIndex%nbRowsPerMillerLoop]
+	}
+	if inputIndex%nbRowsPerMillerLoop >= nbGtLimbs+nbG1Limbs+nbG2Limbs {
+		return gtOneLimbs[inputIndex%nbRowsPerMillerLoop-nbGtLimbs-nbG1Limbs-nbG2Limbs]
+	}
+	return field.Zero()
+}
+
+func inputFillerFinalExp(_, inputIndex int) field.Element {
+	// the accumulator is 1, the points are at infinity and the check succeeds
+	if inputIndex%nbRowsPerFinalExp < nbGtLimbs {
+		return gtOneLimbs[inputIndex%nbRowsPerFinalExp]
+	}
+	if inputIndex%nbRowsPerFinalExp == nbRowsPerFinalExp-1 {
+		return field.One()
+	}
+	return field.Zero()
+}
+
+// MultiMillerLoopCircuit is a circuit that can handle multiple Miller loop
+// steps of the pairing checks. The length of the slice Instances should
+// correspond to the one defined in the PairingLimits struct.
+type MultiMillerLoopCircuit struct {
+	Instances []MillerLoopInstance
+}
+
+// MillerLoopInstance is an intermediate step of a pairing check:
+// AccCurr = AccPrev * MillerLoop(P, Q).
+type MillerLoopInstance struct {
+	AccPrev [nbGtLimbs]frontend.Variable `gnark:",public"`
+	P       [nbG1Limbs]frontend.Variable `gnark:",public"`
+	Q       [nbG2Limbs]frontend.Variable `gnark:",public"`
+	AccCurr [nbGtLimbs]frontend.Variable `gnark:",public"`
+}
+
+// NewMillerLoopCircuit creates a new circuit for verifying the intermediate
+// steps of the pairing checks.
+func NewMillerLoopCircuit(limits *PairingLimits) *MultiMillerLoopCircuit {
+	return &MultiMillerLoopCircuit{
+		Instances: make([]MillerLoopInstance, limits.NbMillerLoopInputInstances),
+	}
+}
+
+func (c *MultiMillerLoopCircuit) Define(api frontend.API) error {
+	pc, err := newPairingChecker(api)
+	if err != nil {
+		return err
+	}
+	for i := range c.Instances {
+		acc := pc.accumulate(c.Instances[i].AccPrev, c.Instances[i].P, c.Instances[i].Q)
+		pc.ext12.AssertIsEqual(acc, gtFromLimbs(api, pc.ext12, pc.fp, c.Instances[i].AccCurr[:]))
+	}
+	return nil
+}
+
+// MultiFinalExpCircuit is a circuit that can handle multiple final steps of
+// the pairing checks. The length of the slice Instances should correspond to
+// the one defined in the PairingLimits struct.
+type MultiFinalExpCircuit struct {
+	Instances []FinalExpInstance
+}
+
+// FinalExpInstance is the last step of a pairing check: the result is 1 if
+// FinalExponentiation(AccPrev * MillerLoop(P, Q)) == 1 and 0 otherwise.
+type FinalExpInstance struct {
+	AccPrev [nbGtLimbs]frontend.Variable            `gnark:",public"`
+	P       [nbG1Limbs]frontend.Variable            `gnark:",public"`
+	Q       [nbG2Limbs]frontend.Variable            `gnark:",public"`
+	Result  [nbPairingResultLimbs]frontend.Variable `gnark:",public"`
+}
+
+// NewFinalExpCircuit creates a new circuit for verifying the final steps of
+// the pairing checks.
+func NewFinalExpCircuit(limits *PairingLimits) *MultiFinalExpCircuit {
+	return &MultiFinalExpCircuit{
+		Instances: make([]FinalExpInstance, limits.NbFinalExpInputInstances),
+	}
+}
+
+func (c *MultiFinalExpCircuit) Define(api frontend.API) error {
+	pc, err := newPairingChecker(api)
+	if err != nil {
+		return err
+	}
+	for i := range c.Instances {
+		var (
+			acc   = pc.accumulate(c.Instances[i].AccPrev, c.Instances[i].P, c.Instances[i].Q)
+			isOne = pc.ext12.IsEqual(pc.pairing.FinalExponentiation(acc), pc.ext12.One())
+		)
+		api.AssertIsEqual(c.Instances[i].Result[0], 0)
+		api.AssertIsEqual(c.Instances[i].Result[1], isOne)
+	}
+	return nil
+}
+
+// pairingChecker gathers the gadgets shared by the pairing circuits.
+type pairingChecker struct {
+	api        frontend.API
+	fp         *emulated.Field[baseField]
+	ext12      *fields_bls12381.Ext12
+	pairing    *sw_bls12381.Pairing
+	g1         *g1Arith
+	g2         *g2Arith
+	g1Gen      sw_bls12381.G1Affine
+	g2GenTwist *g2Affine
+}
+
+func newPairingChecker(api frontend.API) (*pairingChecker, error) {
+	fp, err := emulated.NewField[baseField](api)
+	if err != nil {
+		return nil, fmt.Errorf("field emulation: %w", err)
+	}
+	pairing, err := sw_bls12381.NewPairing(api)
+	if err != nil {
+		return nil, fmt.Errorf("new pairing: %w", err)
+	}
+	_, _, g1Gen, g2Gen := bls12381.Generators()
+	return &pairingChecker{
+		api:        api,
+		fp:         fp,
+		ext12:      fields_bls12381.NewExt12(api),
+		pairing:    pairing,
+		g1:         newG1Arith(api, fp),
+		g2:         newG2Arith(api),
+		g1Gen:      sw_bls12381.NewG1Affine(g1Gen),
+		g2GenTwist: g2AffineConst(g2Gen),
+	}, nil
+}
+
+// accumulate returns accPrev * MillerLoop(P, Q) after checking that P and Q
+// are in the subgroups. The Miller loop is replaced by 1 when one of the
+// points is at infinity.
+func (pc *pairingChecker) accumulate(accPrev [nbGtLimbs]frontend.Variable, pLimbs [nbG1Limbs]frontend.Variable, qLimbs [nbG2Limbs]frontend.Variable) *sw_bls12381.GTEl {
+	var (
+		api   = pc.api
+		prev  = gtFromLimbs(api, pc.ext12, pc.fp, accPrev[:])
+		p     = g1FromLimbs(api, pc.fp, pLimbs[:])
+		q     = g2FromLimbs(api, pc.fp, qLimbs[:])
+		pInf  = pc.g1.isInfinity(p)
+		qInf  = pc.g2.isInfinity(q)
+		isInf = api.Or(pInf, qInf)
+	)
+
+	// The subgroup checks and the Miller loop do not support the point at
+	// infinity which is replaced by the generator.
+	var (
+		pSafe = pc.g1.selectPoint(pInf, &pc.g1Gen, p)
+		qSafe = pc.g2.selectPoint(qInf, pc.g2GenTwist, q).toGnark()
+	)
+	pc.pairing.AssertIsOnG1(pSafe)
+	pc.pairing.AssertIsOnG2(qSafe)
+
+	ml, err := pc.pairing.MillerLoop([]*sw_bls12381.G1Affine{pSafe}, []*sw_bls12381.G2Affine{qSafe})
+	if err != nil {
+		panic(fmt.Sprintf("miller loop: %v", err))
+	}
+
+	return pc.ext12.Mul(prev, pc.selectGt(isInf, pc.ext12.One(), ml))
+}
+
+// selectGt returns a if sel is 1 and b otherwise.
+func (pc *pairingChecker) selectGt(sel frontend.Variable, a, b *sw_bls12381.GTEl) *sw_bls12381.GTEl {
+	var (
+		ta  = pc.ext12.ToTower(a)
+		tb  = pc.ext12.ToTower(b)
+		res [12]*baseEl
+	)
+	for i := range res {
+		res[i] = pc.fp.Select(sel, ta[i], tb[i])
+	}
+	return pc.ext12.FromTower(res)
+}
@@ -0,0 +1,69 @@
+package bls12381
+
+import (
+	"github.com/consensys/linea-monorepo/prover/protocol/ifaces"
+	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
+)
+
+const (
+	ROUND_NR = 0
+)
+
+// isSupported returns true if the arithmetization defines the BLS_DATA module.
+func isSupported(comp *wizard.CompiledIOP) bool {
+	return comp.Columns.Exists("blsdata.LIMB")
+}
+
+// DataSource is a struct that holds the columns that are used to fetch the
+// data of an operation with a fixed number of inputs from the BLS_DATA module
+// of the arithmetization.
+type DataSource struct {
+	CsSelector ifaces.Column
+	Limb       ifaces.Column
+	Index      ifaces.Column
+	IsData     ifaces.Column
+	IsRes      ifaces.Column
+}
+
+// newDataSourceZkEvm returns the source columns of the operation op, for
+// instance "G1_ADD".
+func newDataSourceZkEvm(comp *wizard.CompiledIOP, op string) *DataSource {
+	return &DataSource{
+		CsSelector: comp.Columns.GetHandle(ifaces.ColID("blsdata.CIRCUIT_SELECTOR_" + op)),
+		Limb:       comp.Columns.GetHandle("blsdata.LIMB"),
+		Index:      comp.Columns.GetHandle("blsdata.INDEX"),
+		IsData:     comp.Columns.GetHandle(ifaces.ColID("blsdata.IS_" + op + "_DATA")),
+		IsRes:      comp.Columns.GetHandle(ifaces.ColID("blsdata.IS_" + op + "_RESULT")),
+	}
+}
+
+// MultiInputSource holds the columns used to fetch the data of the MSMs and of
+// the pairing check from the BLS_DATA module. These operations take a variable
+// number of inputs: AccInputs numbers the inputs of a call starting from 1
+// and is zero on the result rows, TotalInputs is the number of inputs of the
+// call and ID identifies the call.
+type MultiInputSource struct {
+	ID          ifaces.Column
+	CsSelector  ifaces.Column
+	Limb        ifaces.Column
+	Index       ifaces.Column
+	AccInputs   ifaces.Column
+	TotalInputs ifaces.Column
+	IsData      ifaces.Column
+	IsRes       ifaces.Column
+}
+
+// newMultiInputSourceZkEvm returns the source columns of the operation op, for
+// instance "G1_MSM".
+func newMultiInputSourceZkEvm(comp *wizard.CompiledIOP, op string) *MultiInputSource {
+	return &MultiInputSource{
+		ID:          comp.Columns.GetHandle("blsdata.ID"),
+		CsSelector:  comp.Columns.GetHandle(ifaces.ColID("blsdata.CIRCUIT_SELECTOR_" + op)),
+		Limb:        comp.Columns.GetHandle("blsdata.LIMB"),
+		Index:       comp.Columns.GetHandle("blsdata.INDEX"),
+		AccInputs:   comp.Columns.GetHandle("blsdata.ACC_INPUTS"),
+		TotalInputs: comp.Columns.GetHandle("blsdata.TOTAL_INPUTS"),
+		IsData:      comp.Columns.GetHandle(ifaces.ColID("blsdata.IS_" + op + "_DATA")),
+		IsRes:       comp.Columns.GetHandle(ifaces.ColID("blsdata.IS_" + op + "_RESULT")),
+	}
+}
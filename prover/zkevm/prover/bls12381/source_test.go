@@ -0,0 +1,100 @@
+package bls12381
+
+import (
+	"testing"
+
+	"github.com/consensys/go-corset/pkg/air"
+	"github.com/consensys/go-corset/pkg/schema"
+	"github.com/consensys/go-corset/pkg/trace"
+	"github.com/consensys/linea-monorepo/prover/config"
+	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
+	"github.com/consensys/linea-monorepo/prover/zkevm/arithmetization"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestZkEvmUnsupported(t *testing.T) {
+
+	var (
+		limits = &Limits{NbInputInstances: 1, NbCircuitInstances: 1}
+		built  []any
+	)
+
+	// The arithmetization has no BLS_DATA module
+	wizard.Compile(func(b *wizard.Builder) {
+		b.RegisterCommit("ecdata.LIMB", 16)
+		comp := b.CompiledIOP
+		built = []any{
+			NewG1AddZkEvm(comp, limits),
+			NewG2AddZkEvm(comp, limits),
+			NewG1MsmZkEvm(comp, limits),
+			NewG2MsmZkEvm(comp, limits),
+			NewPairingZkEvm(comp, &PairingLimits{}),
+			NewMapFpToG1ZkEvm(comp, limits),
+			NewMapFp2ToG2ZkEvm(comp, limits),
+			NewNonMembershipZkEvm(comp, &NonMembershipLimits{}),
+		}
+	})
+
+	for i := range built {
+		assert.Nil(t, built[i], "module %v", i)
+	}
+}
+
+// TestZkEvmSupported builds the modules against an arithmetization whose
+// schema defines the BLS_DATA module. The columns are sized by the limit of
+// the module in the trace limits.
+func TestZkEvmSupported(t *testing.T) {
+
+	var (
+		sch      = air.EmptySchema[air.Expr]()
+		ctx      = trace.NewContext(sch.AddModule("blsdata"), 1)
+		columns  = []string{"ID", "LIMB", "INDEX", "ACC_INPUTS", "TOTAL_INPUTS", "SUCCESS_BIT"}
+		limits   = &Limits{NbInputInstances: 1, NbCircuitInstances: 1}
+		tlimits  = &config.TracesLimits{Blsdata: 1 << 10}
+		limbSize int
+		built    []any
+	)
+
+	for _, op := range blsOperations {
+		columns = append(columns, "CIRCUIT_SELECTOR_"+op, "IS_"+op+"_DATA", "IS_"+op+"_RESULT")
+	}
+
+	for _, m := range []string{"C1", "G1", "C2", "G2"} {
+		columns = append(columns, "CIRCUIT_SELECTOR_"+m+"_MEMBERSHIP")
+	}
+
+	for _, name := range columns {
+		sch.AddColumn(ctx, name, schema.NewUintType(128))
+	}
+
+	wizard.Compile(func(b *wizard.Builder) {
+		comp := b.CompiledIOP
+		arithmetization.Define(comp, sch, tlimits)
+		limbSize = comp.Columns.GetHandle("blsdata.LIMB").Size()
+		built = []any{
+			NewG1AddZkEvm(comp, limits),
+			NewG2AddZkEvm(comp, limits),
+			NewG1MsmZkEvm(comp, limits),
+			NewG2MsmZkEvm(comp, limits),
+			NewPairingZkEvm(comp, &PairingLimits{
+				NbMillerLoopInputInstances: 1,
+				NbMillerLoopCircuits:       1,
+				NbFinalExpInputInstances:   1,
+				NbFinalExpCircuits:         1,
+			}),
+			NewMapFpToG1ZkEvm(comp, limits),
+			NewMapFp2ToG2ZkEvm(comp, limits),
+			NewNonMembershipZkEvm(comp, &NonMembershipLimits{
+				C1: *limits,
+				G1: *limits,
+				C2: *limits,
+				G2: *limits,
+			}),
+		}
+	})
+
+	assert.Equal(t, tlimits.Blsdata, limbSize)
+	for i := range built {
+		assert.NotNil(t, built[i], "module %v", i)
+	}
+}
@@ -0,0 +1,382 @@
+package main
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"math/rand"
+	"os"
+	"strings"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381"
+	blsfp "github.com/consensys/gnark-crypto/ecc/bls12-381/fp"
+	"github.com/consensys/linea-monorepo/prover/backend/files"
+)
+
+// vector is a test vector of the EIP-2537 precompiles as found in the test
+// suite of go-ethereum.
+type vector struct {
+	Name     string
+	Input    string
+	Expected string
+}
+
+// inputs of a call with a variable number of inputs: the size in bytes of
+// one input.
+const (
+	g1MsmInputBytes   = 128 + 32
+	pairingInputBytes = 128 + 256
+)
+
+func main() {
+
+	var (
+		rng     = rand.New(rand.NewSource(2537))
+		vectors = readVectors("./eip2537_vectors.json")
+	)
+
+	// G1ADD with the fixed number of inputs layout
+	tab := make([][]*big.Int, 5)
+	pushFillerToInput(tab, rng, 5)
+	pushFixedCallToInput(tab, vectors["g1_add"][0])
+	pushFillerToInput(tab, rng, 3)
+	pushFixedCallToInput(tab, vectors["g1_add"][2])
+	pushFillerToInput(tab, rng, 2)
+
+	f := files.MustOverwrite("./g1add_test.csv")
+	dumpFixedInputAsCsv(f, tab, "CS_G1_ADD")
+	f.Close()
+
+	// G1MSM with a single input and with several inputs
+	tab = make([][]*big.Int, 8)
+	pushMultiFillerToInput(tab, rng, 1, 4)
+	pushMultiCallToInput(tab, 2, vectors["g1_msm"][0], g1MsmInputBytes)
+	pushMultiFillerToInput(tab, rng, 3, 7)
+	pushMultiCallToInput(tab, 4, vectors["g1_msm"][1], g1MsmInputBytes)
+
+	f = files.MustOverwrite("./g1msm_test.csv")
+	dumpMultiInputAsCsv(f, tab, "CS_G1_MSM")
+	f.Close()
+
+	// PAIRING_CHECK: a successful and a failing check of two pairs and a
+	// check of a single pair
+	tab = make([][]*big.Int, 8)
+	pushMultiCallToInput(tab, 1, vectors["pairing_check"][0], pairingInputBytes)
+	pushMultiFillerToInput(tab, rng, 2, 5)
+	pushMultiCallToInput(tab, 3, vectors["pairing_check"][1], pairingInputBytes)
+	pushMultiCallToInput(tab, 4, vectors["pairing_check"][2], pairingInputBytes)
+	pushMultiFillerToInput(tab, rng, 5, 3)
+
+	f = files.MustOverwrite("./pairing_test.csv")
+	dumpMultiInputAsCsv(f, tab, "CS_PAIRING_CHECK")
+	f.Close()
+
+	// Non-membership: successful additions and failing calls with a point
+	// outside of each of the curves and of each of the subgroups.
+	var (
+		_, _, g1Gen, g2Gen = bls12381.Generators()
+		scalarTwo          = make([]byte, 32)
+		nm                 = newNonMembershipTable()
+	)
+	scalarTwo[31] = 2
+
+	nm.pushFiller(rng, 3)
+	nm.pushCall("G1_ADD", vectors["g1_add"][0], true, "", 0)
+	nm.pushCall("G1_ADD", vector{Input: encodeG1(g1NotOnCurve()) + encodeG1(g1Gen)}, false, "C1", g1Limbs)
+	nm.pushFiller(rng, 2)
+	nm.pushCall("G1_MSM", vector{Input: encodeG1(g1NotInSubgroup()) + hex.EncodeToString(scalarTwo)}, false, "G1", g1Limbs)
+	nm.pushCall("G2_ADD", vector{Input: encodeG2(g2NotOnTwist()) + encodeG2(g2Gen)}, false, "C2", g2Limbs)
+	nm.pushFiller(rng, 4)
+	nm.pushCall("G2_MSM", vector{Input: encodeG2(g2NotInSubgroup()) + hex.EncodeToString(scalarTwo)}, false, "G2", g2Limbs)
+	nm.pushCall("G2_ADD", vectors["g2_add"][0], true, "", 0)
+	nm.pushFiller(rng, 1)
+
+	f = files.MustOverwrite("./non_membership_test.csv")
+	nm.dump(f)
+	f.Close()
+}
+
+func readVectors(fname string) map[string][]vector {
+	b, err := os.ReadFile(fname)
+	if err != nil {
+		panic(err)
+	}
+	var res map[string][]vector
+	if err := json.Unmarshal(b, &res); err != nil {
+		panic(err)
+	}
+	return res
+}
+
+func mustDecodeHex(s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+func dumpFixedInputAsCsv(w io.Writer, tab [][]*big.Int, selector string) {
+
+	fmt.Fprintf(w, "%v,LIMB,INDEX,IS_DATA,IS_RES\n", selector)
+
+	for i := range tab[0] {
+		fmt.Fprintf(w, "%v,0x%v,%v,%v,%v\n",
+			tab[0][i].String(), tab[1][i].Text(16), tab[2][i].String(),
+			tab[3][i].String(), tab[4][i].String(),
+		)
+	}
+}
+
+func dumpMultiInputAsCsv(w io.Writer, tab [][]*big.Int, selector string) {
+
+	fmt.Fprintf(w, "ID,%v,LIMB,INDEX,ACC_INPUTS,TOTAL_INPUTS,IS_DATA,IS_RES\n", selector)
+
+	for i := range tab[0] {
+		fmt.Fprintf(w, "%v,%v,0x%v,%v,%v,%v,%v,%v\n",
+			tab[0][i].String(), tab[1][i].String(), tab[2][i].Text(16),
+			tab[3][i].String(), tab[4][i].String(), tab[5][i].String(),
+			tab[6][i].String(), tab[7][i].String(),
+		)
+	}
+}
+
+// pushFillerToInput pushes rows corresponding to other precompiles of the
+// BLS_DATA module.
+func pushFillerToInput(tab [][]*big.Int, rng *rand.Rand, numRow int) {
+
+	maxValue := new(big.Int).Lsh(big.NewInt(1), 128)
+
+	for i := 0; i < numRow; i++ {
+		tab[0] = append(tab[0], &big.Int{})
+		tab[1] = append(tab[1], new(big.Int).Rand(rng, maxValue))
+		tab[2] = append(tab[2], big.NewInt(int64(i)))
+		tab[3] = append(tab[3], &big.Int{})
+		tab[4] = append(tab[4], &big.Int{})
+	}
+}
+
+// pushFixedCallToInput pushes the rows of a call with a fixed number of
+// inputs.
+func pushFixedCallToInput(tab [][]*big.Int, v vector) {
+
+	var (
+		data   = mustDecodeHex(v.Input)
+		result = mustDecodeHex(v.Expected)
+	)
+
+	for i := 0; i < len(data); i += 16 {
+		tab[0] = append(tab[0], big.NewInt(1))
+		tab[1] = append(tab[1], new(big.Int).SetBytes(data[i:i+16]))
+		tab[2] = append(tab[2], big.NewInt(int64(i/16)))
+		tab[3] = append(tab[3], big.NewInt(1))
+		tab[4] = append(tab[4], &big.Int{})
+	}
+
+	for i := 0; i < len(result); i += 16 {
+		tab[0] = append(tab[0], big.NewInt(1))
+		tab[1] = append(tab[1], new(big.Int).SetBytes(result[i:i+16]))
+		tab[2] = append(tab[2], big.NewInt(int64(i/16)))
+		tab[3] = append(tab[3], &big.Int{})
+		tab[4] = append(tab[4], big.NewInt(1))
+	}
+}
+
+// pushMultiFillerToInput pushes the rows of a call to another precompile of
+// the BLS_DATA module.
+func pushMultiFillerToInput(tab [][]*big.Int, rng *rand.Rand, id, numRow int) {
+
+	maxValue := new(big.Int).Lsh(big.NewInt(1), 128)
+
+	for i := 0; i < numRow; i++ {
+		tab[0] = append(tab[0], big.NewInt(int64(id)))
+		tab[1] = append(tab[1], &big.Int{})
+		tab[2] = append(tab[2], new(big.Int).Rand(rng, maxValue))
+		tab[3] = append(tab[3], big.NewInt(int64(i)))
+		tab[4] = append(tab[4], &big.Int{})
+		tab[5] = append(tab[5], &big.Int{})
+		tab[6] = append(tab[6], &big.Int{})
+		tab[7] = append(tab[7], &big.Int{})
+	}
+}
+
+// pushMultiCallToInput pushes the rows of a call with a variable number of
+// inputs, each input taking inputBytes bytes.
+func pushMultiCallToInput(tab [][]*big.Int, id int, v vector, inputBytes int) {
+
+	var (
+		data     = mustDecodeHex(v.Input)
+		result   = mustDecodeHex(v.Expected)
+		nbInputs = len(data) / inputBytes
+	)
+
+	for i := 0; i < len(data); i += 16 {
+		tab[0] = append(tab[0], big.NewInt(int64(id)))
+		tab[1] = append(tab[1], big.NewInt(1))
+		tab[2] = append(tab[2], new(big.Int).SetBytes(data[i:i+16]))
+		tab[3] = append(tab[3], big.NewInt(int64(i/16)))
+		tab[4] = append(tab[4], big.NewInt(int64(i/inputBytes+1)))
+		tab[5] = append(tab[5], big.NewInt(int64(nbInputs)))
+		tab[6] = append(tab[6], big.NewInt(1))
+		tab[7] = append(tab[7], &big.Int{})
+	}
+
+	for i := 0; i < len(result); i += 16 {
+		tab[0] = append(tab[0], big.NewInt(int64(id)))
+		tab[1] = append(tab[1], big.NewInt(1))
+		tab[2] = append(tab[2], new(big.Int).SetBytes(result[i:i+16]))
+		tab[3] = append(tab[3], big.NewInt(int64(i/16)))
+		tab[4] = append(tab[4], &big.Int{})
+		tab[5] = append(tab[5], big.NewInt(int64(nbInputs)))
+		tab[6] = append(tab[6], &big.Int{})
+		tab[7] = append(tab[7], big.NewInt(1))
+	}
+}
+
+// number of limbs of the encoding of a point
+const (
+	g1Limbs = 8
+	g2Limbs = 16
+)
+
+// nonMembershipTable holds the columns of BLS_DATA used by the
+// non-membership module for the operations G1_ADD, G2_ADD, G1_MSM and G2_MSM.
+type nonMembershipTable struct {
+	names []string
+	limbs []*big.Int
+	cols  map[string][]int
+}
+
+func newNonMembershipTable() *nonMembershipTable {
+	names := []string{"SUCCESS_BIT", "CS_C1_MEMBERSHIP", "CS_G1_MEMBERSHIP", "CS_C2_MEMBERSHIP", "CS_G2_MEMBERSHIP"}
+	for _, op := range []string{"G1_ADD", "G2_ADD", "G1_MSM", "G2_MSM"} {
+		names = append(names, "IS_"+op+"_DATA", "CS_"+op)
+	}
+	return &nonMembershipTable{names: names, cols: map[string][]int{}}
+}
+
+// pushRow pushes a row where the columns of set are 1 and the others are 0.
+func (t *nonMembershipTable) pushRow(limb *big.Int, set ...string) {
+	t.limbs = append(t.limbs, limb)
+	for _, name := range t.names {
+		t.cols[name] = append(t.cols[name], 0)
+	}
+	for _, name := range set {
+		t.cols[name][len(t.limbs)-1] = 1
+	}
+}
+
+// pushFiller pushes rows corresponding to other precompiles of the BLS_DATA
+// module.
+func (t *nonMembershipTable) pushFiller(rng *rand.Rand, numRow int) {
+	maxValue := new(big.Int).Lsh(big.NewInt(1), 128)
+	for i := 0; i < numRow; i++ {
+		t.pushRow(new(big.Int).Rand(rng, maxValue))
+	}
+}
+
+// pushCall pushes the rows of a call to op. The failing calls have no result
+// and the membership selector is set on their first nbMemberLimbs limbs.
+func (t *nonMembershipTable) pushCall(op string, v vector, success bool, membership string, nbMemberLimbs int) {
+
+	data := mustDecodeHex(v.Input)
+
+	for i := 0; i < len(data); i += 16 {
+		set := []string{"IS_" + op + "_DATA"}
+		if success {
+			set = append(set, "SUCCESS_BIT", "CS_"+op)
+		}
+		if membership != "" && i/16 < nbMemberLimbs {
+			set = append(set, "CS_"+membership+"_MEMBERSHIP")
+		}
+		t.pushRow(new(big.Int).SetBytes(data[i:i+16]), set...)
+	}
+
+	if !success {
+		return
+	}
+
+	result := mustDecodeHex(v.Expected)
+	for i := 0; i < len(result); i += 16 {
+		t.pushRow(new(big.Int).SetBytes(result[i:i+16]), "SUCCESS_BIT", "CS_"+op)
+	}
+}
+
+func (t *nonMembershipTable) dump(w io.Writer) {
+
+	fmt.Fprintf(w, "LIMB,%v\n", strings.Join(t.names, ","))
+
+	for i := range t.limbs {
+		fmt.Fprintf(w, "0x%v", t.limbs[i].Text(16))
+		for _, name := range t.names {
+			fmt.Fprintf(w, ",%v", t.cols[name][i])
+		}
+		fmt.Fprintln(w)
+	}
+}
+
+// encodeFp returns the EIP-2537 encoding of x in hexadecimal.
+func encodeFp(x blsfp.Element) string {
+	b := x.Bytes()
+	return strings.Repeat("00", 16) + hex.EncodeToString(b[:])
+}
+
+func encodeG1(p bls12381.G1Affine) string {
+	return encodeFp(p.X) + encodeFp(p.Y)
+}
+
+func encodeG2(p bls12381.G2Affine) string {
+	return encodeFp(p.X.A0) + encodeFp(p.X.A1) + encodeFp(p.Y.A0) + encodeFp(p.Y.A1)
+}
+
+// g1NotOnCurve returns (1, 1) which is not on y² = x³ + 4.
+func g1NotOnCurve() bls12381.G1Affine {
+	var p bls12381.G1Affine
+	p.X.SetOne()
+	p.Y.SetOne()
+	return p
+}
+
+// g2NotOnTwist returns (1, 1) which is not on y² = x³ + 4(1+u).
+func g2NotOnTwist() bls12381.G2Affine {
+	var p bls12381.G2Affine
+	p.X.SetOne()
+	p.Y.SetOne()
+	return p
+}
+
+// g1NotInSubgroup returns a point of E(Fp) which is not in G1.
+func g1NotInSubgroup() bls12381.G1Affine {
+	var p bls12381.G1Affine
+	for x := uint64(1); ; x++ {
+		var rhs blsfp.Element
+		p.X.SetUint64(x)
+		rhs.Square(&p.X).Mul(&rhs, &p.X).Add(&rhs, new(blsfp.Element).SetUint64(4))
+		if p.Y.Sqrt(&rhs) == nil || p.IsInSubGroup() {
+			continue
+		}
+		return p
+	}
+}
+
+// g2NotInSubgroup returns a point of E'(Fp2) with x in Fp which is not in G2.
+func g2NotInSubgroup() bls12381.G2Affine {
+	var (
+		p      bls12381.G2Affine
+		bTwist = bls12381.E2{A0: blsfp.NewElement(4), A1: blsfp.NewElement(4)}
+	)
+	for x := uint64(1); ; x++ {
+		var rhs bls12381.E2
+		p.X.A0.SetUint64(x)
+		rhs.Square(&p.X).Mul(&rhs, &p.X).Add(&rhs, &bTwist)
+		if rhs.Legendre() < 0 {
+			continue
+		}
+		p.Y.Sqrt(&rhs)
+		if !p.IsInSubGroup() {
+			return p
+		}
+	}
+}
@@ -0,0 +1,156 @@
+package bls12381
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/linea-monorepo/prover/maths/field"
+)
+
+// eip2537Vector is a test vector of the EIP-2537 precompiles as found in the
+// test suite of go-ethereum.
+type eip2537Vector struct {
+	Name     string
+	Input    string
+	Expected string
+}
+
+// loadVectors returns the test vectors of the precompile op from
+// testdata/eip2537_vectors.json.
+func loadVectors(t *testing.T, op string) []eip2537Vector {
+	b, err := os.ReadFile("testdata/eip2537_vectors.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var vectors map[string][]eip2537Vector
+	if err := json.Unmarshal(b, &vectors); err != nil {
+		t.Fatal(err)
+	}
+	if len(vectors[op]) == 0 {
+		t.Fatalf("no test vector for %v", op)
+	}
+	return vectors[op]
+}
+
+// hexToLimbs splits the hex-encoded string s in limbs of 128 bits as in the
+// arithmetization.
+func hexToLimbs(t *testing.T, s string) []field.Element {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(b)%16 != 0 {
+		t.Fatalf("the length of the encoding is not a multiple of 16: %v", len(b))
+	}
+	res := make([]field.Element, len(b)/16)
+	for i := range res {
+		res[i].SetBytes(b[16*i : 16*(i+1)])
+	}
+	return res
+}
+
+// assignLimbs assigns the limbs to the circuit variables dst.
+func assignLimbs(dst []frontend.Variable, limbs []field.Element) {
+	if len(dst) != len(limbs) {
+		panic("the number of limbs does not match")
+	}
+	for i := range dst {
+		dst[i] = limbs[i]
+	}
+}
+
+// perturbed returns a copy of limbs where the last limb is incremented.
+func perturbed(limbs []field.Element) []field.Element {
+	res := append([]field.Element{}, limbs...)
+	res[len(res)-1].Add(&res[len(res)-1], new(field.Element).SetOne())
+	return res
+}
+
+func TestEncodingRoundTrip(t *testing.T) {
+	_, _, g1, g2 := bls12381.Generators()
+
+	if p := g1FromLimbsNative(g1ToLimbs(g1)); !p.Equal(&g1) {
+		t.Error("G1 round trip failed")
+	}
+	if q := g2FromLimbsNative(g2ToLimbs(g2)); !q.Equal(&g2) {
+		t.Error("G2 round trip failed")
+	}
+
+	gt, err := bls12381.Pair([]bls12381.G1Affine{g1}, []bls12381.G2Affine{g2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if x := gtFromLimbsNative(gtToLimbs(gt)); !x.Equal(&gt) {
+		t.Error("GT round trip failed")
+	}
+
+	// the encoding matches the one of the precompiles
+	for _, v := range loadVectors(t, "g1_add") {
+		var (
+			limbs = hexToLimbs(t, v.Input)
+			p     = g1FromLimbsNative(limbs[:nbG1Limbs])
+			back  = g1ToLimbs(p)
+		)
+		for i := range back {
+			if back[i] != limbs[i] {
+				t.Errorf("%v: limb %v mismatch", v.Name, i)
+			}
+		}
+	}
+}
+
+func TestMsmAccumulate(t *testing.T) {
+	testCases := []struct {
+		op         string
+		accumulate func(acc, input []field.Element) []field.Element
+		nbAcc      int
+		nbInput    int
+	}{
+		{"g1_msm", g1MsmAccumulate, nbG1Limbs, nbG1Limbs + nbScalarLimbs},
+		{"g2_msm", g2MsmAccumulate, nbG2Limbs, nbG2Limbs + nbScalarLimbs},
+	}
+
+	for _, tc := range testCases {
+		for _, v := range loadVectors(t, tc.op) {
+			var (
+				input    = hexToLimbs(t, v.Input)
+				expected = hexToLimbs(t, v.Expected)
+				acc      = make([]field.Element, tc.nbAcc)
+			)
+			for i := 0; i < len(input); i += tc.nbInput {
+				acc = tc.accumulate(acc, input[i:i+tc.nbInput])
+			}
+			for i := range acc {
+				if acc[i] != expected[i] {
+					t.Errorf("%v: limb %v mismatch", v.Name, i)
+				}
+			}
+		}
+	}
+}
+
+func TestPairingAccumulate(t *testing.T) {
+	const nbPairLimbs = nbG1Limbs + nbG2Limbs
+
+	for _, v := range loadVectors(t, "pairing_check") {
+		var (
+			input    = hexToLimbs(t, v.Input)
+			expected = hexToLimbs(t, v.Expected)
+			acc      = gtOneLimbs
+		)
+		for i := 0; i < len(input); i += nbPairLimbs {
+			acc = pairingAccumulate(acc, input[i:i+nbPairLimbs])
+		}
+		var (
+			ml  = gtFromLimbsNative(acc)
+			res = bls12381.FinalExponentiation(&ml)
+		)
+		if res.IsOne() != expected[1].IsOne() {
+			t.Errorf("%v: got %v", v.Name, res.IsOne())
+		}
+	}
+}
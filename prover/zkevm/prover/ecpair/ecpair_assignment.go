@@ -5,16 +5,20 @@ import (
 	"github.com/consensys/linea-monorepo/prover/maths/field"
 	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
 	"github.com/consensys/linea-monorepo/prover/utils"
+	"github.com/consensys/linea-monorepo/prover/zkevm/arithmetization"
 	"github.com/consensys/linea-monorepo/prover/zkevm/prover/common"
+	"github.com/sirupsen/logrus"
 )
 
 // Assign assigns the data to the circuit
 func (ec *ECPair) Assign(run *wizard.ProverRuntime) {
 
 	// assign data to the pairing check part
-	ec.assignPairingData(run)
-	// assign data to the membership check part
-	ec.assignMembershipData(run)
+	nbPairingRows := ec.assignPairingData(run)
+	// assign data to the membership check part. The membership data shares
+	// the IsActive column with the pairing data and the two are mutually
+	// exclusive, so it is placed right after the pairing data.
+	ec.assignMembershipData(run, nbPairingRows)
 	// assign the column telling wether the previous and the current row have
 	// the same id.
 	ec.CptPrevEqualCurrID.Run(run)
@@ -53,7 +57,9 @@ func (ec *ECPair) Assign(run *wizard.ProverRuntime) {
 	}
 }
 
-func (ec *ECPair) assignPairingData(run *wizard.ProverRuntime) {
+// assignPairingData assigns the unaligned pairing data and returns the number
+// of rows it spans.
+func (ec *ECPair) assignPairingData(run *wizard.ProverRuntime) int {
 	var (
 		srcIsPairing = ec.ECPairSource.CsEcpairing.GetColAssignment(run).IntoRegVecSaveAlloc()
 		srcLimbs     = ec.ECPairSource.Limb.GetColAssignment(run).IntoRegVecSaveAlloc()
@@ -69,6 +75,11 @@ func (ec *ECPair) assignPairingData(run *wizard.ProverRuntime) {
 		inputResult [2]field.Element
 		pairingInG1 [][nbG1Limbs]field.Element
 		pairingInG2 [][nbG2Limbs]field.Element
+		// nbMillerLoops and nbFinalExps count the number of instances sent
+		// to the respective circuits. The last pair of every pairing check is
+		// handled by the final exponentiation circuit.
+		nbMillerLoops = 0
+		nbFinalExps   = 0
 	)
 
 	var (
@@ -119,6 +130,8 @@ func (ec *ECPair) assignPairingData(run *wizard.ProverRuntime) {
 			inputResult[0] = srcLimbs[currPos+(i+1)*(nbG1Limbs+nbG2Limbs)]
 			inputResult[1] = srcLimbs[currPos+(i+1)*(nbG1Limbs+nbG2Limbs)+1]
 		}
+		nbMillerLoops += nbInputs - 1
+		nbFinalExps++
 		limbs := processPairingData(pairingInG1, pairingInG2, inputResult)
 		instanceId := srcID[currPos]
 		// processed data has the input limbs, but we have entered the intermediate Gt accumulator values
@@ -223,6 +236,17 @@ func (ec *ECPair) assignPairingData(run *wizard.ProverRuntime) {
 		}
 		currPos += nbInputs*(nbG1Limbs+nbG2Limbs) + 2
 	}
+
+	if nbMillerLoops > ec.nbMillerLoops() {
+		logrus.Errorf("limit overflow: the ecpair Miller loop count is %v and the limit is %v\n", nbMillerLoops, ec.nbMillerLoops())
+		arithmetization.PanicOverflow("ecpair_miller_loops", nbMillerLoops, ec.nbMillerLoops())
+	}
+
+	if nbFinalExps > ec.nbFinalExps() {
+		logrus.Errorf("limit overflow: the ecpair final exponentiation count is %v and the limit is %v\n", nbFinalExps, ec.nbFinalExps())
+		arithmetization.PanicOverflow("ecpair_final_exponentiations", nbFinalExps, ec.nbFinalExps())
+	}
+
 	dstIsActive.PadAndAssign(run, field.Zero())
 	dstLimb.PadAndAssign(run, field.Zero())
 	dstPairId.PadAndAssign(run, field.Zero())
@@ -239,6 +263,8 @@ func (ec *ECPair) assignPairingData(run *wizard.ProverRuntime) {
 	dstIndex.PadAndAssign(run, field.Zero())
 	dstIsFirstPrev.PadAndAssign(run, field.Zero())
 	dstIsFirstCurr.PadAndAssign(run, field.Zero())
+
+	return dstIsActive.Height()
 }
 
 func processPairingData(pairingInG1 [][nbG1Limbs]field.Element, pairingInG2 [][nbG2Limbs]field.Element, inputResult [2]field.Element) []field.Element {
@@ -277,7 +303,9 @@ func processPairingData(pairingInG1 [][nbG1Limbs]field.Element, pairingInG2 [][n
 	return res
 }
 
-func (ec *ECPair) assignMembershipData(run *wizard.ProverRuntime) {
+// assignMembershipData assigns the unaligned G2 membership data starting at
+// row offset.
+func (ec *ECPair) assignMembershipData(run *wizard.ProverRuntime, offset int) {
 	var (
 		srcIsG2       = ec.ECPairSource.CsG2Membership.GetColAssignment(run).IntoRegVecSaveAlloc()
 		srcLimbs      = ec.ECPairSource.Limb.GetColAssignment(run).IntoRegVecSaveAlloc()
@@ -292,8 +320,17 @@ func (ec *ECPair) assignMembershipData(run *wizard.ProverRuntime) {
 		dstIsPulling  = common.NewVectorBuilder(ec.UnalignedG2MembershipData.IsPulling)
 		dstIsComputed = common.NewVectorBuilder(ec.UnalignedG2MembershipData.IsComputed)
 		dstSuccessBit = common.NewVectorBuilder(ec.UnalignedG2MembershipData.SuccessBit)
+		nbG2Checks    = 0
 	)
 
+	for i := 0; i < offset; i++ {
+		dstLimb.PushZero()
+		dstSuccessBit.PushZero()
+		dstMask.PushZero()
+		dstIsPulling.PushZero()
+		dstIsComputed.PushZero()
+	}
+
 	for currPos := 0; currPos < len(srcLimbs); {
 		if srcIsG2[currPos].IsZero() {
 			currPos++
@@ -314,9 +351,15 @@ func (ec *ECPair) assignMembershipData(run *wizard.ProverRuntime) {
 		dstIsPulling.PushZero()
 		dstIsComputed.PushOne()
 
+		nbG2Checks++
 		currPos += nbG2Limbs
 	}
 
+	if nbG2Checks > ec.nbG2MembershipChecks() {
+		logrus.Errorf("limit overflow: the ecpair G2 membership check count is %v and the limit is %v\n", nbG2Checks, ec.nbG2MembershipChecks())
+		arithmetization.PanicOverflow("ecpair_g2_membership_checks", nbG2Checks, ec.nbG2MembershipChecks())
+	}
+
 	dstLimb.PadAndAssign(run, field.Zero())
 	dstSuccessBit.PadAndAssign(run, field.Zero())
 	dstMask.PadAndAssign(run, field.Zero())
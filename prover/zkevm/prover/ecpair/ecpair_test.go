@@ -205,3 +205,24 @@ func TestMembership(t *testing.T) {
 		testModule(t, tc, false, false, false, true)
 	}
 }
+
+var traceTestCases = []pairingDataTestCase{
+	{
+		// trace containing both pairing checks and G2 membership checks. No
+		// conflated trace with a G2 membership call is available yet: the
+		// input is the concatenation of the ecpair_g2_both_cases and the
+		// ecpair_trace inputs, in the format of `go run ./testdata`. It is
+		// meant to be replaced by the output of this tool on such a trace.
+		InputFName:       "testdata/ecpair_trace_with_g2_input.csv",
+		ModuleFName:      "",
+		NbMillerLoops:    2,
+		NbFinalExps:      1,
+		NbSubgroupChecks: 3,
+	},
+}
+
+func TestTrace(t *testing.T) {
+	for _, tc := range traceTestCases {
+		testModule(t, tc, false, false, false, false)
+	}
+}
@@ -17,3 +17,9 @@ func TestMembershipCircuit(t *testing.T) {
 		testModule(t, tc, false, true, false, true)
 	}
 }
+
+func TestTraceCircuit(t *testing.T) {
+	for _, tc := range traceTestCases {
+		testModule(t, tc, true, true, false, false)
+	}
+}
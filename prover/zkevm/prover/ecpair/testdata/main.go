@@ -0,0 +1,103 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/consensys/go-corset/pkg/trace/lt"
+	"github.com/consensys/go-corset/pkg/util"
+	"github.com/consensys/linea-monorepo/prover/backend/files"
+)
+
+// columns lists the columns of the EC_DATA module read by the ECPAIR module,
+// in the order of the CSV file, along with their name in the CSV file.
+var columns = []struct {
+	ecdata, csv string
+}{
+	{"ID", "ECDATA_ID"},
+	{"CIRCUIT_SELECTOR_ECPAIRING", "ECDATA_CS_PAIRING"},
+	{"CIRCUIT_SELECTOR_G2_MEMBERSHIP", "ECDATA_CS_G2_MEMBERSHIP"},
+	{"INDEX", "ECDATA_INDEX"},
+	{"TOTAL_PAIRINGS", "ECDATA_TOTAL_PAIRINGS"},
+	{"ACC_PAIRINGS", "ECDATA_ACC_PAIRINGS"},
+	{"LIMB", "ECDATA_LIMB"},
+	{"SUCCESS_BIT", "ECDATA_SUCCESS_BIT"},
+	{"IS_ECPAIRING_DATA", "ECDATA_IS_DATA"},
+	{"IS_ECPAIRING_RESULT", "ECDATA_IS_RES"},
+}
+
+// main extracts the ECPAIRING calls of the EC_DATA module from a conflated
+// trace into ecpair_trace_with_g2_input.csv. The trace should contain both
+// pairing checks and calls failing the G2 membership check.
+//
+//	go run . <conflated-trace.lt>
+func main() {
+
+	if len(os.Args) != 2 {
+		fmt.Fprintln(os.Stderr, "usage: go run . <conflated-trace.lt>")
+		os.Exit(1)
+	}
+
+	data, err := os.ReadFile(os.Args[1])
+	if err != nil {
+		panic(err)
+	}
+
+	rawCols, err := lt.FromBytes(data)
+	if err != nil {
+		panic(err)
+	}
+
+	ecdata := map[string]util.FrArray{}
+	for _, col := range rawCols {
+		if col.Module == "ecdata" {
+			ecdata[col.Name] = col.Data
+		}
+	}
+
+	tab := make([]util.FrArray, len(columns))
+	for i := range columns {
+		if tab[i] = ecdata[columns[i].ecdata]; tab[i] == nil {
+			panic(fmt.Sprintf("the trace has no column ecdata.%v", columns[i].ecdata))
+		}
+	}
+
+	f := files.MustOverwrite("./ecpair_trace_with_g2_input.csv")
+	dumpAsCsv(f, tab)
+	f.Close()
+}
+
+// dumpAsCsv writes the rows of the ECPAIRING calls, that is the rows where
+// either of the circuit selectors is set.
+func dumpAsCsv(w io.Writer, tab []util.FrArray) {
+
+	for i := range columns {
+		if i > 0 {
+			fmt.Fprintf(w, ",")
+		}
+		fmt.Fprintf(w, "%v", columns[i].csv)
+	}
+	fmt.Fprintf(w, "\n")
+
+	for r := uint(0); r < tab[0].Len(); r++ {
+
+		var (
+			csPairing      = tab[1].Get(r)
+			csG2Membership = tab[2].Get(r)
+		)
+
+		if csPairing.IsZero() && csG2Membership.IsZero() {
+			continue
+		}
+
+		for i := range tab {
+			if i > 0 {
+				fmt.Fprintf(w, ",")
+			}
+			v := tab[i].Get(r)
+			fmt.Fprintf(w, "0x%v", v.Text(16))
+		}
+		fmt.Fprintf(w, "\n")
+	}
+}
@@ -28,6 +28,13 @@ var (
 		laneSizeBytes:     4,
 		nbOfLanesPerBlock: 16,
 	}
+
+	// RipemdUsecase represents using the Ripemd160 hash function.
+	RipemdUsecase = HashingUsecase{
+		paddingStrat:      ripemdPadding,
+		laneSizeBytes:     4,
+		nbOfLanesPerBlock: 16,
+	}
 )
 
 type paddingStrat int
@@ -36,6 +43,7 @@ const (
 	zeroPadding paddingStrat = iota
 	keccakPadding
 	sha2Padding
+	ripemdPadding
 )
 
 type HashingUsecase struct {
@@ -104,6 +104,8 @@ func ImportAndPad(comp *wizard.CompiledIOP, inp ImportAndPadInputs, numRows int)
 		res.padder = res.newKeccakPadder(comp)
 	case inp.PaddingStrategy == generic.Sha2Usecase:
 		res.padder = res.newSha2Padder(comp)
+	case inp.PaddingStrategy == generic.RipemdUsecase:
+		res.padder = res.newRipemdPadder(comp)
 	case inp.PaddingStrategy == generic.MiMCUsecase:
 		res.padder = res.newMimcPadder(comp)
 	default:
@@ -231,6 +233,17 @@ func (imp *importation) Run(run *wizard.ProverRuntime) {
 		iab.Padder = &sha2PaddingAssignmentBuilder{
 			AccInsertedBytes: common.NewVectorBuilder(imp.padder.(*sha2Padder).AccInsertedBytes),
 		}
+	case imp.Inputs.PaddingStrategy == generic.RipemdUsecase:
+		var (
+			rp  = imp.padder.(*ripemdPadder)
+			rpa = &ripemdPaddingAssignmentBuilder{
+				AccInsertedBytes: common.NewVectorBuilder(rp.AccInsertedBytes),
+			}
+		)
+		for k := range rpa.LengthBytes {
+			rpa.LengthBytes[k] = common.NewVectorBuilder(rp.LengthBytes[k])
+		}
+		iab.Padder = rpa
 	case imp.Inputs.PaddingStrategy == generic.MiMCUsecase:
 		iab.Padder = &mimcPadderAssignmentBuilder{}
 	default:
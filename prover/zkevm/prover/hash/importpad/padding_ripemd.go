@@ -0,0 +1,277 @@
+package importpad
+
+import (
+	"github.com/consensys/linea-monorepo/prover/maths/field"
+	"github.com/consensys/linea-monorepo/prover/protocol/column"
+	"github.com/consensys/linea-monorepo/prover/protocol/ifaces"
+	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
+	sym "github.com/consensys/linea-monorepo/prover/symbolic"
+	"github.com/consensys/linea-monorepo/prover/utils"
+	"github.com/consensys/linea-monorepo/prover/zkevm/prover/common"
+	"github.com/consensys/linea-monorepo/prover/zkevm/prover/hash/generic"
+)
+
+// ripemdNbLengthBytes is the number of bytes of the (little-endian) bit-length
+// of the message that we allow being non-zero. This caps the size of the
+// hashed messages to 2^29 bytes which is way above what a conflation can
+// contain.
+const ripemdNbLengthBytes = 4
+
+// ripemdPadder implements the [padder] interface for the Ripemd160 hash
+// function.
+type ripemdPadder struct {
+	AccInsertedBytes ifaces.Column
+	// LengthBytes stores the little-endian decomposition in bytes of the bit
+	// length of the message. They are only set on the last padded row of each
+	// hash.
+	LengthBytes [ripemdNbLengthBytes]ifaces.Column
+}
+
+// ripemdPaddingAssignmentBuilder is a utility serving during the assignment of
+// the ripemdPadder module
+type ripemdPaddingAssignmentBuilder struct {
+	AccInsertedBytes *common.VectorBuilder
+	LengthBytes      [ripemdNbLengthBytes]*common.VectorBuilder
+}
+
+// newRipemdPadder declares all the constraints ensuring the imported byte
+// strings are properly padded following the specification of Ripemd160.
+func (ipad *importation) newRipemdPadder(comp *wizard.CompiledIOP) padder {
+
+	// The padding structure is the same as for Sha2 at the difference that the
+	// bit-length of the message is encoded in little-endian order. This makes
+	// the last padded limb non-linear in the size of the message so we add
+	// byte columns to decompose it:
+	//
+	// 	=> xxxxxxx 		|| 	size n 	bytes	||	isPadded:false
+	// 	=> 		1 		|| 	size 1 	bytes	||	isPadded:true
+	// 	=>		0 		||	size n0	bytes	||	isPadded:true
+	// 	=>		..		|| 	size .. bytes	||	isPadded:true
+	// 	=> LE(msgSize) 	||	size 8	bytes	||	isPadded:true
+	//
+	// In addition to the constraints of the Sha2 padder, we have
+	//
+	//	- The length bytes recompose the bit-length of the message
+	//
+	//		isPadded[i] * (1 - isPadded[i+1]) * (8 * accInsertedBytes[i] - sum_k lengthBytes_k[i] * 256^k) == 0
+	//
+	//	- The length bytes are bytes
+	//
+	//		lengthBytes_k \in [0; 256)
+
+	var (
+		numRows = ipad.Limbs.Size()
+		pad     = &ripemdPadder{
+			AccInsertedBytes: comp.InsertCommit(0,
+				ifaces.ColIDf("%v_RIPEMD_ACC_INSERTED_BYTES", ipad.Inputs.Name),
+				numRows,
+			),
+		}
+	)
+
+	for k := range pad.LengthBytes {
+		pad.LengthBytes[k] = comp.InsertCommit(0,
+			ifaces.ColIDf("%v_RIPEMD_LENGTH_BYTE_%v", ipad.Inputs.Name, k),
+			numRows,
+		)
+	}
+
+	var (
+		isInsertedPrev       = column.Shift(ipad.IsInserted, -1)
+		isInserted           = ipad.IsInserted
+		isPaddedPrev         = column.Shift(ipad.IsPadded, -1)
+		isPadded             = ipad.IsPadded
+		isPaddedNext         = column.Shift(ipad.IsPadded, 1)
+		nbBytes              = ipad.NBytes
+		accInsertedBytesPrev = column.Shift(pad.AccInsertedBytes, -1)
+		accInsertedBytes     = pad.AccInsertedBytes
+		isBinary             = func(x any) *sym.Expression {
+			return sym.Sub(
+				sym.Mul(x, x),
+				x,
+			)
+		}
+		lengthAsInt   = []any{}
+		lengthAsLimbs = []any{}
+	)
+
+	for k := range pad.LengthBytes {
+		lengthAsInt = append(lengthAsInt, sym.Mul(pad.LengthBytes[k], 1<<(8*k)))
+		lengthAsLimbs = append(lengthAsLimbs, sym.Mul(pad.LengthBytes[k], leftAlign(1, k+1)))
+	}
+
+	comp.InsertGlobal(0,
+		ifaces.QueryIDf("%v_RIPEMD_PADDING_AT_LEAST_TWO_LIMBS", ipad.Inputs.Name),
+		sym.Mul(
+			isPadded,
+			isBinary(sym.Add(isPaddedPrev, isPaddedNext, -1)),
+		),
+	)
+
+	comp.InsertGlobal(0,
+		ifaces.QueryIDf("%v_RIPEMD_FIRST_PADDING_HAS_1_BYTE", ipad.Inputs.Name),
+		sym.Mul(
+			isPadded,
+			sym.Sub(1, isPaddedPrev),
+			sym.Sub(nbBytes, 1),
+		),
+	)
+
+	comp.InsertGlobal(0,
+		ifaces.QueryIDf("%v_RIPEMD_LAST_PADDING_HAS_8_BYTE", ipad.Inputs.Name),
+		sym.Mul(
+			isPadded,
+			sym.Sub(1, isPaddedNext),
+			sym.Sub(nbBytes, 8),
+		),
+	)
+
+	comp.InsertGlobal(0,
+		ifaces.QueryIDf("%v_RIPEMD_INTERMEDIATE_PADDING_BYTES_ARE_ZEROES", ipad.Inputs.Name),
+		sym.Mul(
+			isPaddedPrev,
+			isPadded,
+			isPaddedNext,
+			ipad.Limbs,
+		),
+	)
+
+	comp.InsertGlobal(0,
+		ifaces.QueryIDf("%v_RIPEMD_ACC_INSERTED_BYTES_CORRECTLY_SET", ipad.Inputs.Name),
+		sym.Sub(
+			accInsertedBytes,
+			sym.Mul(isPadded, accInsertedBytesPrev),
+			sym.Mul(isInserted, sym.Add(sym.Mul(isInsertedPrev, accInsertedBytesPrev), nbBytes)),
+		),
+	)
+
+	comp.InsertGlobal(0,
+		ifaces.QueryIDf("%v_RIPEMD_LENGTH_BYTES_DECOMPOSITION", ipad.Inputs.Name),
+		sym.Mul(
+			isPadded,
+			sym.Sub(1, isPaddedNext),
+			sym.Sub(
+				sym.Mul(accInsertedBytes, 8),
+				sym.Add(lengthAsInt...),
+			),
+		),
+	)
+
+	comp.InsertGlobal(0,
+		ifaces.QueryIDf("%v_RIPEMD_PADDING_VALUES", ipad.Inputs.Name),
+		sym.Mul(
+			ipad.IsPadded,
+			sym.Sub(
+				ipad.Limbs,
+				sym.Mul(
+					sym.Sub(1, isPaddedPrev),
+					leftAlign(0x80, 1), // The domain separation byte 0b10000000
+				),
+				sym.Mul(
+					sym.Sub(1, isPaddedNext),
+					sym.Add(lengthAsLimbs...),
+				),
+			),
+		),
+	)
+
+	for k := range pad.LengthBytes {
+		comp.InsertInclusion(0,
+			ifaces.QueryIDf("%v_RIPEMD_LENGTH_BYTE_%v_IS_A_BYTE", ipad.Inputs.Name, k),
+			[]ifaces.Column{getByteLookup(comp)},
+			[]ifaces.Column{pad.LengthBytes[k]},
+		)
+	}
+
+	// Same as for Sha2, the +8 accounts for the last 64 bits string length.
+	comp.InsertInclusionConditionalOnIncluded(0,
+		ifaces.QueryIDf("%v_RIPEMD_LOOKUP_NB_PADDED_BYTES", ipad.Inputs.Name),
+		[]ifaces.Column{getLookupForSize(comp, 8+generic.RipemdUsecase.BlockSizeBytes())},
+		[]ifaces.Column{ipad.AccPaddedBytes},
+		ipad.IsPadded,
+	)
+
+	return pad
+}
+
+func (rp *ripemdPadder) pushPaddingRows(byteStringSize int, ipad *importationAssignmentBuilder) {
+
+	var (
+		blocksize      = generic.RipemdUsecase.BlockSizeBytes()
+		remainToPad    = blocksize - (byteStringSize % blocksize)
+		rpa            = ipad.Padder.(*ripemdPaddingAssignmentBuilder)
+		accPaddedBytes = 0
+		bitLength      = uint64(byteStringSize) * 8
+	)
+
+	if bitLength >= 1<<(8*ripemdNbLengthBytes) {
+		utils.Panic("the message is too large for the ripemd padder: %v bytes", byteStringSize)
+	}
+
+	if remainToPad < 9 {
+		remainToPad += 64
+	}
+
+	accPaddedBytes++
+	remainToPad--
+
+	ipad.pushPaddingCommonColumns()
+	ipad.Limbs.PushField(leftAlign(0x80, 1))
+	ipad.NBytes.PushOne()
+	ipad.AccPaddedBytes.PushOne()
+	rpa.AccInsertedBytes.PushInt(byteStringSize)
+	rpa.pushLengthBytes(0)
+
+	for remainToPad > 8 {
+		currNbBytes := utils.Min(remainToPad-8, 16)
+		accPaddedBytes += currNbBytes
+		remainToPad -= currNbBytes
+
+		ipad.pushPaddingCommonColumns()
+		ipad.Limbs.PushZero()
+		ipad.NBytes.PushInt(currNbBytes)
+		ipad.AccPaddedBytes.PushInt(accPaddedBytes)
+		rpa.AccInsertedBytes.PushInt(byteStringSize)
+		rpa.pushLengthBytes(0)
+	}
+
+	accPaddedBytes += 8
+
+	// The limb is the little-endian encoding of the bit-length on 8 bytes
+	var lengthLimb field.Element
+	for k := 0; k < ripemdNbLengthBytes; k++ {
+		b := leftAlign((bitLength>>(8*k))&0xff, k+1)
+		lengthLimb.Add(&lengthLimb, &b)
+	}
+
+	ipad.pushPaddingCommonColumns()
+	ipad.Limbs.PushField(lengthLimb)
+	ipad.NBytes.PushInt(8)
+	ipad.AccPaddedBytes.PushInt(accPaddedBytes)
+	rpa.AccInsertedBytes.PushInt(byteStringSize)
+	rpa.pushLengthBytes(bitLength)
+}
+
+// pushLengthBytes pushes the little-endian decomposition of length on the
+// LengthBytes columns.
+func (rpa *ripemdPaddingAssignmentBuilder) pushLengthBytes(length uint64) {
+	for k := range rpa.LengthBytes {
+		rpa.LengthBytes[k].PushInt(int((length >> (8 * k)) & 0xff))
+	}
+}
+
+func (rpa *ripemdPaddingAssignmentBuilder) pushInsertingRow(nbBytes int, isNewHash bool) {
+	if isNewHash {
+		rpa.AccInsertedBytes.PushInt(nbBytes)
+	} else {
+		rpa.AccInsertedBytes.PushIncBy(nbBytes)
+	}
+	rpa.pushLengthBytes(0)
+}
+
+func (rpa *ripemdPaddingAssignmentBuilder) padAndAssign(run *wizard.ProverRuntime) {
+	rpa.AccInsertedBytes.PadAndAssign(run, field.Zero())
+	for k := range rpa.LengthBytes {
+		rpa.LengthBytes[k].PadAndAssign(run, field.Zero())
+	}
+}
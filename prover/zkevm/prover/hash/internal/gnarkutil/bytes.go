@@ -1,4 +1,6 @@
-package sha2
+// Package gnarkutil holds the gnark helpers shared by the hash modules whose
+// compression function is checked by a gnark circuit.
+package gnarkutil
 
 import (
 	"errors"
@@ -8,18 +10,19 @@ import (
 	"github.com/consensys/gnark/std/rangecheck"
 )
 
-// Decompose x in 'nBytes' bytes in big endian order
+// ToNBytes decomposes x in 'nBytes' bytes in big endian order. The circuits
+// calling it must register [DecomposeIntoBytesHint].
 //
 // Deprecated: These are utility functions that have been copy-pasted from circuits/internal
 // waiting for them or equivalent function to be merged in gnark/std. We will
 // be able to substitute them at this point.
-func toNBytes(api frontend.API, x frontend.Variable, nBytes int) []frontend.Variable {
+func ToNBytes(api frontend.API, x frontend.Variable, nBytes int) []frontend.Variable {
 	return decomposeIntoBytes(api, x, nBytes)
 }
 
 func decomposeIntoBytes(api frontend.API, data frontend.Variable, nbBytes int) []frontend.Variable {
 
-	bytes, err := api.Compiler().NewHint(decomposeIntoBytesHint, nbBytes, data)
+	bytes, err := api.Compiler().NewHint(DecomposeIntoBytesHint, nbBytes, data)
 	if err != nil {
 		panic(err)
 	}
@@ -40,7 +43,8 @@ func decomposeIntoBytes(api frontend.API, data frontend.Variable, nbBytes int) [
 	return bytes
 }
 
-func decomposeIntoBytesHint(_ *big.Int, ins, outs []*big.Int) error {
+// DecomposeIntoBytesHint is the hint called by [ToNBytes]
+func DecomposeIntoBytesHint(_ *big.Int, ins, outs []*big.Int) error {
 	nbBytes := len(outs) / len(ins)
 	if nbBytes*len(ins) != len(outs) {
 		return errors.New("incongruent number of ins/outs")
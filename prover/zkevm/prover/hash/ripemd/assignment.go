@@ -0,0 +1,221 @@
+package ripemd
+
+import (
+	"sync"
+
+	"github.com/consensys/gnark/constraint/solver"
+	"github.com/consensys/linea-monorepo/prover/crypto/ripemd160"
+	"github.com/consensys/linea-monorepo/prover/maths/field"
+	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
+	"github.com/consensys/linea-monorepo/prover/utils"
+	"github.com/consensys/linea-monorepo/prover/zkevm/prover/common"
+	"github.com/consensys/linea-monorepo/prover/zkevm/prover/hash/internal/gnarkutil"
+)
+
+// ripemdBlockHashingAssignment is a collection of column builder used to
+// construct the assignment to a [ripemdBlockModule].
+type ripemdBlockHashingAssignment struct {
+	IsActive                *common.VectorBuilder
+	IsEffBlock              *common.VectorBuilder
+	IsEffFirstLaneOfNewHash *common.VectorBuilder
+	IsEffLastLaneOfCurrHash *common.VectorBuilder
+	Limbs                   *common.VectorBuilder
+	HashHi, HashLo          *common.VectorBuilder
+}
+
+func newRipemdBlockHashingAssignment(rbh *ripemdBlockModule) ripemdBlockHashingAssignment {
+	return ripemdBlockHashingAssignment{
+		IsActive:                common.NewVectorBuilder(rbh.IsActive),
+		IsEffBlock:              common.NewVectorBuilder(rbh.IsEffBlock),
+		IsEffFirstLaneOfNewHash: common.NewVectorBuilder(rbh.IsEffFirstLaneOfNewHash),
+		IsEffLastLaneOfCurrHash: common.NewVectorBuilder(rbh.IsEffLastLaneOfCurrHash),
+		Limbs:                   common.NewVectorBuilder(rbh.Limbs),
+		HashHi:                  common.NewVectorBuilder(rbh.HashHi),
+		HashLo:                  common.NewVectorBuilder(rbh.HashLo),
+	}
+}
+
+// Run implements the [wizard.ProverAction] interface.
+func (rbh *ripemdBlockModule) Run(run *wizard.ProverRuntime) {
+
+	var (
+		assi                 = newRipemdBlockHashingAssignment(rbh)
+		isFirstLaneOfNewHash = rbh.Inputs.IsFirstLaneOfNewHash.GetColAssignment(run).IntoRegVecSaveAlloc()
+		packedUint32         = rbh.Inputs.PackedUint32.GetColAssignment(run).IntoRegVecSaveAlloc()
+		selector             = rbh.Inputs.Selector.GetColAssignment(run).IntoRegVecSaveAlloc()
+		numRowInp            = len(isFirstLaneOfNewHash)
+		cursorInp            = 0
+	)
+
+	// scanCurrHash starts from the cursor and increments it until it finds
+	// a row where "isFirstNewHash" is 1 or reaches the end of the input module.
+	scanCurrHash := func() []field.Element {
+
+		var (
+			blocks  []field.Element
+			isFirst = true
+		)
+
+		for ; cursorInp < numRowInp; cursorInp++ {
+
+			// If we cross a new hash, it hits a stopping condition. We don't
+			// include in the loop boundary as it features a sanity-check.
+			if !isFirst && isFirstLaneOfNewHash[cursorInp].IsOne() {
+
+				if selector[cursorInp].IsZero() {
+					utils.Panic("unexpected: at row %v, the selector is zero but isNewHash is one", cursorInp)
+				}
+
+				return blocks
+			}
+
+			isFirst = false
+			if selector[cursorInp].IsZero() {
+				continue
+			}
+
+			blocks = append(blocks, packedUint32[cursorInp])
+		}
+
+		return blocks
+	}
+
+	for cursorInp < numRowInp {
+
+		var (
+			currBlock    [16]field.Element
+			blocks       = scanCurrHash()
+			currState    = initializationVector
+			isFirstBlock = true
+		)
+
+		if len(blocks)%16 != 0 {
+			panic("unappropriate number of lanes in the current stream. Has it been padded?")
+		}
+
+		for len(blocks) > 0 {
+
+			copy(currBlock[:], blocks)
+			blocks = blocks[16:]
+			currState = assi.pushBlock(currState, currBlock, isFirstBlock, len(blocks) == 0)
+			isFirstBlock = false
+		}
+
+		assi.catchUpHashHiLo(currState)
+	}
+
+	assi.padAndAssign(run)
+
+	for i := range rbh.proverActions {
+		rbh.proverActions[i].Run(run)
+	}
+
+	if rbh.hasCircuit {
+		// this is guarded by a once, so it is safe to call multiple times
+		registerGnarkHint()
+		rbh.GnarkCircuitConnector.Assign(run)
+	}
+}
+
+// pushBlock pushes the first block of a hash
+func (rbha *ripemdBlockHashingAssignment) pushBlock(
+	oldState [2]field.Element,
+	block [16]field.Element,
+	isFirstBlockOfHash bool,
+	isLastBlockOfHash bool,
+) (newState [2]field.Element) {
+
+	newState = ripemdCompress(oldState, block)
+
+	for i := range oldState {
+		rbha.IsActive.PushOne()
+		rbha.IsEffBlock.PushZero()
+		rbha.IsEffFirstLaneOfNewHash.PushBoolean(isFirstBlockOfHash && i == 0)
+		rbha.IsEffLastLaneOfCurrHash.PushZero()
+		rbha.Limbs.PushField(oldState[i])
+	}
+
+	for i := range block {
+		rbha.IsActive.PushOne()
+		rbha.IsEffBlock.PushOne()
+		rbha.IsEffFirstLaneOfNewHash.PushZero()
+		rbha.IsEffLastLaneOfCurrHash.PushZero()
+		rbha.Limbs.PushField(block[i])
+	}
+
+	for i := range newState {
+		rbha.IsActive.PushOne()
+		rbha.IsEffBlock.PushZero()
+		rbha.IsEffFirstLaneOfNewHash.PushZero()
+		rbha.IsEffLastLaneOfCurrHash.PushBoolean(isLastBlockOfHash && i == 1)
+		rbha.Limbs.PushField(newState[i])
+	}
+
+	return newState
+}
+
+// catchUpHashHiLo pushes over the HashHi and HashLo columns so that their
+// heights match the one of the rest of the columns
+func (rbha *ripemdBlockHashingAssignment) catchUpHashHiLo(finalState [2]field.Element) {
+
+	var (
+		heightHash   = rbha.HashHi.Height()
+		heightRest   = rbha.IsActive.Height()
+		numToCatchUp = heightRest - heightHash
+	)
+
+	for i := 0; i < numToCatchUp; i++ {
+		rbha.HashHi.PushField(finalState[0])
+		rbha.HashLo.PushField(finalState[1])
+	}
+}
+
+// padAndAssign concludes the building by effectively assign what has been
+// accumulated so far.
+func (rbha *ripemdBlockHashingAssignment) padAndAssign(run *wizard.ProverRuntime) {
+	rbha.IsActive.PadAndAssign(run, field.Zero())
+	rbha.IsEffFirstLaneOfNewHash.PadAndAssign(run, field.Zero())
+	rbha.IsEffLastLaneOfCurrHash.PadAndAssign(run, field.Zero())
+	rbha.IsEffBlock.PadAndAssign(run, field.Zero())
+	rbha.Limbs.PadAndAssign(run, field.Zero())
+	rbha.HashHi.PadAndAssign(run, field.Zero())
+	rbha.HashLo.PadAndAssign(run, field.Zero())
+}
+
+// ripemdCompress runs the compression function and returns the resulting
+// hasher state in the form of two field elements: the first one storing the
+// first 4 bytes of the state and the second one storing the remaining 16 bytes.
+func ripemdCompress(oldState [2]field.Element, block [16]field.Element) (newState [2]field.Element) {
+
+	var (
+		oldStateBytes = ripemd160.Digest{}
+		blockBytes    = ripemd160.Block{}
+		osHi          = oldState[0].Bytes()
+		osLo          = oldState[1].Bytes()
+	)
+
+	copy(oldStateBytes[:4], osHi[32-4:])
+	copy(oldStateBytes[4:], osLo[32-16:])
+
+	for i := range block {
+		bI := block[i].Bytes()
+		copy(blockBytes[4*i:], bI[32-4:])
+	}
+
+	newStateBytes := ripemd160.Compress(oldStateBytes, blockBytes)
+
+	newState[0].SetBytes(newStateBytes[:4])
+	newState[1].SetBytes(newStateBytes[4:])
+
+	return newState
+}
+
+var onceRegisterGnarkHint = sync.Once{}
+
+// registerGnarkHint registers the circuit specific hint needed to assign to
+// the circuit
+func registerGnarkHint() {
+	onceRegisterGnarkHint.Do(func() {
+		solver.RegisterHint(gnarkutil.DecomposeIntoBytesHint)
+	})
+}
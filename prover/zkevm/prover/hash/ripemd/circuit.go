@@ -0,0 +1,120 @@
+package ripemd
+
+import (
+	"fmt"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/uints"
+	"github.com/consensys/gnark/std/permutation/ripemd160"
+	"github.com/consensys/linea-monorepo/prover/zkevm/prover/hash/internal/gnarkutil"
+)
+
+// ripemdCircuit is the gnark circuit (compiled as Plonk) used to check the
+// Ripemd160 compression function.
+type ripemdCircuit struct {
+	Instances []ripemdBlockPermutationInstance `gnark:",public"`
+}
+
+func allocateRipemdCircuit(nbInstances int) *ripemdCircuit {
+	return &ripemdCircuit{
+		Instances: make([]ripemdBlockPermutationInstance, nbInstances),
+	}
+}
+
+// Define implements the [frontend.Circuit] interface
+func (rc *ripemdCircuit) Define(api frontend.API) error {
+	for i := range rc.Instances {
+		rc.Instances[i].checkRipemdPermutation(api)
+	}
+	return nil
+}
+
+// ripemdBlockPermutationInstance represents a instance of the ripemd160 block
+// permutation.
+type ripemdBlockPermutationInstance struct {
+	// prevDigest is the previous digest formatted as a uint32 and a uint128
+	PrevDigest [2]frontend.Variable
+	// the block formatted as [16]uint32 in big-endian order
+	Block [16]frontend.Variable
+	// the current digest formatted as a uint32 and a uint128
+	NewDigest [2]frontend.Variable
+}
+
+// checkRipemdPermutation adds the constraints ensuring the correctness of the
+// instance.
+func (rbpi *ripemdBlockPermutationInstance) checkRipemdPermutation(api frontend.API) {
+
+	uapi, err := uints.New[uints.U32](api)
+	if err != nil {
+		panic(fmt.Sprintf("unexpected error when instantiating `uapi`: %v", err.Error()))
+	}
+
+	var (
+		// If the new digest is zero, then the block check is skipped as this is
+		// considered a padding instance. The wizard should externally check that
+		// NewDigest = 0x0 is forbidden.
+		inpIsZero = api.Add(
+			api.IsZero(rbpi.NewDigest[0]),
+			api.IsZero(rbpi.NewDigest[1]),
+		)
+	)
+
+	var (
+		prevDigest = castU32U128To5xU32s(api, rbpi.PrevDigest)
+		newDigest  = castU32U128To5xU32s(api, rbpi.NewDigest)
+		blockBytes = [64]uints.U8{}
+	)
+
+	// The block is given as a sequence of big-endian uint32 so unpacking them
+	// in MSB order recovers the stream of bytes. The permutation takes care
+	// of the little-endian conversion of the words.
+	for i := range rbpi.Block {
+		blockU32 := uapi.ValueOf(rbpi.Block[i])
+		blockU8 := uapi.UnpackMSB(blockU32)
+		copy(blockBytes[4*i:], blockU8)
+	}
+
+	recomputedNewDigest := ripemd160.Permute(uapi, prevDigest, blockBytes)
+
+	for i := range recomputedNewDigest {
+		// This checks that newDigest == recomputedDigest unless inpIsZero == 2
+		api.AssertIsEqual(
+			api.Mul(
+				api.Sub(inpIsZero, 2),
+				api.Sub(
+					uapi.ToValue(recomputedNewDigest[i]),
+					uapi.ToValue(newDigest[i]),
+				),
+			),
+			0,
+		)
+	}
+}
+
+// castU32U128To5xU32s converts a ripemd160 state given as a uint32 and a
+// uint128 (storing the 20 bytes of the serialized state) into the 5 uint32
+// words of the state. The words are serialized in little-endian order.
+func castU32U128To5xU32s(api frontend.API, v [2]frontend.Variable) [5]uints.U32 {
+
+	var (
+		u8Vars = append(
+			gnarkutil.ToNBytes(api, v[0], 4),
+			gnarkutil.ToNBytes(api, v[1], 16)...,
+		)
+		u8s     = make([]uints.U8, 20)
+		u32s    = [5]uints.U32{}
+		uapi, _ = uints.New[uints.U32](api)
+	)
+
+	for i := range u8Vars {
+		// Converting this way instead of using the uapi constructor saves a
+		// rangecheck.
+		u8s[i] = uints.U8{Val: u8Vars[i]}
+	}
+
+	for i := range u32s {
+		u32s[i] = uapi.PackLSB(u8s[4*i : 4*i+4]...)
+	}
+
+	return u32s
+}
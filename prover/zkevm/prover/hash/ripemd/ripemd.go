@@ -0,0 +1,152 @@
+// The ripemd package provides all the necessary tools to verify the calls to
+// the ripemd160 precompile in the Linea's zkevm.
+package ripemd
+
+import (
+	"github.com/consensys/linea-monorepo/prover/protocol/column"
+	"github.com/consensys/linea-monorepo/prover/protocol/dedicated/projection"
+	"github.com/consensys/linea-monorepo/prover/protocol/ifaces"
+	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
+	"github.com/consensys/linea-monorepo/prover/utils"
+	"github.com/consensys/linea-monorepo/prover/zkevm/prover/hash/generic"
+	"github.com/consensys/linea-monorepo/prover/zkevm/prover/hash/importpad"
+	"github.com/consensys/linea-monorepo/prover/zkevm/prover/hash/packing"
+)
+
+const (
+	maxNbRipemdBlockPerCircuitZkevm = 10
+)
+
+type Settings struct {
+	MaxNumRipemdF int
+}
+
+// RipemdSingleProviderInput stores the inputs for [newRipemdSingleProvider]
+type RipemdSingleProviderInput struct {
+	Settings
+	Provider generic.GenericByteModule
+}
+
+// RipemdSingleProvider stores the hash result and [wizard.ProverAction] of the
+// submodules.
+type RipemdSingleProvider struct {
+	Inputs         *RipemdSingleProviderInput
+	HashHi, HashLo ifaces.Column
+	// indicates the active part of HashHi/HashLo
+	IsActive      ifaces.Column
+	MaxNumRipemdF int
+
+	// prover actions for  internal modules
+	pa_importPad, pa_packing wizard.ProverAction
+	pa_cRipemd               *ripemdBlockModule
+}
+
+// NewRipemdZkEvm constructs the Ripemd module as used in Linea's zkEVM.
+func NewRipemdZkEvm(comp *wizard.CompiledIOP, s Settings) *RipemdSingleProvider {
+	return newRipemdSingleProvider(comp, RipemdSingleProviderInput{
+		Settings: s,
+		Provider: generic.GenericByteModule{
+			Data: generic.GenDataModule{
+				HashNum: comp.Columns.GetHandle("shakiradata.ID"),
+				Index:   comp.Columns.GetHandle("shakiradata.INDEX"),
+				Limb:    comp.Columns.GetHandle("shakiradata.LIMB"),
+				NBytes:  comp.Columns.GetHandle("shakiradata.nBYTES"),
+				ToHash:  comp.Columns.GetHandle("shakiradata.IS_RIPEMD_DATA"),
+			},
+			Info: generic.GenInfoModule{
+				HashNum:  comp.Columns.GetHandle("shakiradata.ID"),
+				HashLo:   comp.Columns.GetHandle("shakiradata.LIMB"),
+				HashHi:   comp.Columns.GetHandle("shakiradata.LIMB"),
+				IsHashLo: column.Shift(comp.Columns.GetHandle("shakiradata.SELECTOR_RIPEMD_RES_HI"), -1),
+				IsHashHi: comp.Columns.GetHandle("shakiradata.SELECTOR_RIPEMD_RES_HI"),
+			},
+		},
+	})
+}
+
+// newRipemdSingleProvider implements the utilities for proving ripemd160 hash
+// over the streams which are encoded inside a set of structs [generic.GenDataModule].
+// It calls;
+// -  Padding module to insure the correct padding of the streams.
+// -  packing module to insure the correct packing of padded-stream into blocks.
+// -  ripemdBlocks to insures the correct hash computation over the given blocks.
+func newRipemdSingleProvider(comp *wizard.CompiledIOP, inp RipemdSingleProviderInput) *RipemdSingleProvider {
+	var (
+		maxNumRipemdF = inp.MaxNumRipemdF
+		size          = utils.NextPowerOfTwo(maxNumRipemdF * generic.RipemdUsecase.BlockSizeBytes())
+
+		// apply import and pad
+		inpImportPadd = importpad.ImportAndPadInputs{
+			Name: "RIPEMD",
+			Src: generic.GenericByteModule{
+				Data: inp.Provider.Data,
+			},
+			PaddingStrategy: generic.RipemdUsecase,
+		}
+
+		imported = importpad.ImportAndPad(comp, inpImportPadd, size)
+
+		// apply packing
+		inpPck = packing.PackingInput{
+			MaxNumBlocks: maxNumRipemdF,
+			PackingParam: generic.RipemdUsecase,
+			Imported: packing.Importation{
+				Limb:      imported.Limbs,
+				NByte:     imported.NBytes,
+				IsNewHash: imported.IsNewHash,
+				IsActive:  imported.IsActive,
+			},
+			Name: "RIPEMD",
+		}
+
+		packing = packing.NewPack(comp, inpPck)
+
+		// this ensures the correctness of the block hashing
+		cRipemdInp = &ripemdBlocksInputs{
+			Name:                 "RIPEMD_OVER_BLOCK",
+			MaxNbBlockPerCirc:    maxNbRipemdBlockPerCircuitZkevm,
+			MaxNbCircuit:         utils.DivCeil(maxNumRipemdF, maxNbRipemdBlockPerCircuitZkevm),
+			PackedUint32:         packing.Repacked.Lanes,
+			Selector:             packing.Repacked.IsLaneActive,
+			IsFirstLaneOfNewHash: packing.Repacked.IsFirstLaneOfNewHash,
+		}
+		cRipemd = newRipemdBlockModule(comp, cRipemdInp).WithCircuit(comp)
+	)
+
+	projection.InsertProjection(comp, "RIPEMD_RES_HI",
+		[]ifaces.Column{cRipemd.HashHi},
+		[]ifaces.Column{inp.Provider.Info.HashHi},
+		cRipemd.IsEffFirstLaneOfNewHash,
+		inp.Provider.Info.IsHashHi,
+	)
+	projection.InsertProjection(comp, "RIPEMD_RES_LO",
+		[]ifaces.Column{cRipemd.HashLo},
+		[]ifaces.Column{inp.Provider.Info.HashLo},
+		cRipemd.IsEffFirstLaneOfNewHash,
+		inp.Provider.Info.IsHashLo,
+	)
+
+	// set the module
+	m := &RipemdSingleProvider{
+		Inputs:        &inp,
+		MaxNumRipemdF: maxNumRipemdF,
+		HashHi:        cRipemd.HashHi,
+		HashLo:        cRipemd.HashLo,
+		IsActive:      cRipemd.IsActive,
+		pa_importPad:  imported,
+		pa_packing:    packing,
+		pa_cRipemd:    cRipemd,
+	}
+
+	return m
+}
+
+// It implements [wizard.ProverAction] for ripemd.
+func (m *RipemdSingleProvider) Run(run *wizard.ProverRuntime) {
+
+	// assign ImportAndPad module
+	m.pa_importPad.Run(run)
+	// assign packing module
+	m.pa_packing.Run(run)
+	m.pa_cRipemd.Run(run)
+}
@@ -0,0 +1,26 @@
+//go:build !fuzzlight
+
+package ripemd
+
+import (
+	"strconv"
+	"testing"
+)
+
+func TestRipemdWithCircuit(t *testing.T) {
+
+	var testCases = []testCaseFile{
+		{
+			InpFile:      "testdata/input.csv",
+			ModFile:      "testdata/mod.csv",
+			NbBlockLimit: 10,
+			WithCircuit:  true,
+		},
+	}
+
+	for i := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			runTestRipemd(t, testCases[i])
+		})
+	}
+}
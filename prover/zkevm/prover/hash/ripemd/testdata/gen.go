@@ -0,0 +1,50 @@
+package main
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math/rand"
+
+	"github.com/consensys/linea-monorepo/prover/backend/files"
+	"github.com/consensys/linea-monorepo/prover/crypto/ripemd160"
+)
+
+// main generates the input file of the test of the ripemd block module. It
+// is to be run from the package directory.
+func main() {
+
+	var (
+		rng        = rand.New(rand.NewSource(98761))
+		streamLens = []int{0, 13, 55, 56, 64, 130}
+		oF         = files.MustOverwrite("./testdata/input.csv")
+	)
+
+	fmt.Fprint(oF, "PACKED_DATA,SELECTOR,IS_FIRST_LANE_OF_NEW_HASH\n")
+
+	for _, l := range streamLens {
+
+		var (
+			stream = make([]byte, l)
+			_, _   = rng.Read(stream)
+			padded = ripemd160.PadStream(stream)
+		)
+
+		for i := 0; i < len(padded); i += 4 {
+
+			isFirst := 0
+			if i == 0 {
+				isFirst = 1
+			}
+
+			fmt.Fprintf(oF, "%v,1,%v\n", binary.BigEndian.Uint32(padded[i:i+4]), isFirst)
+
+			// Adds an inactive row from time to time to ensure that the
+			// module correctly skips them.
+			if rng.Intn(8) == 0 {
+				fmt.Fprintf(oF, "%v,0,0\n", binary.BigEndian.Uint32(padded[i:i+4]))
+			}
+		}
+	}
+
+	oF.Close()
+}
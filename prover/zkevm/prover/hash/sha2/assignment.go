@@ -9,6 +9,7 @@ import (
 	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
 	"github.com/consensys/linea-monorepo/prover/utils"
 	"github.com/consensys/linea-monorepo/prover/zkevm/prover/common"
+	"github.com/consensys/linea-monorepo/prover/zkevm/prover/hash/internal/gnarkutil"
 )
 
 // sha2BlockHashingAssignment is a collection of column builder used to construct
@@ -215,6 +216,6 @@ var onceRegisterGnarkHint = sync.Once{}
 // the circuit
 func registerGnarkHint() {
 	onceRegisterGnarkHint.Do(func() {
-		solver.RegisterHint(decomposeIntoBytesHint)
+		solver.RegisterHint(gnarkutil.DecomposeIntoBytesHint)
 	})
 }
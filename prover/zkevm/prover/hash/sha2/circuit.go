@@ -6,6 +6,7 @@ import (
 	"github.com/consensys/gnark/frontend"
 	"github.com/consensys/gnark/std/math/uints"
 	"github.com/consensys/gnark/std/permutation/sha2"
+	"github.com/consensys/linea-monorepo/prover/zkevm/prover/hash/internal/gnarkutil"
 )
 
 // sha2Circuit is the gnark circuit (compiled as Plonk) used to check the Sha2
@@ -90,8 +91,8 @@ func cast2xu128To8xU32s(api frontend.API, v [2]frontend.Variable) [8]uints.U32 {
 
 	var (
 		u8Vars = append(
-			toNBytes(api, v[0], 16),
-			toNBytes(api, v[1], 16)...,
+			gnarkutil.ToNBytes(api, v[0], 16),
+			gnarkutil.ToNBytes(api, v[1], 16)...,
 		)
 		u8s     = make([]uints.U8, 32)
 		u32s    = [8]uints.U32{}
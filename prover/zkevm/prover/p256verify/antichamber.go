@@ -0,0 +1,186 @@
+package p256verify
+
+import (
+	"github.com/consensys/linea-monorepo/prover/maths/field"
+	"github.com/consensys/linea-monorepo/prover/protocol/dedicated/plonk"
+	"github.com/consensys/linea-monorepo/prover/protocol/ifaces"
+	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
+	"github.com/consensys/linea-monorepo/prover/utils"
+	"github.com/consensys/linea-monorepo/prover/zkevm/arithmetization"
+	"github.com/consensys/linea-monorepo/prover/zkevm/prover/common"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	ROUND_NR         = 0
+	NAME_ANTICHAMBER = "P256_VERIFY_ANTICHAMBER"
+	NAME_GNARK_DATA  = "P256_VERIFY_ANTICHAMBER_GNARK_DATA"
+)
+
+const (
+	// 10 rows for the inputs: h, r, s, qx, qy each on two limbs
+	nbRowsPerP256VerifyData = 10
+	// 2 rows for the result which is 1 if the signature is valid
+	nbRowsPerP256VerifyRes = 2
+	// number of public inputs gnark circuit takes as a public witness. All the
+	// rows fetched from EC_DATA are pushed to the circuit.
+	nbRowsPerP256Verify = nbRowsPerP256VerifyData + nbRowsPerP256VerifyRes
+)
+
+func createColFn(comp *wizard.CompiledIOP, rootName string, size int) func(name string) ifaces.Column {
+	return func(name string) ifaces.Column {
+		return comp.InsertCommit(ROUND_NR, ifaces.ColIDf("%s_%s", rootName, name), size)
+	}
+}
+
+type antichamberInput struct {
+	ecSource     *ecDataSource
+	settings     *Settings
+	plonkOptions []plonk.Option
+}
+
+// antichamber fetches the P256VERIFY segments of the EC_DATA module and
+// pushes them to the gnark circuit. As opposed to ecrecover, the precompile
+// takes the public key as an input so no data needs to be computed in the
+// antichamber.
+type antichamber struct {
+	Inputs   *antichamberInput
+	IsActive ifaces.Column
+	ID       ifaces.Column
+	Limb     ifaces.Column
+	Index    ifaces.Column
+	IsData   ifaces.Column
+	IsRes    ifaces.Column
+
+	AlignedGnarkData *plonk.Alignment
+
+	// size of AntiChamber
+	size int
+}
+
+type Settings struct {
+	MaxNbP256Verify    int
+	NbInputInstance    int
+	NbCircuitInstances int
+}
+
+func (l *Settings) sizeAntichamber() int {
+	return utils.NextPowerOfTwo(l.MaxNbP256Verify * nbRowsPerP256Verify)
+}
+
+// ecDataSource collects the columns of the EC_DATA module relevant to the
+// P256VERIFY precompile.
+type ecDataSource struct {
+	CsP256Verify ifaces.Column
+	ID           ifaces.Column
+	Limb         ifaces.Column
+	Index        ifaces.Column
+	IsData       ifaces.Column
+	IsRes        ifaces.Column
+}
+
+func newAntichamber(comp *wizard.CompiledIOP, inputs *antichamberInput) *antichamber {
+
+	settings := inputs.settings
+	if settings.MaxNbP256Verify > settings.NbInputInstance*settings.NbCircuitInstances {
+		utils.Panic("the number of supported instances %v should be at least %v", settings.NbInputInstance*settings.NbCircuitInstances, settings.MaxNbP256Verify)
+	}
+	size := settings.sizeAntichamber()
+	createCol := createColFn(comp, NAME_ANTICHAMBER, size)
+
+	res := &antichamber{
+		Inputs:   inputs,
+		IsActive: createCol("IS_ACTIVE"),
+		ID:       createCol("ID"),
+		Limb:     createCol("LIMB"),
+		Index:    createCol("INDEX"),
+		IsData:   createCol("IS_DATA"),
+		IsRes:    createCol("IS_RES"),
+		size:     size,
+	}
+
+	toAlign := &plonk.CircuitAlignmentInput{
+		Name:               NAME_GNARK_DATA,
+		Round:              ROUND_NR,
+		DataToCircuit:      res.Limb,
+		DataToCircuitMask:  res.IsActive,
+		Circuit:            newMultiP256VerifyCircuit(settings.NbInputInstance),
+		PlonkOptions:       inputs.plonkOptions,
+		NbCircuitInstances: settings.NbCircuitInstances,
+	}
+	res.AlignedGnarkData = plonk.DefineAlignment(comp, toAlign)
+
+	res.csIsActiveActivation(comp)
+	res.csZeroWhenInactive(comp)
+	res.csDataOrResult(comp)
+	res.csEcDataProjection(comp)
+
+	return res
+}
+
+// assign assigns the columns of the antichamber by copying the P256VERIFY
+// segments of the EC_DATA module and then assigns the gnark circuit.
+func (ac *antichamber) assign(run *wizard.ProverRuntime) {
+
+	var (
+		src           = ac.Inputs.ecSource
+		srcCs         = src.CsP256Verify.GetColAssignment(run).IntoRegVecSaveAlloc()
+		srcID         = src.ID.GetColAssignment(run).IntoRegVecSaveAlloc()
+		srcLimb       = src.Limb.GetColAssignment(run).IntoRegVecSaveAlloc()
+		srcIndex      = src.Index.GetColAssignment(run).IntoRegVecSaveAlloc()
+		srcIsData     = src.IsData.GetColAssignment(run).IntoRegVecSaveAlloc()
+		srcIsRes      = src.IsRes.GetColAssignment(run).IntoRegVecSaveAlloc()
+		dstIsActive   = common.NewVectorBuilder(ac.IsActive)
+		dstID         = common.NewVectorBuilder(ac.ID)
+		dstLimb       = common.NewVectorBuilder(ac.Limb)
+		dstIndex      = common.NewVectorBuilder(ac.Index)
+		dstIsData     = common.NewVectorBuilder(ac.IsData)
+		dstIsRes      = common.NewVectorBuilder(ac.IsRes)
+		nbP256Verify  = 0
+		maxP256Verify = ac.Inputs.settings.MaxNbP256Verify
+	)
+
+	if len(srcCs) != len(srcID) || len(srcCs) != len(srcLimb) || len(srcCs) != len(srcIndex) || len(srcCs) != len(srcIsData) || len(srcCs) != len(srcIsRes) {
+		utils.Panic("all source columns must have the same length")
+	}
+
+	for currRow := 0; currRow < len(srcCs); {
+
+		if srcCs[currRow].IsZero() {
+			currRow++
+			continue
+		}
+
+		// This sanity-check is purely defensive and will indicate that we
+		// missed the start of a P256VERIFY instance
+		if len(srcCs)-currRow < nbRowsPerP256Verify {
+			utils.Panic("a new p256verify is starting but there is not enough rows (currRow=%v len(ecdata)=%v)", currRow, len(srcCs))
+		}
+
+		for j := 0; j < nbRowsPerP256Verify; j++ {
+			dstIsActive.PushOne()
+			dstID.PushField(srcID[currRow+j])
+			dstLimb.PushField(srcLimb[currRow+j])
+			dstIndex.PushField(srcIndex[currRow+j])
+			dstIsData.PushField(srcIsData[currRow+j])
+			dstIsRes.PushField(srcIsRes[currRow+j])
+		}
+
+		nbP256Verify++
+		currRow += nbRowsPerP256Verify
+	}
+
+	if nbP256Verify > maxP256Verify {
+		logrus.Errorf("limit overflow: the p256verify count is %v and the limit is %v\n", nbP256Verify, maxP256Verify)
+		arithmetization.PanicOverflow("p256verify", nbP256Verify, maxP256Verify)
+	}
+
+	dstIsActive.PadAndAssign(run, field.Zero())
+	dstID.PadAndAssign(run, field.Zero())
+	dstLimb.PadAndAssign(run, field.Zero())
+	dstIndex.PadAndAssign(run, field.Zero())
+	dstIsData.PadAndAssign(run, field.Zero())
+	dstIsRes.PadAndAssign(run, field.Zero())
+
+	ac.AlignedGnarkData.Assign(run)
+}
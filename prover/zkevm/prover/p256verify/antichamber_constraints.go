@@ -0,0 +1,44 @@
+package p256verify
+
+import (
+	"github.com/consensys/linea-monorepo/prover/protocol/dedicated/projection"
+	"github.com/consensys/linea-monorepo/prover/protocol/ifaces"
+	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
+	commoncs "github.com/consensys/linea-monorepo/prover/zkevm/prover/common/common_constraints"
+)
+
+// csIsActiveActivation constraints that IsActive module to be only one for antichamber rounds.
+func (ac *antichamber) csIsActiveActivation(comp *wizard.CompiledIOP) {
+	// IsActive must be binary and cannot transition from 0 to 1
+	commoncs.MustBeActivationColumns(comp, ac.IsActive)
+}
+
+func (ac *antichamber) csZeroWhenInactive(comp *wizard.CompiledIOP) {
+	commoncs.MustZeroWhenInactive(comp, ac.IsActive,
+		ac.ID,
+		ac.Limb,
+		ac.Index,
+		ac.IsData,
+		ac.IsRes,
+	)
+}
+
+func (ac *antichamber) csDataOrResult(comp *wizard.CompiledIOP) {
+	// every active row is either a data row or a result row
+	commoncs.MustBeMutuallyExclusiveBinaryFlags(comp, ac.IsActive, []ifaces.Column{
+		ac.IsData,
+		ac.IsRes,
+	})
+}
+
+func (ac *antichamber) csEcDataProjection(comp *wizard.CompiledIOP) {
+	// The arithmetization ensures that the P256VERIFY segments of EC_DATA
+	// always span exactly nbRowsPerP256Verify rows, so the projection also
+	// ensures that the instances are aligned with the circuit inputs.
+	src := ac.Inputs.ecSource
+	projection.InsertProjection(comp, ifaces.QueryIDf("%v_PROJECT_ECDATA", NAME_ANTICHAMBER),
+		[]ifaces.Column{ac.ID, ac.Limb, ac.Index, ac.IsData, ac.IsRes},
+		[]ifaces.Column{src.ID, src.Limb, src.Index, src.IsData, src.IsRes},
+		ac.IsActive, src.CsP256Verify,
+	)
+}
@@ -0,0 +1,65 @@
+package p256verify
+
+import (
+	"testing"
+
+	"github.com/consensys/linea-monorepo/prover/protocol/compiler/dummy"
+	"github.com/consensys/linea-monorepo/prover/protocol/dedicated/plonk"
+	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
+	"github.com/consensys/linea-monorepo/prover/utils/csvtraces"
+)
+
+func TestAntichamber(t *testing.T) {
+
+	var (
+		ac       *antichamber
+		ecCt     = csvtraces.MustOpenCsvFile("testdata/ecdata.csv")
+		acCt     = csvtraces.MustOpenCsvFile("testdata/antichamber.csv")
+		settings = &Settings{
+			MaxNbP256Verify:    4,
+			NbInputInstance:    2,
+			NbCircuitInstances: 2,
+		}
+	)
+
+	cmp := wizard.Compile(
+		func(b *wizard.Builder) {
+			ac = newAntichamber(
+				b.CompiledIOP,
+				&antichamberInput{
+					ecSource: &ecDataSource{
+						CsP256Verify: ecCt.GetCommit(b, "EC_DATA_CS_P256_VERIFY"),
+						ID:           ecCt.GetCommit(b, "EC_DATA_ID"),
+						Limb:         ecCt.GetCommit(b, "EC_DATA_LIMB"),
+						Index:        ecCt.GetCommit(b, "EC_DATA_INDEX"),
+						IsData:       ecCt.GetCommit(b, "EC_DATA_IS_DATA"),
+						IsRes:        ecCt.GetCommit(b, "EC_DATA_IS_RES"),
+					},
+					plonkOptions: []plonk.Option{plonk.WithRangecheck(16, 6, true)},
+					settings:     settings,
+				},
+			)
+		},
+		dummy.Compile,
+	)
+
+	proof := wizard.Prove(cmp,
+		func(run *wizard.ProverRuntime) {
+			ecCt.Assign(run,
+				"EC_DATA_CS_P256_VERIFY", "EC_DATA_ID", "EC_DATA_LIMB", "EC_DATA_INDEX", "EC_DATA_IS_DATA", "EC_DATA_IS_RES",
+			)
+			ac.assign(run)
+			acCt.CheckAssignment(run,
+				"P256_VERIFY_ANTICHAMBER_IS_ACTIVE",
+				"P256_VERIFY_ANTICHAMBER_ID",
+				"P256_VERIFY_ANTICHAMBER_INDEX",
+				"P256_VERIFY_ANTICHAMBER_LIMB",
+				"P256_VERIFY_ANTICHAMBER_IS_DATA",
+				"P256_VERIFY_ANTICHAMBER_IS_RES",
+			)
+		})
+
+	if err := wizard.Verify(cmp, proof); err != nil {
+		t.Fatal("proof failed", err)
+	}
+}
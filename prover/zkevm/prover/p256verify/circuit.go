@@ -0,0 +1,142 @@
+package p256verify
+
+import (
+	"fmt"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/algopts"
+	"github.com/consensys/gnark/std/algebra/emulated/sw_emulated"
+	"github.com/consensys/gnark/std/math/bitslice"
+	"github.com/consensys/gnark/std/math/emulated"
+	"github.com/consensys/gnark/std/math/emulated/emparams"
+)
+
+// P256VerifyInstance stores the public inputs of a single P256VERIFY call as
+// they are laid out in the EC_DATA module: the message hash, the signature
+// (r, s), the public key (qx, qy) and the 32 bytes result. Every value is
+// split in two 128 bits limbs.
+type P256VerifyInstance struct {
+	HHi, HLo           frontend.Variable `gnark:",public"`
+	RHi, RLo           frontend.Variable `gnark:",public"`
+	SHi, SLo           frontend.Variable `gnark:",public"`
+	QXHi, QXLo         frontend.Variable `gnark:",public"`
+	QYHi, QYLo         frontend.Variable `gnark:",public"`
+	ResultHi, ResultLo frontend.Variable `gnark:",public"`
+}
+
+type MultiP256VerifyCircuit struct {
+	Instances []P256VerifyInstance `gnark:",public"`
+}
+
+func newMultiP256VerifyCircuit(nbInstances int) *MultiP256VerifyCircuit {
+	return &MultiP256VerifyCircuit{
+		Instances: make([]P256VerifyInstance, nbInstances),
+	}
+}
+
+func (c *MultiP256VerifyCircuit) Define(api frontend.API) error {
+	curve, err := sw_emulated.New[emparams.P256Fp, emparams.P256Fr](api, sw_emulated.GetP256Params())
+	if err != nil {
+		return fmt.Errorf("new curve: %w", err)
+	}
+	fr, err := emulated.NewField[emparams.P256Fr](api)
+	if err != nil {
+		return fmt.Errorf("field emulation: %w", err)
+	}
+	fp, err := emulated.NewField[emparams.P256Fp](api)
+	if err != nil {
+		return fmt.Errorf("field emulation: %w", err)
+	}
+	for i := range c.Instances {
+		isValid := c.Instances[i].isValid(api, curve, fr, fp)
+		api.AssertIsEqual(c.Instances[i].ResultHi, 0)
+		api.AssertIsEqual(c.Instances[i].ResultLo, isValid)
+	}
+	return nil
+}
+
+// isValid returns 1 if the signature is valid for the message hash and the
+// public key following the specification of RIP-7212 and 0 otherwise. The
+// function must be satisfiable for any input: when the inputs are out of
+// range or when the public key is not on the curve, the arithmetic is carried
+// on with placeholder values and the result is forced to 0.
+func (c *P256VerifyInstance) isValid(
+	api frontend.API,
+	curve *sw_emulated.Curve[emparams.P256Fp, emparams.P256Fr],
+	fr *emulated.Field[emparams.P256Fr],
+	fp *emulated.Field[emparams.P256Fp],
+) frontend.Variable {
+
+	var (
+		h  = fr.NewElement(toLimbs(api, c.HHi, c.HLo))
+		r  = fr.NewElement(toLimbs(api, c.RHi, c.RLo))
+		s  = fr.NewElement(toLimbs(api, c.SHi, c.SLo))
+		qx = fp.NewElement(toLimbs(api, c.QXHi, c.QXLo))
+		qy = fp.NewElement(toLimbs(api, c.QYHi, c.QYLo))
+	)
+
+	// 0 < r < n and 0 < s < n
+	var (
+		rIsInRange = api.Mul(isCanonical(api, fr, r), api.Sub(1, fr.IsZero(r)))
+		sIsInRange = api.Mul(isCanonical(api, fr, s), api.Sub(1, fr.IsZero(s)))
+	)
+
+	// qx < p, qy < p and y^2 = x^3 + ax + b. This also excludes the point at
+	// infinity as (0, 0) is not on the curve.
+	var (
+		params   = sw_emulated.GetP256Params()
+		a        = fp.NewElement(params.A)
+		b        = fp.NewElement(params.B)
+		lhs      = fp.Mul(qy, qy)
+		rhs      = fp.Add(fp.Mul(fp.Add(fp.Mul(qx, qx), a), qx), b)
+		qIsValid = api.Mul(
+			isCanonical(api, fp, qx),
+			isCanonical(api, fp, qy),
+			fp.IsZero(fp.Sub(lhs, rhs)),
+		)
+	)
+
+	// When the inputs are invalid, we replace them with values for which the
+	// computation below is well-defined. The result does not matter in this
+	// case as it is multiplied by zero.
+	var (
+		sSafe = fr.Select(sIsInRange, s, fr.One())
+		qSafe = curve.Select(qIsValid, &sw_emulated.AffinePoint[emparams.P256Fp]{X: *qx, Y: *qy}, curve.Generator())
+		u1    = fr.Div(h, sSafe)
+		u2    = fr.Div(r, sSafe)
+	)
+
+	// R = [u1]G + [u2]Q. The complete arithmetic ensures that the point at
+	// infinity is returned as (0, 0) in which case the comparison below fails
+	// as r is non-zero.
+	var (
+		rPoint  = curve.JointScalarMulBase(qSafe, u2, u1, algopts.WithCompleteArithmetic())
+		rxBits  = fp.ToBitsCanonical(&rPoint.X)
+		rxModN  = fr.FromBits(rxBits...)
+		rxIsRes = fr.IsZero(fr.Sub(rxModN, r))
+	)
+
+	return api.Mul(rIsInRange, sIsInRange, qIsValid, rxIsRes)
+}
+
+// isCanonical returns 1 if the element, whose limbs are assumed to be well
+// formed, is strictly smaller than the modulus of the field and 0 otherwise.
+func isCanonical[T emulated.FieldParams](api frontend.API, f *emulated.Field[T], x *emulated.Element[T]) frontend.Variable {
+	var (
+		reduced = f.ReduceStrict(x)
+		res     = frontend.Variable(1)
+	)
+	for i := range x.Limbs {
+		res = api.Mul(res, api.IsZero(api.Sub(x.Limbs[i], reduced.Limbs[i])))
+	}
+	return res
+}
+
+// toLimbs splits a 256 bits value given as two 128 bits limbs into the four 64
+// bits limbs (in little-endian order) used by the field emulation.
+func toLimbs(api frontend.API, hi, lo frontend.Variable) []frontend.Variable {
+	limbs := make([]frontend.Variable, 4)
+	limbs[2], limbs[3] = bitslice.Partition(api, hi, 64, bitslice.WithNbDigits(128))
+	limbs[0], limbs[1] = bitslice.Partition(api, lo, 64, bitslice.WithNbDigits(128))
+	return limbs
+}
@@ -0,0 +1,50 @@
+// Package p256verify implements the wizard module responsible for verifying
+// the calls to the P256VERIFY precompile (RIP-7212). The signature claims are
+// fetched from the EC_DATA module of the arithmetization into an antichamber
+// and then checked by a gnark circuit through the Plonk-in-Wizard alignment.
+package p256verify
+
+import (
+	"github.com/consensys/linea-monorepo/prover/protocol/dedicated/plonk"
+	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
+)
+
+type P256VerifyZkEvm struct {
+	ant *antichamber
+}
+
+// NewP256VerifyZkEvm returns the module verifying the calls to P256VERIFY or
+// nil if the EC_DATA module has no P256VERIFY columns.
+func NewP256VerifyZkEvm(
+	comp *wizard.CompiledIOP,
+	settings *Settings,
+) *P256VerifyZkEvm {
+	if !comp.Columns.Exists("ecdata.CIRCUIT_SELECTOR_P256_VERIFY") {
+		return nil
+	}
+	return &P256VerifyZkEvm{
+		ant: newAntichamber(
+			comp,
+			&antichamberInput{
+				settings:     settings,
+				ecSource:     getEcdataArithmetization(comp),
+				plonkOptions: []plonk.Option{plonk.WithRangecheck(16, 6, true)},
+			},
+		),
+	}
+}
+
+func (p *P256VerifyZkEvm) Assign(run *wizard.ProverRuntime) {
+	p.ant.assign(run)
+}
+
+func getEcdataArithmetization(comp *wizard.CompiledIOP) *ecDataSource {
+	return &ecDataSource{
+		CsP256Verify: comp.Columns.GetHandle("ecdata.CIRCUIT_SELECTOR_P256_VERIFY"),
+		ID:           comp.Columns.GetHandle("ecdata.ID"),
+		Limb:         comp.Columns.GetHandle("ecdata.LIMB"),
+		Index:        comp.Columns.GetHandle("ecdata.INDEX"),
+		IsData:       comp.Columns.GetHandle("ecdata.IS_P256_VERIFY_DATA"),
+		IsRes:        comp.Columns.GetHandle("ecdata.IS_P256_VERIFY_RESULT"),
+	}
+}
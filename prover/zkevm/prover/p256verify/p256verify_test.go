@@ -0,0 +1,23 @@
+package p256verify
+
+import (
+	"testing"
+
+	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewP256VerifyZkEvmUnsupported(t *testing.T) {
+
+	var p *P256VerifyZkEvm
+
+	wizard.Compile(func(b *wizard.Builder) {
+		// EC_DATA without the columns of the precompile
+		b.RegisterCommit("ecdata.ID", 16)
+		b.RegisterCommit("ecdata.LIMB", 16)
+		b.RegisterCommit("ecdata.INDEX", 16)
+		p = NewP256VerifyZkEvm(b.CompiledIOP, &Settings{MaxNbP256Verify: 1, NbInputInstance: 1, NbCircuitInstances: 1})
+	})
+
+	assert.Nil(t, p)
+}
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"crypto/elliptic"
+	"fmt"
+	"io"
+	"math/big"
+	"math/rand"
+
+	"github.com/consensys/linea-monorepo/prover/backend/files"
+)
+
+func main() {
+
+	var (
+		rng = rand.New(rand.NewSource(73546781))
+		tab = make([][]*big.Int, 6)
+		id  = int64(1)
+	)
+
+	pushFillerToInput(tab, rng, 3, &id)
+	pushP256VerifyToInput(tab, createValidP256Verify(rng), &id)
+	pushFillerToInput(tab, rng, 5, &id)
+	pushP256VerifyToInput(tab, createWrongHashP256Verify(rng), &id)
+	pushP256VerifyToInput(tab, createZeroRP256Verify(rng), &id)
+	pushFillerToInput(tab, rng, 2, &id)
+	pushP256VerifyToInput(tab, createOffCurveP256Verify(rng), &id)
+
+	f := files.MustOverwrite("./ecdata.csv")
+	dumpInputAsCsv(f, tab)
+	f.Close()
+
+	f = files.MustOverwrite("./antichamber.csv")
+	dumpAntichamberAsCsv(f, tab)
+	f.Close()
+}
+
+// p256VerifyInstance stores the operands and the result of a P256VERIFY call
+type p256VerifyInstance struct {
+	h, r, s, qx, qy *big.Int
+	isValid         bool
+}
+
+func createValidP256Verify(rng *rand.Rand) p256VerifyInstance {
+
+	var (
+		curve  = elliptic.P256()
+		n      = curve.Params().N
+		d      = randScalar(rng, n)
+		k      = randScalar(rng, n)
+		h      = new(big.Int).Rand(rng, new(big.Int).Lsh(big.NewInt(1), 256))
+		qx, qy = curve.ScalarBaseMult(d.Bytes())
+		rx, _  = curve.ScalarBaseMult(k.Bytes())
+		r      = new(big.Int).Mod(rx, n)
+		s      = new(big.Int)
+	)
+
+	// s = k^-1 * (h + r * d) mod n
+	s.Mul(r, d)
+	s.Add(s, h)
+	s.Mul(s, new(big.Int).ModInverse(k, n))
+	s.Mod(s, n)
+
+	return p256VerifyInstance{h: h, r: r, s: s, qx: qx, qy: qy, isValid: true}
+}
+
+func createWrongHashP256Verify(rng *rand.Rand) p256VerifyInstance {
+	res := createValidP256Verify(rng)
+	res.h = new(big.Int).Add(res.h, big.NewInt(1))
+	res.isValid = false
+	return res
+}
+
+func createZeroRP256Verify(rng *rand.Rand) p256VerifyInstance {
+	res := createValidP256Verify(rng)
+	res.r = new(big.Int)
+	res.isValid = false
+	return res
+}
+
+func createOffCurveP256Verify(rng *rand.Rand) p256VerifyInstance {
+	res := createValidP256Verify(rng)
+	res.qy = new(big.Int).Add(res.qy, big.NewInt(1))
+	res.isValid = false
+	return res
+}
+
+func randScalar(rng *rand.Rand, n *big.Int) *big.Int {
+	res := new(big.Int).Rand(rng, new(big.Int).Sub(n, big.NewInt(1)))
+	return res.Add(res, big.NewInt(1))
+}
+
+func dumpInputAsCsv(w io.Writer, tab [][]*big.Int) {
+
+	fmt.Fprintf(w, "EC_DATA_CS_P256_VERIFY,EC_DATA_ID,EC_DATA_INDEX,EC_DATA_LIMB,EC_DATA_IS_DATA,EC_DATA_IS_RES\n")
+
+	for i := range tab[0] {
+		fmt.Fprintf(w, "%v,%v,%v,0x%v,%v,%v\n",
+			tab[0][i].String(), tab[1][i].String(), tab[2][i].String(),
+			tab[3][i].Text(16), tab[4][i].String(), tab[5][i].String(),
+		)
+	}
+}
+
+func dumpAntichamberAsCsv(w io.Writer, tab [][]*big.Int) {
+
+	fmt.Fprintf(w, "P256_VERIFY_ANTICHAMBER_IS_ACTIVE,P256_VERIFY_ANTICHAMBER_ID,P256_VERIFY_ANTICHAMBER_INDEX,P256_VERIFY_ANTICHAMBER_LIMB,P256_VERIFY_ANTICHAMBER_IS_DATA,P256_VERIFY_ANTICHAMBER_IS_RES\n")
+
+	for i := range tab[0] {
+		if tab[0][i].Sign() == 0 {
+			continue
+		}
+		fmt.Fprintf(w, "1,%v,%v,0x%v,%v,%v\n",
+			tab[1][i].String(), tab[2][i].String(),
+			tab[3][i].Text(16), tab[4][i].String(), tab[5][i].String(),
+		)
+	}
+}
+
+// pushFillerToInput pushes rows corresponding to other precompiles of the
+// EC_DATA module.
+func pushFillerToInput(tab [][]*big.Int, rng *rand.Rand, numRow int, id *int64) {
+
+	maxValue := new(big.Int).Lsh(big.NewInt(1), 128)
+
+	for i := 0; i < numRow; i++ {
+		tab[0] = append(tab[0], &big.Int{})
+		tab[1] = append(tab[1], big.NewInt(*id))
+		tab[2] = append(tab[2], big.NewInt(int64(i)))
+		tab[3] = append(tab[3], new(big.Int).Rand(rng, maxValue))
+		tab[4] = append(tab[4], &big.Int{})
+		tab[5] = append(tab[5], &big.Int{})
+	}
+
+	*id++
+}
+
+func pushP256VerifyToInput(tab [][]*big.Int, inst p256VerifyInstance, id *int64) {
+
+	var (
+		mask128 = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 128), big.NewInt(1))
+		result  = &big.Int{}
+		rows    = []*big.Int{}
+	)
+
+	if inst.isValid {
+		result = big.NewInt(1)
+	}
+
+	for _, x := range []*big.Int{inst.h, inst.r, inst.s, inst.qx, inst.qy, result} {
+		rows = append(rows, new(big.Int).Rsh(x, 128), new(big.Int).And(x, mask128))
+	}
+
+	for i := range rows {
+		isData := int64(0)
+		if i < 10 {
+			isData = 1
+		}
+		tab[0] = append(tab[0], big.NewInt(1))
+		tab[1] = append(tab[1], big.NewInt(*id))
+		tab[2] = append(tab[2], big.NewInt(int64(i%10)))
+		tab[3] = append(tab[3], rows[i])
+		tab[4] = append(tab[4], big.NewInt(isData))
+		tab[5] = append(tab[5], big.NewInt(1-isData))
+	}
+
+	*id++
+}
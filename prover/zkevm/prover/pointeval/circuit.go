@@ -0,0 +1,499 @@
+package pointeval
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/algopts"
+	"github.com/consensys/gnark/std/algebra/emulated/sw_bls12381"
+	"github.com/consensys/gnark/std/algebra/emulated/sw_emulated"
+	"github.com/consensys/gnark/std/hash/sha2"
+	"github.com/consensys/gnark/std/math/bitslice"
+	"github.com/consensys/gnark/std/math/emulated"
+	"github.com/consensys/gnark/std/math/uints"
+	"github.com/consensys/linea-monorepo/prover/utils"
+)
+
+const (
+	// fieldElementsPerBlob is the first value returned by the precompile
+	fieldElementsPerBlob = 4096
+	// versionedHashVersionKzg is the first byte of the versioned hash
+	versionedHashVersionKzg = 0x01
+)
+
+// g1EndomorphismW is the cube root of unity defining the endomorphism ϕ of
+// G1, as used by gnark for the subgroup check.
+const g1EndomorphismW = "4002409555221667392624310435006688643935503118305586438271171395842971157480381377015405980053539358417135540939436"
+
+// seedSquare is the square of the seed x = -0xd201000000010000 of BLS12-381
+var seedSquare = func() *big.Int {
+	x := new(big.Int).SetUint64(0xd201000000010000)
+	return x.Mul(x, x)
+}()
+
+// kzgSetupG2Tau is the degree-1 G2 element of the Ethereum KZG ceremony,
+// denoted `KZG_SETUP_G2[1]` in the EIP-4844 specification.
+const kzgSetupG2Tau = "b5bfd7dd8cdeb128843bc287230af38926187075cbfbefa81009a2ce615ac53d2914e5870cb452d2afaaab24f3499f72185cbfee53492714734429b7b38608e23926c911cceceac9a36851477ba4c60b087041de621000edc98edada20c1def2"
+
+// MultiPointEvalCircuit is a circuit that can handle multiple point evaluation
+// instances. The length of the slice Instances should correspond to the one
+// defined in the Limits struct.
+type MultiPointEvalCircuit struct {
+	Instances []PointEvalInstance
+}
+
+// MultiPointEvalFailureCircuit is a circuit proving that the point evaluation
+// calls of its instances fail. Its instances are the failing calls of the
+// EC_DATA module which do not have result rows.
+type MultiPointEvalFailureCircuit struct {
+	Instances []PointEvalInputs
+}
+
+// PointEvalInputs stores the inputs of a single point evaluation call as they
+// are laid out in the EC_DATA module. All the values are split in limbs of 128
+// bits in big-endian order. The commitment and the proof are in the
+// compressed serialization of the BLS12-381 G1 points.
+type PointEvalInputs struct {
+	VersionedHash [2]frontend.Variable `gnark:",public"`
+	Z             [2]frontend.Variable `gnark:",public"`
+	Y             [2]frontend.Variable `gnark:",public"`
+	Commitment    [3]frontend.Variable `gnark:",public"`
+	Proof         [3]frontend.Variable `gnark:",public"`
+}
+
+// PointEvalInstance stores the public inputs of a single successful point
+// evaluation call as they are laid out in the EC_DATA module.
+type PointEvalInstance struct {
+	PointEvalInputs
+
+	// The result of the precompile which is constant for successful calls
+	FieldElementsPerBlob [2]frontend.Variable `gnark:",public"`
+	BlsModulus           [2]frontend.Variable `gnark:",public"`
+}
+
+// NewPointEvalCircuit creates a new circuit for verifying the point evaluation
+// precompile based on the defined number of inputs.
+func NewPointEvalCircuit(limits *Limits) *MultiPointEvalCircuit {
+	return &MultiPointEvalCircuit{
+		Instances: make([]PointEvalInstance, limits.NbInputInstances),
+	}
+}
+
+// NewPointEvalFailureCircuit creates a new circuit for verifying the failing
+// calls to the point evaluation precompile based on the defined number of
+// inputs.
+func NewPointEvalFailureCircuit(limits *Limits) *MultiPointEvalFailureCircuit {
+	return &MultiPointEvalFailureCircuit{
+		Instances: make([]PointEvalInputs, limits.NbInputInstances),
+	}
+}
+
+func (c *MultiPointEvalCircuit) Define(api frontend.API) error {
+
+	v, err := newPointEvalVerifier(api)
+	if err != nil {
+		return err
+	}
+
+	for i := range c.Instances {
+		c.Instances[i].checkResult(api)
+		isValid, err := v.isValid(&c.Instances[i].PointEvalInputs)
+		if err != nil {
+			return err
+		}
+		api.AssertIsEqual(isValid, 1)
+	}
+	return nil
+}
+
+func (c *MultiPointEvalFailureCircuit) Define(api frontend.API) error {
+
+	v, err := newPointEvalVerifier(api)
+	if err != nil {
+		return err
+	}
+
+	for i := range c.Instances {
+		isValid, err := v.isValid(&c.Instances[i])
+		if err != nil {
+			return err
+		}
+		api.AssertIsEqual(isValid, 0)
+	}
+	return nil
+}
+
+// pointEvalVerifier computes whether the inputs of a point evaluation call
+// are valid. All the checks are computed as flags rather than assertions so
+// that the same code proves the successful and the failing calls.
+type pointEvalVerifier struct {
+	api     frontend.API
+	fp      *emulated.Field[sw_bls12381.BaseField]
+	fr      *emulated.Field[sw_bls12381.ScalarField]
+	curve   *sw_emulated.Curve[sw_bls12381.BaseField, sw_bls12381.ScalarField]
+	pairing *sw_bls12381.Pairing
+	uapi    *uints.BinaryField[uints.U64]
+	g2, tau sw_bls12381.G2Affine
+}
+
+func newPointEvalVerifier(api frontend.API) (*pointEvalVerifier, error) {
+
+	fp, err := emulated.NewField[sw_bls12381.BaseField](api)
+	if err != nil {
+		return nil, fmt.Errorf("field emulation: %w", err)
+	}
+	fr, err := emulated.NewField[sw_bls12381.ScalarField](api)
+	if err != nil {
+		return nil, fmt.Errorf("field emulation: %w", err)
+	}
+	curve, err := sw_emulated.New[sw_bls12381.BaseField, sw_bls12381.ScalarField](api, sw_emulated.GetBLS12381Params())
+	if err != nil {
+		return nil, fmt.Errorf("new curve: %w", err)
+	}
+	pairing, err := sw_bls12381.NewPairing(api)
+	if err != nil {
+		return nil, fmt.Errorf("new pairing: %w", err)
+	}
+	uapi, err := uints.New[uints.U64](api)
+	if err != nil {
+		return nil, fmt.Errorf("new uints api: %w", err)
+	}
+
+	_, _, _, g2Gen := bls12381.Generators()
+
+	return &pointEvalVerifier{
+		api:     api,
+		fp:      fp,
+		fr:      fr,
+		curve:   curve,
+		pairing: pairing,
+		uapi:    uapi,
+		g2:      sw_bls12381.NewG2AffineFixed(g2Gen),
+		tau:     sw_bls12381.NewG2AffineFixed(kzgTauG2()),
+	}, nil
+}
+
+// isValid returns 1 if the call succeeds and 0 otherwise. The call succeeds
+// if the versioned hash matches the commitment, z and y are canonical scalars,
+// the commitment and the proof are valid encodings of points of G1 and the
+// opening proof is valid.
+func (v *pointEvalVerifier) isValid(inp *PointEvalInputs) (frontend.Variable, error) {
+
+	var (
+		api                        = v.api
+		isHashOk                   = v.isVersionedHashOk(inp)
+		z, isZOk                   = v.limbsToScalar(inp.Z)
+		y, isYOk                   = v.limbsToScalar(inp.Y)
+		commitment, isCommitmentOk = v.decompressG1(inp.Commitment)
+		proof, isProofOk           = v.decompressG1(inp.Proof)
+		isInputOk                  = api.Mul(isHashOk, isZOk, isYOk, isCommitmentOk, isProofOk)
+		infinity                   = &sw_bls12381.G1Affine{X: *v.fp.Zero(), Y: *v.fp.Zero()}
+	)
+
+	// If the inputs are invalid, the opening proof is checked on the input
+	// filler instead so that it is well-defined. Its result is then ignored.
+	z = v.fr.Select(isInputOk, z, v.fr.Zero())
+	y = v.fr.Select(isInputOk, y, v.fr.Zero())
+	commitment = v.curve.Select(isInputOk, commitment, infinity)
+	proof = v.curve.Select(isInputOk, proof, infinity)
+
+	// The opening proof is valid if
+	//
+	// 	e(C - [y]G1, G2) == e(proof, [tau]G2 - [z]G2)
+	//
+	// which we rewrite as e(C - [y]G1 + [z]proof, G2) * e(-proof, [tau]G2) == 1
+	// so that the G2 arguments are constants and the lines of the Miller
+	// loop can be precomputed.
+	var (
+		lhs = v.curve.AddUnified(
+			commitment,
+			v.curve.JointScalarMulBase(proof, z, v.fr.Neg(y), algopts.WithCompleteArithmetic()),
+		)
+		isInfProof = isInfinity(api, v.fp, proof)
+		isInfLhs   = isInfinity(api, v.fp, lhs)
+	)
+
+	// The pairing is not defined for the point at infinity. If the proof
+	// is the point at infinity, then the check reduces to lhs == 0.
+	// Otherwise, lhs cannot be the point at infinity as the pairing is
+	// non-degenerate. When either is the point at infinity, placeholder
+	// points are used to keep the pairing well-defined and its result is
+	// ignored.
+	var (
+		isInfAny  = api.Or(isInfProof, isInfLhs)
+		lhsSafe   = v.curve.Select(isInfAny, v.curve.Generator(), lhs)
+		proofSafe = v.curve.Select(isInfAny, v.curve.Generator(), proof)
+	)
+
+	res, err := v.pairing.Pair(
+		[]*sw_bls12381.G1Affine{lhsSafe, v.curve.Neg(proofSafe)},
+		[]*sw_bls12381.G2Affine{&v.g2, &v.tau},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("pairing: %w", err)
+	}
+
+	var (
+		isOne       = v.pairing.Ext12.IsEqual(res, v.pairing.Ext12.One())
+		isOpeningOk = api.Select(isInfAny, api.Mul(isInfProof, isInfLhs), isOne)
+	)
+
+	return api.Mul(isInputOk, isOpeningOk), nil
+}
+
+// checkResult ensures the result of the precompile is (FIELD_ELEMENTS_PER_BLOB,
+// BLS_MODULUS).
+func (inst *PointEvalInstance) checkResult(api frontend.API) {
+
+	var (
+		modulus = emulated.BLS12381Fr{}.Modulus()
+		mask    = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 128), big.NewInt(1))
+	)
+
+	api.AssertIsEqual(inst.FieldElementsPerBlob[0], 0)
+	api.AssertIsEqual(inst.FieldElementsPerBlob[1], fieldElementsPerBlob)
+	api.AssertIsEqual(inst.BlsModulus[0], new(big.Int).Rsh(modulus, 128))
+	api.AssertIsEqual(inst.BlsModulus[1], new(big.Int).And(modulus, mask))
+}
+
+// isVersionedHashOk returns 1 if the versioned hash is the sha256 hash of the
+// commitment with the first byte replaced by the KZG version and 0 otherwise.
+func (v *pointEvalVerifier) isVersionedHashOk(inp *PointEvalInputs) frontend.Variable {
+
+	hasher, err := sha2.New(v.api)
+	if err != nil {
+		panic(err)
+	}
+
+	hasher.Write(limbsToBytes(v.api, v.uapi, inp.Commitment[:]))
+
+	var (
+		api           = v.api
+		digest        = hasher.Sum()
+		versionedHash = limbsToBytes(api, v.uapi, inp.VersionedHash[:])
+		res           = api.IsZero(api.Sub(versionedHash[0].Val, versionedHashVersionKzg))
+	)
+
+	for i := 1; i < len(digest); i++ {
+		res = api.Mul(res, api.IsZero(api.Sub(versionedHash[i].Val, digest[i].Val)))
+	}
+
+	return res
+}
+
+// decompressG1 returns the G1 point encoded in the compressed form by the
+// limbs and 1 if the encoding is canonical and the point lies in the prime
+// order subgroup, 0 otherwise. The point at infinity is returned as (0, 0).
+// The returned point is meaningless if the encoding is invalid.
+func (v *pointEvalVerifier) decompressG1(limbs [3]frontend.Variable) (*sw_bls12381.G1Affine, frontend.Variable) {
+
+	var (
+		api = v.api
+		fp  = v.fp
+	)
+
+	// The 3 most significant bits of the encoding are flags. The remaining
+	// 381 bits store the x coordinate.
+	var (
+		xLimbs = make([]frontend.Variable, 6)
+		xBits  = make([]frontend.Variable, 0, 381)
+		xTop   frontend.Variable
+		flags  frontend.Variable
+	)
+
+	xLimbs[0], xLimbs[1] = bitslice.Partition(api, limbs[2], 64, bitslice.WithNbDigits(128))
+	xLimbs[2], xLimbs[3] = bitslice.Partition(api, limbs[1], 64, bitslice.WithNbDigits(128))
+	xLimbs[4], xTop = bitslice.Partition(api, limbs[0], 64, bitslice.WithNbDigits(128))
+	xLimbs[5], flags = bitslice.Partition(api, xTop, 61, bitslice.WithNbDigits(64))
+
+	for i := range xLimbs {
+		nbBits := 64
+		if i == len(xLimbs)-1 {
+			nbBits = 61
+		}
+		xBits = append(xBits, api.ToBinary(xLimbs[i], nbBits)...)
+	}
+
+	var (
+		flagBits   = api.ToBinary(flags, 3)
+		isSorted   = flagBits[0]
+		isInf      = flagBits[1]
+		isCompress = flagBits[2]
+		x          = fp.NewElement(xLimbs)
+		isXZero    = fp.IsZero(x)
+	)
+
+	// The encoding must be compressed, x must be canonical and the point at
+	// infinity is encoded as 0xc0 followed by zeroes.
+	isEncodingOk := api.Mul(
+		isCompress,
+		isLessThan(api, xBits, emulated.BLS12381Fp{}.Modulus()),
+		api.Sub(1, api.Mul(isInf, isSorted)),
+		api.Sub(1, api.Mul(isInf, api.Sub(1, isXZero))),
+	)
+
+	// x is the abscissa of a point of the curve iff x^3 + 4 is a square. The
+	// hint returns a square root of either x^3 + 4 or -(x^3 + 4) and one of
+	// them is a square as -1 is not a square in Fp. Both are only squares if
+	// x^3 + 4 is zero.
+	rhs := fp.Add(fp.Mul(fp.Mul(x, x), x), fp.NewElement(4))
+
+	hint, err := fp.NewHint(sqrtOrNegSqrtHint, 1, rhs)
+	if err != nil {
+		panic(err)
+	}
+
+	var (
+		root      = hint[0]
+		rootSq    = fp.Mul(root, root)
+		isOnCurve = fp.IsZero(fp.Sub(rootSq, rhs))
+	)
+
+	api.AssertIsEqual(api.Mul(api.Sub(1, isOnCurve), api.Sub(1, fp.IsZero(fp.Add(rootSq, rhs)))), 0)
+
+	// y is the square root of x^3 + 4 which is lexicographically largest iff
+	// the sort flag is set.
+	var (
+		isLargest = isLexicographicallyLargest(api, fp, root)
+		y         = fp.Select(api.IsZero(api.Sub(isLargest, isSorted)), root, fp.Neg(root))
+		zero      = fp.Zero()
+		point     = &sw_bls12381.G1Affine{X: *x, Y: *y}
+	)
+
+	// The subgroup check is run on the generator for the point at infinity
+	// and for the abscissas not on the curve, it is then ignored.
+	var (
+		isPoint   = api.Mul(api.Sub(1, isInf), isOnCurve)
+		g1Gen     = sw_bls12381.NewG1Affine(g1Generator())
+		isInG1    = v.isInG1(v.curve.Select(isPoint, point, &g1Gen))
+		isPointOk = api.Add(isInf, api.Mul(isPoint, isInG1))
+	)
+
+	return &sw_bls12381.G1Affine{
+		X: *fp.Select(isInf, zero, x),
+		Y: *fp.Select(isInf, zero, y),
+	}, api.Mul(isEncodingOk, isPointOk)
+}
+
+// isInG1 returns 1 if p, which must be on the curve, lies in the prime order
+// subgroup and 0 otherwise. As in [sw_bls12381.Pairing.AssertIsOnG1], this is
+// the case iff p == -[x²]ϕ(p) where x is the seed of the curve. The complete
+// arithmetic is used so that the check is defined for all the points of the
+// curve.
+func (v *pointEvalVerifier) isInG1(p *sw_bls12381.G1Affine) frontend.Variable {
+
+	var (
+		fp   = v.fp
+		w    = emulated.ValueOf[sw_bls12381.BaseField](g1EndomorphismW)
+		phiP = &sw_bls12381.G1Affine{X: *fp.Mul(&p.X, &w), Y: p.Y}
+		q    = phiP
+	)
+
+	for i := seedSquare.BitLen() - 2; i >= 0; i-- {
+		q = v.curve.AddUnified(q, q)
+		if seedSquare.Bit(i) == 1 {
+			q = v.curve.AddUnified(q, phiP)
+		}
+	}
+
+	q = v.curve.Neg(q)
+
+	return v.api.Mul(
+		fp.IsZero(fp.Sub(&q.X, &p.X)),
+		fp.IsZero(fp.Sub(&q.Y, &p.Y)),
+	)
+}
+
+// isLexicographicallyLargest returns 1 if y > (p-1)/2 and 0 otherwise.
+func isLexicographicallyLargest(api frontend.API, fp *emulated.Field[sw_bls12381.BaseField], y *emulated.Element[sw_bls12381.BaseField]) frontend.Variable {
+	var (
+		bits  = fp.ToBitsCanonical(y)
+		halfP = new(big.Int).Rsh(emulated.BLS12381Fp{}.Modulus(), 1)
+	)
+	isGreater, _ := compareToConstant(api, bits, halfP)
+	return isGreater
+}
+
+// isLessThan returns 1 if the integer given by its bits in little-endian order
+// is lower than bound and 0 otherwise.
+func isLessThan(api frontend.API, bits []frontend.Variable, bound *big.Int) frontend.Variable {
+	isGreater, isEqual := compareToConstant(api, bits, bound)
+	return api.Sub(1, api.Add(isGreater, isEqual))
+}
+
+// compareToConstant compares the integer given by its bits in little-endian
+// order with c. It returns whether it is greater than c and whether it is
+// equal to c. c must fit in the number of bits.
+func compareToConstant(api frontend.API, bits []frontend.Variable, c *big.Int) (isGreater, isEqual frontend.Variable) {
+
+	isGreater, isEqual = frontend.Variable(0), frontend.Variable(1)
+
+	for i := len(bits) - 1; i >= 0; i-- {
+		if c.Bit(i) == 0 {
+			isGreater = api.Add(isGreater, api.Mul(isEqual, bits[i]))
+			isEqual = api.Mul(isEqual, api.Sub(1, bits[i]))
+		} else {
+			isEqual = api.Mul(isEqual, bits[i])
+		}
+	}
+
+	return isGreater, isEqual
+}
+
+// isInfinity returns 1 if p is (0, 0) and 0 otherwise
+func isInfinity(api frontend.API, fp *emulated.Field[sw_bls12381.BaseField], p *sw_bls12381.G1Affine) frontend.Variable {
+	return api.And(fp.IsZero(&p.X), fp.IsZero(&p.Y))
+}
+
+// limbsToScalar converts a 256 bits value given as two 128 bits limbs into an
+// element of the scalar field. It also returns 1 if the value is canonical and
+// 0 otherwise.
+func (v *pointEvalVerifier) limbsToScalar(limbs [2]frontend.Variable) (*emulated.Element[sw_bls12381.ScalarField], frontend.Variable) {
+
+	var (
+		api     = v.api
+		frLimbs = make([]frontend.Variable, 4)
+		bits    = make([]frontend.Variable, 0, 256)
+	)
+
+	frLimbs[2], frLimbs[3] = bitslice.Partition(api, limbs[0], 64, bitslice.WithNbDigits(128))
+	frLimbs[0], frLimbs[1] = bitslice.Partition(api, limbs[1], 64, bitslice.WithNbDigits(128))
+
+	for i := range frLimbs {
+		bits = append(bits, api.ToBinary(frLimbs[i], 64)...)
+	}
+
+	return v.fr.NewElement(frLimbs), isLessThan(api, bits, emulated.BLS12381Fr{}.Modulus())
+}
+
+// limbsToBytes converts a sequence of 16 bytes limbs into the big-endian
+// sequence of bytes they represent.
+func limbsToBytes(api frontend.API, uapi *uints.BinaryField[uints.U64], limbs []frontend.Variable) []uints.U8 {
+	res := make([]uints.U8, 0, 16*len(limbs))
+	for i := range limbs {
+		lo, hi := bitslice.Partition(api, limbs[i], 64, bitslice.WithNbDigits(128))
+		res = append(res, uapi.UnpackMSB(uapi.ValueOf(hi))...)
+		res = append(res, uapi.UnpackMSB(uapi.ValueOf(lo))...)
+	}
+	return res
+}
+
+func g1Generator() bls12381.G1Affine {
+	_, _, g1Gen, _ := bls12381.Generators()
+	return g1Gen
+}
+
+func kzgTauG2() bls12381.G2Affine {
+	b, err := hex.DecodeString(kzgSetupG2Tau)
+	if err != nil {
+		utils.Panic("could not decode the KZG setup: %v", err)
+	}
+	var res bls12381.G2Affine
+	if _, err := res.SetBytes(b); err != nil {
+		utils.Panic("could not deserialize the KZG setup: %v", err)
+	}
+	return res
+}
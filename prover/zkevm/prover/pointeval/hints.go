@@ -0,0 +1,32 @@
+package pointeval
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/consensys/gnark/constraint/solver"
+	"github.com/consensys/gnark/std/math/emulated"
+)
+
+func init() {
+	solver.RegisterHint(sqrtOrNegSqrtHint)
+}
+
+// sqrtOrNegSqrtHint returns a square root of the input if it is a square in
+// Fp and a square root of its opposite otherwise. The result is not trusted:
+// the caller has to check which of the two it squares to.
+func sqrtOrNegSqrtHint(mod *big.Int, inputs, outputs []*big.Int) error {
+	return emulated.UnwrapHint(inputs, outputs, func(field *big.Int, inputs, outputs []*big.Int) error {
+		if len(inputs) != 1 || len(outputs) != 1 {
+			return errors.New("expecting one input and one output")
+		}
+		x := new(big.Int).Mod(inputs[0], field)
+		if big.Jacobi(x, field) < 0 {
+			x.Neg(x).Mod(x, field)
+		}
+		if outputs[0].ModSqrt(x, field) == nil {
+			return errors.New("no square root")
+		}
+		return nil
+	})
+}
@@ -0,0 +1,250 @@
+// Package pointeval provides the integration of the EIP-4844 point
+// evaluation precompile (0x0a) calls. The calls are fetched from the EC_DATA
+// module and verified in a gnark circuit aligned via PLONK-in-Wizard.
+//
+// The successful calls are forwarded to a circuit proving that the inputs are
+// valid and that the opening proof holds. The failing calls are forwarded to
+// a second circuit proving that at least one of these checks does not hold:
+// a versioned hash not matching the commitment, a non-canonical evaluation
+// point or claimed value, a commitment or a proof that is not the encoding of
+// a point of G1, or an invalid opening proof. The module ensures that every
+// call is sent to one of the two circuits and that the success bit of the
+// call matches the circuit it is sent to.
+package pointeval
+
+import (
+	"github.com/consensys/linea-monorepo/prover/maths/field"
+	"github.com/consensys/linea-monorepo/prover/protocol/dedicated/plonk"
+	"github.com/consensys/linea-monorepo/prover/protocol/ifaces"
+	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
+	sym "github.com/consensys/linea-monorepo/prover/symbolic"
+	"github.com/consensys/linea-monorepo/prover/utils"
+	"github.com/consensys/linea-monorepo/prover/zkevm/arithmetization"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	NAME_POINT_EVAL = "POINT_EVAL_INTEGRATION"
+	ROUND_NR        = 0
+)
+
+const (
+	// versioned hash, z and y on 2 limbs each and the commitment and the
+	// proof on 3 limbs each.
+	nbRowsPerPointEvalData = 12
+	// FIELD_ELEMENTS_PER_BLOB and BLS_MODULUS on 2 limbs each
+	nbRowsPerPointEvalRes = 4
+	nbRowsPerPointEval    = nbRowsPerPointEvalData + nbRowsPerPointEvalRes
+)
+
+// Limits defines the upper limits on the size of the circuit and the number of
+// gnark circuits. The total number of allowed point evaluation precompile
+// calls is the product of the fields.
+type Limits struct {
+	// how many point evaluations can we do in a single circuit
+	NbInputInstances int
+	// how many circuit instances can we have
+	NbCircuitInstances int
+	// how many circuit instances proving the failing calls can we have. Each
+	// of them takes NbInputInstances calls.
+	NbFailureCircuitInstances int
+}
+
+func (l *Limits) sizePointEvalIntegration() int {
+	return utils.NextPowerOfTwo(l.NbInputInstances*nbRowsPerPointEval) * utils.NextPowerOfTwo(l.NbCircuitInstances)
+}
+
+// PointEval integrates the verification of the point evaluation precompile
+// calls inside a gnark circuit.
+type PointEval struct {
+	*EcDataPointEvalSource
+	AlignedGnarkData        *plonk.Alignment
+	AlignedFailureGnarkData *plonk.Alignment
+
+	size int
+	*Limits
+}
+
+// EcDataPointEvalSource is a struct that holds the columns that are used to
+// fetch data from the EC_DATA module from the arithmetization.
+type EcDataPointEvalSource struct {
+	CsPointEval        ifaces.Column
+	CsPointEvalFailure ifaces.Column
+	SuccessBit         ifaces.Column
+	Limb               ifaces.Column
+	Index              ifaces.Column
+	IsData             ifaces.Column
+	IsRes              ifaces.Column
+}
+
+// NewPointEvalZkEvm returns the module proving the calls to the point
+// evaluation precompile or nil if the EC_DATA module has no point evaluation
+// columns.
+func NewPointEvalZkEvm(comp *wizard.CompiledIOP, limits *Limits) *PointEval {
+	if !comp.Columns.Exists("ecdata.CIRCUIT_SELECTOR_POINT_EVALUATION") {
+		return nil
+	}
+	return newPointEval(
+		comp,
+		limits,
+		&EcDataPointEvalSource{
+			CsPointEval:        comp.Columns.GetHandle("ecdata.CIRCUIT_SELECTOR_POINT_EVALUATION"),
+			CsPointEvalFailure: comp.Columns.GetHandle("ecdata.CIRCUIT_SELECTOR_POINT_EVALUATION_FAILURE"),
+			SuccessBit:         comp.Columns.GetHandle("ecdata.SUCCESS_BIT"),
+			Limb:               comp.Columns.GetHandle("ecdata.LIMB"),
+			Index:              comp.Columns.GetHandle("ecdata.INDEX"),
+			IsData:             comp.Columns.GetHandle("ecdata.IS_POINT_EVALUATION_DATA"),
+			IsRes:              comp.Columns.GetHandle("ecdata.IS_POINT_EVALUATION_RESULT"),
+		},
+		[]plonk.Option{plonk.WithRangecheck(16, 6, false)},
+	)
+}
+
+// newPointEval creates a new point evaluation integration.
+func newPointEval(comp *wizard.CompiledIOP, limits *Limits, src *EcDataPointEvalSource, plonkOptions []plonk.Option) *PointEval {
+	size := limits.sizePointEvalIntegration()
+
+	toAlign := &plonk.CircuitAlignmentInput{
+		Name:               NAME_POINT_EVAL + "_ALIGNMENT",
+		Round:              ROUND_NR,
+		DataToCircuitMask:  src.CsPointEval,
+		DataToCircuit:      src.Limb,
+		Circuit:            NewPointEvalCircuit(limits),
+		NbCircuitInstances: limits.NbCircuitInstances,
+		PlonkOptions:       plonkOptions,
+		InputFiller:        inputFiller,
+	}
+	// The failing calls have no result rows. The padding instances are all
+	// zeroes, which is a failing call as the version of the versioned hash is
+	// wrong.
+	toAlignFailure := &plonk.CircuitAlignmentInput{
+		Name:               NAME_POINT_EVAL + "_FAILURE_ALIGNMENT",
+		Round:              ROUND_NR,
+		DataToCircuitMask:  src.CsPointEvalFailure,
+		DataToCircuit:      src.Limb,
+		Circuit:            NewPointEvalFailureCircuit(limits),
+		NbCircuitInstances: limits.NbFailureCircuitInstances,
+		PlonkOptions:       plonkOptions,
+	}
+	res := &PointEval{
+		EcDataPointEvalSource:   src,
+		AlignedGnarkData:        plonk.DefineAlignment(comp, toAlign),
+		AlignedFailureGnarkData: plonk.DefineAlignment(comp, toAlignFailure),
+		size:                    size,
+		Limits:                  limits,
+	}
+
+	res.csConstraints(comp)
+
+	return res
+}
+
+// csConstraints ensures that the data rows of every call are sent to either
+// of the circuits and that the success bit of the call matches the circuit.
+// The selectors are binary by construction of the EC_DATA module.
+func (pe *PointEval) csConstraints(comp *wizard.CompiledIOP) {
+
+	comp.InsertGlobal(
+		ROUND_NR,
+		ifaces.QueryIDf("%v_DATA_IS_PROVEN", NAME_POINT_EVAL),
+		sym.Mul(
+			pe.IsData,
+			sym.Sub(1, pe.CsPointEval, pe.CsPointEvalFailure),
+		),
+	)
+
+	comp.InsertGlobal(
+		ROUND_NR,
+		ifaces.QueryIDf("%v_FAILURE_ONLY_ON_DATA", NAME_POINT_EVAL),
+		sym.Mul(pe.CsPointEvalFailure, sym.Sub(1, pe.IsData)),
+	)
+
+	comp.InsertGlobal(
+		ROUND_NR,
+		ifaces.QueryIDf("%v_SUCCESS_BIT_MATCHES_CIRCUIT", NAME_POINT_EVAL),
+		sym.Mul(
+			pe.IsData,
+			sym.Sub(pe.SuccessBit, pe.CsPointEval),
+		),
+	)
+}
+
+// Assign assigns the data from the trace to the gnark inputs. The number of
+// successful and failing calls is checked against the limits first, as the
+// alignment cannot hold more calls than the circuits.
+func (pe *PointEval) Assign(run *wizard.ProverRuntime) {
+
+	var (
+		srcCs        = pe.CsPointEval.GetColAssignment(run).IntoRegVecSaveAlloc()
+		srcCsFailure = pe.CsPointEvalFailure.GetColAssignment(run).IntoRegVecSaveAlloc()
+		srcIndex     = pe.Index.GetColAssignment(run).IntoRegVecSaveAlloc()
+		srcIsData    = pe.IsData.GetColAssignment(run).IntoRegVecSaveAlloc()
+		nbSuccess    = 0
+		nbFailure    = 0
+		maxSuccess   = pe.NbInputInstances * pe.NbCircuitInstances
+		maxFailure   = pe.NbInputInstances * pe.NbFailureCircuitInstances
+	)
+
+	// A call starts on its first data row
+	for i := range srcIsData {
+		if !srcIsData[i].IsOne() || !srcIndex[i].IsZero() {
+			continue
+		}
+		if srcCs[i].IsOne() {
+			nbSuccess++
+		}
+		if srcCsFailure[i].IsOne() {
+			nbFailure++
+		}
+	}
+
+	if nbSuccess > maxSuccess {
+		logrus.Errorf("limit overflow: the point evaluation count is %v and the limit is %v\n", nbSuccess, maxSuccess)
+		arithmetization.PanicOverflow("point_evaluation", nbSuccess, maxSuccess)
+	}
+
+	if nbFailure > maxFailure {
+		logrus.Errorf("limit overflow: the failing point evaluation count is %v and the limit is %v\n", nbFailure, maxFailure)
+		arithmetization.PanicOverflow("point_evaluation_failure", nbFailure, maxFailure)
+	}
+
+	pe.AlignedGnarkData.Assign(run)
+	pe.AlignedFailureGnarkData.Assign(run)
+}
+
+// inputFiller returns the inputs of a valid point evaluation to use for the
+// unused instances of the circuit. It corresponds to the opening of the
+// commitment to the zero polynomial at zero: the commitment and the proof are
+// both the point at infinity.
+func inputFiller(_, inputIndex int) field.Element {
+	tbl := []string{
+		// versioned hash
+		"0x010657f37554c781402a22917dee2f75",
+		"0xdef7ab966d7b770905398eba3c444014",
+		// z
+		"0x00000000000000000000000000000000",
+		"0x00000000000000000000000000000000",
+		// y
+		"0x00000000000000000000000000000000",
+		"0x00000000000000000000000000000000",
+		// commitment
+		"0xc0000000000000000000000000000000",
+		"0x00000000000000000000000000000000",
+		"0x00000000000000000000000000000000",
+		// proof
+		"0xc0000000000000000000000000000000",
+		"0x00000000000000000000000000000000",
+		"0x00000000000000000000000000000000",
+		// FIELD_ELEMENTS_PER_BLOB
+		"0x00000000000000000000000000000000",
+		"0x00000000000000000000000000001000",
+		// BLS_MODULUS
+		"0x73eda753299d7d483339d80809a1d805",
+		"0x53bda402fffe5bfeffffffff00000001",
+	}
+	var res field.Element
+	if _, err := res.SetString(tbl[inputIndex%nbRowsPerPointEval]); err != nil {
+		utils.Panic("could not parse the input filler: %v", err)
+	}
+	return res
+}
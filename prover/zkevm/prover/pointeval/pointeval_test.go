@@ -0,0 +1,318 @@
+//go:build !fuzzlight
+
+package pointeval
+
+import (
+	"crypto/sha256"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fp"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/emulated"
+	"github.com/consensys/gnark/test"
+	"github.com/consensys/linea-monorepo/prover/protocol/compiler/dummy"
+	"github.com/consensys/linea-monorepo/prover/protocol/dedicated/plonk"
+	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
+	"github.com/consensys/linea-monorepo/prover/utils/csvtraces"
+	"github.com/ethereum/go-ethereum/crypto/kzg4844"
+)
+
+func TestPointEvalCircuit(t *testing.T) {
+
+	var (
+		blob, constBlob kzg4844.Blob
+		z               kzg4844.Point
+	)
+
+	for i := 0; i < len(blob); i += 32 {
+		blob[i+5] = byte(i)
+		blob[i+31] = byte(7 * i)
+		// A constant polynomial has an opening proof at infinity
+		constBlob[i+31] = 3
+	}
+	z[3], z[31] = 77, 9
+
+	testCases := []struct {
+		name    string
+		inst    PointEvalInstance
+		success bool
+	}{
+		{
+			name:    "valid",
+			inst:    newPointEvalAssignment(t, &blob, z),
+			success: true,
+		},
+		{
+			name:    "valid-proof-at-infinity",
+			inst:    newPointEvalAssignment(t, &constBlob, z),
+			success: true,
+		},
+		{
+			name:    "input-filler",
+			inst:    inputFillerAssignment(),
+			success: true,
+		},
+		{
+			name: "wrong-evaluation",
+			inst: func() PointEvalInstance {
+				inst := newPointEvalAssignment(t, &blob, z)
+				inst.Y[1] = new(big.Int).Add(inst.Y[1].(*big.Int), big.NewInt(1))
+				return inst
+			}(),
+			success: false,
+		},
+		{
+			name: "wrong-evaluation-proof-at-infinity",
+			inst: func() PointEvalInstance {
+				inst := newPointEvalAssignment(t, &constBlob, z)
+				inst.Y[1] = big.NewInt(4)
+				return inst
+			}(),
+			success: false,
+		},
+		{
+			name: "wrong-versioned-hash",
+			inst: func() PointEvalInstance {
+				inst := newPointEvalAssignment(t, &blob, z)
+				inst.VersionedHash[1] = big.NewInt(4)
+				return inst
+			}(),
+			success: false,
+		},
+		{
+			name: "non-canonical-z",
+			inst: func() PointEvalInstance {
+				inst := newPointEvalAssignment(t, &blob, z)
+				copy(inst.Z[:], bytesToLimbs(blsModulus.FillBytes(make([]byte, 32))))
+				return inst
+			}(),
+			success: false,
+		},
+		{
+			name: "uncompressed-commitment",
+			inst: func() PointEvalInstance {
+				inst := newPointEvalAssignment(t, &blob, z)
+				commitment := commitmentOf(t, &blob)
+				commitment[0] &^= 0x80
+				setCommitment(&inst, commitment)
+				return inst
+			}(),
+			success: false,
+		},
+		{
+			name: "commitment-not-on-curve",
+			inst: func() PointEvalInstance {
+				inst := newPointEvalAssignment(t, &blob, z)
+				setCommitment(&inst, compressedX(t, false))
+				return inst
+			}(),
+			success: false,
+		},
+		{
+			name: "proof-not-in-subgroup",
+			inst: func() PointEvalInstance {
+				inst := newPointEvalAssignment(t, &blob, z)
+				proof := compressedX(t, true)
+				copy(inst.Proof[:], bytesToLimbs(proof[:]))
+				return inst
+			}(),
+			success: false,
+		},
+	}
+
+	limits := &Limits{NbInputInstances: 1}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+
+			assignment := NewPointEvalCircuit(limits)
+			assignment.Instances[0] = tc.inst
+			err := test.IsSolved(NewPointEvalCircuit(limits), assignment, ecc.BLS12_377.ScalarField())
+			if tc.success && err != nil {
+				t.Fatalf("expected the circuit to be solved: %v", err)
+			}
+			if !tc.success && err == nil {
+				t.Fatal("expected the circuit not to be solved")
+			}
+
+			failureAssignment := NewPointEvalFailureCircuit(limits)
+			failureAssignment.Instances[0] = tc.inst.PointEvalInputs
+			err = test.IsSolved(NewPointEvalFailureCircuit(limits), failureAssignment, ecc.BLS12_377.ScalarField())
+			if !tc.success && err != nil {
+				t.Fatalf("expected the failure circuit to be solved: %v", err)
+			}
+			if tc.success && err == nil {
+				t.Fatal("expected the failure circuit not to be solved")
+			}
+		})
+	}
+}
+
+func TestPointEvalIntegration(t *testing.T) {
+	limits := &Limits{
+		NbInputInstances:          1,
+		NbCircuitInstances:        1,
+		NbFailureCircuitInstances: 1,
+	}
+	ct := csvtraces.MustOpenCsvFile("testdata/pointeval_test.csv")
+	var pointEval *PointEval
+	var pointEvalSource *EcDataPointEvalSource
+	cmp := wizard.Compile(
+		func(b *wizard.Builder) {
+			pointEvalSource = &EcDataPointEvalSource{
+				CsPointEval:        ct.GetCommit(b, "CS_POINT_EVAL"),
+				CsPointEvalFailure: ct.GetCommit(b, "CS_POINT_EVAL_FAILURE"),
+				SuccessBit:         ct.GetCommit(b, "SUCCESS_BIT"),
+				Limb:               ct.GetCommit(b, "LIMB"),
+				Index:              ct.GetCommit(b, "INDEX"),
+				IsData:             ct.GetCommit(b, "IS_DATA"),
+				IsRes:              ct.GetCommit(b, "IS_RES"),
+			}
+			pointEval = newPointEval(b.CompiledIOP, limits, pointEvalSource, []plonk.Option{plonk.WithRangecheck(16, 6, false)})
+		},
+		dummy.Compile,
+	)
+
+	proof := wizard.Prove(cmp,
+		func(run *wizard.ProverRuntime) {
+			ct.Assign(run, "CS_POINT_EVAL", "CS_POINT_EVAL_FAILURE", "SUCCESS_BIT", "LIMB", "INDEX", "IS_DATA", "IS_RES")
+			pointEval.Assign(run)
+		})
+
+	if err := wizard.Verify(cmp, proof); err != nil {
+		t.Fatal("proof failed", err)
+	}
+
+	t.Log("proof succeeded")
+}
+
+// commitmentOf returns the commitment to the blob
+func commitmentOf(t *testing.T, blob *kzg4844.Blob) kzg4844.Commitment {
+	commitment, err := kzg4844.BlobToCommitment(blob)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return commitment
+}
+
+// setCommitment sets the commitment of the instance along with the matching
+// versioned hash.
+func setCommitment(inst *PointEvalInstance, commitment kzg4844.Commitment) {
+	versionedHash := kzg4844.CalcBlobHashV1(sha256.New(), &commitment)
+	copy(inst.VersionedHash[:], bytesToLimbs(versionedHash[:]))
+	copy(inst.Commitment[:], bytesToLimbs(commitment[:]))
+}
+
+// compressedX returns the compressed encoding of the smallest x such that
+// x^3 + 4 is a square when onCurve is set, and is not a square otherwise. In
+// the former case, the point is not in G1.
+func compressedX(t *testing.T, onCurve bool) kzg4844.Commitment {
+
+	for i := uint64(1); ; i++ {
+
+		var x, rhs fp.Element
+		x.SetUint64(i)
+		rhs.Square(&x).Mul(&rhs, &x).Add(&rhs, new(fp.Element).SetUint64(4))
+
+		if (rhs.Legendre() >= 0) != onCurve {
+			continue
+		}
+
+		var res kzg4844.Commitment
+		if onCurve {
+			var p bls12381.G1Affine
+			p.X = x
+			p.Y.Sqrt(&rhs)
+			if p.IsInSubGroup() {
+				t.Fatal("unexpected point in the subgroup")
+			}
+			res = p.Bytes()
+		} else {
+			b := x.Bytes()
+			copy(res[:], b[:])
+			res[0] |= 0x80
+		}
+
+		return res
+	}
+}
+
+// newPointEvalAssignment returns the assignment of a successful point
+// evaluation call for the opening of the blob at z.
+func newPointEvalAssignment(t *testing.T, blob *kzg4844.Blob, z kzg4844.Point) PointEvalInstance {
+
+	commitment, err := kzg4844.BlobToCommitment(blob)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	proof, y, err := kzg4844.ComputeProof(blob, z)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		versionedHash = kzg4844.CalcBlobHashV1(sha256.New(), &commitment)
+		res           = inputFillerAssignment()
+	)
+
+	copy(res.VersionedHash[:], bytesToLimbs(versionedHash[:]))
+	copy(res.Z[:], bytesToLimbs(z[:]))
+	copy(res.Y[:], bytesToLimbs(y[:]))
+	copy(res.Commitment[:], bytesToLimbs(commitment[:]))
+	copy(res.Proof[:], bytesToLimbs(proof[:]))
+	return res
+}
+
+// inputFillerAssignment returns the instance used to pad the circuit
+func inputFillerAssignment() PointEvalInstance {
+
+	var (
+		res    PointEvalInstance
+		fields = []frontend.Variable{}
+	)
+
+	for i := 0; i < nbRowsPerPointEval; i++ {
+		fields = append(fields, inputFiller(0, i))
+	}
+
+	copy(res.VersionedHash[:], fields[0:2])
+	copy(res.Z[:], fields[2:4])
+	copy(res.Y[:], fields[4:6])
+	copy(res.Commitment[:], fields[6:9])
+	copy(res.Proof[:], fields[9:12])
+	copy(res.FieldElementsPerBlob[:], fields[12:14])
+	copy(res.BlsModulus[:], fields[14:16])
+	return res
+}
+
+// blsModulus is the modulus of the scalar field of BLS12-381
+var blsModulus = emulated.BLS12381Fr{}.Modulus()
+
+func bytesToLimbs(b []byte) []frontend.Variable {
+	res := []frontend.Variable{}
+	for i := 0; i < len(b); i += 16 {
+		res = append(res, new(big.Int).SetBytes(b[i:i+16]))
+	}
+	return res
+}
+
+func TestNewPointEvalZkEvmUnsupported(t *testing.T) {
+
+	var pe *PointEval
+
+	wizard.Compile(func(b *wizard.Builder) {
+		// EC_DATA without the columns of the precompile
+		b.RegisterCommit("ecdata.LIMB", 16)
+		b.RegisterCommit("ecdata.INDEX", 16)
+		b.RegisterCommit("ecdata.SUCCESS_BIT", 16)
+		pe = NewPointEvalZkEvm(b.CompiledIOP, &Limits{NbInputInstances: 1, NbCircuitInstances: 1, NbFailureCircuitInstances: 1})
+	})
+
+	if pe != nil {
+		t.Fatal("the module is built without the columns of the precompile")
+	}
+}
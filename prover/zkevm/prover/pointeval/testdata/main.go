@@ -0,0 +1,145 @@
+package main
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"math/big"
+	"math/rand"
+
+	"github.com/consensys/linea-monorepo/prover/backend/files"
+	"github.com/ethereum/go-ethereum/crypto/kzg4844"
+)
+
+// blsModulus is the modulus of the scalar field of BLS12-381
+var blsModulus, _ = new(big.Int).SetString("73eda753299d7d483339d80809a1d80553bda402fffe5bfeffffffff00000001", 16)
+
+func main() {
+
+	var (
+		rng = rand.New(rand.NewSource(8754239))
+		tab = make([][]*big.Int, 7)
+	)
+
+	pushFillerToInput(tab, rng, 4)
+	pushPointEvalToInput(tab, rng, randomBlob(rng), true)
+	pushFillerToInput(tab, rng, 3)
+	pushPointEvalToInput(tab, rng, randomBlob(rng), false)
+	pushFillerToInput(tab, rng, 7)
+
+	f := files.MustOverwrite("./pointeval_test.csv")
+	dumpInputAsCsv(f, tab)
+	f.Close()
+}
+
+func randomBlob(rng *rand.Rand) *kzg4844.Blob {
+	var blob kzg4844.Blob
+	for i := 0; i < len(blob); i += 32 {
+		x := randScalar(rng)
+		x.FillBytes(blob[i : i+32])
+	}
+	return &blob
+}
+
+func randScalar(rng *rand.Rand) *big.Int {
+	return new(big.Int).Rand(rng, blsModulus)
+}
+
+func dumpInputAsCsv(w io.Writer, tab [][]*big.Int) {
+
+	fmt.Fprintf(w, "CS_POINT_EVAL,LIMB,INDEX,IS_DATA,IS_RES,CS_POINT_EVAL_FAILURE,SUCCESS_BIT\n")
+
+	for i := range tab[0] {
+		fmt.Fprintf(w, "%v,0x%v,%v,%v,%v,%v,%v\n",
+			tab[0][i].String(), tab[1][i].Text(16), tab[2][i].String(),
+			tab[3][i].String(), tab[4][i].String(), tab[5][i].String(),
+			tab[6][i].String(),
+		)
+	}
+}
+
+// pushFillerToInput pushes rows corresponding to other precompiles of the
+// EC_DATA module.
+func pushFillerToInput(tab [][]*big.Int, rng *rand.Rand, numRow int) {
+
+	maxValue := new(big.Int).Lsh(big.NewInt(1), 128)
+
+	for i := 0; i < numRow; i++ {
+		tab[0] = append(tab[0], &big.Int{})
+		tab[1] = append(tab[1], new(big.Int).Rand(rng, maxValue))
+		tab[2] = append(tab[2], big.NewInt(int64(i)))
+		tab[3] = append(tab[3], &big.Int{})
+		tab[4] = append(tab[4], &big.Int{})
+		tab[5] = append(tab[5], &big.Int{})
+		tab[6] = append(tab[6], big.NewInt(int64(rng.Intn(2))))
+	}
+}
+
+// pushPointEvalToInput pushes the rows of a point evaluation call. A failing
+// call is obtained by altering the claimed value. It has no result rows.
+func pushPointEvalToInput(tab [][]*big.Int, rng *rand.Rand, blob *kzg4844.Blob, success bool) {
+
+	var z kzg4844.Point
+	randScalar(rng).FillBytes(z[:])
+
+	commitment, err := kzg4844.BlobToCommitment(blob)
+	if err != nil {
+		panic(err)
+	}
+
+	proof, y, err := kzg4844.ComputeProof(blob, z)
+	if err != nil {
+		panic(err)
+	}
+
+	var (
+		versionedHash = kzg4844.CalcBlobHashV1(sha256.New(), &commitment)
+		result        = [64]byte{}
+		data          = []byte{}
+	)
+
+	big.NewInt(4096).FillBytes(result[:32])
+	blsModulus.FillBytes(result[32:])
+
+	if !success {
+		y[31] ^= 1
+	}
+
+	var (
+		cs, csFailure, successBit = big.NewInt(1), &big.Int{}, big.NewInt(1)
+	)
+
+	if !success {
+		cs, csFailure, successBit = &big.Int{}, big.NewInt(1), &big.Int{}
+	}
+
+	data = append(data, versionedHash[:]...)
+	data = append(data, z[:]...)
+	data = append(data, y[:]...)
+	data = append(data, commitment[:]...)
+	data = append(data, proof[:]...)
+
+	for i := 0; i < len(data); i += 16 {
+		tab[0] = append(tab[0], cs)
+		tab[1] = append(tab[1], new(big.Int).SetBytes(data[i:i+16]))
+		tab[2] = append(tab[2], big.NewInt(int64(i/16)))
+		tab[3] = append(tab[3], big.NewInt(1))
+		tab[4] = append(tab[4], &big.Int{})
+		tab[5] = append(tab[5], csFailure)
+		tab[6] = append(tab[6], successBit)
+	}
+
+	if !success {
+		return
+	}
+
+	for i := 0; i < len(result); i += 16 {
+		tab[0] = append(tab[0], big.NewInt(1))
+		tab[1] = append(tab[1], new(big.Int).SetBytes(result[i:i+16]))
+		tab[2] = append(tab[2], big.NewInt(int64(i/16)))
+		tab[3] = append(tab[3], &big.Int{})
+		tab[4] = append(tab[4], big.NewInt(1))
+		tab[5] = append(tab[5], &big.Int{})
+		tab[6] = append(tab[6], big.NewInt(1))
+	}
+}
@@ -157,6 +157,33 @@ func newAssignmentBuilder(s Settings) *assignmentBuilder {
 	return &amb
 }
 
+// NumProofs returns the number of Merkle proofs that the accumulator module
+// verifies to assign the provided traces. It is the value that is compared to
+// [Settings.MaxNumProofs] and it can be computed without running the prover.
+func NumProofs(traces []statemanager.DecodedTrace) int {
+
+	res := 0
+
+	for _, trace := range traces {
+		switch t := trace.Underlying.(type) {
+		case statemanager.UpdateTraceST, statemanager.UpdateTraceWS,
+			statemanager.ReadZeroTraceST, statemanager.ReadZeroTraceWS,
+			statemanager.ReadNonZeroTraceST, statemanager.ReadNonZeroTraceWS:
+			// a row before and a row after the operation
+			res += 2
+		case statemanager.InsertionTraceST, statemanager.InsertionTraceWS,
+			statemanager.DeletionTraceST, statemanager.DeletionTraceWS:
+			// the previous and the next leaves are updated in addition to the
+			// inserted or deleted leaf
+			res += 6
+		default:
+			utils.Panic("Unexpected type : %T", t)
+		}
+	}
+
+	return res
+}
+
 // Assign is a high level function which is used to arithmetize the columns
 // of the Accumulator module from a slice of decoded traces
 func (am *Module) Assign(
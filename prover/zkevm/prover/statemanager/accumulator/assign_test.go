@@ -240,3 +240,38 @@ func assertCorrectMerkleProofsUsingWizard(t *testing.T, builder *assignmentBuild
 
 	require.NoError(t, err)
 }
+
+func TestNumProofs(t *testing.T) {
+
+	var (
+		builder = newAssignmentBuilder(testSetting)
+		acc     = statemanager.NewStorageTrie(statemanager.MIMC_CONFIG, types.EthAddress{})
+		key     = types.FullBytes32FromHex("0x32")
+		traces  = []statemanager.DecodedTrace{
+			{Underlying: acc.ReadZeroAndProve(key)},
+			{Underlying: acc.InsertAndProve(key, types.FullBytes32FromHex("0x12"))},
+			{Underlying: acc.UpdateAndProve(key, types.FullBytes32FromHex("0x20"))},
+			{Underlying: acc.ReadNonZeroAndProve(key)},
+			{Underlying: acc.DeleteAndProve(key)},
+		}
+	)
+
+	for _, trace := range traces {
+		switch tr := trace.Underlying.(type) {
+		case statemanager.ReadZeroTraceST:
+			pushReadZeroRows(builder, tr)
+		case statemanager.InsertionTraceST:
+			pushInsertionRows(builder, tr)
+		case statemanager.UpdateTraceST:
+			pushUpdateRows(builder, tr)
+		case statemanager.ReadNonZeroTraceST:
+			pushReadNonZeroRows(builder, tr)
+		case statemanager.DeletionTraceST:
+			pushDeletionRows(builder, tr)
+		default:
+			t.Fatalf("unexpected trace type %T", tr)
+		}
+	}
+
+	assert.Equal(t, len(builder.leaves), NumProofs(traces))
+}
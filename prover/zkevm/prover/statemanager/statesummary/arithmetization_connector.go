@@ -1,14 +1,13 @@
 package statesummary
 
 import (
-	"sync"
-
 	"github.com/consensys/linea-monorepo/prover/maths/common/smartvectors"
 	"github.com/consensys/linea-monorepo/prover/maths/field"
 	"github.com/consensys/linea-monorepo/prover/protocol/dedicated"
 	"github.com/consensys/linea-monorepo/prover/protocol/ifaces"
 	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
 	sym "github.com/consensys/linea-monorepo/prover/symbolic"
+	"github.com/consensys/linea-monorepo/prover/utils/parallel"
 )
 
 // arithmetizationLink collects columns from the hub that are of interest for
@@ -47,16 +46,11 @@ func (ss *Module) assignArithmetizationLink(run *wizard.ProverRuntime) {
 	// @alex: this should be commonized utility or should be simplified to not
 	// use a closure because the closure is used only once.
 	runConcurrent := func(pas []wizard.ProverAction) {
-		wg := &sync.WaitGroup{}
-		for _, pa := range pas {
-			wg.Add(1)
-			go func(pa wizard.ProverAction) {
-				pa.Run(run)
-				wg.Done()
-			}(pa)
-		}
-
-		wg.Wait()
+		parallel.Execute(len(pas), func(start, stop int) {
+			for i := start; i < stop; i++ {
+				pas[i].Run(run)
+			}
+		}, len(pas))
 	}
 
 	runConcurrent([]wizard.ProverAction{
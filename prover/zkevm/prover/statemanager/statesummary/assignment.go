@@ -2,7 +2,6 @@ package statesummary
 
 import (
 	"io"
-	"sync"
 
 	"github.com/consensys/linea-monorepo/prover/maths/field"
 	"github.com/consensys/linea-monorepo/prover/zkevm/prover/common"
@@ -10,6 +9,7 @@ import (
 	"github.com/consensys/linea-monorepo/prover/backend/execution/statemanager"
 	"github.com/consensys/linea-monorepo/prover/crypto/mimc"
 	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
+	"github.com/consensys/linea-monorepo/prover/utils/parallel"
 	"github.com/consensys/linea-monorepo/prover/utils/types"
 )
 
@@ -301,16 +301,11 @@ func (ss *stateSummaryAssignmentBuilder) finalize(run *wizard.ProverRuntime) {
 	ss.accumulatorStatement.PadAndAssign(run)
 
 	runConcurrent := func(pas []wizard.ProverAction) {
-		wg := &sync.WaitGroup{}
-		for _, pa := range pas {
-			wg.Add(1)
-			go func(pa wizard.ProverAction) {
-				pa.Run(run)
-				wg.Done()
-			}(pa)
-		}
-
-		wg.Wait()
+		parallel.Execute(len(pas), func(start, stop int) {
+			for i := start; i < stop; i++ {
+				pas[i].Run(run)
+			}
+		}, len(pas))
 	}
 
 	runConcurrent([]wizard.ProverAction{
@@ -3,12 +3,17 @@ package zkevm
 import (
 	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
 	"github.com/consensys/linea-monorepo/prover/zkevm/arithmetization"
+	"github.com/consensys/linea-monorepo/prover/zkevm/prover/blake2f"
+	"github.com/consensys/linea-monorepo/prover/zkevm/prover/bls12381"
 	"github.com/consensys/linea-monorepo/prover/zkevm/prover/ecarith"
 	"github.com/consensys/linea-monorepo/prover/zkevm/prover/ecdsa"
 	"github.com/consensys/linea-monorepo/prover/zkevm/prover/ecpair"
 	"github.com/consensys/linea-monorepo/prover/zkevm/prover/hash/keccak"
+	"github.com/consensys/linea-monorepo/prover/zkevm/prover/hash/ripemd"
 	"github.com/consensys/linea-monorepo/prover/zkevm/prover/hash/sha2"
 	"github.com/consensys/linea-monorepo/prover/zkevm/prover/modexp"
+	"github.com/consensys/linea-monorepo/prover/zkevm/prover/p256verify"
+	"github.com/consensys/linea-monorepo/prover/zkevm/prover/pointeval"
 	"github.com/consensys/linea-monorepo/prover/zkevm/prover/publicInput"
 	"github.com/consensys/linea-monorepo/prover/zkevm/prover/statemanager"
 )
@@ -27,6 +32,18 @@ type Settings struct {
 	Ecadd, Ecmul     ecarith.Limits
 	Ecpair           ecpair.Limits
 	Sha2             sha2.Settings
+	Ripemd           ripemd.Settings
+	Blake2f          blake2f.Settings
+	P256Verify       p256verify.Settings
+	PointEval        pointeval.Limits
+	BlsG1Add         bls12381.Limits
+	BlsG2Add         bls12381.Limits
+	BlsG1Msm         bls12381.Limits
+	BlsG2Msm         bls12381.Limits
+	BlsPairing       bls12381.PairingLimits
+	BlsMapFpToG1     bls12381.Limits
+	BlsMapFp2ToG2    bls12381.Limits
+	BlsNonMembership bls12381.NonMembershipLimits
 	PublicInput      publicInput.Settings
 	CompilationSuite compilationSuite
 	Metadata         wizard.VersionMetadata
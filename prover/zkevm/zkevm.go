@@ -1,15 +1,22 @@
 package zkevm
 
 import (
+	"github.com/consensys/go-corset/pkg/trace"
 	"github.com/consensys/linea-monorepo/prover/config"
 	"github.com/consensys/linea-monorepo/prover/protocol/serialization"
 	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
 	"github.com/consensys/linea-monorepo/prover/zkevm/arithmetization"
+	"github.com/consensys/linea-monorepo/prover/zkevm/prover/blake2f"
+	"github.com/consensys/linea-monorepo/prover/zkevm/prover/bls12381"
 	"github.com/consensys/linea-monorepo/prover/zkevm/prover/ecarith"
 	"github.com/consensys/linea-monorepo/prover/zkevm/prover/ecdsa"
+	"github.com/consensys/linea-monorepo/prover/zkevm/prover/ecpair"
 	"github.com/consensys/linea-monorepo/prover/zkevm/prover/hash/keccak"
+	"github.com/consensys/linea-monorepo/prover/zkevm/prover/hash/ripemd"
 	"github.com/consensys/linea-monorepo/prover/zkevm/prover/hash/sha2"
 	"github.com/consensys/linea-monorepo/prover/zkevm/prover/modexp"
+	"github.com/consensys/linea-monorepo/prover/zkevm/prover/p256verify"
+	"github.com/consensys/linea-monorepo/prover/zkevm/prover/pointeval"
 	"github.com/consensys/linea-monorepo/prover/zkevm/prover/publicInput"
 	"github.com/consensys/linea-monorepo/prover/zkevm/prover/statemanager"
 )
@@ -41,10 +48,36 @@ type ZkEvm struct {
 	ecmul *ecarith.EcMul
 	// ecpair is the module responsible for the proving the calls the ecpairing
 	// precompile
-	// ecpair *ecpair.ECPair
+	ecpair *ecpair.ECPair
 	// sha2 is the module responsible for doing the computation of the sha2
 	// precompile.
 	sha2 *sha2.Sha2SingleProvider
+	// ripemd is the module responsible for doing the computation of the
+	// ripemd160 precompile.
+	ripemd *ripemd.RipemdSingleProvider
+	// blake2f is the module responsible for proving the calls to the blake2f
+	// precompile
+	blake2f *blake2f.Module
+	// p256verify is the module responsible for verifying the calls to the
+	// p256verify precompile. It is nil if the arithmetization does not support
+	// the precompile.
+	p256verify *p256verify.P256VerifyZkEvm
+	// pointEval is the module responsible for proving the calls to the point
+	// evaluation precompile. It is nil if the arithmetization does not support
+	// the precompile.
+	pointEval *pointeval.PointEval
+	// the bls* modules are responsible for proving the calls to the BLS12-381
+	// precompiles of EIP-2537. They are nil if the arithmetization does not
+	// support the precompiles.
+	blsG1Add      *bls12381.G1Add
+	blsG2Add      *bls12381.G2Add
+	blsG1Msm      *bls12381.G1Msm
+	blsG2Msm      *bls12381.G2Msm
+	blsPairing    *bls12381.Pairing
+	blsMapFpToG1  *bls12381.MapFpToG1
+	blsMapFp2ToG2 *bls12381.MapFp2ToG2
+	// blsNonMember proves the failing calls to the BLS12-381 precompiles
+	blsNonMember *bls12381.NonMembership
 
 	// Contains the actual wizard-IOP compiled object. This object is called to
 	// generate the inner-proof.
@@ -74,9 +107,39 @@ func NewZkEVM(
 }
 
 // Prove assigns and runs the inner-prover of the zkEVM and then, it returns the
-// inner-proof
-func (z *ZkEvm) ProveInner(input *Witness) wizard.Proof {
-	return wizard.Prove(z.WizardIOP, z.prove(input))
+// inner-proof. The execution traces are checked against the limits before the
+// prover starts: if they overflow, the returned error wraps an
+// [arithmetization.TraceOverflowError] for every overflowing module. The
+// modules whose limits can only be checked during the assignment report
+// their overflow the same way.
+func (z *ZkEvm) ProveInner(input *Witness) (wizard.Proof, error) {
+	return z.ProveInnerWithOptions(input)
+}
+
+// ProveInnerWithOptions is as [ZkEvm.ProveInner] but it passes the options to
+// the inner-prover, see [wizard.ProveWithOptions]. In particular, if the
+// prover is interrupted by [wizard.WithContext], the error of the context is
+// returned.
+func (z *ZkEvm) ProveInnerWithOptions(input *Witness, opts ...wizard.ProveOption) (proof wizard.Proof, err error) {
+
+	expTraces, err := z.arithmetization.ReadTraces(input.ExecTracesFPath)
+	if err != nil {
+		return wizard.Proof{}, err
+	}
+
+	// Recover the overflows detected by the modules during the assignment,
+	// see [arithmetization.PanicOverflow]. Any other panic is forwarded.
+	defer func() {
+		if r := recover(); r != nil {
+			errOverflow, ok := r.(*arithmetization.TraceOverflowError)
+			if !ok {
+				panic(r)
+			}
+			proof, err = wizard.Proof{}, errOverflow
+		}
+	}()
+
+	return wizard.ProveWithOptions(z.WizardIOP, z.prove(input, expTraces), opts...)
 }
 
 // Verify verifies the inner-proof of the zkEVM
@@ -99,9 +162,21 @@ func newZkEVM(b *wizard.Builder, s *Settings) *ZkEvm {
 		modexp       = modexp.NewModuleZkEvm(comp, s.Modexp)
 		ecadd        = ecarith.NewEcAddZkEvm(comp, &s.Ecadd)
 		ecmul        = ecarith.NewEcMulZkEvm(comp, &s.Ecmul)
-		// ecpair      = ecpair.NewECPairZkEvm(comp, &s.Ecpair)
-		sha2        = sha2.NewSha2ZkEvm(comp, s.Sha2)
-		publicInput = publicInput.NewPublicInputZkEVM(comp, &s.PublicInput, &stateManager.StateSummary)
+		ecpair       = ecpair.NewECPairZkEvm(comp, &s.Ecpair)
+		sha2         = sha2.NewSha2ZkEvm(comp, s.Sha2)
+		ripemd       = ripemd.NewRipemdZkEvm(comp, s.Ripemd)
+		blake2f      = blake2f.NewModuleZkEvm(comp, s.Blake2f)
+		p256verify   = p256verify.NewP256VerifyZkEvm(comp, &s.P256Verify)
+		pointEval    = pointeval.NewPointEvalZkEvm(comp, &s.PointEval)
+		blsG1Add     = bls12381.NewG1AddZkEvm(comp, &s.BlsG1Add)
+		blsG2Add     = bls12381.NewG2AddZkEvm(comp, &s.BlsG2Add)
+		blsG1Msm     = bls12381.NewG1MsmZkEvm(comp, &s.BlsG1Msm)
+		blsG2Msm     = bls12381.NewG2MsmZkEvm(comp, &s.BlsG2Msm)
+		blsPairing   = bls12381.NewPairingZkEvm(comp, &s.BlsPairing)
+		blsMapG1     = bls12381.NewMapFpToG1ZkEvm(comp, &s.BlsMapFpToG1)
+		blsMapG2     = bls12381.NewMapFp2ToG2ZkEvm(comp, &s.BlsMapFp2ToG2)
+		blsNonMember = bls12381.NewNonMembershipZkEvm(comp, &s.BlsNonMembership)
+		publicInput  = publicInput.NewPublicInputZkEVM(comp, &s.PublicInput, &stateManager.StateSummary)
 	)
 
 	return &ZkEvm{
@@ -112,21 +187,37 @@ func newZkEVM(b *wizard.Builder, s *Settings) *ZkEvm {
 		modexp:          modexp,
 		ecadd:           ecadd,
 		ecmul:           ecmul,
-		// ecpair:      ecpair,
-		sha2:        sha2,
-		PublicInput: &publicInput,
+		ecpair:          ecpair,
+		sha2:            sha2,
+		ripemd:          ripemd,
+		blake2f:         blake2f,
+		p256verify:      p256verify,
+		pointEval:       pointEval,
+		blsG1Add:        blsG1Add,
+		blsG2Add:        blsG2Add,
+		blsG1Msm:        blsG1Msm,
+		blsG2Msm:        blsG2Msm,
+		blsPairing:      blsPairing,
+		blsMapFpToG1:    blsMapG1,
+		blsMapFp2ToG2:   blsMapG2,
+		blsNonMember:    blsNonMember,
+		PublicInput:     &publicInput,
 	}
 }
 
 // Returns a prover function for the zkEVM module. The resulting function is
 // aimed to be passed to the wizard.Prove function.
-func (z *ZkEvm) prove(input *Witness) (prover wizard.ProverStep) {
+func (z *ZkEvm) prove(input *Witness, expTraces trace.Trace) (prover wizard.ProverStep) {
 	return func(run *wizard.ProverRuntime) {
 
 		// Assigns the arithmetization module. From Corset. Must be done first
 		// because the following modules use the content of these columns to
 		// assign themselves.
-		z.arithmetization.Assign(run, input.ExecTracesFPath)
+		z.arithmetization.Assign(run, expTraces)
+
+		// The expanded traces are not used past this point. Dropping the
+		// reference lets the GC reclaim them while the rest of the proof runs.
+		expTraces = nil
 
 		// Assign the state-manager module
 		z.ecdsa.Assign(run, input.TxSignatureGetter, len(input.TxSignatures))
@@ -135,8 +226,43 @@ func (z *ZkEvm) prove(input *Witness) (prover wizard.ProverStep) {
 		z.modexp.Assign(run)
 		z.ecadd.Assign(run)
 		z.ecmul.Assign(run)
-		// z.ecpair.Assign(run)
+		z.ecpair.Assign(run)
 		z.sha2.Run(run)
+		z.ripemd.Run(run)
+		z.blake2f.Assign(run)
+		// The constraints up to v0.8.0-rc8 do not define the columns of the
+		// more recent precompiles. The constructors of their modules return
+		// nil in that case so that the zkEVM can be built without them.
+		if z.p256verify != nil {
+			z.p256verify.Assign(run)
+		}
+		if z.pointEval != nil {
+			z.pointEval.Assign(run)
+		}
+		if z.blsG1Add != nil {
+			z.blsG1Add.Assign(run)
+		}
+		if z.blsG2Add != nil {
+			z.blsG2Add.Assign(run)
+		}
+		if z.blsG1Msm != nil {
+			z.blsG1Msm.Assign(run)
+		}
+		if z.blsG2Msm != nil {
+			z.blsG2Msm.Assign(run)
+		}
+		if z.blsPairing != nil {
+			z.blsPairing.Assign(run)
+		}
+		if z.blsMapFpToG1 != nil {
+			z.blsMapFpToG1.Assign(run)
+		}
+		if z.blsMapFp2ToG2 != nil {
+			z.blsMapFp2ToG2.Assign(run)
+		}
+		if z.blsNonMember != nil {
+			z.blsNonMember.Assign(run)
+		}
 		z.PublicInput.Assign(run, input.L2BridgeAddress)
 	}
 }
@@ -0,0 +1,39 @@
+package zkevm
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/consensys/linea-monorepo/prover/config"
+	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
+	"github.com/consensys/linea-monorepo/prover/zkevm/arithmetization"
+	"github.com/stretchr/testify/require"
+)
+
+// TestNewZkEVMBundledSchema defines the full zkEVM against the schema bundled
+// in zkevm.bin. It fails if a module of the zkEVM uses a column that the
+// schema does not define.
+func TestNewZkEVMBundledSchema(t *testing.T) {
+
+	var (
+		limits    = &config.TracesLimits{}
+		limitRefl = reflect.ValueOf(limits).Elem()
+	)
+
+	for i := 0; i < limitRefl.NumField(); i++ {
+		limitRefl.Field(i).SetInt(1 << 10)
+	}
+
+	_, errBin := arithmetization.ReadZkevmBin()
+	require.NoError(t, errBin)
+
+	// The modules are only defined: the compilation suite is left empty as it
+	// does not depend on the schema.
+	settings := fullSettings(limits, compilationSuite{})
+
+	require.NotPanics(t, func() {
+		wizard.Compile(func(b *wizard.Builder) {
+			newZkEVM(b, &settings)
+		})
+	})
+}